@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/ppowo/zzk/internal/audio"
+	"github.com/spf13/cobra"
+)
+
+var (
+	audioDryRun   bool
+	audioReencode bool
+	audioTarget   float64
+	audioForce    bool
+	audioNoAlbum  bool
+	audioJobs     int
+)
+
+var audioCmd = &cobra.Command{
+	Use:   "audio",
+	Short: "Audio library operations using ffmpeg",
+	Long:  `Parent command for audio library operations. Use subcommands to perform actions.`,
+}
+
+var audioNormalizeCmd = &cobra.Command{
+	Use:   "normalize [path...]",
+	Short: "Normalize loudness across the music library",
+	Long: `Measure the loudness (EBU R128) of audio files under the given paths
+(default ~/Music) and normalize them.
+
+By default ReplayGain track and album tags are written without touching
+the audio, so players that support ReplayGain play everything at the same
+volume. Files in the same directory are treated as one album. Files that
+already have ReplayGain tags are skipped unless --force is given.
+
+With --reencode, the audio itself is re-encoded to the target loudness
+(two-pass loudnorm), for players without ReplayGain support. This is lossy
+for lossy formats.
+
+Examples:
+  zzk audio normalize --dry-run                 # Analyze ~/Music, change nothing
+  zzk audio normalize ~/Music/Artist-Album      # Tag one album
+  zzk audio normalize --reencode --target -14 song.mp3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := CheckFfmpeg(); err != nil {
+			return err
+		}
+
+		roots := args
+		if len(roots) == 0 {
+			roots = []string{filepath.Join(os.Getenv("HOME"), "Music")}
+		}
+
+		var files []string
+		for _, root := range roots {
+			found, err := audio.Find(root)
+			if err != nil {
+				return fmt.Errorf("failed to scan %s: %w", root, err)
+			}
+			files = append(files, found...)
+		}
+
+		if !audioForce && !audioReencode && !audioDryRun {
+			var pending []string
+			for _, file := range files {
+				if !audio.HasReplayGain(file) {
+					pending = append(pending, file)
+				}
+			}
+			if skipped := len(files) - len(pending); skipped > 0 {
+				fmt.Printf("ℹ Skipping %d files that already have ReplayGain tags (use --force to redo)\n", skipped)
+			}
+			files = pending
+		}
+
+		if len(files) == 0 {
+			fmt.Println("No audio files to process")
+			return nil
+		}
+
+		target := audio.ReplayGainReference
+		if audioReencode {
+			target = audioTarget
+		}
+
+		fmt.Printf("Analyzing %d files...\n", len(files))
+		results := audioAnalyzeAll(files, target)
+
+		fmt.Println()
+		fmt.Printf("%8s %8s %8s  %s\n", "LUFS", "Peak", "Gain", "File")
+		failed := 0
+		for _, file := range files {
+			r := results[file]
+			if r.err != nil {
+				fmt.Printf("%8s %8s %8s  %s (%v)\n", "✗", "", "", file, r.err)
+				failed++
+				continue
+			}
+			gain := r.loudness.TrackGain()
+			if audioReencode {
+				gain = target - r.loudness.Integrated
+			}
+			fmt.Printf("%8.1f %8.1f %+7.1fdB  %s\n", r.loudness.Integrated, r.loudness.TruePeak, gain, file)
+		}
+
+		if audioDryRun {
+			fmt.Println("\nℹ Dry run - no files changed")
+			return nil
+		}
+
+		// Group by directory for album gain
+		albums := make(map[string][]audio.Loudness)
+		for _, file := range files {
+			if r := results[file]; r.err == nil {
+				dir := filepath.Dir(file)
+				albums[dir] = append(albums[dir], r.loudness)
+			}
+		}
+
+		fmt.Println()
+		done := 0
+		for _, file := range files {
+			r := results[file]
+			if r.err != nil {
+				continue
+			}
+
+			var err error
+			if audioReencode {
+				err = audio.Normalize(file, r.loudness, target)
+			} else {
+				var album *audio.Loudness
+				if !audioNoAlbum {
+					a := audio.AlbumLoudness(albums[filepath.Dir(file)])
+					album = &a
+				}
+				err = audio.WriteReplayGain(file, r.loudness, album)
+			}
+			if err != nil {
+				fmt.Printf("✗ %s: %v\n", file, err)
+				failed++
+				continue
+			}
+			done++
+		}
+
+		verb := "Tagged"
+		if audioReencode {
+			verb = "Normalized"
+		}
+		fmt.Printf("✓ %s %d files", verb, done)
+		if failed > 0 {
+			fmt.Printf(" (%d failed)", failed)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+type audioResult struct {
+	loudness audio.Loudness
+	err      error
+}
+
+// audioAnalyzeAll measures files in parallel
+func audioAnalyzeAll(files []string, target float64) map[string]audioResult {
+	results := make(map[string]audioResult, len(files))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobs := audioJobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, jobs)
+
+	for _, file := range files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(file string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			loudness, err := audio.Analyze(file, target)
+			mu.Lock()
+			results[file] = audioResult{loudness, err}
+			mu.Unlock()
+		}(file)
+	}
+	wg.Wait()
+	return results
+}
+
+func CheckFfmpeg() error {
+	_, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg is not installed. Please install ffmpeg first.\n" +
+			"  macOS: brew install ffmpeg\n" +
+			"  Linux (Debian/Ubuntu): sudo apt install ffmpeg\n" +
+			"  Linux (Fedora): sudo dnf install ffmpeg\n" +
+			"  Windows: scoop install ffmpeg or choco install ffmpeg")
+	}
+	return nil
+}
+
+func init() {
+	audioNormalizeCmd.Flags().BoolVarP(&audioDryRun, "dry-run", "n", false, "Only analyze and report, don't change files")
+	audioNormalizeCmd.Flags().BoolVar(&audioReencode, "reencode", false, "Re-encode audio to the target loudness instead of tagging")
+	audioNormalizeCmd.Flags().Float64Var(&audioTarget, "target", -14, "Target loudness in LUFS for --reencode")
+	audioNormalizeCmd.Flags().BoolVarP(&audioForce, "force", "f", false, "Process files that already have ReplayGain tags")
+	audioNormalizeCmd.Flags().BoolVar(&audioNoAlbum, "no-album", false, "Only write track gain, not album gain")
+	audioNormalizeCmd.Flags().IntVarP(&audioJobs, "jobs", "j", 0, "Parallel analyses (default: number of CPUs)")
+	audioCmd.AddCommand(audioNormalizeCmd)
+	rootCmd.AddCommand(audioCmd)
+}
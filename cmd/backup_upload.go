@@ -7,10 +7,17 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/ppowo/zzk/internal/archive"
+	"github.com/ppowo/zzk/internal/checksum"
+	"github.com/ppowo/zzk/internal/notify"
 )
 
-func uploadBackup(target BackupTarget) error {
-	timestamp := time.Now().Format("2006-01-02 15:04")
+func uploadBackup(target BackupTarget) (err error) {
+	started := time.Now()
+	defer func() { notify.Failed(fmt.Sprintf("%s backup failed", target.Name), err, started) }()
+
+	timestamp := started.Format("2006-01-02 15:04")
 	fmt.Printf("%s - Starting %s backup\n", timestamp, target.Name)
 	fmt.Printf("This will archive your ~/%s and upload it for backup/sharing\n", target.Path)
 	fmt.Println()
@@ -33,22 +40,16 @@ func uploadBackup(target BackupTarget) error {
 		return fmt.Errorf("failed to create temporary file: %w", err)
 	}
 	tmpArchive := tmpFile.Name()
-	tmpFile.Close()
 	defer os.Remove(tmpArchive)
 
-	// Build tar command
-	tarArgs := []string{"-cJf", tmpArchive}
-	for _, pattern := range globalExcludeGlobs {
-		tarArgs = append(tarArgs, "--exclude", pattern)
-	}
-	tarArgs = append(tarArgs, target.Path)
-
 	fmt.Printf("%s - Creating compressed archive...\n", time.Now().Format("2006-01-02 15:04"))
 
-	cmd := exec.Command("tar", tarArgs...)
-	cmd.Dir = home
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to create archive: %w\n%s", err, output)
+	if err := archive.Write(tmpFile, archive.FormatTarXz, home, []string{target.Path}, globalExcludeGlobs); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
 	}
 
 	// Get archive size
@@ -98,6 +99,19 @@ func uploadBackup(target BackupTarget) error {
 		return fmt.Errorf("upload verification failed: %w\nReceived file may be an error page instead of archive", err)
 	}
 
+	// Check the downloaded archive is byte-for-byte what we uploaded
+	localSum, err := checksum.HashFile(tmpArchive, checksum.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to hash archive: %w", err)
+	}
+	remoteSum, err := checksum.HashFile(verifyPath, checksum.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to hash downloaded archive: %w", err)
+	}
+	if localSum != remoteSum {
+		return fmt.Errorf("upload verification failed: checksum mismatch (local %s, remote %s)", localSum[:12], remoteSum[:12])
+	}
+
 	fmt.Printf("%s - Upload verified successfully!\n", time.Now().Format("2006-01-02 15:04"))
 	fmt.Printf("%s - Your %s backup is available at:\n", time.Now().Format("2006-01-02 15:04"), target.Name)
 	fmt.Println(url)
@@ -107,6 +121,7 @@ func uploadBackup(target BackupTarget) error {
 	fmt.Printf("%s - Restore with: zzk backup %s %s\n", time.Now().Format("2006-01-02 15:04"), target.Name, code)
 	fmt.Printf("%s - Temporary archive removed.\n", time.Now().Format("2006-01-02 15:04"))
 
+	notify.Done(fmt.Sprintf("%s backup complete", target.Name), "Uploaded to "+url, started)
 	return nil
 }
 
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/brightness"
+	"github.com/spf13/cobra"
+)
+
+var (
+	brightnessDisplay string
+	brightnessList    bool
+	brightnessStep    int
+)
+
+var brightnessCmd = &cobra.Command{
+	Use:   "brightness [level|up|down|+N|-N]",
+	Short: "Get or set display brightness",
+	Long: `Get or set display brightness (0-100).
+
+Without arguments the current level is printed. "up" and "down" change it by
+--step; "+N" and "-N" by N (use -- before negative values).
+
+macOS uses the brightness tool (brew install brightness), which only controls
+built-in and Apple displays. Linux uses /sys/class/backlight, falling back to
+brightnessctl when it isn't writable.
+
+Examples:
+  zzk brightness            # Show the current level
+  zzk brightness 60         # Set to 60
+  zzk brightness up         # Up by 10
+  zzk brightness down -s 5  # Down by 5
+  zzk brightness -- -20     # Down by 20
+  zzk brightness --list     # List displays
+  zzk brightness -d 1 80    # Set a specific display`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if brightnessList {
+			displays, err := brightness.List()
+			if err != nil {
+				return err
+			}
+			if len(displays) == 0 {
+				fmt.Println("No displays with brightness control found")
+				return nil
+			}
+			for _, d := range displays {
+				fmt.Printf("%-20s %-10s %3d%%\n", d.ID, d.Name, d.Level)
+			}
+			return nil
+		}
+
+		display, err := brightness.Find(brightnessDisplay)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			fmt.Printf("Brightness is %d\n", display.Level)
+			return nil
+		}
+
+		target, err := brightnessTarget(args[0], display.Level)
+		if err != nil {
+			return err
+		}
+
+		if err := brightness.Set(display, target); err != nil {
+			return fmt.Errorf("failed to set brightness: %w", err)
+		}
+		fmt.Printf("Brightness set to %d (was %d)\n", target, display.Level)
+		return nil
+	},
+}
+
+// brightnessTarget resolves an absolute or relative argument to a level between 0 and 100
+func brightnessTarget(arg string, current int) (int, error) {
+	var target int
+	switch {
+	case arg == "up":
+		target = current + brightnessStep
+	case arg == "down":
+		target = current - brightnessStep
+	case strings.HasPrefix(arg, "+"), strings.HasPrefix(arg, "-"):
+		delta, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, fmt.Errorf("brightness must be a number, up or down")
+		}
+		target = current + delta
+	default:
+		v, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, fmt.Errorf("brightness must be a number, up or down")
+		}
+		if v < 0 || v > 100 {
+			return 0, fmt.Errorf("brightness must be between 0 and 100")
+		}
+		target = v
+	}
+	return max(0, min(100, target)), nil
+}
+
+func init() {
+	brightnessCmd.Flags().StringVarP(&brightnessDisplay, "display", "d", "", "Display ID (see --list)")
+	brightnessCmd.Flags().BoolVarP(&brightnessList, "list", "l", false, "List displays")
+	brightnessCmd.Flags().IntVarP(&brightnessStep, "step", "s", 10, "Step for up/down")
+	rootCmd.AddCommand(brightnessCmd)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ppowo/zzk/internal/checksum"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checksumAlgo  string
+	checksumWrite string
+)
+
+var checksumCmd = &cobra.Command{
+	Use:   "checksum <path...>",
+	Short: "Compute, verify and compare checksums",
+	Long: `Compute SHA256 or BLAKE3 checksums of files and directories.
+
+Directories are hashed recursively (excluding the global backup exclude
+patterns) with paths relative to the directory. Output uses the
+sha256sum/b3sum format, so manifests are interchangeable with those tools.
+
+Examples:
+  zzk checksum file.iso                       # SHA256 of a file
+  zzk checksum -a blake3 ~/Music              # BLAKE3 of every file in a tree
+  zzk checksum -w SHA256SUMS ~/Music          # Write a manifest
+  zzk checksum verify SHA256SUMS -C ~/Music   # Verify a manifest
+  zzk checksum compare ~/Music /mnt/nas/Music # Compare two trees`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var out io.Writer = os.Stdout
+		if checksumWrite != "" {
+			f, err := os.Create(checksumWrite)
+			if err != nil {
+				return fmt.Errorf("failed to create manifest: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		count := 0
+		for _, path := range args {
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("cannot read %s: %w", path, err)
+			}
+
+			var entries []checksum.Entry
+			if info.IsDir() {
+				entries, err = checksum.HashTree(path, checksumAlgo, globalExcludeGlobs)
+				if err != nil {
+					return err
+				}
+			} else {
+				sum, err := checksum.HashFile(path, checksumAlgo)
+				if err != nil {
+					return fmt.Errorf("failed to hash %s: %w", path, err)
+				}
+				entries = []checksum.Entry{{Path: filepath.ToSlash(path), Hash: sum}}
+			}
+
+			if err := checksum.WriteManifest(out, entries); err != nil {
+				return err
+			}
+			count += len(entries)
+		}
+
+		if checksumWrite != "" {
+			fmt.Printf("✓ Wrote %d checksums to %s\n", count, checksumWrite)
+		}
+		return nil
+	},
+}
+
+var checksumVerifyDir string
+
+var checksumVerifyCmd = &cobra.Command{
+	Use:   "verify <manifest>",
+	Short: "Verify files against a checksum manifest",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest := args[0]
+
+		algo := checksumAlgo
+		if !cmd.Flags().Changed("algo") {
+			algo = checksum.DetectAlgorithm(manifest)
+		}
+
+		f, err := os.Open(manifest)
+		if err != nil {
+			return fmt.Errorf("failed to open manifest: %w", err)
+		}
+		entries, err := checksum.ParseManifest(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", manifest, err)
+		}
+
+		result, err := checksum.Verify(entries, checksumVerifyDir, algo)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range result.Mismatch {
+			fmt.Printf("✗ %s: MISMATCH\n", path)
+		}
+		for _, path := range result.Missing {
+			fmt.Printf("⚠ %s: MISSING\n", path)
+		}
+
+		fmt.Printf("\n%d OK, %d mismatched, %d missing (%s)\n",
+			len(result.OK), len(result.Mismatch), len(result.Missing), algo)
+
+		if len(result.Mismatch) > 0 || len(result.Missing) > 0 {
+			return fmt.Errorf("verification failed")
+		}
+		return nil
+	},
+}
+
+var checksumCompareCmd = &cobra.Command{
+	Use:   "compare <dir-a> <dir-b>",
+	Short: "Compare the contents of two directory trees",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := checksum.CompareTrees(args[0], args[1], checksumAlgo, globalExcludeGlobs)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range result.Differ {
+			fmt.Printf("≠ %s\n", path)
+		}
+		for _, path := range result.OnlyLeft {
+			fmt.Printf("< %s\n", path)
+		}
+		for _, path := range result.OnlyRight {
+			fmt.Printf("> %s\n", path)
+		}
+
+		if result.Equal() {
+			fmt.Printf("✓ Trees are identical (%d files)\n", result.Same)
+			return nil
+		}
+
+		fmt.Printf("\n%d identical, %d differ, %d only in %s, %d only in %s\n",
+			result.Same, len(result.Differ), len(result.OnlyLeft), args[0], len(result.OnlyRight), args[1])
+		return fmt.Errorf("trees differ")
+	},
+}
+
+func init() {
+	checksumCmd.PersistentFlags().StringVarP(&checksumAlgo, "algo", "a", checksum.SHA256, "Hash algorithm: sha256 or blake3")
+	checksumCmd.Flags().StringVarP(&checksumWrite, "write", "w", "", "Write a manifest file instead of printing")
+	checksumVerifyCmd.Flags().StringVarP(&checksumVerifyDir, "dir", "C", ".", "Directory the manifest paths are relative to")
+	checksumCmd.AddCommand(checksumVerifyCmd)
+	checksumCmd.AddCommand(checksumCompareCmd)
+	rootCmd.AddCommand(checksumCmd)
+}
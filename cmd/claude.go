@@ -16,15 +16,53 @@ Claude Code uses Anthropic as the default provider, but you can configure and
 switch between alternative providers like Synthetic or other API-compatible services.
 
 Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+Several accounts with one provider are kept as profiles, named after a colon:
+'synthetic:work', 'synthetic:personal'.
 
-Configuration file: ~/.claude-providers.json
-Environment file: ~/.config/zzk/claude-env.sh
+Providers beyond the built-in ones, such as a company gateway, can be defined
+in ~/.config/zzk/claude-templates.json:
+
+  {
+    "templates": [
+      {
+        "id": "corp",
+        "name": "Corp Gateway",
+        "base_url": "https://llm.corp.example/anthropic",
+        "allow_models": true,
+        "default_model": "claude-sonnet-4-5"
+      }
+    ]
+  }
+
+A template with a built-in ID replaces the built-in one. The model picker of
+'zzk claude set' lists <base_url>/v1/models unless "models_url" is set.
+'zzk claude templates update' downloads the signed template registry published
+with releases, so new providers and changed base URLs arrive without
+upgrading zzk.
+
+Configuration file: ~/.claude-providers.json (.age when encrypted)
+Templates file: ~/.config/zzk/claude-templates.json
+Template registry: ~/.config/zzk/claude-registry.json
+Environment file: ~/.config/zzk/claude-env.sh (.nu for nushell, .ps1 for PowerShell)
 
 Examples:
+  zzk claude setup                # Source the env file from your shell RC file
   zzk claude ls                   # List providers (shows active)
+  zzk claude status               # Spot shells still using an old provider
+  zzk claude ping                 # Check the provider is up and fast
+  zzk claude prompt --color       # Provider segment for PS1 or starship
+  zzk claude usage                # Balance and recent spend per provider
   zzk claude set synthetic        # Configure a provider (add or update)
   zzk claude use syn              # Switch to a provider (prefix matching)
+  zzk claude use syn:work         # Switch to a profile of a provider
+  zzk claude run -- claude        # Run a command with the project or active provider's env
+  zzk claude env --project        # Print exports for this directory's provider
+  zzk claude rotate synthetic     # Replace a provider's API key
+  zzk claude templates update     # Fetch the latest provider templates
+  zzk claude settings zai --timeout-ms 300000   # Timeout and telemetry, per provider or global
   zzk claude reset                # Reset to official Anthropic
+  zzk claude encrypt              # Keep the config age-encrypted at rest
+  zzk claude export -o p.age      # Encrypted bundle of providers for another machine
   zzk claude rm synthetic         # Remove a provider`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if runtime.GOOS == "windows" {
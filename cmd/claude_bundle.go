@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/crypt"
+	"github.com/ppowo/zzk/internal/fileutil"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claudeExportOutput     string
+	claudeExportForce      bool
+	claudeImportOnConflict string
+)
+
+var claudeExportCmd = &cobra.Command{
+	Use:   "export --output <file.age> [provider...]",
+	Short: "Export providers, API keys included, to an encrypted bundle",
+	Long: `Write the configured providers, or only those given, with their API keys
+and settings to a bundle encrypted with age to a passphrase, to move them to
+another machine with 'zzk claude import'.
+
+The passphrase is read from $ZZK_CRYPT_PASSPHRASE or prompted twice. The
+active provider isn't part of the bundle.
+
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+
+Examples:
+  zzk claude export --output providers.age           # All providers
+  zzk claude export -o providers.age synthetic zai   # Only these`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if claudeExportOutput == "" {
+			return fmt.Errorf("--output is required")
+		}
+		if _, err := os.Stat(claudeExportOutput); err == nil && !claudeExportForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", claudeExportOutput)
+		}
+
+		ids := make([]string, 0, len(args))
+		for _, arg := range args {
+			id, err := claude.ResolveProviderID(arg)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if len(config.Providers) == 0 {
+			return fmt.Errorf("no providers configured")
+		}
+		bundle, err := claude.NewBundle(config, ids)
+		if err != nil {
+			return err
+		}
+
+		passphrase, err := crypt.ReadPassphrase(true)
+		if err != nil {
+			return err
+		}
+		data, err := bundle.Encrypt(passphrase)
+		if err != nil {
+			return err
+		}
+		if err := fileutil.AtomicWrite(claudeExportOutput, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", claudeExportOutput, err)
+		}
+
+		fmt.Printf("✓ Exported %d provider(s) to %s: %s\n", len(bundle.Providers), claudeExportOutput,
+			strings.Join(slices.Sorted(maps.Keys(bundle.Providers)), ", "))
+		fmt.Println("  It holds your API keys - delete it once imported")
+		return nil
+	},
+}
+
+var claudeImportCmd = &cobra.Command{
+	Use:   "import <file.age>",
+	Short: "Import providers from a bundle written by 'zzk claude export'",
+	Long: `Add the providers in a bundle written by 'zzk claude export' to the config,
+storing their API keys in the vault. The passphrase is read from
+$ZZK_CRYPT_PASSPHRASE or prompted.
+
+A provider that is already configured with other settings is a conflict,
+resolved by --on-conflict:
+  ask      Show what differs and ask whether to replace it (default)
+  skip     Keep the configured provider
+  replace  Take the bundle's provider
+
+Providers without a template on this machine (custom ones from
+~/.config/zzk/claude-templates.json) are skipped; copy the templates file
+over first. If the active provider is replaced, its env file is rewritten.
+
+Examples:
+  zzk claude import providers.age
+  zzk claude import providers.age --on-conflict skip`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch claudeImportOnConflict {
+		case "ask", "skip", "replace":
+		default:
+			return fmt.Errorf("invalid --on-conflict %q (use ask, skip or replace)", claudeImportOnConflict)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read bundle: %w", err)
+		}
+		passphrase, err := crypt.ReadPassphrase(false)
+		if err != nil {
+			return err
+		}
+		bundle, err := claude.OpenBundle(data, passphrase)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		var added, replaced, unchanged, skipped []string
+		for _, id := range slices.Sorted(maps.Keys(bundle.Providers)) {
+			provider := bundle.Providers[id]
+			if !claude.IsValidProviderID(id) {
+				fmt.Printf("⚠ Skipping %s: no such provider template here\n", id)
+				skipped = append(skipped, id)
+				continue
+			}
+
+			existing, exists := config.GetProvider(id)
+			if exists {
+				if existing, err = config.ResolveKey(id); err != nil {
+					return err
+				}
+				diff := claudeProviderDiff(existing, provider)
+				if len(diff) == 0 {
+					unchanged = append(unchanged, id)
+					continue
+				}
+
+				replace := claudeImportOnConflict == "replace"
+				if claudeImportOnConflict == "ask" {
+					fmt.Printf("%s is already configured with a different %s\n", id, strings.Join(diff, ", "))
+					if replace, err = claude.PromptYesNo(fmt.Sprintf("Replace %s with the imported one?", id), false); err != nil {
+						return fmt.Errorf("%w (use --on-conflict skip or replace)", err)
+					}
+				}
+				if !replace {
+					skipped = append(skipped, id)
+					continue
+				}
+			}
+
+			if err := config.AddProvider(id, provider); err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", id, err)
+				skipped = append(skipped, id)
+				continue
+			}
+			if provider.BaseURLOverride != "" {
+				fmt.Printf("⚠ %s sends requests to %s instead of its default URL\n", id, provider.BaseURLOverride)
+			}
+			if exists {
+				replaced = append(replaced, id)
+			} else {
+				added = append(added, id)
+			}
+		}
+
+		if len(added)+len(replaced) > 0 {
+			if err := claude.SaveConfig(config); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		for _, line := range []struct {
+			label string
+			ids   []string
+		}{{"Added", added}, {"Replaced", replaced}, {"Unchanged", unchanged}, {"Skipped", skipped}} {
+			if len(line.ids) > 0 {
+				fmt.Printf("  %-10s %s\n", line.label+":", strings.Join(line.ids, ", "))
+			}
+		}
+		fmt.Printf("✓ Imported %d provider(s) from %s\n", len(added)+len(replaced), args[0])
+
+		if slices.Contains(replaced, config.Active) {
+			if err := claude.WriteEnvFile(config, config.Active); err != nil {
+				return fmt.Errorf("failed to write env file: %w", err)
+			}
+			fmt.Println(claude.GetReloadInstructions())
+		} else if config.Active == "" && len(added) > 0 {
+			fmt.Printf("  Activate one with: zzk claude use %s\n", added[0])
+		}
+		return nil
+	},
+}
+
+// claudeProviderDiff names the settings in which two providers differ
+func claudeProviderDiff(a, b claude.Provider) []string {
+	var diff []string
+	if a.APIKey != b.APIKey {
+		diff = append(diff, "API key")
+	}
+	if a.OpusModel != b.OpusModel || a.SonnetModel != b.SonnetModel ||
+		a.HaikuModel != b.HaikuModel || a.SubagentModel != b.SubagentModel {
+		diff = append(diff, "models")
+	}
+	if a.BaseURLOverride != b.BaseURLOverride {
+		diff = append(diff, "base URL")
+	}
+	if a.HTTPSProxy != b.HTTPSProxy || a.NoProxy != b.NoProxy {
+		diff = append(diff, "proxy")
+	}
+	return diff
+}
+
+func init() {
+	claudeExportCmd.Flags().StringVarP(&claudeExportOutput, "output", "o", "", "Bundle file to write")
+	claudeExportCmd.Flags().BoolVarP(&claudeExportForce, "force", "f", false, "Overwrite an existing bundle file")
+	claudeImportCmd.Flags().StringVar(&claudeImportOnConflict, "on-conflict", "ask", "Conflicting providers: ask, skip or replace")
+	claudeCmd.AddCommand(claudeExportCmd)
+	claudeCmd.AddCommand(claudeImportCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var claudeEncryptPassphrase bool
+
+var claudeEncryptCmd = &cobra.Command{
+	Use:   "encrypt [--passphrase]",
+	Short: "Encrypt the providers config at rest",
+	Long: `Encrypt ~/.claude-providers.json with age into ~/.claude-providers.json.age
+and remove the plaintext file and its backup. Every 'zzk claude' command then
+decrypts it transparently and writes it back encrypted.
+
+By default the config is encrypted to a key generated at
+~/.config/zzk/claude-key.txt, so a synced or backed up copy of the config
+is useless without it. With --passphrase it is encrypted to a passphrase
+instead, read from $ZZK_CRYPT_PASSPHRASE or prompted whenever the config is
+loaded.
+
+API keys aren't part of the config either way; they live in the vault.
+
+Examples:
+  zzk claude encrypt                # Encrypt to the generated key
+  zzk claude encrypt --passphrase   # Encrypt to a passphrase
+  zzk claude decrypt                # Back to plaintext`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if config.Encrypted() {
+			fmt.Printf("ℹ %s is already encrypted - run 'zzk claude decrypt' first to switch modes\n", claude.EncryptedConfigPath())
+			return nil
+		}
+
+		if err := config.Encrypt(claudeEncryptPassphrase); err != nil {
+			return err
+		}
+		if err := claude.SaveConfig(config); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		fmt.Printf("✓ Encrypted the config to %s\n", claude.EncryptedConfigPath())
+		if claudeEncryptPassphrase {
+			fmt.Println("  zzk claude commands will ask for the passphrase (or read $ZZK_CRYPT_PASSPHRASE)")
+		} else {
+			fmt.Printf("  Key: %s - keep it out of the places the config is synced to\n", claude.ConfigKeyPath())
+		}
+		return nil
+	},
+}
+
+var claudeDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the providers config in plaintext again",
+	Long: `Decrypt ~/.claude-providers.json.age back to ~/.claude-providers.json and
+remove the encrypted file. The key at ~/.config/zzk/claude-key.txt is kept.
+
+Examples:
+  zzk claude decrypt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if !config.Encrypted() {
+			fmt.Printf("ℹ %s isn't encrypted\n", claude.ConfigPath())
+			return nil
+		}
+
+		config.Decrypt()
+		if err := claude.SaveConfig(config); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Decrypted the config to %s\n", claude.ConfigPath())
+		return nil
+	},
+}
+
+func init() {
+	claudeEncryptCmd.Flags().BoolVar(&claudeEncryptPassphrase, "passphrase", false, "Encrypt to a passphrase instead of a key")
+	claudeCmd.AddCommand(claudeEncryptCmd)
+	claudeCmd.AddCommand(claudeDecryptCmd)
+}
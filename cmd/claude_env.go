@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claudeEnvProvider string
+	claudeEnvProject  bool
+)
+
+var claudeEnvCmd = &cobra.Command{
+	Use:   "env [--project] [--provider <provider>]",
+	Short: "Print the shell exports for a Claude API provider",
+	Long: `Print the export and unset commands for the active provider, or the one
+given with --provider, to eval in the current shell.
+
+With --project, a provider selected for the current directory is used over
+the active one: $ZZK_CLAUDE_PROVIDER if set (e.g. exported by a direnv
+.envrc), else the first line of the nearest .zzk-claude file in the directory
+or its parents. 'anthropic' selects the official Anthropic API.
+
+'zzk claude run' always looks for a project provider.
+
+Examples:
+  eval "$(zzk claude env --project)"   # Use this project's provider
+  eval "$(zzk claude env -p zai)"      # Use Z.AI in this shell only
+  echo zai > ~/Personal/.zzk-claude    # Z.AI for every repo in ~/Personal
+  echo anthropic > ~/Work/.zzk-claude  # Official API for work repos`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		id, source, vars, err := claudeSelectEnv(config, claudeEnvProvider, claudeEnvProject)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			id, source = claude.OfficialProvider, "no active provider"
+			vars, _ = config.Env(claude.OfficialProvider)
+		}
+		fmt.Printf("# %s (%s)\n", id, source)
+		fmt.Print(claude.ShellExports(vars))
+		return nil
+	},
+}
+
+func init() {
+	claudeEnvCmd.Flags().StringVarP(&claudeEnvProvider, "provider", "p", "", "Provider to use (default: active)")
+	claudeEnvCmd.Flags().BoolVar(&claudeEnvProject, "project", false, "Prefer the provider selected for the current directory")
+	claudeCmd.AddCommand(claudeEnvCmd)
+}
+
+// claudeSelectEnv returns the provider given as flag, else with project the
+// one selected for the current directory, else the active one, with where
+// the choice came from and its environment. id is empty if none is selected.
+func claudeSelectEnv(config *claude.Config, flag string, project bool) (id, source string, vars []claude.EnvVar, err error) {
+	switch {
+	case flag != "":
+		source = "--provider"
+		if flag == claude.OfficialProvider {
+			id = flag
+		} else if id, err = claude.ResolveProviderID(flag); err != nil {
+			return "", "", nil, err
+		}
+	case project:
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", "", nil, err
+		}
+		if id, source, err = claude.ProjectProvider(cwd); err != nil {
+			return "", "", nil, err
+		}
+	}
+	if id == "" && config.Active != "" {
+		id, source = config.Active, "active"
+	}
+
+	switch id {
+	case "":
+		return "", "", nil, nil
+	case claude.OfficialProvider:
+		vars, _ = config.Env(id)
+		return id, source, vars, nil
+	}
+	vars, err = config.Env(id)
+	return id, source, vars, err
+}
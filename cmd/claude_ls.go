@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ppowo/zzk/internal/claude"
 	"github.com/spf13/cobra"
 )
 
+var claudeLsJSON bool
+
 var claudeLsCmd = &cobra.Command{
 	Use:     "ls",
 	Aliases: []string{"list"},
@@ -18,8 +22,19 @@ Shows all provider templates with:
   + - configured (has API key)
   - - not configured
 
-Example:
-  zzk claude ls`,
+Providers defined in ~/.config/zzk/claude-templates.json are marked custom,
+and those set to another URL with 'zzk claude set --base-url' overridden.
+Providers reached through a proxy ('zzk claude set --https-proxy') show it.
+API keys older than 90 days (see 'zzk claude rotate --max-age') are flagged.
+Profiles of a provider ('synthetic:work') are listed below each other.
+
+With --json, prints one record per provider and profile with its configured
+and active flags, base URL, model overrides and masked API key, for status
+bars and scripts.
+
+Examples:
+  zzk claude ls
+  zzk claude ls --json | jq -r '.[] | select(.active) | .name'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load config
 		config, err := claude.LoadConfig()
@@ -27,10 +42,16 @@ Example:
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if claudeLsJSON {
+			printClaudeProviderRecords(config)
+			return nil
+		}
+
 		// Show active provider
 		if config.Active != "" {
-			if tmpl, ok := claude.GetTemplate(config.Active); ok {
-				fmt.Printf("Active: %s (%s)\n\n", tmpl.Name, tmpl.BaseURL)
+			if tmpl, ok := claude.TemplateFor(config.Active); ok {
+				provider, _ := config.GetProvider(config.Active)
+				fmt.Printf("Active: %s (%s)\n\n", claude.ProviderName(config.Active), provider.BaseURL(tmpl))
 			} else {
 				fmt.Printf("Active: %s\n\n", config.Active)
 			}
@@ -39,32 +60,60 @@ Example:
 			fmt.Println()
 		}
 
-		// Show all templates with status
+		// Widen the ID column for profiles
+		width := 12
+		for id := range config.Providers {
+			width = max(width, len(id))
+		}
+
+		// Show all templates with status, one line per profile
 		fmt.Println("Providers:")
+		var unconfigured, stale []string
 		for _, tmpl := range claude.ListTemplates() {
-			marker := "-"
-			status := "not configured"
-
-			if config.HasProvider(tmpl.ID) {
-				marker = "+"
-				status = "configured"
-				if tmpl.ID == config.Active {
-					marker = "*"
-					status = "active"
-				}
+			ids := config.Profiles(tmpl.ID)
+			if len(ids) == 0 {
+				unconfigured = append(unconfigured, tmpl.ID)
+				ids = []string{tmpl.ID}
 			}
 
-			fmt.Printf("  %s %-12s %-15s %s\n", marker, tmpl.ID, "("+status+")", tmpl.BaseURL)
-		}
+			for _, id := range ids {
+				marker := "-"
+				status := "not configured"
+				baseURL := tmpl.BaseURL
+				source := ""
+				if tmpl.Custom {
+					source = " (custom)"
+				}
 
-		// Show help for unconfigured providers
-		var unconfigured []string
-		for _, tmpl := range claude.ListTemplates() {
-			if !config.HasProvider(tmpl.ID) {
-				unconfigured = append(unconfigured, tmpl.ID)
+				if provider, ok := config.GetProvider(id); ok {
+					if provider.BaseURLOverride != "" {
+						baseURL = provider.BaseURLOverride
+						source = " (overridden)"
+					}
+					marker = "+"
+					status = "configured"
+					if id == config.Active {
+						marker = "*"
+						status = "active"
+					}
+				}
+
+				fmt.Printf("  %s %-*s %-15s %s%s\n", marker, width, id, "("+status+")", baseURL, source)
+				if provider, ok := config.GetProvider(id); ok && provider.HTTPSProxy != "" {
+					fmt.Printf("    via proxy %s\n", provider.RedactedProxy())
+				}
+				if days, old := config.StaleKey(id); old {
+					stale = append(stale, id)
+					fmt.Printf("    ⚠ API key set %d days ago\n", days)
+				}
 			}
 		}
 
+		if len(stale) > 0 {
+			fmt.Println("\nTo replace an old API key:")
+			fmt.Printf("  zzk claude rotate %s\n", stale[0])
+		}
+
 		if len(unconfigured) > 0 {
 			fmt.Println("\nTo configure a provider:")
 			fmt.Printf("  zzk claude set <provider>\n")
@@ -74,6 +123,80 @@ Example:
 	},
 }
 
+// claudeProviderRecord is a provider as printed by 'zzk claude ls --json'
+type claudeProviderRecord struct {
+	ID                string              `json:"id"`
+	Template          string              `json:"template"`
+	Profile           string              `json:"profile,omitempty"`
+	Name              string              `json:"name"`
+	Configured        bool                `json:"configured"`
+	Active            bool                `json:"active"`
+	Custom            bool                `json:"custom"`
+	BaseURL           string              `json:"base_url"`
+	BaseURLOverridden bool                `json:"base_url_overridden"`
+	APIKey            string              `json:"api_key,omitempty"` // Masked
+	Models            *claudeModelsRecord `json:"models,omitempty"`  // Overrides only
+	KeyRotatedAt      *time.Time          `json:"key_rotated_at,omitempty"`
+	KeyStale          bool                `json:"key_stale,omitempty"`
+	HTTPSProxy        string              `json:"https_proxy,omitempty"` // Password redacted
+	NoProxy           string              `json:"no_proxy,omitempty"`
+}
+
+type claudeModelsRecord struct {
+	Opus     string `json:"opus,omitempty"`
+	Sonnet   string `json:"sonnet,omitempty"`
+	Haiku    string `json:"haiku,omitempty"`
+	Subagent string `json:"subagent,omitempty"`
+}
+
+// printClaudeProviderRecords prints every template, or each of its profiles
+// if configured, as a JSON record in 'zzk claude ls' order
+func printClaudeProviderRecords(config *claude.Config) {
+	records := []claudeProviderRecord{}
+	for _, tmpl := range claude.ListTemplates() {
+		ids := config.Profiles(tmpl.ID)
+		if len(ids) == 0 {
+			ids = []string{tmpl.ID}
+		}
+		for _, id := range ids {
+			_, profile := claude.SplitProviderID(id)
+			record := claudeProviderRecord{
+				ID:       id,
+				Template: tmpl.ID,
+				Profile:  profile,
+				Name:     claude.ProviderName(id),
+				Custom:   tmpl.Custom,
+				BaseURL:  tmpl.BaseURL,
+			}
+			if provider, ok := config.GetProvider(id); ok {
+				record.Configured = true
+				record.Active = id == config.Active
+				record.BaseURL = provider.BaseURL(&tmpl)
+				record.BaseURLOverridden = provider.BaseURLOverride != ""
+				if provider, err := config.ResolveKey(id); err == nil {
+					record.APIKey = claude.MaskAPIKey(provider.APIKey)
+				}
+				if provider.HasModelOverrides() {
+					record.Models = &claudeModelsRecord{provider.OpusModel, provider.SonnetModel, provider.HaikuModel, provider.SubagentModel}
+				}
+				if !provider.KeyRotatedAt.IsZero() {
+					record.KeyRotatedAt = &provider.KeyRotatedAt
+				}
+				_, record.KeyStale = config.StaleKey(id)
+				if provider.HTTPSProxy != "" {
+					record.HTTPSProxy = provider.RedactedProxy()
+				}
+				record.NoProxy = provider.NoProxy
+			}
+			records = append(records, record)
+		}
+	}
+
+	data, _ := json.MarshalIndent(records, "", "  ")
+	fmt.Println(string(data))
+}
+
 func init() {
+	claudeLsCmd.Flags().BoolVar(&claudeLsJSON, "json", false, "Print providers as JSON")
 	claudeCmd.AddCommand(claudeLsCmd)
 }
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var claudePingCount int
+
+var claudePingCmd = &cobra.Command{
+	Use:   "ping [provider] [--count N]",
+	Short: "Check a provider's latency and availability",
+	Long: `Send empty requests to a provider's Messages API, like the API key check of
+'zzk claude set', and report the HTTP status and round-trip time of each,
+then check that the provider lists the models Claude Code will ask for.
+
+The requests use no tokens. The first one also opens the connection, so its
+DNS, connect and TLS times show the cost of reaching the provider (through
+its proxy, if it has one); the following ones reuse the connection and
+mostly time the provider itself. Slow first pings and fast later ones point
+at your network, slow pings throughout at the provider.
+
+Without a provider the active one is pinged.
+
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+
+Examples:
+  zzk claude ping                # The active provider
+  zzk claude ping openrouter     # Another one
+  zzk claude ping syn -n 10      # More samples`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		id := config.Active
+		if len(args) == 1 {
+			if id, err = claude.ResolveProviderID(args[0]); err != nil {
+				return err
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("no active provider - name one, or activate one with 'zzk claude use <provider>'")
+		}
+		provider, ok := config.GetProvider(id)
+		if !ok {
+			return fmt.Errorf("provider '%s' not configured. Use 'zzk claude set %s' to configure it", id, id)
+		}
+		if provider, err = config.ResolveKey(id); err != nil {
+			return err
+		}
+		tmpl, _ := claude.TemplateFor(id)
+		if claudePingCount < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		fmt.Printf("Pinging %s (%s)\n", claude.ProviderName(id), provider.BaseURL(tmpl))
+		if provider.HTTPSProxy != "" {
+			fmt.Printf("  via proxy %s\n", provider.RedactedProxy())
+		}
+		client := provider.HTTPClient()
+		var totals []time.Duration
+		var last *claude.Ping
+		for i := range claudePingCount {
+			ping, err := claude.PingProvider(cmd.Context(), client, provider.BaseURL(tmpl), provider.APIKey)
+			if err != nil {
+				fmt.Printf("  %d: ✗ %v\n", i+1, err)
+				continue
+			}
+			last = ping
+			totals = append(totals, ping.Total)
+			fmt.Printf("  %d: %-26s %6s  (%s)\n", i+1, ping.Status, claudeMillis(ping.Total), claudePingPhases(ping))
+		}
+
+		fmt.Println()
+		switch {
+		case last == nil:
+			return fmt.Errorf("%s didn't answer", tmpl.Name)
+		case errors.Is(last.Err, claude.ErrKeyRejected):
+			fmt.Printf("✗ %s is up but rejected the API key: %v\n", tmpl.Name, strings.TrimPrefix(last.Err.Error(), claude.ErrKeyRejected.Error()+": "))
+		case last.Err != nil:
+			fmt.Printf("⚠ %s answered, but not as expected: %v\n", tmpl.Name, last.Err)
+		default:
+			fmt.Printf("✓ %s is up and accepted the API key\n", tmpl.Name)
+		}
+		if len(totals) > 1 {
+			var sum time.Duration
+			for _, total := range totals {
+				sum += total
+			}
+			fmt.Printf("  Round trip: min %s, avg %s, max %s\n", claudeMillis(slices.Min(totals)),
+				claudeMillis(sum/time.Duration(len(totals))), claudeMillis(slices.Max(totals)))
+		}
+
+		claudePingModels(cmd, tmpl, provider)
+		return nil
+	},
+}
+
+// claudePingPhases describes where the time of a ping went
+func claudePingPhases(ping *claude.Ping) string {
+	var phases []string
+	for _, phase := range []struct {
+		name     string
+		duration time.Duration
+	}{{"dns", ping.DNS}, {"connect", ping.Connect}, {"tls", ping.TLS}, {"server", ping.Server}} {
+		if phase.duration > 0 {
+			phases = append(phases, phase.name+" "+claudeMillis(phase.duration))
+		}
+	}
+	if ping.Connect == 0 {
+		phases = append([]string{"reused connection"}, phases...)
+	}
+	return strings.Join(phases, ", ")
+}
+
+// claudeMillis formats a duration in whole milliseconds
+func claudeMillis(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// claudePingModels checks that the provider lists the models Claude Code
+// will request: the overrides, or the template's default
+func claudePingModels(cmd *cobra.Command, tmpl *claude.ProviderTemplate, provider claude.Provider) {
+	roles := make(map[string][]string)
+	var models []string
+	for _, model := range []struct{ role, id string }{
+		{"opus", provider.OpusModel},
+		{"sonnet", provider.SonnetModel},
+		{"haiku", provider.HaikuModel},
+		{"subagent", provider.SubagentModel},
+	} {
+		id := model.id
+		if id == "" {
+			id = tmpl.DefaultModel
+		}
+		if id == "" {
+			continue
+		}
+		if !slices.Contains(models, id) {
+			models = append(models, id)
+		}
+		roles[id] = append(roles[id], model.role)
+	}
+	if len(models) == 0 {
+		return // The provider picks the models
+	}
+
+	available, err := claude.ListModels(cmd.Context(), provider.HTTPClient(), provider.ModelsURL(tmpl), provider.APIKey)
+	if err != nil {
+		fmt.Printf("\n⚠ Could not list the models to check them: %v\n", err)
+		return
+	}
+	fmt.Println("\nModels:")
+	for _, id := range models {
+		listed := slices.ContainsFunc(available, func(m claude.Model) bool { return m.ID == id })
+		if listed {
+			fmt.Printf("  ✓ %s (%s)\n", id, strings.Join(roles[id], ", "))
+		} else {
+			fmt.Printf("  ✗ %s (%s) isn't listed by %s\n", id, strings.Join(roles[id], ", "), tmpl.Name)
+		}
+	}
+}
+
+func init() {
+	claudePingCmd.Flags().IntVarP(&claudePingCount, "count", "n", 3, "Number of requests to send")
+	claudeCmd.AddCommand(claudePingCmd)
+}
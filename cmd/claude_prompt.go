@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claudePromptSymbol       string
+	claudePromptColor        bool
+	claudePromptHideOfficial bool
+)
+
+var claudePromptCmd = &cobra.Command{
+	Use:   "prompt [--color] [--symbol <s>] [--hide-official]",
+	Short: "Print the shell's Claude API provider for a prompt",
+	Long: `Print a short segment naming the provider Claude Code started from this
+shell would use, e.g. "⚡synthetic", for PS1 or a starship custom command.
+
+The provider is told from the shell's ANTHROPIC_BASE_URL, so the segment
+follows what a reloaded (or not yet reloaded) shell really exports. It reads
+no API keys and skips the network, taking a few milliseconds. Profiles
+sharing a base URL show as their provider; an unknown URL as its host.
+
+--color colors the segment, wrapped in the non-printing markers of bash or
+zsh ($SHELL) so the prompt keeps its width.
+
+Examples:
+  PS1='$(zzk claude prompt --color) \w \$ '            # bash
+  PROMPT='$(zzk claude prompt --color) %~ %# '         # zsh, with setopt prompt_subst
+
+  # starship.toml
+  [custom.claude]
+  command = "zzk claude prompt --hide-official"
+  when = true
+  style = "bold yellow"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := claude.ShellProvider()
+		if id == claude.OfficialProvider && claudePromptHideOfficial {
+			return nil
+		}
+		segment := claudePromptSymbol + id
+		if !claudePromptColor {
+			fmt.Println(segment)
+			return nil
+		}
+
+		// Yellow for third-party providers, blue for the official API
+		color := "\033[33m"
+		if id == claude.OfficialProvider {
+			color = "\033[34m"
+		}
+		start, end := "", ""
+		switch claude.DetectShell() {
+		case "bash":
+			start, end = `\[`, `\]`
+		case "zsh":
+			start, end = "%{", "%}"
+		}
+		fmt.Println(start + color + end + segment + start + "\033[0m" + end)
+		return nil
+	},
+}
+
+func init() {
+	claudePromptCmd.Flags().StringVar(&claudePromptSymbol, "symbol", "⚡", "Text before the provider")
+	claudePromptCmd.Flags().BoolVar(&claudePromptColor, "color", false, "Color the segment for bash or zsh prompts")
+	claudePromptCmd.Flags().BoolVar(&claudePromptHideOfficial, "hide-official", false, "Print nothing for the official Anthropic API")
+	claudeCmd.AddCommand(claudePromptCmd)
+}
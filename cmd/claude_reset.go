@@ -22,6 +22,12 @@ Your provider configurations will be preserved for future use.
 Example:
   zzk claude reset`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
 		if err := claude.ResetToOfficialAPI(); err != nil {
 			return fmt.Errorf("failed to reset to official API: %w", err)
 		}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/vault"
 	"github.com/spf13/cobra"
 )
 
@@ -20,7 +23,8 @@ This removes the API key and any model overrides for the specified provider.
 If the provider is currently active, it will be automatically reset
 to the official Anthropic API.
 
-Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic');
+'synthetic:work' removes only that profile.
 By default, you will be prompted to confirm deletion. Use -f to skip confirmation.
 
 Examples:
@@ -28,13 +32,18 @@ Examples:
   zzk claude rm syn -f         # Remove with prefix matching, no confirmation`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Resolve prefix to full template ID
-		templateID, err := claude.ResolveTemplateID(args[0])
+		// Resolve prefix to full provider ID
+		templateID, err := claude.ResolveProviderID(args[0])
 		if err != nil {
 			return err
 		}
+		name := claude.ProviderName(templateID)
 
-		tmpl, _ := claude.GetTemplate(templateID)
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
 
 		// Load config
 		config, err := claude.LoadConfig()
@@ -49,7 +58,7 @@ Examples:
 
 		// Confirm deletion unless forced
 		if !forceRemove {
-			confirmed, err := claude.PromptYesNo(fmt.Sprintf("Remove configuration for '%s'?", tmpl.Name), false)
+			confirmed, err := claude.PromptYesNo(fmt.Sprintf("Remove configuration for '%s'?", name), false)
 			if err != nil {
 				return fmt.Errorf("%w. Use -f to force", err)
 			}
@@ -71,7 +80,11 @@ Examples:
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 
-		fmt.Printf("Provider '%s' configuration removed\n", tmpl.Name)
+		if err := vault.Delete(claude.KeyRef(templateID)); err != nil && !errors.Is(err, vault.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: failed to remove API key from the vault: %v\n", err)
+		}
+
+		fmt.Printf("Provider '%s' configuration removed\n", name)
 
 		if wasActive {
 			if err := claude.ResetToOfficialAPI(); err != nil {
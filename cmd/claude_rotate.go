@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claudeRotateNoCheck bool
+	claudeRotateMaxAge  int
+)
+
+var claudeRotateCmd = &cobra.Command{
+	Use:   "rotate <provider>",
+	Short: "Replace a provider's API key",
+	Long: `Replace a provider's API key with a new one, keeping its other settings.
+
+The new key is read without echo (or from stdin when piped) and checked with
+an empty request to the provider first, like 'zzk claude set' does; a key
+the provider rejects is not saved. --no-check skips the request.
+
+The key is swapped in the vault and the config, and in the env file if the
+provider is active; if writing the env file fails, the old key is put back.
+The rotation date is recorded, and 'zzk claude ls' warns about keys older
+than 90 days. --max-age sets another limit (in days, -1 to never warn), with
+or without a provider to rotate.
+
+Remember to revoke the old key with the provider afterwards.
+
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+
+Examples:
+  zzk claude rotate synthetic
+  zzk claude rotate syn:work                      # A profile
+  pass show synthetic | zzk claude rotate syn     # Key from another tool
+  zzk claude rotate --max-age 30                  # Warn after 30 days instead`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if cmd.Flags().Changed("max-age") {
+			if claudeRotateMaxAge == 0 || claudeRotateMaxAge < -1 {
+				return fmt.Errorf("--max-age must be a number of days, or -1 to never warn")
+			}
+			config.KeyMaxAgeDays = claudeRotateMaxAge
+			if len(args) == 0 {
+				if err := claude.SaveConfig(config); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				if claudeRotateMaxAge < 0 {
+					fmt.Println("✓ API key age warnings turned off")
+				} else {
+					fmt.Printf("✓ 'zzk claude ls' now warns about API keys older than %d days\n", claudeRotateMaxAge)
+				}
+				return nil
+			}
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("name the provider whose key to rotate")
+		}
+
+		id, err := claude.ResolveProviderID(args[0])
+		if err != nil {
+			return err
+		}
+		old, ok := config.GetProvider(id)
+		if !ok {
+			return fmt.Errorf("provider '%s' not configured. Use 'zzk claude set %s' to configure it", id, id)
+		}
+		if old, err = config.ResolveKey(id); err != nil {
+			return err
+		}
+		tmpl, _ := claude.TemplateFor(id)
+
+		key, err := claude.PromptForNewAPIKey(old.APIKey)
+		if err != nil {
+			return err
+		}
+		provider := old
+		provider.APIKey = key
+		provider.KeyRotatedAt = time.Now().UTC()
+		if err := provider.Validate(id); err != nil {
+			return fmt.Errorf("invalid API key: %w", err)
+		}
+
+		if !claudeRotateNoCheck {
+			err := claude.CheckAPIKey(cmd.Context(), provider.HTTPClient(), provider.BaseURL(tmpl), key)
+			switch {
+			case errors.Is(err, claude.ErrKeyRejected):
+				return fmt.Errorf("%s rejected the new key, keeping the old one: %w", tmpl.Name, err)
+			case err != nil:
+				fmt.Printf("⚠ Could not check the new key: %v\n", err)
+			default:
+				fmt.Printf("✓ %s accepted the new key\n", tmpl.Name)
+			}
+		}
+
+		if err := config.AddProvider(id, provider); err != nil {
+			return fmt.Errorf("failed to save provider: %w", err)
+		}
+		if err := claude.SaveConfig(config); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		active := config.Active == id
+		if active {
+			if err := claude.WriteEnvFile(config, id); err != nil {
+				config.Providers[id] = old
+				if restoreErr := claude.SaveConfig(config); restoreErr != nil {
+					return fmt.Errorf("failed to write env file: %w (and restoring the old key failed: %v)", err, restoreErr)
+				}
+				return fmt.Errorf("failed to write env file, kept the old key: %w", err)
+			}
+		}
+
+		fmt.Printf("✓ Rotated the API key of %s\n", claude.ProviderName(id))
+		fmt.Printf("  Revoke the old key (%s) with %s\n", claude.MaskAPIKey(old.APIKey), tmpl.Name)
+		if active {
+			fmt.Println(claude.GetReloadInstructions())
+		}
+		return nil
+	},
+}
+
+func init() {
+	claudeRotateCmd.Flags().BoolVar(&claudeRotateNoCheck, "no-check", false, "Don't send a test request to check the new key")
+	claudeRotateCmd.Flags().IntVar(&claudeRotateMaxAge, "max-age", claude.DefaultKeyMaxAgeDays, "Days after which 'zzk claude ls' warns about a key (-1: never)")
+	claudeCmd.AddCommand(claudeRotateCmd)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/envrun"
+	"github.com/spf13/cobra"
+)
+
+var claudeRunProvider string
+
+var claudeRunCmd = &cobra.Command{
+	Use:   "run [--provider <provider>] -- <command> [args...]",
+	Short: "Run a command with a Claude API provider's environment",
+	Long: `Run a command with the provider's ANTHROPIC_* variables set, without
+sourcing ~/.config/zzk/claude-env.sh, so CI jobs and one-off terminals can use
+a provider without changing your shell config or the active provider.
+
+A provider selected for the current directory is used over the active one:
+$ZZK_CLAUDE_PROVIDER if set, else the nearest .zzk-claude file (see 'zzk
+claude env'). 'anthropic' selects the official Anthropic API.
+
+Variables the provider leaves unset (e.g. model overrides) are removed from
+the command's environment. The command's exit code is passed through.
+
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+
+Examples:
+  zzk claude run -- claude                        # Project or active provider
+  zzk claude run --provider openrouter -- claude  # Another provider
+  zzk claude run -p syn -- claude -p "Summarize README.md"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		id, _, vars, err := claudeSelectEnv(config, claudeRunProvider, true)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return fmt.Errorf("no active provider - use --provider=<name>, a %s file or 'zzk claude use <name>'", claude.ProjectFile)
+		}
+
+		values := make(map[string]string)
+		var order, unset []string
+		for _, v := range vars {
+			if v.Value == "" {
+				unset = append(unset, v.Key)
+				continue
+			}
+			values[v.Key] = v.Value
+			order = append(order, v.Key)
+		}
+		base := slices.DeleteFunc(os.Environ(), func(entry string) bool {
+			key, _, _ := strings.Cut(entry, "=")
+			return slices.Contains(unset, key)
+		})
+
+		code, err := envrun.Run(args, envrun.Merge(base, values, order))
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			os.Exit(code)
+		}
+		return nil
+	},
+}
+
+func init() {
+	claudeRunCmd.Flags().SetInterspersed(false) // Flags after the command are its own
+	claudeRunCmd.Flags().StringVarP(&claudeRunProvider, "provider", "p", "", "Provider to use (default: active)")
+	claudeCmd.AddCommand(claudeRunCmd)
+}
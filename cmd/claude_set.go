@@ -1,13 +1,41 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ppowo/zzk/internal/claude"
 	"github.com/spf13/cobra"
 )
 
+var (
+	claudeSetBaseURL       string
+	claudeSetHTTPSProxy    string
+	claudeSetNoProxy       string
+	claudeSetNoCheck       bool
+	claudeSetNoPicker      bool
+	claudeSetAPIKey        string
+	claudeSetAPIKeyStdin   bool
+	claudeSetAPIKeyFile    string
+	claudeSetOpusModel     string
+	claudeSetSonnetModel   string
+	claudeSetHaikuModel    string
+	claudeSetSubagentModel string
+)
+
+// claudeSetValueFlags configure a provider without prompting
+var claudeSetValueFlags = []string{"api-key", "api-key-stdin", "api-key-file", "opus-model", "sonnet-model", "haiku-model", "subagent-model", "https-proxy", "no-proxy"}
+
+// claudeAPIKeyEnv holds an API key for 'zzk claude set' to use without
+// prompting, e.g. exported by a password manager wrapper
+const claudeAPIKeyEnv = "ZZK_CLAUDE_API_KEY"
+
 var claudeSetCmd = &cobra.Command{
 	Use:   "set <provider>",
 	Short: "Configure a Claude API provider",
@@ -16,21 +44,65 @@ var claudeSetCmd = &cobra.Command{
 If the provider is already configured, existing values are shown as defaults.
 If the provider is currently active, the environment is automatically reloaded.
 
+After saving, the API key is checked with an empty request to the provider's
+Messages API, which is refused for bad keys without using any tokens. A
+rejected key is only a warning; --no-check skips the request.
+
+For providers that allow model overrides, the models are fetched from the
+provider with the API key and picked from a list you can filter by typing;
+Enter on a filter that matches nothing uses the typed ID. --no-picker, or
+failing to fetch the list, falls back to typing the IDs.
+
 Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+To use several accounts with the same provider, give each a profile name
+after a colon, e.g. 'synthetic:work' and 'synthetic:personal'; each profile
+has its own API key, model overrides and base URL.
+
+--api-key, the model and the proxy flags configure the provider without
+prompting, for scripts and dotfile installers: settings not given keep their
+current value, a model of 'default' resets it. To keep the key out of the
+process list and shell history, pipe it in with --api-key-stdin (or
+'--api-key -'), read it from a file with --api-key-file, or export
+$ZZK_CLAUDE_API_KEY, which is used when no other key is given and also skips
+the prompts.
+
+--base-url sends the provider's requests, and your API key, to another URL
+than the built-in one, e.g. a regional endpoint or a staging gateway. It is
+kept on later updates until reset with '--base-url default'.
+
+--https-proxy exports HTTPS_PROXY for this provider only, e.g. for a gateway
+only reachable through a corporate proxy, and --no-proxy the NO_PROXY hosts
+to reach directly. zzk's own requests to the provider use the proxy too.
+Once any provider has a proxy, switching to one without unsets both, so
+other providers never go through it. 'none' removes them.
 
 Examples:
   zzk claude set synthetic    # Configure Synthetic provider
   zzk claude set syn          # Same (prefix matching)
-  zzk claude set openrouter   # Configure OpenRouter provider`,
+  zzk claude set openrouter   # Configure OpenRouter provider
+  zzk claude set syn:work     # Configure a "work" profile of Synthetic
+  zzk claude set zai --base-url https://open.bigmodel.cn/api/anthropic
+  zzk claude set zai --base-url default   # Back to the built-in URL
+  zzk claude set corp --https-proxy http://proxy.corp.example:3128 --no-proxy localhost,.corp.example
+  zzk claude set openrouter --api-key "$OPENROUTER_KEY" --sonnet-model anthropic/claude-sonnet-4.5
+  op read op://dev/zai/key | zzk claude set zai --api-key-stdin
+  zzk claude set synthetic --api-key-file ~/.secrets/synthetic`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Resolve prefix to full template ID
-		templateID, err := claude.ResolveTemplateID(args[0])
+		// Resolve prefix to full provider ID
+		templateID, err := claude.ResolveProviderID(args[0])
 		if err != nil {
 			return err
 		}
 
-		tmpl, _ := claude.GetTemplate(templateID)
+		tmpl, _ := claude.TemplateFor(templateID)
+		name := claude.ProviderName(templateID)
+
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
 
 		// Load config
 		config, err := claude.LoadConfig()
@@ -42,17 +114,69 @@ Examples:
 		existingProvider, exists := config.GetProvider(templateID)
 		var existing *claude.Provider
 		if exists {
+			if existingProvider, err = config.ResolveKey(templateID); err != nil {
+				return err
+			}
 			existing = &existingProvider
-			fmt.Printf("Updating %s (%s)\n\n", tmpl.Name, tmpl.BaseURL)
+		}
+
+		baseURLOverride := existingProvider.BaseURLOverride
+		if cmd.Flags().Changed("base-url") {
+			baseURLOverride, err = claudeConfirmBaseURL(tmpl, claudeSetBaseURL)
+			if err != nil {
+				return err
+			}
+		}
+
+		proxy, noProxy := existingProvider.HTTPSProxy, existingProvider.NoProxy
+		if cmd.Flags().Changed("https-proxy") {
+			if proxy = strings.TrimSpace(claudeSetHTTPSProxy); proxy == "none" {
+				proxy = ""
+			}
+			if proxy != "" {
+				if err := claude.ValidateProxyURL(proxy); err != nil {
+					return fmt.Errorf("invalid --https-proxy: %w", err)
+				}
+			}
+		}
+		if cmd.Flags().Changed("no-proxy") {
+			if noProxy = strings.TrimSpace(claudeSetNoProxy); noProxy == "none" {
+				noProxy = ""
+			}
+		}
+		client := (&claude.Provider{HTTPSProxy: proxy, NoProxy: noProxy}).HTTPClient()
+
+		baseURL := tmpl.BaseURL
+		if baseURLOverride != "" {
+			baseURL = baseURLOverride
+		}
+		if exists {
+			fmt.Printf("Updating %s (%s)\n\n", name, baseURL)
 		} else {
-			fmt.Printf("Configuring %s (%s)\n\n", tmpl.Name, tmpl.BaseURL)
+			fmt.Printf("Configuring %s (%s)\n\n", name, baseURL)
 		}
 
-		// Prompt for provider configuration
-		provider, err := claude.PromptForProvider(templateID, existing)
+		// Prompt for provider configuration, unless given as flags
+		var provider *claude.Provider
+		if slices.ContainsFunc(claudeSetValueFlags, cmd.Flags().Changed) || os.Getenv(claudeAPIKeyEnv) != "" {
+			provider, err = claudeSetFromFlags(cmd, existingProvider)
+		} else {
+			modelsURL := ""
+			if !claudeSetNoPicker {
+				modelsURL = (&claude.Provider{BaseURLOverride: baseURLOverride}).ModelsURL(tmpl)
+			}
+			provider, err = claude.PromptForProvider(templateID, existing, modelsURL, client)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to configure provider: %w", err)
 		}
+		provider.BaseURLOverride = baseURLOverride
+		provider.HTTPSProxy, provider.NoProxy = proxy, noProxy
+		provider.EnvSettings = existingProvider.EnvSettings
+		provider.KeyRotatedAt = existingProvider.KeyRotatedAt
+		if provider.APIKey != existingProvider.APIKey {
+			provider.KeyRotatedAt = time.Now().UTC()
+		}
 
 		// Save provider to config
 		if err := config.AddProvider(templateID, *provider); err != nil {
@@ -63,23 +187,28 @@ Examples:
 		}
 
 		if exists {
-			fmt.Printf("\nProvider '%s' updated successfully!\n", tmpl.Name)
+			fmt.Printf("\nProvider '%s' updated successfully!\n", name)
 		} else {
-			fmt.Printf("\nProvider '%s' configured successfully!\n", tmpl.Name)
+			fmt.Printf("\nProvider '%s' configured successfully!\n", name)
+		}
+
+		if !claudeSetNoCheck {
+			claudeCheckAPIKey(cmd.Context(), templateID, tmpl, provider)
 		}
 
 		// Reload if this is the active provider
 		shouldReload := config.Active == templateID
-		if !shouldReload {
-			// Also check if shell environment is using this provider's base URL
+		if !shouldReload && len(config.Profiles(tmpl.ID)) == 1 {
+			// Also check if shell environment is using this provider's base URL,
+			// unless another profile of it could be the one in use
 			envBaseURL := os.Getenv("ANTHROPIC_BASE_URL")
-			if envBaseURL == tmpl.BaseURL {
+			if envBaseURL == tmpl.BaseURL || (exists && envBaseURL == existingProvider.BaseURL(tmpl)) {
 				shouldReload = true
 			}
 		}
 
 		if shouldReload {
-			if err := claude.ReloadClaudeEnvironment(templateID, *provider); err != nil {
+			if err := claude.ReloadClaudeEnvironment(templateID); err != nil {
 				return fmt.Errorf("failed to reload Claude environment: %w", err)
 			}
 		} else if !exists {
@@ -91,6 +220,132 @@ Examples:
 	},
 }
 
+// claudeSetFromFlags applies the value flags to the existing provider settings
+func claudeSetFromFlags(cmd *cobra.Command, existing claude.Provider) (*claude.Provider, error) {
+	provider := existing
+	key, err := claudeSetReadAPIKey(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		provider.APIKey = key
+	}
+	if provider.APIKey == "" {
+		return nil, fmt.Errorf("an API key is required for a new provider (--api-key, --api-key-stdin, --api-key-file or $%s)", claudeAPIKeyEnv)
+	}
+
+	for _, model := range []struct {
+		flag  string
+		value string
+		field *string
+	}{
+		{"opus-model", claudeSetOpusModel, &provider.OpusModel},
+		{"sonnet-model", claudeSetSonnetModel, &provider.SonnetModel},
+		{"haiku-model", claudeSetHaikuModel, &provider.HaikuModel},
+		{"subagent-model", claudeSetSubagentModel, &provider.SubagentModel},
+	} {
+		if !cmd.Flags().Changed(model.flag) {
+			continue
+		}
+		*model.field = strings.TrimSpace(model.value)
+		if *model.field == "default" {
+			*model.field = "" // Reset to template default
+		}
+	}
+	return &provider, nil
+}
+
+// claudeSetReadAPIKey returns the API key given by flag or $ZZK_CLAUDE_API_KEY,
+// or "" if none is
+func claudeSetReadAPIKey(cmd *cobra.Command) (string, error) {
+	var key string
+	switch {
+	case claudeSetAPIKeyStdin || (cmd.Flags().Changed("api-key") && claudeSetAPIKey == "-"):
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, 64<<10))
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key from stdin: %w", err)
+		}
+		if key = strings.TrimSpace(string(data)); key == "" {
+			return "", fmt.Errorf("no API key on stdin")
+		}
+	case cmd.Flags().Changed("api-key-file"):
+		path := claudeSetAPIKeyFile
+		if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0077 != 0 {
+			fmt.Fprintf(os.Stderr, "⚠ %s is readable by other users (chmod 600 it)\n", path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key file: %w", err)
+		}
+		if key = strings.TrimSpace(string(data)); key == "" {
+			return "", fmt.Errorf("%s is empty", path)
+		}
+	case cmd.Flags().Changed("api-key"):
+		key = strings.TrimSpace(claudeSetAPIKey)
+	case os.Getenv(claudeAPIKeyEnv) != "":
+		key = strings.TrimSpace(os.Getenv(claudeAPIKeyEnv))
+		fmt.Printf("ℹ Using the API key from $%s\n", claudeAPIKeyEnv)
+	}
+	// A key spanning lines would be caught by Validate, but say where it came from
+	if strings.ContainsAny(key, "\n\r") {
+		return "", fmt.Errorf("the API key must be a single line")
+	}
+	return key, nil
+}
+
 func init() {
+	claudeSetCmd.Flags().StringVar(&claudeSetAPIKey, "api-key", "", "API key, or - to read it from stdin (no prompts)")
+	claudeSetCmd.Flags().BoolVar(&claudeSetAPIKeyStdin, "api-key-stdin", false, "Read the API key from stdin (no prompts)")
+	claudeSetCmd.Flags().StringVar(&claudeSetAPIKeyFile, "api-key-file", "", "Read the API key from a file (no prompts)")
+	claudeSetCmd.MarkFlagsMutuallyExclusive("api-key", "api-key-stdin", "api-key-file")
+	claudeSetCmd.Flags().StringVar(&claudeSetOpusModel, "opus-model", "", "Opus model override, 'default' to reset (no prompts)")
+	claudeSetCmd.Flags().StringVar(&claudeSetSonnetModel, "sonnet-model", "", "Sonnet model override, 'default' to reset (no prompts)")
+	claudeSetCmd.Flags().StringVar(&claudeSetHaikuModel, "haiku-model", "", "Haiku model override, 'default' to reset (no prompts)")
+	claudeSetCmd.Flags().StringVar(&claudeSetSubagentModel, "subagent-model", "", "Subagent model override, 'default' to reset (no prompts)")
+	claudeSetCmd.Flags().BoolVar(&claudeSetNoPicker, "no-picker", false, "Type model IDs instead of picking from the provider's list")
+	claudeSetCmd.Flags().BoolVar(&claudeSetNoCheck, "no-check", false, "Don't send a test request to check the API key")
+	claudeSetCmd.Flags().StringVar(&claudeSetBaseURL, "base-url", "", "Send requests to this URL instead of the provider's ('default' to reset)")
+	claudeSetCmd.Flags().StringVar(&claudeSetHTTPSProxy, "https-proxy", "", "Proxy URL exported as HTTPS_PROXY for this provider ('none' to remove)")
+	claudeSetCmd.Flags().StringVar(&claudeSetNoProxy, "no-proxy", "", "Comma-separated hosts exported as NO_PROXY ('none' to remove)")
 	claudeCmd.AddCommand(claudeSetCmd)
 }
+
+// claudeCheckAPIKey warns if the provider rejects the API key
+func claudeCheckAPIKey(ctx context.Context, templateID string, tmpl *claude.ProviderTemplate, provider *claude.Provider) {
+	err := claude.CheckAPIKey(ctx, provider.HTTPClient(), provider.BaseURL(tmpl), provider.APIKey)
+	switch {
+	case err == nil:
+		fmt.Printf("✓ %s accepted the API key\n", tmpl.Name)
+	case errors.Is(err, claude.ErrKeyRejected):
+		fmt.Printf("⚠ %s rejected the API key: %v\n", tmpl.Name, strings.TrimPrefix(err.Error(), claude.ErrKeyRejected.Error()+": "))
+		fmt.Printf("  Claude Code will fail with it; run 'zzk claude set %s' again with the right key\n", templateID)
+	default:
+		fmt.Printf("⚠ Could not check the API key: %v\n", err)
+	}
+}
+
+// claudeConfirmBaseURL validates a --base-url value and has the user confirm
+// sending their API key there, returning the override to store ("" for the
+// template's URL)
+func claudeConfirmBaseURL(tmpl *claude.ProviderTemplate, baseURL string) (string, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "default" || baseURL == "" || baseURL == tmpl.BaseURL {
+		return "", nil
+	}
+	if err := claude.ValidateBaseURL(baseURL); err != nil {
+		return "", fmt.Errorf("invalid --base-url: %w", err)
+	}
+
+	fmt.Printf("⚠ %s requests will go to %s instead of %s.\n", tmpl.Name, baseURL, tmpl.BaseURL)
+	fmt.Println("  Your API key and every prompt, file and command output Claude Code sends")
+	fmt.Println("  go to that server. Only continue if you trust whoever runs it.")
+	ok, err := claude.PromptYesNo("Override the base URL?", false)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("cancelled")
+	}
+	fmt.Println()
+	return baseURL, nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claudeSettingsTimeoutMS int
+	claudeSettingsTelemetry string
+)
+
+var claudeSettingsCmd = &cobra.Command{
+	Use:   "settings [provider] [--timeout-ms <ms>] [--telemetry on|off|default]",
+	Short: "Configure the Claude Code timeout and telemetry",
+	Long: `Show or change the Claude Code settings written to the env file besides the
+provider's URL, key and models:
+
+  --timeout-ms   API_TIMEOUT_MS, between 10000 (10s) and 86400000 (24h);
+                 0 resets it (default 6000000, 100 minutes)
+  --telemetry    'off' sets CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC=1,
+                 'on' unsets it, 'default' resets it (default off)
+
+Without a provider the settings apply to every provider and the official API.
+With one they apply to that provider only, or with 'anthropic' to the
+official API, and take precedence over the global ones. Without flags the
+settings and the values they result in are shown.
+
+The env file is rewritten when the active provider is affected.
+
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+
+Examples:
+  zzk claude settings                            # Show the global settings
+  zzk claude settings zai --timeout-ms 300000    # Shorter timeout for one gateway
+  zzk claude settings anthropic --telemetry on   # Telemetry on for the official API
+  zzk claude settings --timeout-ms 0             # Back to the default timeout`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Resolve the scope: "" for global, the official API or a provider
+		id := ""
+		if len(args) == 1 {
+			id = claude.OfficialProvider
+			if args[0] != claude.OfficialProvider {
+				if id, err = claude.ResolveProviderID(args[0]); err != nil {
+					return err
+				}
+				if !config.HasProvider(id) {
+					return fmt.Errorf("provider '%s' not configured. Use 'zzk claude set %s' to configure it", id, id)
+				}
+			}
+		}
+
+		var settings claude.EnvSettings
+		switch id {
+		case "":
+			settings = config.EnvSettings
+		case claude.OfficialProvider:
+			settings = config.Official
+		default:
+			provider, _ := config.GetProvider(id)
+			settings = provider.EnvSettings
+		}
+
+		changed := cmd.Flags().Changed("timeout-ms") || cmd.Flags().Changed("telemetry")
+		if !changed {
+			printClaudeSettings(config, id, settings)
+			return nil
+		}
+
+		if cmd.Flags().Changed("timeout-ms") {
+			settings.TimeoutMS = claudeSettingsTimeoutMS
+		}
+		if cmd.Flags().Changed("telemetry") {
+			switch claudeSettingsTelemetry {
+			case "on", "off":
+				disable := claudeSettingsTelemetry == "off"
+				settings.DisableTraffic = &disable
+			case "default":
+				settings.DisableTraffic = nil
+			default:
+				return fmt.Errorf("invalid --telemetry %q (valid: on, off, default)", claudeSettingsTelemetry)
+			}
+		}
+		if err := settings.Validate(); err != nil {
+			return err
+		}
+
+		switch id {
+		case "":
+			config.EnvSettings = settings
+		case claude.OfficialProvider:
+			config.Official = settings
+		default:
+			provider, _ := config.GetProvider(id)
+			provider.EnvSettings = settings
+			if err := config.AddProvider(id, provider); err != nil {
+				return err
+			}
+		}
+		if err := claude.SaveConfig(config); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Updated the settings for %s\n", claudeSettingsScope(id))
+
+		active := config.Active
+		if active == "" {
+			active = claude.OfficialProvider
+		}
+		if id == "" || id == active {
+			if err := claude.WriteEnvFile(config, config.Active); err != nil {
+				return fmt.Errorf("failed to write env file: %w", err)
+			}
+			fmt.Println(claude.GetReloadInstructions())
+		}
+		return nil
+	},
+}
+
+// claudeSettingsScope describes who the settings of scope id apply to
+func claudeSettingsScope(id string) string {
+	switch id {
+	case "":
+		return "all providers"
+	case claude.OfficialProvider:
+		return "the official Anthropic API"
+	}
+	return claude.ProviderName(id)
+}
+
+// printClaudeSettings shows the settings of scope id and, for a provider or
+// the official API, the values Claude Code gets
+func printClaudeSettings(config *claude.Config, id string, settings claude.EnvSettings) {
+	timeout, telemetry := "default", "default"
+	if settings.TimeoutMS != 0 {
+		timeout = strconv.Itoa(settings.TimeoutMS)
+	}
+	if settings.DisableTraffic != nil {
+		telemetry = map[bool]string{true: "off", false: "on"}[*settings.DisableTraffic]
+	}
+	fmt.Printf("Settings for %s:\n", claudeSettingsScope(id))
+	fmt.Printf("  Timeout (ms): %s\n", timeout)
+	fmt.Printf("  Telemetry:    %s\n", telemetry)
+
+	if id == "" {
+		return
+	}
+	vars, err := config.Env(id)
+	if err != nil {
+		return
+	}
+	fmt.Println("\nResulting environment:")
+	for _, v := range vars {
+		if v.Key != "API_TIMEOUT_MS" && v.Key != "CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC" {
+			continue
+		}
+		if v.Value == "" {
+			fmt.Printf("  %s (unset)\n", v.Key)
+		} else {
+			fmt.Printf("  %s=%s\n", v.Key, v.Value)
+		}
+	}
+}
+
+func init() {
+	claudeSettingsCmd.Flags().IntVar(&claudeSettingsTimeoutMS, "timeout-ms", 0, "API timeout in milliseconds (0 for the default)")
+	claudeSettingsCmd.Flags().StringVar(&claudeSettingsTelemetry, "telemetry", "", "Claude Code telemetry: on, off or default")
+	claudeCmd.AddCommand(claudeSettingsCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var claudeSetupRemove bool
+
+var claudeSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Source the provider env file from your shell RC file",
+	Long: `Add the line sourcing ~/.config/zzk/claude-env.sh to the RC file of your
+shell ($SHELL: ~/.zshrc, ~/.bashrc or ~/.bash_profile, fish's config.fish,
+nushell's config.nu or the PowerShell $PROFILE; nushell and PowerShell source
+claude-env.nu and claude-env.ps1 instead),
+inside a "# BEGIN zzk claude" block. Running it again changes nothing;
+--remove takes the block out again.
+
+An RC file that already sources the env file outside the block is left alone.
+
+Examples:
+  zzk claude setup            # Add the block
+  zzk claude setup --remove   # Remove it`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Parallel shell startups may run this at once
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		shell := claude.DetectShell()
+
+		if claudeSetupRemove {
+			rcFile, removed, err := claude.RemoveRCFile(shell)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				fmt.Printf("ℹ %s has no zzk claude block\n", rcFile)
+				return nil
+			}
+			fmt.Printf("✓ Removed the zzk claude block from %s\n", rcFile)
+			fmt.Println("  Open a new shell to drop the provider variables")
+			return nil
+		}
+
+		rcFile, changed, err := claude.SetupRCFile(shell)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Printf("ℹ %s already sources %s\n", rcFile, claude.EnvFilePath())
+			return nil
+		}
+		fmt.Printf("✓ %s now sources %s\n", rcFile, claude.EnvFilePath())
+
+		// The env file of a newly set up shell may not exist yet
+		if config, err := claude.LoadConfig(); err == nil {
+			if err := claude.WriteEnvFile(config, config.Active); err != nil {
+				return fmt.Errorf("failed to write env file: %w", err)
+			}
+		}
+		fmt.Printf("  Reload your shell: %s\n", claude.ReloadCommand())
+		return nil
+	},
+}
+
+func init() {
+	claudeSetupCmd.Flags().BoolVar(&claudeSetupRemove, "remove", false, "Remove the block from the RC file")
+	claudeCmd.AddCommand(claudeSetupCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var claudeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check that the shell uses the active Claude API provider",
+	Long: `Compare the active provider in ~/.claude-providers.json, the env file your
+shell sources and the ANTHROPIC_* variables of the current shell, and report
+where they disagree, with the command that fixes each problem.
+
+Typical drift: switching providers rewrites the env file, but shells opened
+before keep exporting the old provider until they are reloaded.
+
+API keys are compared, never printed. Exits with status 1 if anything drifted.
+
+Example:
+  zzk claude status`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		status, err := claude.GetStatus(config)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Config:    %s\n", status.Active)
+		if status.File != nil {
+			fmt.Printf("Env file:  %s  %s\n", claudeDescribeEnv(*status.File), status.FilePath)
+		} else {
+			fmt.Printf("Env file:  missing  %s\n", status.FilePath)
+		}
+		fmt.Printf("Shell:     %s\n", claudeDescribeEnv(status.Shell))
+		if cwd, err := os.Getwd(); err == nil {
+			if id, source, err := claude.ProjectProvider(cwd); err == nil && id != "" {
+				fmt.Printf("Project:   %s  %s (used by 'zzk claude run')\n", id, source)
+			}
+		}
+
+		if len(status.Drift) == 0 {
+			fmt.Println("\n✓ Shell, env file and config agree")
+			return nil
+		}
+		fmt.Println()
+		for _, drift := range status.Drift {
+			fmt.Printf("⚠ %s\n", drift.Message)
+			fmt.Printf("  Fix: %s\n", drift.Fix)
+		}
+		os.Exit(1)
+		return nil
+	},
+}
+
+func init() {
+	claudeCmd.AddCommand(claudeStatusCmd)
+}
+
+// claudeDescribeEnv names the provider of an env state, noting settings
+// that no longer match it
+func claudeDescribeEnv(state claude.EnvState) string {
+	switch {
+	case state.Provider == "":
+		return "unknown provider (" + state.Vars["ANTHROPIC_BASE_URL"] + ")"
+	case !state.Current:
+		return state.Provider + " (outdated settings)"
+	default:
+		return state.Provider
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var claudeTemplatesURL string
+
+var claudeTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the Claude API provider templates",
+	Long: `List the provider templates zzk knows, and where each comes from:
+
+  built-in   compiled into zzk
+  registry   the template registry, downloaded by 'zzk claude templates update'
+  custom     ~/.config/zzk/claude-templates.json
+
+Registry templates replace built-in ones with the same ID, and custom ones
+replace both.
+
+Examples:
+  zzk claude templates
+  zzk claude templates update`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := claude.LoadTemplates(); err != nil {
+			fmt.Printf("⚠ %v\n\n", err)
+		}
+
+		fmt.Printf("%-14s %-20s %-9s %s\n", "ID", "NAME", "SOURCE", "BASE URL")
+		for _, tmpl := range claude.ListTemplates() {
+			source := "built-in"
+			switch {
+			case tmpl.Custom:
+				source = "custom"
+			case tmpl.FromRegistry:
+				source = "registry"
+			}
+			fmt.Printf("%-14s %-20s %-9s %s\n", tmpl.ID, tmpl.Name, source, tmpl.BaseURL)
+		}
+
+		registry, err := claude.CachedRegistry()
+		switch {
+		case err != nil:
+		case registry == nil:
+			fmt.Println("\nRegistry: not downloaded - run 'zzk claude templates update'")
+		default:
+			fmt.Printf("\nRegistry: published %s (%s)\n", registry.Updated.Format(time.DateOnly), claude.RegistryPath())
+		}
+		return nil
+	},
+}
+
+var claudeTemplatesUpdateCmd = &cobra.Command{
+	Use:   "update [--url <url>]",
+	Short: "Download the latest provider templates",
+	Long: `Download the template registry published with zzk releases and cache it in
+~/.config/zzk/claude-registry.json, so new providers and changed base URLs
+are picked up without upgrading zzk.
+
+The registry is a JSON file like ~/.config/zzk/claude-templates.json with an
+"updated" time, signed with Ed25519; the base64 signature is downloaded from
+<url>.sig and cached next to it, and checked again whenever the cache is
+read. Registries whose signature doesn't match the key built into zzk, or
+that are older than the cached one, are refused.
+
+--url fetches the registry from a mirror; it must still be signed with the
+release key.
+
+If the active provider's base URL changed, the env file is rewritten.
+
+Examples:
+  zzk claude templates update
+  zzk claude templates update --url https://mirror.example/zzk/claude-templates.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		// Update before the templates are loaded, so the config sees the new ones
+		old, updated, err := claude.UpdateRegistry(cmd.Context(), claudeTemplatesURL)
+		if err != nil {
+			return fmt.Errorf("failed to update templates: %w", err)
+		}
+		changes := claude.RegistryChanges(old, updated)
+		if len(changes) == 0 {
+			fmt.Printf("✓ Templates are up to date (registry published %s)\n", updated.Updated.Format(time.DateOnly))
+			return nil
+		}
+		fmt.Printf("✓ Updated the provider templates (registry published %s)\n", updated.Updated.Format(time.DateOnly))
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		var rewrite bool
+		for _, change := range changes {
+			switch {
+			case change.Old == nil:
+				fmt.Printf("  + %-14s %s (%s)\n", change.ID, change.New.Name, change.New.BaseURL)
+			case change.New == nil:
+				fmt.Printf("  - %-14s no longer in the registry\n", change.ID)
+			default:
+				fmt.Printf("  ~ %-14s %s changed\n", change.ID, strings.Join(change.Details, ", "))
+				if change.Old.BaseURL != change.New.BaseURL {
+					fmt.Printf("    %s -> %s\n", change.Old.BaseURL, change.New.BaseURL)
+				}
+			}
+
+			tmpl, ok := claude.GetTemplate(change.ID)
+			if ok && tmpl.Custom {
+				fmt.Printf("    ℹ Your %s replaces it\n", claude.TemplatesPath())
+				continue
+			}
+			for _, id := range config.Profiles(change.ID) {
+				provider, _ := config.GetProvider(id)
+				switch {
+				case change.New == nil:
+					fmt.Printf("    ⚠ %s is configured; remove it with 'zzk claude rm %s'\n", id, id)
+				case change.Old != nil && change.Old.BaseURL != change.New.BaseURL && provider.BaseURLOverride == "":
+					fmt.Printf("    ⚠ %s now sends your API key to the new URL\n", id)
+					rewrite = rewrite || id == config.Active
+				}
+			}
+		}
+
+		if rewrite {
+			if err := claude.WriteEnvFile(config, config.Active); err != nil {
+				return fmt.Errorf("failed to write env file: %w", err)
+			}
+			fmt.Println(claude.GetReloadInstructions())
+		}
+		return nil
+	},
+}
+
+func init() {
+	claudeTemplatesUpdateCmd.Flags().StringVar(&claudeTemplatesURL, "url", claude.RegistryURL, "Registry to download")
+	claudeTemplatesCmd.AddCommand(claudeTemplatesUpdateCmd)
+	claudeCmd.AddCommand(claudeTemplatesCmd)
+}
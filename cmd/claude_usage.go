@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/spf13/cobra"
+)
+
+var claudeUsageCmd = &cobra.Command{
+	Use:   "usage [provider...]",
+	Short: "Show the balance and recent spend of configured providers",
+	Long: `Query the usage endpoints of the configured providers, or only those given,
+with their API keys and show the remaining balance and recent spend:
+
+  openrouter  Account credits and the key's spend today, this week and month
+  synthetic   Requests left in the subscription period
+  zai         GLM Coding Plan token quota and tool calls
+
+Custom providers have no known usage endpoint and are listed as such.
+
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+
+Examples:
+  zzk claude usage              # All configured providers
+  zzk claude usage openrouter   # Only OpenRouter`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		ids := slices.Sorted(maps.Keys(config.Providers))
+		if len(args) > 0 {
+			ids = ids[:0]
+			for _, arg := range args {
+				id, err := claude.ResolveProviderID(arg)
+				if err != nil {
+					return err
+				}
+				if !config.HasProvider(id) {
+					return fmt.Errorf("provider '%s' not configured", id)
+				}
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			fmt.Println("No providers configured; add one with 'zzk claude set <provider>'")
+			return nil
+		}
+
+		type result struct {
+			usage *claude.Usage
+			err   error
+		}
+		results := make([]result, len(ids))
+		var wg sync.WaitGroup
+		for i, id := range ids {
+			tmpl, _ := claude.TemplateFor(id)
+			if !claude.SupportsUsage(tmpl) {
+				continue
+			}
+			provider, err := config.ResolveKey(id)
+			if err != nil {
+				results[i] = result{nil, err}
+				continue
+			}
+			wg.Go(func() {
+				usage, err := claude.GetUsage(cmd.Context(), tmpl, provider)
+				results[i] = result{usage, err}
+			})
+		}
+		wg.Wait()
+
+		fmt.Printf("%-20s %-28s %s\n", "PROVIDER", "BALANCE", "RECENT SPEND")
+		for i, id := range ids {
+			tmpl, _ := claude.TemplateFor(id)
+			r := results[i]
+			switch {
+			case !claude.SupportsUsage(tmpl):
+				fmt.Printf("%-20s %s\n", id, "- (no usage endpoint)")
+			case r.err != nil:
+				fmt.Printf("%-20s ✗ %v\n", id, r.err)
+			default:
+				fmt.Printf("%-20s %-28s %s\n", id, r.usage.Balance, r.usage.Spend)
+				if r.usage.Note != "" {
+					fmt.Printf("%-20s %s\n", "", r.usage.Note)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	claudeCmd.AddCommand(claudeUsageCmd)
+}
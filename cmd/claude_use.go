@@ -17,20 +17,29 @@ This command will:
 2. Mark the provider as active
 3. Show instructions if shell setup is needed
 
-Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic').
+Provider IDs support prefix matching (e.g., 'syn' matches 'synthetic'),
+and a profile name after a colon selects one of several configurations of the
+same provider (see 'zzk claude set').
 
 Examples:
   zzk claude use synthetic    # Switch to Synthetic provider
   zzk claude use syn          # Same (prefix matching)
+  zzk claude use syn:work     # Switch to the "work" profile of Synthetic
   zzk claude use openrouter   # Switch to OpenRouter provider`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Resolve prefix to full template ID
-		templateID, err := claude.ResolveTemplateID(args[0])
+		// Resolve prefix to full provider ID
+		templateID, err := claude.ResolveProviderID(args[0])
 		if err != nil {
 			return err
 		}
 
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
 		// Load config
 		config, err := claude.LoadConfig()
 		if err != nil {
@@ -38,13 +47,13 @@ Examples:
 		}
 
 		// Check if provider is configured
-		provider, exists := config.GetProvider(templateID)
+		_, exists := config.GetProvider(templateID)
 		if !exists {
 			return fmt.Errorf("provider '%s' not configured. Use 'zzk claude set %s' to configure it",
 				templateID, templateID)
 		}
 
-		if err := claude.ReloadClaudeEnvironment(templateID, provider); err != nil {
+		if err := claude.ReloadClaudeEnvironment(templateID); err != nil {
 			return fmt.Errorf("failed to reload Claude environment: %w", err)
 		}
 
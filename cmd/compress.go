@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/archive"
+	"github.com/ppowo/zzk/internal/crypt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compressFormat     string
+	compressOutput     string
+	compressEncrypt    bool
+	compressRecipients []string
+	compressNoExclude  bool
+)
+
+var compressCmd = &cobra.Command{
+	Use:   "compress <path...>",
+	Short: "Create tar.zst, tar.xz, tar.gz or zip archives",
+	Long: `Create an archive of one or more files or directories.
+
+The same exclude globs used by 'zzk backup' (.DS_Store, node_modules, caches,
+...) are skipped unless --no-exclude is given. The total input size is shown
+before compressing, and the compression ratio afterwards.
+
+With --encrypt (passphrase) or --recipient, the archive is encrypted with age
+and '.age' is appended to the output name. Decrypt it with 'zzk crypt decrypt'.
+
+The format is taken from --format, or from the --output extension.
+
+Examples:
+  zzk compress project/                   # → project.tar.zst
+  zzk compress -f zip photos/             # → photos.zip
+  zzk compress -o notes.tar.xz a.md b.md  # Format from the output name
+  zzk compress -e documents/              # → documents.tar.zst.age (passphrase)
+  zzk compress -r age1xyz... secrets/     # Encrypt to a recipient`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := compressResolveFormat()
+		if err != nil {
+			return err
+		}
+
+		baseDir, paths, err := compressSplitPaths(args)
+		if err != nil {
+			return err
+		}
+
+		var exclude []string
+		if !compressNoExclude {
+			exclude = globalExcludeGlobs
+		}
+
+		encrypt := compressEncrypt || len(compressRecipients) > 0
+		output := compressOutput
+		if output == "" {
+			name := "archive"
+			if len(paths) == 1 {
+				name = paths[0]
+			}
+			output = name + archive.Extension(format)
+			if encrypt {
+				output += ".age"
+			}
+		} else if encrypt && !strings.HasSuffix(output, ".age") {
+			output += ".age"
+		}
+
+		// The output may be written inside a directory being archived
+		exclude = append(slices.Clip(exclude), outputExcludes(output)...)
+
+		var opts crypt.Options
+		if encrypt {
+			opts.Recipients, err = crypt.ParseRecipients(compressRecipients)
+			if err != nil {
+				return err
+			}
+			if len(opts.Recipients) == 0 {
+				opts.Passphrase, err = cryptReadPassphrase(true)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		files, size, err := archive.Estimate(baseDir, paths, exclude)
+		if err != nil {
+			return fmt.Errorf("failed to scan input: %w", err)
+		}
+		fmt.Printf("ℹ Archiving %d files (%s) as %s...\n", files, humanize.IBytes(uint64(size)), format)
+
+		err = writeOutput(output, func(w io.Writer) error {
+			if !encrypt {
+				return archive.Write(w, format, baseDir, paths, exclude)
+			}
+
+			enc, err := crypt.NewEncryptor(w, opts)
+			if err != nil {
+				return err
+			}
+			if err := archive.Write(enc, format, baseDir, paths, exclude); err != nil {
+				return err
+			}
+			return enc.Close()
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create archive: %w", err)
+		}
+
+		// writeOutput restricts permissions for encrypted output; plain archives get normal ones
+		if !encrypt {
+			if err := os.Chmod(output, 0644); err != nil {
+				return err
+			}
+		}
+
+		info, err := os.Stat(output)
+		if err != nil {
+			return err
+		}
+		ratio := ""
+		if size > 0 {
+			ratio = fmt.Sprintf(", %.0f%% of original", float64(info.Size())*100/float64(size))
+		}
+		fmt.Printf("✓ Created %s (%s%s)\n", output, humanize.IBytes(uint64(info.Size())), ratio)
+		return nil
+	},
+}
+
+// compressResolveFormat picks the archive format from --format or the output name
+func compressResolveFormat() (archive.Format, error) {
+	if compressFormat == "" {
+		if compressOutput != "" {
+			if format, ok := archive.FormatFromName(strings.TrimSuffix(compressOutput, ".age")); ok {
+				return format, nil
+			}
+		}
+		return archive.FormatTarZst, nil
+	}
+
+	format, ok := archive.FormatFromName("." + strings.TrimPrefix(compressFormat, "."))
+	if !ok {
+		return "", fmt.Errorf("unknown format %q (use tar.zst, tar.xz, tar.gz, tar or zip)", compressFormat)
+	}
+	switch format {
+	case archive.FormatTarZst, archive.FormatTarXz, archive.FormatTarGz, archive.FormatTar, archive.FormatZip:
+		return format, nil
+	default:
+		return "", fmt.Errorf("cannot create %s archives", format)
+	}
+}
+
+// compressSplitPaths returns the common parent directory of args and each
+// arg relative to it, so archive entries keep their directory names
+func compressSplitPaths(args []string) (string, []string, error) {
+	var abs []string
+	for _, arg := range args {
+		path, err := filepath.Abs(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := os.Lstat(path); err != nil {
+			return "", nil, fmt.Errorf("cannot read %s: %w", arg, err)
+		}
+		abs = append(abs, path)
+	}
+
+	baseDir := filepath.Dir(abs[0])
+	for _, path := range abs[1:] {
+		for !strings.HasPrefix(path, baseDir+string(filepath.Separator)) && filepath.Dir(baseDir) != baseDir {
+			baseDir = filepath.Dir(baseDir)
+		}
+	}
+
+	var paths []string
+	for _, path := range abs {
+		rel, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return "", nil, err
+		}
+		paths = append(paths, rel)
+	}
+	return baseDir, paths, nil
+}
+
+func init() {
+	compressCmd.Flags().StringVarP(&compressFormat, "format", "f", "", "Archive format: tar.zst, tar.xz, tar.gz, tar, zip (default tar.zst)")
+	compressCmd.Flags().StringVarP(&compressOutput, "output", "o", "", "Output file (default: <name>.<format>)")
+	compressCmd.Flags().BoolVarP(&compressEncrypt, "encrypt", "e", false, "Encrypt with a passphrase")
+	compressCmd.Flags().StringArrayVarP(&compressRecipients, "recipient", "r", nil, "Encrypt to an age or SSH public key, or recipients file (repeatable)")
+	compressCmd.Flags().BoolVar(&compressNoExclude, "no-exclude", false, "Don't skip the global exclude globs")
+	rootCmd.AddCommand(compressCmd)
+}
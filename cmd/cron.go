@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+var cronLogLines int
+
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Schedule recurring zzk commands",
+	Long: `Register recurring zzk commands and install them into the platform
+scheduler: launchd on macOS, systemd user timers on Linux (or crontab when
+systemd is not available).
+
+Schedules:
+  hourly            Every hour on the hour
+  daily[@HH:MM]     Every day (default 03:00)
+  weekly[@HH:MM]    Every Sunday (default 03:00)
+  every <duration>  Fixed interval, e.g. "every 30m", "every 6h"
+
+Jobs are stored in ~/.config/zzk/cron.json and their output is appended to a
+log file shown by 'zzk cron logs'.
+
+Examples:
+  zzk cron add backup-bio daily@02:30 -- backup bio
+  zzk cron add wallpaper daily -- wallpaper --random ~/Pictures/Wallpapers
+  zzk cron add sync "every 1h" -- git sync
+  zzk cron ls
+  zzk cron disable wallpaper
+  zzk cron logs backup-bio
+  zzk cron run backup-bio          # Run now in the foreground`,
+}
+
+var cronAddCmd = &cobra.Command{
+	Use:   "add <name> <schedule> -- <zzk args...>",
+	Short: "Register and enable a job",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() != 2 || len(args) < 3 {
+			return fmt.Errorf("usage: zzk cron add <name> <schedule> -- <zzk args...>")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, when, zzkArgs := args[0], args[1], args[2:]
+
+		if err := schedule.ValidateName(name); err != nil {
+			return err
+		}
+		if _, err := schedule.ParseSpec(when); err != nil {
+			return err
+		}
+		if zzkArgs[0] == "zzk" {
+			zzkArgs = zzkArgs[1:]
+		}
+
+		backend, err := schedule.DetectBackend()
+		if err != nil {
+			return err
+		}
+		config, err := schedule.Load()
+		if err != nil {
+			return err
+		}
+
+		job := &schedule.Job{Name: name, Schedule: when, Args: zzkArgs, Enabled: true}
+		if err := schedule.Enable(backend, job); err != nil {
+			return fmt.Errorf("failed to install job: %w", err)
+		}
+
+		_, replaced := config.Jobs[name]
+		config.Jobs[name] = job
+		if err := config.Save(); err != nil {
+			return err
+		}
+
+		verb := "Added"
+		if replaced {
+			verb = "Updated"
+		}
+		fmt.Printf("✓ %s job '%s' (%s) via %s: zzk %s\n", verb, name, when, backend.Name(), strings.Join(zzkArgs, " "))
+		return nil
+	},
+}
+
+var cronLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List scheduled jobs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := schedule.Load()
+		if err != nil {
+			return err
+		}
+		if len(config.Jobs) == 0 {
+			fmt.Println("No scheduled jobs")
+			return nil
+		}
+
+		for _, job := range config.Sorted() {
+			status := "✓"
+			if !job.Enabled {
+				status = "✗"
+			}
+			lastRun := "never"
+			if logPath, err := schedule.LogPath(job.Name); err == nil {
+				if info, err := os.Stat(logPath); err == nil {
+					lastRun = humanize.Time(info.ModTime())
+				}
+			}
+			fmt.Printf("%s %-20s %-16s last output %-14s zzk %s\n", status, job.Name, job.Schedule, lastRun, strings.Join(job.Args, " "))
+		}
+		return nil
+	},
+}
+
+var cronEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable a disabled job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cronSetEnabled(args[0], true)
+	},
+}
+
+var cronDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Disable a job without removing it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cronSetEnabled(args[0], false)
+	},
+}
+
+var cronRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, job, err := cronLoadJob(args[0])
+		if err != nil {
+			return err
+		}
+		backend, err := schedule.DetectBackend()
+		if err != nil {
+			return err
+		}
+		if err := backend.Uninstall(job.Name); err != nil {
+			return fmt.Errorf("failed to uninstall job: %w", err)
+		}
+
+		delete(config.Jobs, job.Name)
+		if err := config.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed job '%s'\n", job.Name)
+		return nil
+	},
+}
+
+var cronLogsCmd = &cobra.Command{
+	Use:   "logs <name>",
+	Short: "Show a job's recent output",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, _, err := cronLoadJob(args[0]); err != nil {
+			return err
+		}
+		logPath, err := schedule.LogPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(logPath)
+		if os.IsNotExist(err) {
+			fmt.Println("No output yet")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// Keep only the last N lines
+		var lines []string
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+			if len(lines) > cronLogLines {
+				lines = lines[1:]
+			}
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		fmt.Fprintf(os.Stderr, "\nℹ Log file: %s\n", logPath)
+		return scanner.Err()
+	},
+}
+
+var cronRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a job now in the foreground",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, job, err := cronLoadJob(args[0])
+		if err != nil {
+			return err
+		}
+		command, err := schedule.Command(job)
+		if err != nil {
+			return err
+		}
+
+		c := exec.Command(command[0], command[1:]...)
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				os.Exit(exitErr.ExitCode())
+			}
+			return err
+		}
+		return nil
+	},
+}
+
+func cronLoadJob(name string) (*schedule.Config, *schedule.Job, error) {
+	config, err := schedule.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	job, ok := config.Jobs[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("job '%s' not found", name)
+	}
+	return config, job, nil
+}
+
+func cronSetEnabled(name string, enabled bool) error {
+	config, job, err := cronLoadJob(name)
+	if err != nil {
+		return err
+	}
+	backend, err := schedule.DetectBackend()
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		err = schedule.Enable(backend, job)
+	} else {
+		err = backend.Uninstall(job.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	job.Enabled = enabled
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	if enabled {
+		fmt.Printf("✓ Enabled job '%s'\n", name)
+	} else {
+		fmt.Printf("✓ Disabled job '%s'\n", name)
+	}
+	return nil
+}
+
+func init() {
+	cronLogsCmd.Flags().IntVarP(&cronLogLines, "lines", "n", 50, "Number of lines to show")
+	cronCmd.AddCommand(cronAddCmd)
+	cronCmd.AddCommand(cronLsCmd)
+	cronCmd.AddCommand(cronEnableCmd)
+	cronCmd.AddCommand(cronDisableCmd)
+	cronCmd.AddCommand(cronRmCmd)
+	cronCmd.AddCommand(cronLogsCmd)
+	cronCmd.AddCommand(cronRunCmd)
+	rootCmd.AddCommand(cronCmd)
+}
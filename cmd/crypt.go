@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/ppowo/zzk/internal/archive"
+	"github.com/ppowo/zzk/internal/crypt"
+	"github.com/ppowo/zzk/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cryptOutput     string
+	cryptRecipients []string
+	cryptIdentities []string
+	cryptArmor      bool
+)
+
+var cryptCmd = &cobra.Command{
+	Use:   "crypt",
+	Short: "Encrypt and decrypt files with age",
+	Long: `Encrypt and decrypt files and directories using age.
+
+Without recipients, a passphrase is used: $ZZK_CRYPT_PASSPHRASE, else the
+vault secret crypt/passphrase, else prompted without echo. Recipients may be age public keys (age1...),
+SSH public keys, or files containing one recipient per line.
+
+Directories are archived as tar.gz before encryption and extracted on
+decryption. Data is streamed, so large files are fine.
+
+Examples:
+  zzk crypt encrypt secrets.txt                   # Passphrase → secrets.txt.age
+  zzk crypt encrypt -r age1xyz... photos/         # Directory → photos.tar.gz.age
+  zzk crypt encrypt -r ~/.ssh/id_ed25519.pub -a notes.md   # ASCII-armored
+  zzk crypt decrypt secrets.txt.age               # → secrets.txt
+  zzk crypt decrypt -i ~/.ssh/id_ed25519 photos.tar.gz.age # Extracts photos/`,
+}
+
+var cryptEncryptCmd = &cobra.Command{
+	Use:   "encrypt <path>",
+	Short: "Encrypt a file or directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src := args[0]
+
+		recipients, err := crypt.ParseRecipients(cryptRecipients)
+		if err != nil {
+			return err
+		}
+
+		opts := crypt.Options{Recipients: recipients, Armor: cryptArmor}
+		if len(recipients) == 0 {
+			opts.Passphrase, err = cryptReadPassphrase(true)
+			if err != nil {
+				return err
+			}
+		}
+
+		isDir := false
+		if src != "-" {
+			info, err := os.Stat(src)
+			if err != nil {
+				return fmt.Errorf("cannot read %s: %w", src, err)
+			}
+			isDir = info.IsDir()
+		}
+
+		output := cryptOutput
+		if output == "" {
+			if src == "-" {
+				output = "-"
+			} else if isDir {
+				output = filepath.Clean(src) + ".tar.gz.age"
+			} else {
+				output = src + ".age"
+			}
+		}
+
+		err = writeOutput(output, func(w io.Writer) error {
+			enc, err := crypt.NewEncryptor(w, opts)
+			if err != nil {
+				return err
+			}
+
+			if isDir {
+				clean := filepath.Clean(src)
+				if err := archive.Write(enc, archive.FormatTarGz, filepath.Dir(clean), []string{filepath.Base(clean)}, outputExcludes(output)); err != nil {
+					return fmt.Errorf("failed to archive %s: %w", src, err)
+				}
+			} else {
+				in, err := openInput(src)
+				if err != nil {
+					return err
+				}
+				defer in.Close()
+				if _, err := io.Copy(enc, in); err != nil {
+					return fmt.Errorf("failed to encrypt: %w", err)
+				}
+			}
+
+			return enc.Close()
+		})
+		if err != nil {
+			return err
+		}
+
+		if output != "-" {
+			fmt.Fprintf(os.Stderr, "✓ Encrypted %s → %s\n", src, output)
+		}
+		return nil
+	},
+}
+
+var cryptDecryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Decrypt a file (directories are extracted)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src := args[0]
+
+		identities, err := cryptIdentitiesOrPassphrase(cryptIdentities)
+		if err != nil {
+			return err
+		}
+
+		in, err := openInput(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		plain, err := crypt.NewDecryptor(in, identities...)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", src, err)
+		}
+
+		if strings.HasSuffix(src, ".tar.gz.age") {
+			dest := cryptOutput
+			if dest == "" {
+				dest = filepath.Dir(src)
+			}
+			gz, err := gzip.NewReader(plain)
+			if err != nil {
+				return fmt.Errorf("decrypted data is not a tar.gz archive: %w", err)
+			}
+			if err := archive.ExtractTar(gz, dest); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "✓ Decrypted and extracted %s → %s\n", src, dest)
+			return nil
+		}
+
+		output := cryptOutput
+		if output == "" {
+			if src == "-" || !strings.HasSuffix(src, ".age") {
+				output = "-"
+			} else {
+				output = strings.TrimSuffix(src, ".age")
+			}
+		}
+
+		err = writeOutput(output, func(w io.Writer) error {
+			if _, err := io.Copy(w, plain); err != nil {
+				return fmt.Errorf("failed to decrypt: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if output != "-" {
+			fmt.Fprintf(os.Stderr, "✓ Decrypted %s → %s\n", src, output)
+		}
+		return nil
+	},
+}
+
+// cryptIdentitiesOrPassphrase loads identity files, or prompts for a passphrase if none are given
+func cryptIdentitiesOrPassphrase(paths []string) ([]age.Identity, error) {
+	if len(paths) > 0 {
+		return crypt.LoadIdentities(paths)
+	}
+
+	passphrase, err := cryptReadPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	id, err := crypt.PassphraseIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return []age.Identity{id}, nil
+}
+
+// cryptPassphraseSecret is the vault secret used instead of prompting, so
+// scheduled encrypted backups can run unattended
+const cryptPassphraseSecret = "crypt/passphrase"
+
+// cryptReadPassphrase prefers $ZZK_CRYPT_PASSPHRASE, then the vault, then prompts
+func cryptReadPassphrase(confirm bool) (string, error) {
+	if os.Getenv(crypt.PassphraseEnvVar) == "" {
+		passphrase, err := vault.Get(cryptPassphraseSecret)
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "ℹ Using passphrase from the vault (%s)\n", cryptPassphraseSecret)
+			return passphrase, nil
+		}
+		if !errors.Is(err, vault.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: failed to read the vault: %v\n", err)
+		}
+	}
+	return crypt.ReadPassphrase(confirm)
+}
+
+// openInput opens a file, or stdin for "-"
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// writeOutput writes to stdout for "-", otherwise to a temp file that is
+// renamed into place only if write succeeds, so failures never leave partial output.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), outputTempPattern(path))
+	if err != nil {
+		return fmt.Errorf("failed to create output: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// outputTempPattern names the temp file writeOutput writes path through
+func outputTempPattern(path string) string {
+	return "." + filepath.Base(path) + ".tmp-*"
+}
+
+// globEscaper quotes the characters filepath.Match treats specially
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+// outputExcludes returns absolute exclude globs for the output writeOutput
+// writes to path and its temp file, so archiving a directory that holds
+// them doesn't archive them too
+func outputExcludes(path string) []string {
+	if path == "-" {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	quoted := globEscaper.Replace(abs)
+	return []string{quoted, filepath.Join(filepath.Dir(quoted), outputTempPattern(quoted))}
+}
+
+func init() {
+	cryptEncryptCmd.Flags().StringVarP(&cryptOutput, "output", "o", "", "Output file ('-' for stdout)")
+	cryptEncryptCmd.Flags().StringArrayVarP(&cryptRecipients, "recipient", "r", nil, "Recipient public key or recipients file (repeatable)")
+	cryptEncryptCmd.Flags().BoolVarP(&cryptArmor, "armor", "a", false, "ASCII-armored output")
+	cryptDecryptCmd.Flags().StringVarP(&cryptOutput, "output", "o", "", "Output file, or directory for archives ('-' for stdout)")
+	cryptDecryptCmd.Flags().StringArrayVarP(&cryptIdentities, "identity", "i", nil, "Identity file: age key or SSH private key (repeatable)")
+	cryptCmd.AddCommand(cryptEncryptCmd)
+	cryptCmd.AddCommand(cryptDecryptCmd)
+	rootCmd.AddCommand(cryptCmd)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/codec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	decodeEncode  bool
+	decodeURLSafe bool
+)
+
+var decodeCmd = &cobra.Command{
+	Use:   "decode",
+	Short: "Decode and encode base64, URL-encoding and JWTs",
+	Long: `Small decoding helpers. Input is taken from the argument, or stdin if
+none is given.
+
+Examples:
+  zzk decode base64 aGVsbG8=                # → hello
+  echo -n hello | zzk decode base64 -e      # → aGVsbG8=
+  zzk decode url 'a%20b%26c'                # → a b&c
+  zzk decode url -e 'a b&c'                 # → a+b%26c
+  zzk decode jwt eyJhbGciOi...              # Header, claims and expiry
+  pbpaste | zzk decode jwt`,
+}
+
+var decodeBase64Cmd = &cobra.Command{
+	Use:     "base64 [value]",
+	Aliases: []string{"b64"},
+	Short:   "Decode (or --encode) base64; standard and URL-safe are detected",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := decodeInput(args, !decodeEncode)
+		if err != nil {
+			return err
+		}
+
+		if decodeEncode {
+			encoding := base64.StdEncoding
+			if decodeURLSafe {
+				encoding = base64.URLEncoding
+			}
+			fmt.Println(encoding.EncodeToString([]byte(input)))
+			return nil
+		}
+
+		data, err := codec.DecodeBase64(input)
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(data)
+		return nil
+	},
+}
+
+var decodeURLCmd = &cobra.Command{
+	Use:   "url [value]",
+	Short: "Decode (or --encode) URL percent-encoding",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := decodeInput(args, true)
+		if err != nil {
+			return err
+		}
+
+		if decodeEncode {
+			fmt.Println(url.QueryEscape(input))
+			return nil
+		}
+
+		decoded, err := url.QueryUnescape(input)
+		if err != nil {
+			return fmt.Errorf("invalid URL encoding: %w", err)
+		}
+		fmt.Println(decoded)
+		return nil
+	},
+}
+
+var decodeJWTCmd = &cobra.Command{
+	Use:   "jwt [token]",
+	Short: "Show a JWT's header and claims with expiry status (signature not verified)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := decodeInput(args, true)
+		if err != nil {
+			return err
+		}
+
+		token, err := codec.ParseJWT(input)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Header:")
+		decodePrintJSON(token.Header)
+		fmt.Println()
+		fmt.Println("Claims:")
+		decodePrintJSON(token.Claims)
+
+		times := token.Times()
+		if len(times) == 0 {
+			return nil
+		}
+
+		fmt.Println()
+		now := time.Now()
+		if iat, ok := times["iat"]; ok {
+			fmt.Printf("ℹ Issued:     %s (%s)\n", iat.Format("2006-01-02 15:04:05"), humanize.Time(iat))
+		}
+		if nbf, ok := times["nbf"]; ok {
+			symbol := "✓"
+			if now.Before(nbf) {
+				symbol = "⚠"
+			}
+			fmt.Printf("%s Not before: %s (%s)\n", symbol, nbf.Format("2006-01-02 15:04:05"), humanize.Time(nbf))
+		}
+		if exp, ok := times["exp"]; ok {
+			switch {
+			case now.After(exp):
+				fmt.Printf("✗ Expired:    %s (%s)\n", exp.Format("2006-01-02 15:04:05"), humanize.Time(exp))
+			case exp.Sub(now) < 5*time.Minute:
+				fmt.Printf("⚠ Expires:    %s (%s)\n", exp.Format("2006-01-02 15:04:05"), humanize.Time(exp))
+			default:
+				fmt.Printf("✓ Expires:    %s (%s)\n", exp.Format("2006-01-02 15:04:05"), humanize.Time(exp))
+			}
+		}
+		return nil
+	},
+}
+
+// decodeInput returns the argument, or stdin if none was given. Surrounding
+// whitespace is trimmed when trim is set (encoding input is kept verbatim).
+func decodeInput(args []string, trim bool) (string, error) {
+	var input string
+	if len(args) > 0 {
+		input = args[0]
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		input = string(data)
+	}
+	if trim {
+		input = strings.TrimSpace(input)
+	}
+	return input, nil
+}
+
+func decodePrintJSON(data json.RawMessage) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "  ", "  "); err != nil {
+		fmt.Printf("  %s\n", data)
+		return
+	}
+	fmt.Printf("  %s\n", buf.String())
+}
+
+func init() {
+	decodeBase64Cmd.Flags().BoolVarP(&decodeEncode, "encode", "e", false, "Encode instead of decode")
+	decodeBase64Cmd.Flags().BoolVarP(&decodeURLSafe, "url-safe", "u", false, "Use the URL-safe alphabet when encoding")
+	decodeURLCmd.Flags().BoolVarP(&decodeEncode, "encode", "e", false, "Encode instead of decode")
+	decodeCmd.AddCommand(decodeBase64Cmd)
+	decodeCmd.AddCommand(decodeURLCmd)
+	decodeCmd.AddCommand(decodeJWTCmd)
+	rootCmd.AddCommand(decodeCmd)
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/dedupe"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	dedupeKeep      string
+	dedupeHardlink  bool
+	dedupeDelete    bool
+	dedupeMinSize   string
+	dedupeDryRun    bool
+	dedupeYes       bool
+	dedupeNoExclude bool
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe [path...]",
+	Short: "Find duplicate files and hardlink or delete them",
+	Long: `Find files with identical content under one or more directories.
+
+Files are grouped by size, then by a hash of their first 64 KiB, and only
+files that still collide are hashed in full. Existing hardlinks count as one
+file. The global backup exclude patterns are skipped unless --no-exclude.
+
+Without --hardlink or --delete the groups are only listed. With an action,
+you pick the copy to keep for each group, or pass --keep to decide by rule:
+  newest    most recently modified
+  oldest    least recently modified
+  shortest  shortest path
+  first     first path alphabetically
+
+Hardlinking keeps every path but stores the content once, which is the safe
+choice before a big backup. Files must be on the same filesystem.
+
+Examples:
+  zzk dedupe ~/Pictures                        # List duplicate groups
+  zzk dedupe -m 1M ~/Downloads ~/Documents     # Only files of 1 MB or more
+  zzk dedupe --hardlink ~/Music                # Choose interactively, hardlink the rest
+  zzk dedupe --delete --keep oldest -n ~/Pics  # Preview deleting all but the oldest
+  zzk dedupe --delete --keep shortest -y .     # Delete without asking`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dedupeHardlink && dedupeDelete {
+			return fmt.Errorf("--hardlink and --delete are mutually exclusive")
+		}
+		if dedupeKeep != "" && !dedupeHardlink && !dedupeDelete {
+			return fmt.Errorf("--keep requires --hardlink or --delete")
+		}
+		minSize, err := humanize.ParseBytes(dedupeMinSize)
+		if err != nil {
+			return fmt.Errorf("invalid --min-size: %w", err)
+		}
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		for _, root := range args {
+			if _, err := os.Stat(root); err != nil {
+				return fmt.Errorf("cannot read %s: %w", root, err)
+			}
+		}
+
+		exclude := globalExcludeGlobs
+		if dedupeNoExclude {
+			exclude = nil
+		}
+
+		fmt.Println("ℹ Scanning for duplicates...")
+		groups, err := dedupe.Find(args, exclude, int64(minSize))
+		if err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			fmt.Println("✓ No duplicates found")
+			return nil
+		}
+
+		var wasted int64
+		for _, g := range groups {
+			wasted += g.Wasted()
+		}
+
+		action := dedupeAction()
+		if action == "" {
+			for _, g := range groups {
+				dedupePrintGroup(g, -1)
+			}
+			fmt.Printf("ℹ %d duplicate groups, %s reclaimable\n", len(groups), humanize.IBytes(uint64(wasted)))
+			return nil
+		}
+
+		if dedupeKeep != "" {
+			return dedupeByRule(groups, action, wasted)
+		}
+		return dedupeInteractive(groups, action)
+	},
+}
+
+// dedupeAction returns the verb for the chosen action, or "" when only listing
+func dedupeAction() string {
+	switch {
+	case dedupeHardlink:
+		return "hardlink"
+	case dedupeDelete:
+		return "delete"
+	}
+	return ""
+}
+
+func dedupePrintGroup(g dedupe.Group, keep int) {
+	fmt.Printf("%s × %d\n", humanize.IBytes(uint64(g.Size)), len(g.Files))
+	for i, f := range g.Files {
+		marker := " "
+		if keep >= 0 {
+			marker = "✗"
+			if i == keep {
+				marker = "✓"
+			}
+		}
+		fmt.Printf("  %s %d) %s  %s\n", marker, i+1, f.ModTime.Format("2006-01-02 15:04"), f.Path)
+		for _, link := range f.Links {
+			fmt.Printf("       %s  ↳ %s\n", strings.Repeat(" ", 16), link)
+		}
+	}
+	fmt.Println()
+}
+
+func dedupeByRule(groups []dedupe.Group, action string, wasted int64) error {
+	keeps := make([]int, len(groups))
+	count := 0
+	for i, g := range groups {
+		keep, err := dedupe.Keep(g, dedupeKeep)
+		if err != nil {
+			return err
+		}
+		keeps[i] = keep
+		count += len(g.Files) - 1
+		dedupePrintGroup(g, keep)
+	}
+
+	if dedupeDryRun {
+		fmt.Printf("ℹ Dry run - would %s %d files, reclaiming %s\n", action, count, humanize.IBytes(uint64(wasted)))
+		return nil
+	}
+
+	if !dedupeYes {
+		confirmed, err := claude.PromptYesNo(fmt.Sprintf("%s %d files, reclaiming %s?", strings.ToUpper(action[:1])+action[1:], count, humanize.IBytes(uint64(wasted))), false)
+		if err != nil {
+			return fmt.Errorf("%w. Use -y to skip confirmation", err)
+		}
+		if !confirmed {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	var done int
+	var reclaimed int64
+	for i, g := range groups {
+		n := dedupeApply(g, keeps[i])
+		done += n
+		reclaimed += int64(n) * g.Size
+	}
+	dedupeSummary(action, done, reclaimed)
+	return nil
+}
+
+func dedupeInteractive(groups []dedupe.Group, action string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("cannot choose interactively in non-interactive mode. Use --keep")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var done int
+	var reclaimed int64
+	for gi, g := range groups {
+		fmt.Printf("[%d/%d] ", gi+1, len(groups))
+		dedupePrintGroup(g, -1)
+
+		keep := -1
+		for keep < 0 {
+			fmt.Printf("Keep which? [1-%d, s=skip, q=quit]: ", len(g.Files))
+			response, err := reader.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("failed to read input: %w", err)
+			}
+			if errors.Is(err, io.EOF) {
+				response = "q"
+			}
+			response = strings.TrimSpace(strings.ToLower(response))
+			switch response {
+			case "s", "":
+				keep = len(g.Files)
+			case "q":
+				dedupeSummary(action, done, reclaimed)
+				return nil
+			default:
+				n, err := strconv.Atoi(response)
+				if err != nil || n < 1 || n > len(g.Files) {
+					fmt.Println("✗ Invalid choice")
+					continue
+				}
+				keep = n - 1
+			}
+		}
+		if keep == len(g.Files) {
+			fmt.Println()
+			continue
+		}
+
+		if dedupeDryRun {
+			fmt.Printf("ℹ Dry run - would %s %d files\n\n", action, len(g.Files)-1)
+			continue
+		}
+		n := dedupeApply(g, keep)
+		done += n
+		reclaimed += int64(n) * g.Size
+		fmt.Println()
+	}
+
+	dedupeSummary(action, done, reclaimed)
+	return nil
+}
+
+// dedupeApply hardlinks or deletes every file in the group except keep, returning how many succeeded
+func dedupeApply(g dedupe.Group, keep int) int {
+	done := 0
+	for i, f := range g.Files {
+		if i == keep {
+			continue
+		}
+		var err error
+		if dedupeHardlink {
+			err = dedupe.Hardlink(g.Files[keep], f)
+		} else {
+			err = dedupe.Delete(g.Files[keep], f)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", f.Path, err)
+			continue
+		}
+		done++
+	}
+	return done
+}
+
+func dedupeSummary(action string, done int, reclaimed int64) {
+	if dedupeDryRun {
+		return
+	}
+	verb := "Hardlinked"
+	if action == "delete" {
+		verb = "Deleted"
+	}
+	fmt.Printf("✓ %s %d files, reclaimed %s\n", verb, done, humanize.IBytes(uint64(reclaimed)))
+}
+
+func init() {
+	dedupeCmd.Flags().StringVarP(&dedupeKeep, "keep", "k", "", "Keep rule instead of asking: newest, oldest, shortest or first")
+	dedupeCmd.Flags().BoolVar(&dedupeHardlink, "hardlink", false, "Replace duplicates with hardlinks")
+	dedupeCmd.Flags().BoolVar(&dedupeDelete, "delete", false, "Delete duplicates")
+	dedupeCmd.Flags().StringVarP(&dedupeMinSize, "min-size", "m", "1", "Ignore files smaller than this (e.g. 100K, 1M)")
+	dedupeCmd.Flags().BoolVarP(&dedupeDryRun, "dry-run", "n", false, "Show what would be done")
+	dedupeCmd.Flags().BoolVarP(&dedupeYes, "yes", "y", false, "Apply --keep without confirmation")
+	dedupeCmd.Flags().BoolVar(&dedupeNoExclude, "no-exclude", false, "Don't skip the global exclude patterns")
+	rootCmd.AddCommand(dedupeCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/dns"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dnsServer string
+	dnsShort  bool
+	dnsJSON   bool
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns <name> [type...]",
+	Short: "Look up DNS records (A, AAAA, MX, TXT, CNAME, NS)",
+	Long: `Look up DNS records without needing dig.
+
+Queries the system resolver by default. Use --server to query a specific
+DNS server (host or host:port), a DNS-over-HTTPS URL, or one of the DoH
+shorthands: cloudflare, google, quad9.
+
+The record type defaults to A. TTLs are only available when querying a
+server directly.
+
+Examples:
+  zzk dns example.com                        # A records
+  zzk dns example.com AAAA MX                # Several types
+  zzk dns -s 1.1.1.1 example.com TXT         # Ask a specific server
+  zzk dns -s cloudflare example.com          # DNS-over-HTTPS
+  zzk dns --short example.com                # Values only
+  zzk dns --json example.com MX              # Machine-readable output`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		types := args[1:]
+		if len(types) == 0 {
+			types = []string{"A"}
+		}
+
+		var all []dns.Record
+		for _, recordType := range types {
+			records, err := dns.Query(context.Background(), name, recordType, dnsServer)
+			if err != nil {
+				return fmt.Errorf("%s lookup for %s failed: %w", strings.ToUpper(recordType), name, err)
+			}
+			all = append(all, records...)
+		}
+
+		if dnsJSON {
+			if all == nil {
+				all = []dns.Record{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(all)
+		}
+
+		if len(all) == 0 {
+			fmt.Fprintf(os.Stderr, "No %s records found for %s\n", strings.ToUpper(strings.Join(types, "/")), name)
+			return nil
+		}
+
+		for _, record := range all {
+			if dnsShort {
+				fmt.Println(record.Value)
+				continue
+			}
+			ttl := "-"
+			if record.TTL > 0 {
+				ttl = fmt.Sprintf("%d", record.TTL)
+			}
+			fmt.Printf("%-30s %6s  %-5s  %s\n", record.Name, ttl, record.Type, record.Value)
+		}
+		return nil
+	},
+}
+
+func init() {
+	dnsCmd.Flags().StringVarP(&dnsServer, "server", "s", "", "DNS server (host[:port], https:// DoH URL, or cloudflare/google/quad9)")
+	dnsCmd.Flags().BoolVar(&dnsShort, "short", false, "Print values only")
+	dnsCmd.Flags().BoolVar(&dnsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(dnsCmd)
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/ppowo/zzk/internal/dotenv"
+	"github.com/ppowo/zzk/internal/envrun"
+	"github.com/ppowo/zzk/internal/fileutil"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envProject string
+	envFile    string
+	envForce   bool
+	envShow    bool
+	envStored  bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage per-project .env files with an encrypted canonical copy",
+	Long: `Keep a canonical, age-encrypted copy of each project's .env file under
+~/.config/zzk/env and sync it with the working copy in the repository.
+
+The project name defaults to the name of the git repository (or current
+directory) followed by a short hash of its origin URL, or of its path
+without one, so repositories sharing a name get separate copies. The
+working copy defaults to .env at the repository root. The encryption key is
+generated on first use and kept in the zzk vault as env/key (older key.txt
+files are moved there) — back it up, since stored copies cannot be
+decrypted without it.
+
+Examples:
+  zzk env push                    # Store ./.env as the canonical copy
+  zzk env pull                    # Restore .env from the canonical copy
+  zzk env diff                    # Compare working copy and canonical copy
+  zzk env ls                      # List stored projects
+  zzk env run -- npm start        # Run with the .env variables injected
+  zzk env run --stored -- make    # Inject from the canonical copy instead`,
+}
+
+var envPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Save the working .env as the canonical encrypted copy",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := envTarget()
+		if err != nil {
+			return err
+		}
+
+		local, err := envReadFile(target.path)
+		if err != nil {
+			return err
+		}
+
+		stored, err := envLoadStored(target)
+		if err != nil {
+			return err
+		}
+		if stored != nil && !envForce {
+			diff := dotenv.Compare(local, stored)
+			if diff.Empty() && !target.usedLegacy {
+				fmt.Printf("✓ %s is already up to date\n", target.project)
+				return nil
+			}
+			if len(diff.OnlyB) > 0 {
+				envPrintDiff(diff, local, stored, "local", "stored")
+				return fmt.Errorf("stored copy has keys missing locally - pull first or use --force")
+			}
+		}
+
+		if err := dotenv.Save(target.project, local); err != nil {
+			return fmt.Errorf("failed to save env: %w", err)
+		}
+		fmt.Printf("✓ Stored %d variables for %s\n", len(local), target.project)
+		if target.usedLegacy {
+			if err := dotenv.Remove(target.legacy); err != nil {
+				return fmt.Errorf("failed to remove the old copy: %w", err)
+			}
+			fmt.Printf("✓ Removed the old copy stored as %s\n", target.legacy)
+		}
+		return nil
+	},
+}
+
+var envPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Write the canonical copy to the working .env",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := envTarget()
+		if err != nil {
+			return err
+		}
+
+		stored, err := envLoadStored(target)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return fmt.Errorf("no stored env for %s - use 'zzk env push' first", target.project)
+		}
+
+		path := target.path
+		if _, err := os.Stat(path); err == nil && !envForce {
+			local, err := envReadFile(path)
+			if err != nil {
+				return err
+			}
+			diff := dotenv.Compare(local, stored)
+			if diff.Empty() {
+				fmt.Printf("✓ %s is already up to date\n", path)
+				return nil
+			}
+			if len(diff.OnlyA) > 0 || len(diff.Changed) > 0 {
+				envPrintDiff(diff, local, stored, "local", "stored")
+				return fmt.Errorf("%s has local changes - push first or use --force", path)
+			}
+		}
+
+		if err := fileutil.AtomicWrite(path, dotenv.Format(stored), 0600); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Wrote %d variables to %s\n", len(stored), path)
+		return nil
+	},
+}
+
+var envDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the working .env with the canonical copy",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := envTarget()
+		if err != nil {
+			return err
+		}
+
+		local, err := envReadFile(target.path)
+		if err != nil {
+			return err
+		}
+		stored, err := envLoadStored(target)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return fmt.Errorf("no stored env for %s", target.project)
+		}
+
+		diff := dotenv.Compare(local, stored)
+		if diff.Empty() {
+			fmt.Printf("✓ %s matches the stored copy\n", target.path)
+			return nil
+		}
+		envPrintDiff(diff, local, stored, "local", "stored")
+		return nil
+	},
+}
+
+var envLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List projects with a stored env",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projects, err := dotenv.Projects()
+		if err != nil {
+			return err
+		}
+		if len(projects) == 0 {
+			fmt.Println("No stored env files")
+			return nil
+		}
+		for _, project := range projects {
+			vars, err := dotenv.Load(project)
+			if err != nil {
+				fmt.Printf("  ✗ %-30s %v\n", project, err)
+				continue
+			}
+			fmt.Printf("  %-30s %d variables\n", project, len(vars))
+		}
+		return nil
+	},
+}
+
+var envRunCmd = &cobra.Command{
+	Use:   "run -- <command> [args...]",
+	Short: "Run a command with the .env variables injected",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := envTarget()
+		if err != nil {
+			return err
+		}
+
+		var vars []dotenv.Var
+		if envStored {
+			vars, err = envLoadStored(target)
+			if err == nil && vars == nil {
+				err = fmt.Errorf("no stored env for %s", target.project)
+			}
+		} else {
+			vars, err = envReadFile(target.path)
+		}
+		if err != nil {
+			return err
+		}
+
+		order := make([]string, len(vars))
+		for i, v := range vars {
+			order[i] = v.Key
+		}
+		env := envrun.Merge(os.Environ(), dotenv.Map(vars), order)
+
+		code, err := envrun.Run(args, env)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			os.Exit(code)
+		}
+		return nil
+	},
+}
+
+// envProjectRegex limits --project to a file name in the store
+var envProjectRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// envLocation names a project's stored copy and its working .env
+type envLocation struct {
+	project    string
+	legacy     string // Name versions before project IDs stored it under
+	path       string
+	usedLegacy bool // Set by envLoadStored when it read the legacy copy
+}
+
+// envTarget returns the stored copy and working .env path for the current
+// repository. Its stored copy is named after the repository directory plus
+// a hash of the origin URL (or the repository path, without one), so
+// repositories with the same directory name don't share it.
+func envTarget() (*envLocation, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	root := cwd
+	if toplevel, err := git.RunGit(cwd, "rev-parse", "--show-toplevel"); err == nil && toplevel != "" {
+		root = toplevel
+	}
+
+	target := &envLocation{project: envProject, path: envFile}
+	if target.project != "" {
+		if !envProjectRegex.MatchString(target.project) {
+			return nil, fmt.Errorf("invalid project name %q - use letters, digits, '.', '_' and '-'", target.project)
+		}
+	} else {
+		name := filepath.Base(root)
+		if name == "" || name == "." || name == string(filepath.Separator) {
+			return nil, fmt.Errorf("cannot determine project name - use --project")
+		}
+		source := root
+		if origin := git.GetRemoteURL(root, "origin"); origin != "" {
+			source = origin
+		}
+		sum := sha256.Sum256([]byte(source))
+		target.project = fmt.Sprintf("%s-%x", name, sum[:4])
+		target.legacy = name
+	}
+
+	if target.path == "" {
+		target.path = filepath.Join(root, ".env")
+	}
+	return target, nil
+}
+
+// envLoadStored loads the stored copy of target, falling back to the one
+// older versions stored under the repository directory name. That name may
+// be shared with other repositories, so 'zzk env push' moves it to the
+// project ID instead of updating it.
+func envLoadStored(target *envLocation) ([]dotenv.Var, error) {
+	vars, err := dotenv.Load(target.project)
+	if err != nil || vars != nil || target.legacy == "" {
+		return vars, err
+	}
+	if vars, err = dotenv.Load(target.legacy); vars != nil {
+		target.usedLegacy = true
+		fmt.Fprintf(os.Stderr, "ℹ Using the copy stored as %s by an older version; 'zzk env push' stores it as %s\n", target.legacy, target.project)
+	}
+	return vars, err
+}
+
+func envReadFile(path string) ([]dotenv.Var, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	vars, err := dotenv.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// envPrintDiff prints a key-level diff; values are masked unless --show is given
+func envPrintDiff(diff dotenv.Diff, a, b []dotenv.Var, nameA, nameB string) {
+	ma, mb := dotenv.Map(a), dotenv.Map(b)
+	value := func(v string) string {
+		if envShow {
+			return v
+		}
+		return "****"
+	}
+
+	for _, key := range diff.OnlyA {
+		fmt.Printf("  + %s=%s  (only %s)\n", key, value(ma[key]), nameA)
+	}
+	for _, key := range diff.OnlyB {
+		fmt.Printf("  - %s=%s  (only %s)\n", key, value(mb[key]), nameB)
+	}
+	for _, key := range diff.Changed {
+		fmt.Printf("  ~ %s: %s=%s, %s=%s\n", key, nameA, value(ma[key]), nameB, value(mb[key]))
+	}
+}
+
+func init() {
+	envCmd.PersistentFlags().StringVarP(&envProject, "project", "p", "", "Project name (default: repository directory name and a hash of its origin or path)")
+	envCmd.PersistentFlags().StringVarP(&envFile, "file", "f", "", "Working env file (default: .env at the repository root)")
+	envPushCmd.Flags().BoolVar(&envForce, "force", false, "Overwrite the stored copy even if it has other keys")
+	envPullCmd.Flags().BoolVar(&envForce, "force", false, "Overwrite local changes")
+	envDiffCmd.Flags().BoolVar(&envShow, "show", false, "Show values instead of masking them")
+	envRunCmd.Flags().BoolVar(&envStored, "stored", false, "Inject the stored copy instead of the working file")
+	envCmd.AddCommand(envPushCmd)
+	envCmd.AddCommand(envPullCmd)
+	envCmd.AddCommand(envDiffCmd)
+	envCmd.AddCommand(envLsCmd)
+	envCmd.AddCommand(envRunCmd)
+	rootCmd.AddCommand(envCmd)
+}
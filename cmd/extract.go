@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/archive"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractList   bool
+	extractOutput string
+	extractQuiet  bool
+)
+
+var extractCmd = &cobra.Command{
+	Use:   "extract <archive...>",
+	Short: "Extract zip, tar.gz, tar.xz, tar.zst, tar.bz2 and 7z archives",
+	Long: `Extract archives into a sensibly named directory.
+
+If every entry in the archive lives under a single top-level directory, that
+directory is extracted as-is. Otherwise the contents are placed in a new
+directory named after the archive (e.g. photos.zip → photos/). Existing
+directories are never overwritten; a numeric suffix is added instead.
+
+Entries that would escape the destination (zip-slip) are rejected.
+
+Examples:
+  zzk extract release.tar.gz           # Extract next to the archive name
+  zzk extract -o ~/tmp photos.zip      # Extract into ~/tmp
+  zzk extract --list backup.tar.zst    # List contents without extracting`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, path := range args {
+			if extractList {
+				if err := listArchive(path); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := extractArchive(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func listArchive(path string) error {
+	entries, err := archive.List(path)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", path, err)
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir {
+			fmt.Printf("%10s  %s\n", "-", entry.Name)
+			continue
+		}
+		total += entry.Size
+		fmt.Printf("%10s  %s\n", humanize.IBytes(uint64(entry.Size)), entry.Name)
+	}
+	fmt.Printf("\n%d entries, %s uncompressed\n", len(entries), humanize.IBytes(uint64(total)))
+	return nil
+}
+
+func extractArchive(path string) error {
+	entries, err := archive.List(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	parent := extractOutput
+	if parent == "" {
+		parent = "."
+	}
+
+	dest := parent
+	resultDir := singleTopLevelDir(entries)
+	if resultDir == "" || pathExists(filepath.Join(parent, resultDir)) {
+		// Wrap loose entries (or a conflicting top-level dir) in a fresh directory
+		dest = uniquePath(filepath.Join(parent, archive.BaseName(path)))
+		resultDir = dest
+	} else {
+		resultDir = filepath.Join(parent, resultDir)
+	}
+
+	fmt.Printf("Extracting %s (%d entries)...\n", path, len(entries))
+
+	var progress archive.ProgressFunc
+	if !extractQuiet {
+		progress = func(name string, percent int) {
+			fmt.Printf("\r\033[K  [%3d%%] %s", percent, truncate(name, 60))
+		}
+	}
+
+	if err := archive.Extract(path, dest, progress); err != nil {
+		if progress != nil {
+			fmt.Println()
+		}
+		return fmt.Errorf("failed to extract %s: %w", path, err)
+	}
+	if progress != nil {
+		fmt.Print("\r\033[K")
+	}
+
+	fmt.Printf("✓ Extracted to %s\n", resultDir)
+	return nil
+}
+
+// singleTopLevelDir returns the common top-level directory of all entries, if there is one
+func singleTopLevelDir(entries []archive.Entry) string {
+	top := ""
+	for _, entry := range entries {
+		name := strings.TrimPrefix(filepath.ToSlash(entry.Name), "./")
+		if name == "" {
+			continue
+		}
+		first, _, hasSlash := strings.Cut(name, "/")
+		if !hasSlash && !entry.IsDir {
+			return "" // Loose file at the top level
+		}
+		if top == "" {
+			top = first
+		} else if top != first {
+			return ""
+		}
+	}
+	return top
+}
+
+func pathExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// uniquePath appends -1, -2, ... until path does not exist
+func uniquePath(path string) string {
+	if !pathExists(path) {
+		return path
+	}
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", path, i)
+		if !pathExists(candidate) {
+			return candidate
+		}
+	}
+}
+
+func init() {
+	extractCmd.Flags().BoolVarP(&extractList, "list", "l", false, "List archive contents instead of extracting")
+	extractCmd.Flags().StringVarP(&extractOutput, "output", "o", "", "Directory to extract into (default: current directory)")
+	extractCmd.Flags().BoolVarP(&extractQuiet, "quiet", "q", false, "Do not show progress")
+	rootCmd.AddCommand(extractCmd)
+}
@@ -1,15 +1,14 @@
 package cmd
 
 import (
-	"archive/zip"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 
+	"github.com/ppowo/zzk/internal/archive"
 	"github.com/ppowo/zzk/internal/fileutil"
 	"github.com/ppowo/zzk/internal/font"
 	"github.com/spf13/cobra"
@@ -68,7 +67,7 @@ func installDMCAFont() error {
 
 	// Extract zip file
 	fmt.Println("Extracting font files...")
-	if err := unzip(zipPath, tempDir); err != nil {
+	if err := archive.Unzip(zipPath, tempDir); err != nil {
 		return fmt.Errorf("failed to extract zip file: %w", err)
 	}
 
@@ -127,51 +126,3 @@ func downloadFile(filepath string, url string) error {
 	_, err = io.Copy(out, resp.Body)
 	return err
 }
-
-func unzip(src, dest string) error {
-	r, err := zip.OpenReader(src)
-	if err != nil {
-		return err
-	}
-	defer r.Close()
-
-	for _, f := range r.File {
-		// Protect against zip slip
-		fpath := filepath.Join(dest, f.Name)
-		cleanDest := filepath.Clean(dest) + string(os.PathSeparator)
-		cleanPath := filepath.Clean(fpath)
-		if !strings.HasPrefix(cleanPath, cleanDest) && cleanPath != filepath.Clean(dest) {
-			return fmt.Errorf("illegal file path: %s", fpath)
-		}
-
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
-		}
-
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-
-		rc, err := f.Open()
-		if err != nil {
-			outFile.Close()
-			return err
-		}
-
-		_, err = io.Copy(outFile, rc)
-		outFile.Close()
-		rc.Close()
-
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
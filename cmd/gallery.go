@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ppowo/zzk/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	galleryDest      string
+	galleryInput     string
+	galleryNoArchive bool
+)
+
+// galleryDirectory lays downloads out as <site>/<album>, falling back to the
+// extractor's subcategory when a site has no album or gallery name
+var galleryDirectory = `directory=["{category}", "{album|gallery|subcategory}"]`
+
+var baseGalleryDlArgs = []string{
+	"-o", galleryDirectory,
+}
+
+func GetGalleryDlArgs(destDir string, archive bool) []string {
+	args := make([]string, len(baseGalleryDlArgs))
+	copy(args, baseGalleryDlArgs)
+	args = append(args, "-d", destDir)
+	if archive {
+		// Records every downloaded file so re-runs only fetch new images
+		args = append(args, "--download-archive", filepath.Join(destDir, ".gallery-dl-archive.sqlite3"))
+	}
+	return args
+}
+
+var galleryCmd = &cobra.Command{
+	Use:   "gallery [URL...]",
+	Short: "Download image galleries using gallery-dl",
+	Long: `Downloads images and albums from the provided URL(s) to ~/Pictures/<site>/<album>
+using gallery-dl.
+
+Downloaded files are recorded in an archive in the destination directory, so
+running the same URL again only fetches new images.
+
+Examples:
+  zzk gallery https://imgur.com/a/abc123          # → ~/Pictures/imgur/<album>
+  zzk gallery -i urls.txt                         # One URL per line
+  zzk gallery -d ~/Downloads/art https://...      # Different destination
+  zzk gallery --no-archive https://...            # Download everything again`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && galleryInput == "" {
+			return fmt.Errorf("give at least one URL or --input-file")
+		}
+		if err := CheckGalleryDl(); err != nil {
+			return err
+		}
+
+		var destDir string
+		switch {
+		case UseTmpDir:
+			destDir = filepath.Join(os.TempDir(), "zzk-debug")
+		case galleryDest != "":
+			destDir = galleryDest
+		default:
+			destDir = filepath.Join(os.Getenv("HOME"), "Pictures")
+		}
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", destDir, err)
+		}
+		fmt.Printf("Downloading galleries to: %s\n", destDir)
+
+		cmdArgs := GetGalleryDlArgs(destDir, !galleryNoArchive)
+		if galleryInput != "" {
+			cmdArgs = append(cmdArgs, "-i", galleryInput)
+		}
+		cmdArgs = append(cmdArgs, args...)
+
+		galleryDlCmd := exec.Command("gallery-dl", cmdArgs...)
+		galleryDlCmd.Stdout = os.Stdout
+		galleryDlCmd.Stderr = os.Stderr
+		started := time.Now()
+		if err := galleryDlCmd.Run(); err != nil {
+			err = fmt.Errorf("gallery-dl failed: %w", err)
+			notify.Failed("Download failed", err, started)
+			return err
+		}
+		fmt.Println("✓ Download completed successfully!")
+		notify.Done("Download complete", "Saved to "+destDir, started)
+		return nil
+	},
+}
+
+func CheckGalleryDl() error {
+	_, err := exec.LookPath("gallery-dl")
+	if err != nil {
+		return fmt.Errorf("gallery-dl is not installed. Please install gallery-dl first.\n" +
+			"  macOS: brew install gallery-dl\n" +
+			"  Linux (Debian/Ubuntu): sudo apt install gallery-dl\n" +
+			"  Linux (Fedora): sudo dnf install gallery-dl\n" +
+			"  Any: pipx install gallery-dl")
+	}
+	return nil
+}
+
+func init() {
+	galleryCmd.Flags().StringVarP(&galleryDest, "dest", "d", "", "Destination directory (default ~/Pictures)")
+	galleryCmd.Flags().StringVarP(&galleryInput, "input-file", "i", "", "Read URLs from a file, one per line")
+	galleryCmd.Flags().BoolVar(&galleryNoArchive, "no-archive", false, "Don't skip previously downloaded files")
+	rootCmd.AddCommand(galleryCmd)
+}
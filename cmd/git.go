@@ -1,9 +1,20 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/git"
 	"github.com/spf13/cobra"
 )
 
+var (
+	gitQuiet      bool
+	gitVerbose    bool
+	gitConfigPath string
+)
+
 var gitCmd = &cobra.Command{
 	Use:   "git",
 	Short: "Git identity manager - manage multiple git identities",
@@ -16,15 +27,106 @@ Features:
   - HTTPS to SSH URL rewriting
   - Multiple identities per domain (e.g., work and personal GitHub)
 
-Configuration file: ~/.git-identities.json
+Configuration file: ~/.config/zzk/git-identities.json, or git-identities.yaml
+to write it in YAML with comments (kept when zzk updates the file). Use
+--config or ZZK_GIT_CONFIG for another path. A ~/.git-identities.json from
+older versions is moved to ~/.config/zzk automatically. An "overrides"
+section keyed by host name changes identity fields on one machine only.
+
+Commands that sync accept --quiet to print errors only, or --verbose to also
+show the ssh, ssh-keygen and ssh-add commands run and their output.
 
 Examples:
+  zzk git add github-work         # Add an identity interactively
+  zzk git import -n               # Propose identities from an existing setup
+  zzk git export                  # Bundle identities for another machine
   zzk git sync                    # Apply configuration and cleanup orphans
+  zzk git sync -v                 # Same, showing the commands run
+  zzk git sync -w                 # Re-sync whenever the config changes
+  zzk git sync --no-prune         # Apply configuration, keep orphans
+  zzk git push-key github-work    # Upload the public key via the forge API
+  zzk git rm github-work          # Remove an identity and what zzk created for it
+  zzk git backups ls              # List backups of removed identities
+  zzk git ls                      # List identities (--json for scripts)
   zzk git status                  # Show status of all identities
+  zzk git tui                     # Manage identities interactively
   zzk git where                   # Show current identity
-  zzk git info github-work        # Show identity details`,
+  zzk git switch github-work      # Apply an identity to the current repo
+  zzk git mv ~/Personal/tool github-work   # Move a repo to another identity
+  zzk git info github-work        # Show identity details
+  zzk git verify github-work      # Check that signed commits verify
+  zzk git signers                 # Print allowed_signers lines to share
+  zzk git clone git@github.com:owner/repo.git   # Clone into the identity's folder
+  zzk git audit                   # Check repos use the right email, key and host
+  zzk git repos                   # List known repos and when they were verified
+  zzk git fix                     # Repair the issues audit reports
+  zzk git remote fix              # Point remotes at the identity's SSH host
+  zzk git --config ./ids.yaml status   # Use another config file`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if gitConfigPath != "" {
+			git.SetConfigPath(gitConfigPath)
+		}
+		legacy, err := git.MigrateLegacyConfig()
+		if err != nil {
+			return err
+		}
+		if legacy != "" {
+			fmt.Fprintf(os.Stderr, "ℹ Moved %s to %s\n", legacy, git.ConfigPath())
+		}
+		from, backup, err := git.MigrateConfig()
+		if err != nil {
+			return err
+		}
+		if backup != "" {
+			fmt.Fprintf(os.Stderr, "ℹ Upgraded %s from version %d to %d (old file kept as %s)\n", git.ConfigPath(), from, git.ConfigVersion, backup)
+		}
+		return nil
+	},
+}
+
+// gitVerbosity maps --quiet and --verbose to a reporter verbosity
+func gitVerbosity() git.Verbosity {
+	switch {
+	case gitQuiet:
+		return git.VerbosityQuiet
+	case gitVerbose:
+		return git.VerbosityVerbose
+	default:
+		return git.VerbosityNormal
+	}
+}
+
+// gitCompleteIdentity completes identity names from the config for the
+// argument at position pos, with their email and domain as descriptions
+func gitCompleteIdentity(pos int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) != pos {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		// PersistentPreRunE doesn't run while completing
+		if gitConfigPath != "" {
+			git.SetConfigPath(gitConfigPath)
+		}
+		config, err := git.LoadConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var completions []cobra.Completion
+		for _, identity := range config.SortedIdentities() {
+			if !strings.HasPrefix(identity.Name, toComplete) {
+				continue
+			}
+			completions = append(completions, cobra.CompletionWithDesc(identity.Name, fmt.Sprintf("%s on %s", identity.Email, identity.Domain)))
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
 }
 
 func init() {
+	gitCmd.PersistentFlags().BoolVarP(&gitQuiet, "quiet", "q", false, "Print errors only")
+	gitCmd.PersistentFlags().BoolVarP(&gitVerbose, "verbose", "v", false, "Show the commands run and their output")
+	gitCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	gitCmd.PersistentFlags().StringVar(&gitConfigPath, "config", "", "Identities config file (default: $ZZK_GIT_CONFIG or ~/.config/zzk/git-identities.json)")
 	rootCmd.AddCommand(gitCmd)
 }
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	gitAddUser    string
+	gitAddEmail   string
+	gitAddDomain  string
+	gitAddFolders []string
+	gitAddKeyType string
+	gitAddKeyOpts []string
+	gitAddForce   bool
+	gitAddSync    bool
+)
+
+// gitIdentityNameRegex keeps names safe for key and config file names
+var gitIdentityNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+var gitAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a git identity interactively",
+	Long: `Add an identity to ~/.config/zzk/git-identities.json, prompting for
+anything not given as a flag, then optionally sync just that identity.
+
+Folders are comma-separated when prompted. With --force an existing identity
+is replaced, using its current values as defaults.
+
+Examples:
+  zzk git add github-work                      # Prompt for everything
+  zzk git add codeberg --user me --email me@example.com \
+    --domain codeberg.org --folder ~/Codeberg --sync
+  zzk git add work --key-type ed25519-sk \
+    --key-option resident --key-option verify-required   # FIDO2 resident key`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !gitIdentityNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid identity name '%s' (use letters, digits, '.', '_' and '-')", name)
+		}
+
+		config, err := gitLoadOrCreateConfig()
+		if err != nil {
+			return err
+		}
+
+		defaults := git.Identity{Domain: "github.com", Folders: []string{"~/" + gitDefaultFolder(name)}}
+		if existing, ok := config.GetIdentity(name); ok {
+			if !gitAddForce {
+				return fmt.Errorf("identity '%s' already exists. Use -f to replace it", name)
+			}
+			defaults = existing
+		}
+
+		identity := git.Identity{
+			Name:       name,
+			User:       gitAddUser,
+			Email:      gitAddEmail,
+			Domain:     gitAddDomain,
+			Folders:    gitAddFolders,
+			KeyType:    gitAddKeyType,
+			KeyOptions: gitAddKeyOpts,
+		}
+		if identity.KeyType == "" && identity.KeyOptions == nil {
+			identity.KeyType, identity.KeyOptions = defaults.KeyType, defaults.KeyOptions
+		}
+		if err := gitPromptIdentity(&identity, defaults); err != nil {
+			return err
+		}
+		if err := identity.Validate(); err != nil {
+			return fmt.Errorf("invalid identity: %w", err)
+		}
+
+		config.Identities[name] = identity
+		if err := git.SaveConfig(config); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Added %s to %s\n", name, git.ConfigPath())
+
+		sync := gitAddSync
+		if !sync && term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Println()
+			sync, err = claude.PromptYesNo(fmt.Sprintf("Sync %s now?", name), true)
+			if err != nil {
+				return err
+			}
+		}
+		if !sync {
+			fmt.Printf("Run 'zzk git sync %s' to create its SSH key and git config\n", name)
+			return nil
+		}
+
+		fmt.Println()
+		if _, err := git.Sync(config, git.SyncOptions{Identities: []string{name}, Verbosity: gitVerbosity()}); err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// gitLoadOrCreateConfig loads ~/.config/zzk/git-identities.json, starting empty if it doesn't exist yet
+func gitLoadOrCreateConfig() (*git.Config, error) {
+	if _, err := os.Stat(git.ConfigPath()); os.IsNotExist(err) {
+		return &git.Config{Version: git.ConfigVersion, Identities: make(map[string]git.Identity)}, nil
+	}
+	config, err := git.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+	}
+	return config, nil
+}
+
+// gitDefaultFolder matches the folder name orphan cleanup removes, e.g. "work" → "Work"
+func gitDefaultFolder(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// gitPromptIdentity fills in fields not set by flags
+func gitPromptIdentity(identity *git.Identity, defaults git.Identity) error {
+	if identity.User != "" && identity.Email != "" && identity.Domain != "" && len(identity.Folders) > 0 {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("cannot prompt in non-interactive mode. Pass --user, --email, --domain and --folder")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var err error
+	if identity.User == "" {
+		if identity.User, err = gitPrompt(reader, "User", defaults.User); err != nil {
+			return err
+		}
+	}
+	if identity.Email == "" {
+		if identity.Email, err = gitPrompt(reader, "Email", defaults.Email); err != nil {
+			return err
+		}
+	}
+	if identity.Domain == "" {
+		if identity.Domain, err = gitPrompt(reader, "Domain", defaults.Domain); err != nil {
+			return err
+		}
+	}
+	if len(identity.Folders) == 0 {
+		folders, err := gitPrompt(reader, "Folders (comma-separated)", strings.Join(defaults.Folders, ", "))
+		if err != nil {
+			return err
+		}
+		for folder := range strings.SplitSeq(folders, ",") {
+			if folder = strings.TrimSpace(folder); folder != "" {
+				identity.Folders = append(identity.Folders, folder)
+			}
+		}
+	}
+	return nil
+}
+
+// gitPrompt reads one line, returning defaultVal for an empty answer
+func gitPrompt(reader *bufio.Reader, label, defaultVal string) (string, error) {
+	if defaultVal != "" {
+		fmt.Printf("%s [%s]: ", label, defaultVal)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return defaultVal, nil
+	}
+	return line, nil
+}
+
+func init() {
+	gitAddCmd.Flags().StringVar(&gitAddUser, "user", "", "Username on the forge")
+	gitAddCmd.Flags().StringVar(&gitAddEmail, "email", "", "Commit email")
+	gitAddCmd.Flags().StringVar(&gitAddDomain, "domain", "", "Forge domain (e.g. github.com)")
+	gitAddCmd.Flags().StringArrayVar(&gitAddFolders, "folder", nil, "Folder using this identity (repeatable)")
+	gitAddCmd.Flags().StringVar(&gitAddKeyType, "key-type", "", "SSH key type: "+strings.Join(git.KeyTypes, ", ")+" (default ed25519)")
+	gitAddCmd.Flags().StringArrayVar(&gitAddKeyOpts, "key-option", nil, "ssh-keygen -O option for security keys, e.g. resident (repeatable)")
+	gitAddCmd.Flags().BoolVarP(&gitAddForce, "force", "f", false, "Replace an existing identity")
+	gitAddCmd.Flags().BoolVarP(&gitAddSync, "sync", "s", false, "Sync the identity without asking")
+	gitCmd.AddCommand(gitAddCmd)
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitAuditIdentity string
+	gitAuditDepth    int
+	gitAuditJSON     bool
+)
+
+type gitAuditResult struct {
+	Identity string           `json:"identity"`
+	Repo     string           `json:"repo"`
+	Issues   []git.AuditIssue `json:"issues"`
+}
+
+var gitAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check repos use their identity's email, name, key and host",
+	Long: `Walks the repositories in all identity folders and checks each one's
+effective user.email, user.name and user.signingkey against the identity
+owning the folder, and that SSH remotes use the identity's host.
+
+For identities with enforce_noreply, repo emails that aren't a forge noreply
+address are called out, and unpushed commits using a real address are
+reported too.
+
+Mismatches usually come from values set in a repo's own .git/config (e.g. a
+personal email in a work repo) or remotes cloned before host aliases existed.
+Exits with status 1 if any repo has issues. The repos found are recorded in
+the state, with the time each last passed, for 'zzk git repos'.
+
+Examples:
+  zzk git audit                      # All identities
+  zzk git audit --identity work      # One identity
+  zzk git audit --json               # Machine-readable output`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		if gitAuditIdentity != "" && !config.HasIdentity(gitAuditIdentity) {
+			return fmt.Errorf("identity '%s' not found", gitAuditIdentity)
+		}
+
+		state, err := git.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		results := []gitAuditResult{}
+		repos := 0
+		remoteIssues, localIssues, commitIssues := false, false, false
+		for _, identity := range config.SortedIdentities() {
+			if gitAuditIdentity != "" && identity.Name != gitAuditIdentity {
+				continue
+			}
+
+			identityRepos := git.IdentityRepos(identity, gitAuditDepth)
+			var verified []string
+			for _, repo := range identityRepos {
+				repos++
+				issues := git.AuditRepo(config, identity, repo)
+				if len(issues) == 0 {
+					verified = append(verified, repo)
+					continue
+				}
+				results = append(results, gitAuditResult{Identity: identity.Name, Repo: repo, Issues: issues})
+				for _, issue := range issues {
+					if strings.HasPrefix(issue.Field, "remote.") {
+						remoteIssues = true
+					} else if issue.Field == "commits" {
+						commitIssues = true
+					} else if issue.Local {
+						localIssues = true
+					}
+				}
+			}
+			state.RecordRepos(identity.Name, identityRepos, verified)
+		}
+		if err := state.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", err)
+		}
+
+		if gitAuditJSON {
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		} else {
+			for _, result := range results {
+				fmt.Printf("✗ %s (%s)\n", result.Repo, result.Identity)
+				for _, issue := range result.Issues {
+					fmt.Printf("    %s\n", issue.Message)
+				}
+			}
+			if len(results) > 0 {
+				fmt.Println()
+			}
+
+			if len(results) == 0 {
+				fmt.Printf("✓ Audited %d repos, no issues found\n", repos)
+			} else {
+				fmt.Printf("⚠ Audited %d repos, %d with issues\n", repos, len(results))
+				if localIssues {
+					fmt.Println("  → Remove repo overrides: git config --local --unset <key>")
+				}
+				if remoteIssues {
+					fmt.Println("  → Fix remotes: zzk git remote fix")
+				}
+				if commitIssues {
+					fmt.Println("  → Amend unpushed commits: git rebase -r --exec 'git commit --amend --no-edit --reset-author' <base>")
+				}
+			}
+		}
+
+		if len(results) > 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitAuditCmd.Flags().StringVarP(&gitAuditIdentity, "identity", "i", "", "Only audit repos of this identity")
+	gitAuditCmd.Flags().IntVar(&gitAuditDepth, "depth", 3, "Maximum folder depth to search for repos")
+	gitAuditCmd.Flags().BoolVar(&gitAuditJSON, "json", false, "Output as JSON")
+	gitCmd.AddCommand(gitAuditCmd)
+}
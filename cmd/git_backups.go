@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitBackupsRestoreFiles []string
+	gitBackupsRestoreForce bool
+)
+
+var gitBackupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "List and restore backups of removed identities",
+	Long: `Orphan cleanup during 'zzk git sync' and 'zzk git rm' back up the files they
+remove to ~/.config/zzk/backups (the 10 most recent are kept).
+
+Examples:
+  zzk git backups ls                                  # List backups and their files
+  zzk git backups restore 20250102-150405             # Restore every file
+  zzk git backups restore 20250102 --file work_key    # Restore one file`,
+}
+
+var gitBackupsLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List backups and the files in them",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backups, err := git.ListBackups()
+		if err != nil {
+			return err
+		}
+		if len(backups) == 0 {
+			fmt.Println("No backups")
+			return nil
+		}
+
+		for i, backup := range backups {
+			if i > 0 {
+				fmt.Println()
+			}
+			age := ""
+			if !backup.Created.IsZero() {
+				age = fmt.Sprintf(" (%s)", humanize.Time(backup.Created))
+			}
+			fmt.Printf("%s%s - %d files\n", backup.Timestamp, age, len(backup.Files))
+			for _, file := range backup.Files {
+				fmt.Printf("  %-40s %s\n", file.Target, humanize.Bytes(uint64(file.Size)))
+			}
+		}
+		return nil
+	},
+}
+
+var gitBackupsRestoreCmd = &cobra.Command{
+	Use:   "restore <timestamp>",
+	Short: "Restore files from a backup to their original locations",
+	Long: `Restore files from a backup to where they were removed from. The timestamp
+may be shortened as long as it matches a single backup. --file selects files
+by name (work_key) or path (~/.ssh/work_key) and can be repeated.
+
+Existing files are not overwritten unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backup, err := git.FindBackup(args[0])
+		if err != nil {
+			return err
+		}
+
+		restored, err := git.RestoreBackup(backup, gitBackupsRestoreFiles, gitBackupsRestoreForce)
+		for _, file := range restored {
+			fmt.Printf("✓ Restored %s\n", file.Target)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		fmt.Println("ℹ Add the identity back to ~/.config/zzk/git-identities.json before the next 'zzk git sync',")
+		fmt.Println("  or its restored files will be cleaned up again as orphans")
+		return nil
+	},
+}
+
+func init() {
+	gitBackupsRestoreCmd.Flags().StringArrayVar(&gitBackupsRestoreFiles, "file", nil, "File to restore, by name or path (repeatable, default: all)")
+	gitBackupsRestoreCmd.Flags().BoolVarP(&gitBackupsRestoreForce, "force", "f", false, "Overwrite existing files")
+	gitBackupsCmd.AddCommand(gitBackupsLsCmd)
+	gitBackupsCmd.AddCommand(gitBackupsRestoreCmd)
+	gitCmd.AddCommand(gitBackupsCmd)
+}
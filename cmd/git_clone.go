@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitCloneFolder string
+
+var gitCloneCmd = &cobra.Command{
+	Use:   "clone <url> [identity]",
+	Short: "Clone a repository into an identity's folder",
+	Long: `Clone a repository into the first folder of the matching identity, using
+the identity's SSH key and host alias, then check that the clone picks up the
+identity's git config.
+
+The identity is detected from the URL's host. When several identities share
+the domain, name one explicitly. HTTPS URLs are cloned over SSH. With an
+identity given, the URL may be just owner/repo.
+
+Examples:
+  zzk git clone git@github.com:ppowo/zzk.git
+  zzk git clone https://codeberg.org/me/dotfiles
+  zzk git clone ppowo/zzk github-work            # owner/repo on the identity's domain
+  zzk git clone --folder ~/Work/Forks git@github.com:x/y.git github-work`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+
+		var identity git.Identity
+		if len(args) == 2 {
+			var ok bool
+			if identity, ok = config.GetIdentity(args[1]); !ok {
+				return fmt.Errorf("identity '%s' not found", args[1])
+			}
+		}
+
+		host, repoPath, ok := git.ParseRepoURL(args[0])
+		if !ok {
+			if identity.Name == "" || strings.Count(args[0], "/") != 1 {
+				return fmt.Errorf("cannot parse repository URL %q", args[0])
+			}
+			host, repoPath = identity.Domain, strings.TrimSuffix(args[0], ".git")
+		}
+
+		if identity.Name == "" {
+			if identity, err = gitCloneDetectIdentity(config, host); err != nil {
+				return err
+			}
+		} else if host != identity.Domain && host != identity.SSHHost() {
+			return fmt.Errorf("identity '%s' is for %s, not %s", identity.Name, identity.Domain, host)
+		}
+
+		if !git.SSHKeyExists(identity) {
+			return fmt.Errorf("SSH key for '%s' not found. Run 'zzk git sync %s' first", identity.Name, identity.Name)
+		}
+
+		parent := gitCloneFolder
+		if parent == "" {
+			parent = identity.Folders[0]
+		}
+		dest := filepath.Join(git.ExpandPath(parent), path.Base(repoPath))
+		if _, err := os.Stat(dest); err == nil {
+			return fmt.Errorf("%s already exists", dest)
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+		}
+
+		url := identity.RemoteURL(repoPath)
+		fmt.Printf("ℹ Cloning %s as %s into %s\n", url, identity.Name, dest)
+		if err := git.Clone(identity, url, dest); err != nil {
+			return err
+		}
+		fmt.Println()
+
+		gitCloneVerify(config, identity, dest, url)
+		return nil
+	},
+}
+
+// gitCloneDetectIdentity picks the identity for a host, which may be a domain or an alias
+func gitCloneDetectIdentity(config *git.Config, host string) (git.Identity, error) {
+	var matches []git.Identity
+	for _, identity := range config.SortedIdentities() {
+		if identity.SSHHost() == host {
+			return identity, nil
+		}
+		if identity.Domain == host {
+			matches = append(matches, identity)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return git.Identity{}, fmt.Errorf("no identity for %s. Add one with 'zzk git add'", host)
+	case 1:
+		return matches[0], nil
+	}
+
+	var names []string
+	for _, identity := range matches {
+		names = append(names, identity.Name)
+	}
+	return git.Identity{}, fmt.Errorf("several identities use %s (%s). Name one: zzk git clone <url> <identity>", host, strings.Join(names, ", "))
+}
+
+// gitCloneVerify checks the clone resolves to the identity's user, email and remote
+func gitCloneVerify(config *git.Config, identity git.Identity, dest, url string) {
+	ok := true
+	check := func(label, got, want string) {
+		if got == want {
+			fmt.Printf("✓ %s: %s\n", label, got)
+			return
+		}
+		fmt.Printf("⚠ %s is %q, expected %q\n", label, got, want)
+		ok = false
+	}
+
+	detected := ""
+	if d, err := git.DetectIdentity(config, dest); err == nil {
+		detected = d.Name
+	}
+	check("Identity", detected, identity.Name)
+	email, _ := git.RunGit(dest, "config", "user.email")
+	check("Email", email, identity.Email)
+	remote, _ := git.RunGit(dest, "config", "remote.origin.url")
+	check("Remote", remote, url)
+
+	if !ok {
+		fmt.Printf("\nRun 'zzk git sync %s' so the folder picks up the identity's git config\n", identity.Name)
+	}
+}
+
+func init() {
+	gitCloneCmd.Flags().StringVar(&gitCloneFolder, "folder", "", "Clone under this folder instead of the identity's first folder")
+	gitCloneCmd.ValidArgsFunction = gitCompleteIdentity(1)
+	gitCmd.AddCommand(gitCloneCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitExportOutput string
+
+var gitExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export identities as a portable bundle",
+	Long: `Write a tar.gz bundle with ~/.config/zzk/git-identities.json, the public
+keys and the sync state, to set up the same identities on another machine
+with 'zzk git import --bundle'. Private keys are never included; the other
+machine generates its own on sync.
+
+Examples:
+  zzk git export                          # → zzk-identities-<hostname>.tar.gz
+  zzk git export -o ~/Sync/identities.tar.gz
+  zzk git export -o - | ssh laptop zzk git import --bundle - -y`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+
+		bundle, err := git.NewBundle(config)
+		if err != nil {
+			return err
+		}
+
+		output := gitExportOutput
+		if output == "" {
+			host := strings.Split(bundle.Manifest.Hostname, ".")[0]
+			if host == "" {
+				host = "export"
+			}
+			output = fmt.Sprintf("zzk-identities-%s.tar.gz", host)
+		}
+
+		if err := writeOutput(output, func(w io.Writer) error { return bundle.Write(w) }); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+
+		if output != "-" {
+			fmt.Fprintf(os.Stderr, "✓ Exported %d identities (%d public keys) to %s\n", len(config.Identities), len(bundle.PublicKeys), output)
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitExportCmd.Flags().StringVarP(&gitExportOutput, "output", "o", "", "Output file ('-' for stdout)")
+	gitCmd.AddCommand(gitExportCmd)
+}
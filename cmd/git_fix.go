@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitFixRepo   string
+	gitFixDepth  int
+	gitFixDryRun bool
+	gitFixYes    bool
+)
+
+var gitFixCmd = &cobra.Command{
+	Use:   "fix",
+	Short: "Repair repos that don't match their identity",
+	Long: `Fix the issues 'zzk git audit' reports. For each repo with issues, the
+changes are shown and confirmed before applying:
+
+  - user.email, user.name and user.signingkey set in the repo's .git/config
+    are removed so the identity's config applies (or set to the identity's
+    value if nothing else provides it)
+  - SSH remotes are rewritten to the identity's host
+
+Examples:
+  zzk git fix                      # All repos, confirm each
+  zzk git fix --repo .             # Only the current repo
+  zzk git fix -n                   # Show what would change
+  zzk git fix -y                   # Fix everything without asking`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+
+		type target struct {
+			identity git.Identity
+			repo     string
+		}
+		var targets []target
+		if gitFixRepo != "" {
+			repo, err := filepath.Abs(gitFixRepo)
+			if err != nil {
+				return err
+			}
+			if !git.IsGitRepo(repo) {
+				return fmt.Errorf("%s is not a git repository", gitFixRepo)
+			}
+			identity, err := git.DetectIdentity(config, repo)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, target{*identity, repo})
+		} else {
+			for _, identity := range config.SortedIdentities() {
+				for _, repo := range git.IdentityRepos(identity, gitFixDepth) {
+					targets = append(targets, target{identity, repo})
+				}
+			}
+		}
+
+		fixed, fixedRepos, skipped, failed := 0, 0, 0, 0
+		for _, t := range targets {
+			issues := git.AuditRepo(config, t.identity, t.repo)
+			if len(issues) == 0 {
+				continue
+			}
+
+			fmt.Printf("%s (%s)\n", t.repo, t.identity.Name)
+			for _, issue := range issues {
+				fmt.Printf("    %s\n", issue.Message)
+			}
+			if gitFixDryRun {
+				fixed += len(issues)
+				fixedRepos++
+				fmt.Println()
+				continue
+			}
+
+			if !gitFixYes {
+				confirmed, err := claude.PromptYesNo("Fix this repo?", true)
+				if err != nil {
+					return fmt.Errorf("%w. Use -y to skip confirmation", err)
+				}
+				if !confirmed {
+					skipped++
+					fmt.Println()
+					continue
+				}
+			}
+
+			repoFixed := 0
+			for _, issue := range issues {
+				change, err := git.FixIssue(t.identity, t.repo, issue)
+				if err != nil {
+					fmt.Printf("  ✗ %s: %v\n", issue.Field, err)
+					failed++
+					continue
+				}
+				fmt.Printf("  ✓ %s\n", change)
+				repoFixed++
+			}
+			fixed += repoFixed
+			if repoFixed > 0 {
+				fixedRepos++
+			}
+			fmt.Println()
+		}
+
+		switch {
+		case fixed == 0 && skipped == 0 && failed == 0:
+			fmt.Printf("✓ All %d repos match their identity\n", len(targets))
+		case gitFixDryRun:
+			fmt.Printf("ℹ Would fix %d issues in %d repos (dry run)\n", fixed, fixedRepos)
+		default:
+			fmt.Printf("✓ Fixed %d issues in %d repos", fixed, fixedRepos)
+			if skipped > 0 {
+				fmt.Printf(", skipped %d repos", skipped)
+			}
+			if failed > 0 {
+				fmt.Printf(", %d need fixing by hand", failed)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitFixCmd.Flags().StringVar(&gitFixRepo, "repo", "", "Only fix this repository")
+	gitFixCmd.Flags().IntVar(&gitFixDepth, "depth", 3, "Maximum folder depth to search for repos")
+	gitFixCmd.Flags().BoolVarP(&gitFixDryRun, "dry-run", "n", false, "Show issues without fixing them")
+	gitFixCmd.Flags().BoolVarP(&gitFixYes, "yes", "y", false, "Fix without asking")
+	gitCmd.AddCommand(gitFixCmd)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitImportDryRun  bool
+	gitImportYes     bool
+	gitImportNewKeys bool
+	gitImportBundle  string
+	gitImportForce   bool
+)
+
+var gitImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Propose identities from an existing ~/.gitconfig and ~/.ssh/config",
+	Long: `Reconstruct identities from an existing setup: each includeIf "gitdir:..."
+section in ~/.gitconfig becomes an identity with the user and email of the
+included config. The SSH key comes from its core.sshCommand or signing key,
+and the domain from the ~/.ssh/config Host using that key (or from the
+remotes of repos in the folders).
+
+Existing keys are copied to ~/.ssh/<identity>_key so they don't need to be
+registered again; the originals are left untouched. Identities with a name
+or email and domain already in ~/.config/zzk/git-identities.json are skipped.
+
+Run 'zzk git sync' afterwards. Sync leaves hand-written config alone, so
+remove the old includeIf and Host entries once the new identities work.
+
+With --bundle, identities are imported from a 'zzk git export' bundle
+instead. Sync then generates new keys on this machine; the bundle's public
+keys are added to ~/.ssh/allowed_signers so commits signed on the other
+machine still verify.
+
+Examples:
+  zzk git import -n               # Show proposals only
+  zzk git import                  # Confirm, then add to ~/.config/zzk/git-identities.json
+  zzk git import --new-keys       # Let sync generate fresh keys
+  zzk git import --bundle zzk-identities-laptop.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := gitLoadOrCreateConfig()
+		if err != nil {
+			return err
+		}
+
+		if gitImportBundle != "" {
+			return gitImportFromBundle(config)
+		}
+
+		candidates, err := git.FindImportCandidates()
+		if err != nil {
+			return err
+		}
+
+		var accepted []git.ImportCandidate
+		for _, c := range candidates {
+			if existing := gitImportExisting(config, c.Identity); existing != "" {
+				fmt.Printf("ℹ Skipping %s: already configured as %s\n", c.Source, existing)
+				continue
+			}
+
+			c.Identity.Name = gitImportUniqueName(config, accepted, c.Identity.Name)
+			if !gitIdentityNameRegex.MatchString(c.Identity.Name) {
+				fmt.Printf("✗ Skipping %s: cannot derive a valid name\n", c.Source)
+				continue
+			}
+			if gitImportNewKeys {
+				c.KeyFile = ""
+			}
+
+			fmt.Printf("%s (from %s)\n", c.Identity.Name, c.Source)
+			fmt.Printf("  user:    %s\n", c.Identity.User)
+			fmt.Printf("  email:   %s\n", c.Identity.Email)
+			fmt.Printf("  domain:  %s\n", c.Identity.Domain)
+			fmt.Printf("  folders: %s\n", strings.Join(c.Identity.Folders, ", "))
+			if c.KeyFile != "" {
+				fmt.Printf("  key:     %s → %s\n", c.KeyFile, c.Identity.SSHKeyPath())
+			}
+			for _, note := range c.Notes {
+				fmt.Printf("  ⚠ %s\n", note)
+			}
+
+			if err := c.Identity.Validate(); err != nil {
+				fmt.Printf("  ✗ Skipped: %v\n\n", err)
+				continue
+			}
+			fmt.Println()
+			accepted = append(accepted, c)
+		}
+
+		if len(accepted) == 0 {
+			fmt.Println("No identities to import")
+			return nil
+		}
+		if gitImportDryRun {
+			fmt.Printf("ℹ Would add %d identities (dry run)\n", len(accepted))
+			return nil
+		}
+
+		if !gitImportYes {
+			confirmed, err := claude.PromptYesNo(fmt.Sprintf("Add %d identities to %s?", len(accepted), git.ConfigPath()), true)
+			if err != nil {
+				return fmt.Errorf("%w. Use -y to skip confirmation", err)
+			}
+			if !confirmed {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		for _, c := range accepted {
+			if c.KeyFile != "" && !git.SSHKeyExists(c.Identity) {
+				if err := git.ImportKey(c.Identity, c.KeyFile); err != nil {
+					fmt.Fprintf(os.Stderr, "⚠ Warning: %v, sync will generate a new key\n", err)
+				} else {
+					_, algorithm := git.SSHKeyTypeMatches(c.Identity)
+					c.Identity.KeyType = git.KeyTypeOf(algorithm)
+					fmt.Printf("✓ Copied %s to %s\n", c.KeyFile, c.Identity.SSHKeyPath())
+				}
+			}
+			config.Identities[c.Identity.Name] = c.Identity
+		}
+
+		if err := git.SaveConfig(config); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Added %d identities to %s\n", len(accepted), git.ConfigPath())
+		fmt.Println("Run 'zzk git sync' to apply them")
+		return nil
+	},
+}
+
+// gitImportFromBundle merges the identities of an exported bundle into config
+func gitImportFromBundle(config *git.Config) error {
+	in, err := openInput(gitImportBundle)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	bundle, err := git.ReadBundle(in)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle from %s, created %s", bundle.Manifest.Hostname, humanize.Time(bundle.Manifest.Created))
+	if bundle.State != nil && !bundle.State.LastSync.IsZero() {
+		fmt.Printf(" (last synced %s)", humanize.Time(bundle.State.LastSync))
+	}
+	fmt.Print("\n\n")
+
+	var added []git.Identity
+	for _, identity := range bundle.Config.SortedIdentities() {
+		status := "new"
+		if existing, ok := config.GetIdentity(identity.Name); ok {
+			switch {
+			case gitIdentityEqual(existing, identity):
+				status = "unchanged"
+			case gitImportForce:
+				status = "replace"
+			default:
+				status = "differs, use --force to replace"
+			}
+		}
+		fmt.Printf("  %-20s %-15s %-25s %s\n", identity.Name, identity.Domain, identity.Email, status)
+		if status == "new" || status == "replace" {
+			added = append(added, identity)
+		}
+	}
+	fmt.Println()
+
+	if len(added) == 0 {
+		fmt.Println("No identities to import")
+		return nil
+	}
+	if gitImportDryRun {
+		fmt.Printf("ℹ Would import %d identities (dry run)\n", len(added))
+		return nil
+	}
+	if !gitImportYes {
+		confirmed, err := claude.PromptYesNo(fmt.Sprintf("Import %d identities into %s?", len(added), git.ConfigPath()), true)
+		if err != nil {
+			return fmt.Errorf("%w. Use -y to skip confirmation", err)
+		}
+		if !confirmed {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	for _, identity := range added {
+		config.Identities[identity.Name] = identity
+	}
+	if err := git.SaveConfig(config); err != nil {
+		return err
+	}
+	if err := git.SaveBundleSigners(bundle); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: failed to save the bundle's signing keys: %v\n", err)
+	}
+
+	fmt.Printf("✓ Imported %d identities into %s\n", len(added), git.ConfigPath())
+	fmt.Println("Run 'zzk git sync' to generate keys on this machine, then add them to each forge")
+	return nil
+}
+
+// gitIdentityEqual compares identities by their config entry
+func gitIdentityEqual(a, b git.Identity) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
+
+// gitImportExisting returns the identity already covering a candidate, by name or email and domain
+func gitImportExisting(config *git.Config, identity git.Identity) string {
+	if config.HasIdentity(identity.Name) {
+		existing, _ := config.GetIdentity(identity.Name)
+		if existing.Email == identity.Email {
+			return identity.Name
+		}
+	}
+	for _, existing := range config.SortedIdentities() {
+		if existing.Email == identity.Email && existing.Domain == identity.Domain {
+			return existing.Name
+		}
+	}
+	return ""
+}
+
+// gitImportUniqueName appends a number if name is taken
+func gitImportUniqueName(config *git.Config, accepted []git.ImportCandidate, name string) string {
+	taken := func(n string) bool {
+		if config.HasIdentity(n) {
+			return true
+		}
+		for _, c := range accepted {
+			if c.Identity.Name == n {
+				return true
+			}
+		}
+		return false
+	}
+
+	unique := name
+	for i := 2; taken(unique); i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	return unique
+}
+
+func init() {
+	gitImportCmd.Flags().BoolVarP(&gitImportDryRun, "dry-run", "n", false, "Show proposals without saving")
+	gitImportCmd.Flags().BoolVarP(&gitImportYes, "yes", "y", false, "Add without asking")
+	gitImportCmd.Flags().StringVar(&gitImportBundle, "bundle", "", "Import from a 'zzk git export' bundle ('-' for stdin)")
+	gitImportCmd.Flags().BoolVarP(&gitImportForce, "force", "f", false, "Replace identities that differ (with --bundle)")
+	gitImportCmd.Flags().BoolVar(&gitImportNewKeys, "new-keys", false, "Don't copy existing keys; sync generates new ones")
+	gitCmd.AddCommand(gitImportCmd)
+}
@@ -2,20 +2,37 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/ppowo/zzk/internal/git"
 	"github.com/spf13/cobra"
 )
 
+var (
+	gitInfoDepth   int
+	gitInfoNoRepos bool
+)
+
 var gitInfoCmd = &cobra.Command{
 	Use:   "info <identity>",
 	Short: "Show detailed information about a git identity",
-	Long:  `Displays detailed information about a specific git identity including SSH keys, folders, and status.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Displays detailed information about a specific git identity including SSH keys, folders, and status.
+
+Repositories are found up to --depth levels below each folder. For each
+folder it shows how many there are and their disk usage, then lists the
+repositories with uncommitted changes, ahead of or behind their upstream (as
+of the last fetch) or without one.
+
+Examples:
+  zzk git info github-work
+  zzk git info github-work --depth 5     # Look deeper for nested checkouts
+  zzk git info github-work --no-repos    # Skip the repository scan`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		identityName := args[0]
 
@@ -40,18 +57,24 @@ var gitInfoCmd = &cobra.Command{
 		fmt.Printf("Domain:   %s\n", identity.Domain)
 		fmt.Printf("User:     %s\n", identity.User)
 		fmt.Printf("Email:    %s\n", identity.Email)
+		if host, keys := config.Overridden(identity.Name); host != "" {
+			fmt.Printf("Override: %s (on %s)\n", strings.Join(keys, ", "), host)
+		}
 		fmt.Println()
 
 		sshKeyPath := git.ExpandPath(identity.SSHKeyPath())
 		fmt.Printf("SSH Key:        %s\n", identity.SSHKeyPath())
+		fmt.Printf("  Type:         %s\n", identity.SSHKeyType())
+		if len(identity.KeyOptions) > 0 {
+			fmt.Printf("  Options:      %s\n", strings.Join(identity.KeyOptions, ", "))
+		}
 
 		if _, err := os.Stat(sshKeyPath); err == nil {
-			cmd := exec.Command("ssh-keygen", "-l", "-f", sshKeyPath)
-			if output, err := cmd.Output(); err == nil {
-				fingerprint := strings.TrimSpace(string(output))
-				parts := strings.Fields(fingerprint)
-				if len(parts) >= 2 {
-					fmt.Printf("  Fingerprint:  %s\n", parts[1])
+			state, _ := git.LoadState()
+			if fingerprint, drifted := git.KeyDrift(identity, state); fingerprint != "" {
+				fmt.Printf("  Fingerprint:  %s\n", fingerprint)
+				if drifted {
+					fmt.Printf("  ⚠ Changed since the last sync (was %s)\n", state.Identities[identity.Name].SSHKeyFingerprint)
 				}
 			}
 
@@ -62,6 +85,16 @@ var gitInfoCmd = &cobra.Command{
 		}
 
 		fmt.Printf("  Public key:   %s\n", identity.SSHPubKeyPath())
+		if identity.Agent != "" {
+			agent := identity.Agent
+			if socket := identity.AgentSocket(); socket != agent {
+				agent += " (" + socket + ")"
+			}
+			fmt.Printf("  Agent:        %s\n", agent)
+		}
+		for _, option := range slices.Sorted(maps.Keys(identity.SSHOptions)) {
+			fmt.Printf("  SSH option:   %s %s\n", option, identity.SSHOptions[option])
+		}
 		fmt.Println()
 
 		gitConfigPath := git.ExpandPath(identity.GitConfigPath())
@@ -70,30 +103,62 @@ var gitInfoCmd = &cobra.Command{
 			fmt.Printf("  Status:       ✓ Exists\n")
 			fmt.Printf("  Signing:      Enabled (SSH)\n")
 			fmt.Printf("  SSH command:  ssh -i %s\n", identity.SSHKeyPath())
+			if identity.CommitTemplate != "" {
+				fmt.Printf("  Template:     %s\n", identity.CommitTemplatePath())
+			}
+			for _, alias := range slices.Sorted(maps.Keys(identity.Aliases)) {
+				fmt.Printf("  Alias:        %s = %s\n", alias, identity.Aliases[alias])
+			}
 		} else {
 			fmt.Printf("  Status:       ⚠ Not found\n")
 		}
 		fmt.Println()
 
 		fmt.Printf("Folders (%d):\n", len(identity.Folders))
+		var repos []git.RepoStats
 		for i, folder := range identity.Folders {
 			expandedFolder := git.ExpandPath(folder)
 			fmt.Printf("  %d. %s", i+1, folder)
 
-			if _, err := os.Stat(expandedFolder); err == nil {
-				repoCount := countGitRepos(expandedFolder)
-				if repoCount > 0 {
-					fmt.Printf("  ✓ exists (%d repos)", repoCount)
-				} else {
-					fmt.Printf("  ✓ exists")
+			if _, err := os.Stat(expandedFolder); err != nil {
+				fmt.Println("  ⚠ does not exist")
+				continue
+			}
+			if gitInfoNoRepos {
+				fmt.Println("  ✓ exists")
+				continue
+			}
+
+			stats := git.CollectRepoStats(git.FindRepos(expandedFolder, gitInfoDepth), 0)
+			if len(stats) == 0 {
+				fmt.Println("  ✓ exists")
+				continue
+			}
+			var size int64
+			for j := range stats {
+				size += stats[j].Size
+				if rel, err := filepath.Rel(expandedFolder, stats[j].Path); err == nil {
+					stats[j].Path = filepath.Join(folder, rel)
 				}
-			} else {
-				fmt.Printf("  ⚠ does not exist")
 			}
-			fmt.Println()
+			fmt.Printf("  ✓ exists (%d repos, %s)\n", len(stats), humanize.Bytes(uint64(size)))
+			repos = append(repos, stats...)
 		}
 		fmt.Println()
 
+		if len(repos) > 0 {
+			printRepoHealth(repos)
+			fmt.Println()
+		}
+
+		if len(identity.Remotes) > 0 {
+			fmt.Printf("Remotes (%d):\n", len(identity.Remotes))
+			for i, pattern := range identity.Remotes {
+				fmt.Printf("  %d. %s/%s\n", i+1, identity.Domain, pattern)
+			}
+			fmt.Println()
+		}
+
 		status := "✓ Fully configured"
 		if !git.SSHKeyExists(identity) {
 			status = "⚠ SSH key missing"
@@ -111,26 +176,56 @@ var gitInfoCmd = &cobra.Command{
 }
 
 func init() {
+	gitInfoCmd.Flags().IntVar(&gitInfoDepth, "depth", 3, "How deep to search each folder for repositories")
+	gitInfoCmd.Flags().BoolVar(&gitInfoNoRepos, "no-repos", false, "Skip the repository statistics")
+	gitInfoCmd.ValidArgsFunction = gitCompleteIdentity(0)
 	gitCmd.AddCommand(gitInfoCmd)
 }
 
-func countGitRepos(dir string) int {
-	count := 0
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return 0
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
+// printRepoHealth summarizes which repositories have uncommitted changes or
+// differ from their upstream
+func printRepoHealth(repos []git.RepoStats) {
+	var size int64
+	var dirty, ahead, behind, noUpstream, failed []string
+	clean := 0
+	for _, repo := range repos {
+		size += repo.Size
+		switch {
+		case repo.Err != nil:
+			failed = append(failed, fmt.Sprintf("%s (%v)", repo.Path, repo.Err))
 			continue
+		case repo.Dirty:
+			dirty = append(dirty, repo.Path)
 		}
-
-		gitDir := filepath.Join(dir, entry.Name(), ".git")
-		if _, err := os.Stat(gitDir); err == nil {
-			count++
+		if repo.Ahead > 0 {
+			ahead = append(ahead, fmt.Sprintf("%s (+%d)", repo.Path, repo.Ahead))
+		}
+		if repo.Behind > 0 {
+			behind = append(behind, fmt.Sprintf("%s (-%d)", repo.Path, repo.Behind))
+		}
+		if !repo.Upstream {
+			noUpstream = append(noUpstream, repo.Path)
+		}
+		if !repo.Dirty && repo.Ahead == 0 && repo.Behind == 0 && repo.Upstream {
+			clean++
 		}
 	}
 
-	return count
+	fmt.Printf("Repositories (%d, %s):\n", len(repos), humanize.Bytes(uint64(size)))
+	fmt.Printf("  ✓ %d clean and in sync with upstream\n", clean)
+	printRepoGroup("⚠", "with uncommitted changes", dirty)
+	printRepoGroup("⚠", "ahead of upstream", ahead)
+	printRepoGroup("⚠", "behind upstream (as of the last fetch)", behind)
+	printRepoGroup("ℹ", "without an upstream branch", noUpstream)
+	printRepoGroup("✗", "failed", failed)
+}
+
+func printRepoGroup(symbol, label string, repos []string) {
+	if len(repos) == 0 {
+		return
+	}
+	fmt.Printf("  %s %d %s\n", symbol, len(repos), label)
+	for _, repo := range repos {
+		fmt.Printf("      %s\n", repo)
+	}
 }
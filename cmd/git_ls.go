@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitLsJSON bool
+
+var gitLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List all git identities",
+	Long: `List the identities in ~/.config/zzk/git-identities.json with their email,
+domain and folders. 'zzk git status' adds the key status and last sync time.
+
+With --json, prints the same records as 'zzk git status --json'.
+
+Examples:
+  zzk git ls
+  zzk git ls --json | jq -r '.[].name'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+
+		if gitLsJSON {
+			state, err := git.LoadState()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not load state: %v\n", err)
+				state = nil
+			}
+			printGitIdentityRecords(config, state)
+			return nil
+		}
+
+		if len(config.Identities) == 0 {
+			fmt.Println("No identities configured")
+			return nil
+		}
+		for _, identity := range config.SortedIdentities() {
+			fmt.Printf("%-20s %-30s %-16s %s\n",
+				identity.Name,
+				truncate(identity.Email, 30),
+				identity.Domain,
+				strings.Join(identity.Folders, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitLsCmd.Flags().BoolVar(&gitLsJSON, "json", false, "Print identities as JSON")
+	gitCmd.AddCommand(gitLsCmd)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitMvFolder string
+	gitMvDryRun bool
+	gitMvYes    bool
+)
+
+var gitMvCmd = &cobra.Command{
+	Use:   "mv <repo> <identity>",
+	Short: "Move a repository to another identity's folder",
+	Long: `Move a repository into the first folder of another identity, then make it
+use that identity: SSH remotes on the identity's domain are pointed at its
+host alias, and user.name, user.email, user.signingkey, core.sshCommand and
+'zzk git switch' settings in the repo's .git/config are removed so the
+folder's config applies. The result is checked like 'zzk git audit' does.
+
+A repo already in one of the identity's folders is only fixed up in place.
+
+Examples:
+  zzk git mv ~/Personal/tool github-work              # Move into github-work's first folder
+  zzk git mv . github-work -n                         # Show what would change
+  zzk git mv tool github-work --folder ~/Work/Tools   # Move under another of its folders`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		identity, ok := config.GetIdentity(args[1])
+		if !ok {
+			return fmt.Errorf("identity '%s' not found", args[1])
+		}
+
+		abs, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+		if !git.IsGitRepo(abs) {
+			return fmt.Errorf("%s is not a git repository", args[0])
+		}
+		repo, err := git.RunGit(abs, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return err
+		}
+
+		parent := gitMvFolder
+		if parent == "" {
+			parent = identity.Folders[0]
+		}
+		move, err := git.PlanMove(identity, repo, parent)
+		if err != nil {
+			return err
+		}
+
+		from := "no identity"
+		if current, err := git.DetectIdentity(config, repo); err == nil {
+			from = current.Name
+		}
+		fmt.Printf("%s (%s → %s)\n", repo, from, identity.Name)
+		if move.Dest != move.Repo {
+			fmt.Printf("  move    → %s\n", move.Dest)
+		}
+		for _, key := range move.Unset {
+			value, _ := git.RunGit(repo, "config", "--local", key)
+			fmt.Printf("  unset   %s (%s)\n", key, value)
+		}
+		for _, change := range move.Remotes {
+			fmt.Printf("  remote  %s: %s → %s\n", change.Field, change.Got, change.Want)
+		}
+		if move.Dest == move.Repo && len(move.Unset) == 0 && len(move.Remotes) == 0 {
+			fmt.Printf("✓ Already uses %s\n", identity.Name)
+			return nil
+		}
+
+		if gitMvDryRun {
+			return nil
+		}
+		if !gitMvYes {
+			fmt.Println()
+			ok, err := claude.PromptYesNo(fmt.Sprintf("Move to %s?", identity.Name), true)
+			if err != nil {
+				return fmt.Errorf("%w. Use -y to skip confirmation", err)
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		if err := move.Apply(); err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Printf("✓ Moved to %s\n", move.Dest)
+
+		detected := ""
+		if d, err := git.DetectIdentity(config, move.Dest); err == nil {
+			detected = d.Name
+		}
+		if detected != identity.Name {
+			fmt.Printf("⚠ Identity is %q, expected %q\n", detected, identity.Name)
+		}
+		issues := git.AuditRepo(config, identity, move.Dest)
+		for _, issue := range issues {
+			fmt.Printf("⚠ %s\n", issue.Message)
+		}
+		if detected == identity.Name && len(issues) == 0 {
+			fmt.Printf("✓ Uses %s (%s)\n", identity.Name, identity.Email)
+		} else {
+			fmt.Printf("\nRun 'zzk git sync %s' so the folder picks up the identity's git config\n", identity.Name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitMvCmd.Flags().StringVar(&gitMvFolder, "folder", "", "Move under this folder instead of the identity's first folder")
+	gitMvCmd.Flags().BoolVarP(&gitMvDryRun, "dry-run", "n", false, "Show changes without applying them")
+	gitMvCmd.Flags().BoolVarP(&gitMvYes, "yes", "y", false, "Don't ask for confirmation")
+	gitMvCmd.ValidArgsFunction = gitCompleteIdentity(1)
+	gitCmd.AddCommand(gitMvCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/forge"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitPushKeyTitle     string
+	gitPushKeyNoSigning bool
+)
+
+var gitPushKeyCmd = &cobra.Command{
+	Use:   "push-key <identity>",
+	Short: "Upload an identity's public key to its forge account",
+	Long: `Add the identity's public key to the account on its domain through the
+forge API, both for authentication and, where supported, as a signing key so
+commits show as verified.
+
+The API token is read from the vault secret forge/<domain>/<identity>, then
+forge/<domain> or the domain's environment variable (GITHUB_TOKEN,
+GITLAB_TOKEN, CODEBERG_TOKEN, GITEA_TOKEN). Use the per-identity secret when
+several identities share a domain. GitHub tokens need the admin:public_key
+and admin:ssh_signing_key scopes.
+
+'zzk git sync --upload-keys' does this for newly generated keys.
+
+Examples:
+  zzk vault set forge/github.com/github-work
+  zzk git push-key github-work
+  zzk git push-key codeberg --title "laptop"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		identity, ok := config.GetIdentity(args[0])
+		if !ok {
+			return fmt.Errorf("identity '%s' not found", args[0])
+		}
+
+		return gitPushKey(os.Stdout, identity, gitPushKeyTitle, !gitPushKeyNoSigning)
+	},
+}
+
+// gitPushKey uploads the identity's public key, treating an already added key as success
+func gitPushKey(out io.Writer, identity git.Identity, title string, signing bool) error {
+	data, err := os.ReadFile(git.ExpandPath(identity.SSHPubKeyPath()))
+	if err != nil {
+		return fmt.Errorf("failed to read public key (run 'zzk git sync %s' first): %w", identity.Name, err)
+	}
+
+	token, err := forge.LookupIdentityToken(identity.Domain, identity.Name)
+	if err != nil {
+		return err
+	}
+
+	if title == "" {
+		hostname, _ := os.Hostname()
+		title = fmt.Sprintf("zzk %s (%s)", identity.Name, strings.Split(hostname, ".")[0])
+	}
+
+	client := forge.NewClient(identity.Domain, token)
+	err = client.AddSSHKey(forge.SSHKeyOptions{
+		Title:   title,
+		Key:     strings.TrimSpace(string(data)),
+		Signing: signing,
+	})
+	if errors.Is(err, forge.ErrKeyExists) {
+		fmt.Fprintf(out, "✓ %s key is already on %s\n", identity.Name, identity.Domain)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upload key to %s: %w", identity.Domain, err)
+	}
+	fmt.Fprintf(out, "✓ Uploaded %s to %s as %q\n", identity.SSHPubKeyPath(), identity.Domain, title)
+	return nil
+}
+
+func init() {
+	gitPushKeyCmd.Flags().StringVar(&gitPushKeyTitle, "title", "", "Key title on the forge (default: zzk <identity> (<hostname>))")
+	gitPushKeyCmd.Flags().BoolVar(&gitPushKeyNoSigning, "no-signing", false, "Don't also add the key as a signing key")
+	gitPushKeyCmd.ValidArgsFunction = gitCompleteIdentity(0)
+	gitCmd.AddCommand(gitPushKeyCmd)
+}
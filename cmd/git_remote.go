@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitRemoteFixDryRun bool
+	gitRemoteFixDepth  int
+)
+
+var gitRemoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Manage repository remotes for git identities",
+}
+
+var gitRemoteFixCmd = &cobra.Command{
+	Use:   "fix [repo...]",
+	Short: "Point SSH remotes at the identity's host alias",
+	Long: `Rewrite SSH remotes so they use the SSH host of the identity owning the
+repository. When several identities share a domain, each gets a Host alias
+in ~/.ssh/config (e.g. github.com-work), and remotes must use that alias to
+pick the right key outside of git.
+
+Without arguments, every repository in the identities' folders is checked.
+HTTPS remotes are left alone.
+
+Examples:
+  zzk git remote fix              # Fix all repos in identity folders
+  zzk git remote fix -n           # Show what would change
+  zzk git remote fix .            # Fix the current repo only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+
+		repos := args
+		if len(repos) == 0 {
+			for _, identity := range config.SortedIdentities() {
+				repos = append(repos, git.IdentityRepos(identity, gitRemoteFixDepth)...)
+			}
+		}
+
+		fixed, fixedRepos := 0, 0
+		for _, repo := range repos {
+			n, err := gitRemoteFix(config, repo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ %s: %v\n", repo, err)
+				continue
+			}
+			fixed += n
+			if n > 0 {
+				fixedRepos++
+			}
+		}
+
+		switch {
+		case fixed == 0:
+			fmt.Printf("✓ All remotes in %d repos already use the right host\n", len(repos))
+		case gitRemoteFixDryRun:
+			fmt.Printf("ℹ Would fix %d remotes in %d repos (dry run)\n", fixed, fixedRepos)
+		default:
+			fmt.Printf("✓ Fixed %d remotes in %d repos\n", fixed, fixedRepos)
+		}
+		return nil
+	},
+}
+
+// gitRemoteFix rewrites the remotes of one repo, returning how many changed
+func gitRemoteFix(config *git.Config, repo string) (int, error) {
+	abs, err := filepath.Abs(repo)
+	if err != nil {
+		return 0, err
+	}
+	if !git.IsGitRepo(abs) {
+		return 0, fmt.Errorf("not a git repository")
+	}
+
+	identity, err := git.DetectIdentity(config, abs)
+	if err != nil {
+		return 0, err
+	}
+
+	remotes, err := git.Remotes(abs)
+	if err != nil {
+		return 0, err
+	}
+
+	fixed := 0
+	for _, name := range slices.Sorted(maps.Keys(remotes)) {
+		url := remotes[name]
+		host, repoPath, ok := git.ParseSSHRemote(url)
+		if !ok || host == identity.SSHHost() {
+			continue
+		}
+		// Only touch the identity's domain and its aliases
+		if host != identity.Domain && !strings.HasPrefix(host, identity.Domain+"-") {
+			continue
+		}
+
+		newURL := identity.RemoteURL(repoPath)
+		fmt.Printf("%s (%s) %s: %s → %s\n", abs, identity.Name, name, url, newURL)
+		fixed++
+		if gitRemoteFixDryRun {
+			continue
+		}
+		if err := git.SetRemoteURL(abs, name, newURL); err != nil {
+			return fixed, fmt.Errorf("failed to update remote %s: %w", name, err)
+		}
+	}
+	return fixed, nil
+}
+
+func init() {
+	gitRemoteFixCmd.Flags().BoolVarP(&gitRemoteFixDryRun, "dry-run", "n", false, "Show changes without applying them")
+	gitRemoteFixCmd.Flags().IntVar(&gitRemoteFixDepth, "depth", 3, "Maximum folder depth to search for repos")
+	gitRemoteCmd.AddCommand(gitRemoteFixCmd)
+	gitCmd.AddCommand(gitRemoteCmd)
+}
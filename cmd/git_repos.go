@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitReposCmd = &cobra.Command{
+	Use:   "repos [identity]",
+	Short: "List the repositories zzk knows for each identity",
+	Long: `List the repositories 'zzk git sync' and 'zzk git audit' found in each
+identity's folders, with their remotes and when an audit last found their
+email, name, signing key and remotes matching the identity.
+
+The list is as of the last sync or audit; repositories removed since are
+marked missing.
+
+Examples:
+  zzk git repos                # All identities
+  zzk git repos github-work    # One identity`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		if len(args) == 1 && !config.HasIdentity(args[0]) {
+			return fmt.Errorf("identity '%s' not found", args[0])
+		}
+		state, err := git.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		printed := false
+		for _, identity := range config.SortedIdentities() {
+			if len(args) == 1 && identity.Name != args[0] {
+				continue
+			}
+			identityState, ok := state.Identities[identity.Name]
+			if !ok || len(identityState.Repos) == 0 {
+				continue
+			}
+
+			if printed {
+				fmt.Println()
+			}
+			printed = true
+			fmt.Printf("%s (%d repos)\n", identity.Name, len(identityState.Repos))
+			for _, path := range slices.Sorted(maps.Keys(identityState.Repos)) {
+				printGitRepo(path, identityState.Repos[path])
+			}
+		}
+
+		if !printed {
+			fmt.Println("No repositories recorded yet; run 'zzk git sync' or 'zzk git audit'")
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitReposCmd.ValidArgsFunction = gitCompleteIdentity(0)
+	gitCmd.AddCommand(gitReposCmd)
+}
+
+// printGitRepo prints a recorded repository and its remotes, origin first
+func printGitRepo(path string, repo *git.RepoState) {
+	verified := "never verified"
+	if !repo.LastVerified.IsZero() {
+		verified = "verified " + humanize.Time(repo.LastVerified)
+	}
+	if _, err := os.Stat(git.ExpandPath(path)); err != nil {
+		verified = "⚠ missing"
+	}
+	fmt.Printf("  %-40s %s\n", path, verified)
+
+	names := slices.Sorted(maps.Keys(repo.Remotes))
+	if i := slices.Index(names, "origin"); i > 0 {
+		names = append([]string{"origin"}, slices.Delete(names, i, i+1)...)
+	}
+	for _, name := range names {
+		fmt.Printf("      %-10s %s\n", name, repo.Remotes[name])
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitRmKeepKey bool
+	gitRmDryRun  bool
+	gitRmYes     bool
+)
+
+var gitRmCmd = &cobra.Command{
+	Use:   "rm <identity>",
+	Short: "Remove an identity and what zzk created for it",
+	Long: `Remove an identity from ~/.config/zzk/git-identities.json along with exactly
+what zzk recorded creating for it in ~/.config/zzk/git-state.json: its SSH key pair,
+gitconfig and public key copy, folders zzk created (only if empty), and its
+Host entries and includes in the zzk-managed configs.
+
+Removed files are backed up to ~/.config/zzk/backups first. Identities synced
+before artifacts were recorded fall back to zzk's conventional paths.
+
+Examples:
+  zzk git rm github-work           # Show what will be removed, then confirm
+  zzk git rm github-work -n        # Only show what would be removed
+  zzk git rm github-work --keep-key   # Keep ~/.ssh/github-work_key`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		identity, ok := config.GetIdentity(name)
+		if !ok {
+			return fmt.Errorf("identity '%s' not found", name)
+		}
+
+		artifacts, err := git.IdentityArtifacts(config, name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Removing %s (%s)\n", name, identity.Email)
+		for _, file := range artifacts.Files {
+			if gitRmKeepKey && (file == identity.SSHKeyPath() || file == identity.SSHPubKeyPath()) {
+				fmt.Printf("  keep    %s\n", file)
+				continue
+			}
+			fmt.Printf("  file    %s\n", file)
+		}
+		for _, folder := range artifacts.Folders {
+			fmt.Printf("  folder  %s (if empty)\n", folder)
+		}
+		for _, host := range artifacts.SSHHosts {
+			fmt.Printf("  host    %s in ~/.ssh/config\n", host)
+		}
+		for _, include := range artifacts.GitIncludes {
+			fmt.Printf("  include %s\n", include)
+		}
+
+		if gitRmDryRun {
+			return nil
+		}
+		if !gitRmYes {
+			fmt.Println()
+			ok, err := claude.PromptYesNo(fmt.Sprintf("Remove %s?", name), false)
+			if err != nil {
+				return fmt.Errorf("%w. Use -y to skip confirmation", err)
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		result, err := git.RemoveIdentity(config, name, gitRmKeepKey)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		if result.Backup != "" {
+			fmt.Printf("ℹ Backed up removed files to: %s\n", result.Backup)
+		}
+		fmt.Printf("✓ Removed %s\n", name)
+
+		// Identities that shared the domain may have lost their Host alias
+		for _, other := range config.SortedIdentities() {
+			if other.Domain == identity.Domain && other.SSHHost() == other.Domain {
+				fmt.Printf("ℹ %s now uses the plain %s host; run 'zzk git remote fix' for repos using %s-%s\n",
+					other.Name, other.Domain, other.Domain, other.Name)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitRmCmd.Flags().BoolVar(&gitRmKeepKey, "keep-key", false, "Keep the SSH key pair")
+	gitRmCmd.Flags().BoolVarP(&gitRmDryRun, "dry-run", "n", false, "Show what would be removed")
+	gitRmCmd.Flags().BoolVarP(&gitRmYes, "yes", "y", false, "Don't ask for confirmation")
+	gitRmCmd.ValidArgsFunction = gitCompleteIdentity(0)
+	gitCmd.AddCommand(gitRmCmd)
+}
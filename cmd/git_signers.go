@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitSignersCmd = &cobra.Command{
+	Use:   "signers [identity]",
+	Short: "Print the allowed_signers entries for your identities",
+	Long: `Print the ~/.ssh/allowed_signers lines zzk manages for your identities,
+without zzk's tags, so teammates can add them to their own allowed_signers
+file (or a repo's gpg.ssh.allowedSignersFile) to verify your commits.
+
+Each line lists the identity's principals (its email unless signer.principals
+is set), then namespaces="..." and valid-after="..." if signer.namespaces or
+signer.valid_after are set, then the public key.
+
+Examples:
+  zzk git signers                          # All identities
+  zzk git signers github-work              # One identity
+  zzk git signers github-work >> .allowed_signers`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		if len(args) == 1 && !config.HasIdentity(args[0]) {
+			return fmt.Errorf("identity '%s' not found", args[0])
+		}
+
+		for _, identity := range config.SortedIdentities() {
+			if len(args) == 1 && identity.Name != args[0] {
+				continue
+			}
+			entry, ok := git.AllowedSignerEntry(identity)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "⚠ %s has no public key yet (run 'zzk git sync')\n", identity.Name)
+				continue
+			}
+			fmt.Println(entry)
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitSignersCmd.ValidArgsFunction = gitCompleteIdentity(0)
+	gitCmd.AddCommand(gitSignersCmd)
+}
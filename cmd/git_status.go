@@ -1,19 +1,45 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/ppowo/zzk/internal/git"
 	"github.com/spf13/cobra"
 )
 
+var gitStatusJSON bool
+
+// gitIdentityRecord is an identity as printed by 'zzk git status --json' and
+// 'zzk git ls --json'. Status is active, key_missing, key_changed or
+// config_error.
+type gitIdentityRecord struct {
+	Name        string     `json:"name"`
+	User        string     `json:"user"`
+	Email       string     `json:"email"`
+	Domain      string     `json:"domain"`
+	Folders     []string   `json:"folders"`
+	Status      string     `json:"status"`
+	Fingerprint string     `json:"fingerprint,omitempty"`
+	LastSync    *time.Time `json:"last_sync"`
+}
+
 var gitStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show status of all git identities",
-	Long:  `Shows the status of all git identities from ~/.git-identities.json with their last sync time.`,
+	Long: `Shows the status of all git identities from ~/.config/zzk/git-identities.json with their last sync time.
+
+With --json, prints one record per identity with its status (active,
+key_missing, key_changed or config_error), key fingerprint, folders and last
+sync time (null if never synced).
+
+Examples:
+  zzk git status
+  zzk git status --json | jq -r '.[] | select(.status != "active") | .name'`,
 	Run: func(cmd *cobra.Command, args []string) {
 		config, err := git.LoadConfig()
 		if err != nil {
@@ -29,6 +55,11 @@ var gitStatusCmd = &cobra.Command{
 			state = nil
 		}
 
+		if gitStatusJSON {
+			printGitIdentityRecords(config, state)
+			return
+		}
+
 		if len(config.Identities) == 0 {
 			fmt.Println("No identities configured")
 			return
@@ -39,7 +70,7 @@ var gitStatusCmd = &cobra.Command{
 		fmt.Println(strings.Repeat("-", 135))
 
 		for _, identity := range config.Identities {
-			status := getIdentityStatus(identity)
+			status := getIdentityStatus(identity, state)
 
 			// Get last sync time from state
 			lastSync := "Never"
@@ -92,25 +123,69 @@ var gitStatusCmd = &cobra.Command{
 		fmt.Println("Status Legend:")
 		fmt.Println("  ✓ Active       - Fully configured and ready")
 		fmt.Println("  ⚠ Key missing  - SSH key not found (run: zzk git sync)")
+		fmt.Println("  ⚠ Key changed  - Key fingerprint differs from the last sync (see: zzk git info)")
 		fmt.Println("  ✗ Config error - Git config file missing or invalid")
 	},
 }
 
 func init() {
+	gitStatusCmd.Flags().BoolVar(&gitStatusJSON, "json", false, "Print identities as JSON")
 	gitCmd.AddCommand(gitStatusCmd)
 }
 
-func getIdentityStatus(identity git.Identity) string {
+// gitIdentityStatuses are the labels getIdentityStatus shows for each status
+var gitIdentityStatuses = map[string]string{
+	"active":       "✓ Active",
+	"key_missing":  "⚠ Key missing",
+	"key_changed":  "⚠ Key changed",
+	"config_error": "✗ Config error",
+}
+
+func getIdentityStatus(identity git.Identity, state *git.State) string {
+	return gitIdentityStatuses[identityStatus(identity, state)]
+}
+
+// identityStatus returns the status of an identity as used in JSON output
+func identityStatus(identity git.Identity, state *git.State) string {
 	if !git.SSHKeyExists(identity) {
-		return "⚠ Key missing"
+		return "key_missing"
+	}
+	if _, drifted := git.KeyDrift(identity, state); drifted {
+		return "key_changed"
 	}
 
 	gitConfigPath := git.ExpandPath(identity.GitConfigPath())
 	if _, err := os.Stat(gitConfigPath); os.IsNotExist(err) {
-		return "✗ Config error"
+		return "config_error"
+	}
+
+	return "active"
+}
+
+// printGitIdentityRecords prints every identity as a JSON record, sorted by
+// name. state may be nil.
+func printGitIdentityRecords(config *git.Config, state *git.State) {
+	records := []gitIdentityRecord{}
+	for _, identity := range config.SortedIdentities() {
+		record := gitIdentityRecord{
+			Name:    identity.Name,
+			User:    identity.User,
+			Email:   identity.Email,
+			Domain:  identity.Domain,
+			Folders: identity.Folders,
+			Status:  identityStatus(identity, state),
+		}
+		record.Fingerprint, _ = git.SSHKeyFingerprint(identity)
+		if state != nil {
+			if identityState, ok := state.Identities[identity.Name]; ok && !identityState.LastSync.IsZero() {
+				record.LastSync = &identityState.LastSync
+			}
+		}
+		records = append(records, record)
 	}
 
-	return "✓ Active"
+	data, _ := json.MarshalIndent(records, "", "  ")
+	fmt.Println(string(data))
 }
 
 func truncate(s string, maxLen int) string {
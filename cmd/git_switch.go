@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitSwitchDryRun bool
+
+var gitSwitchCmd = &cobra.Command{
+	Use:   "switch <identity>",
+	Short: "Apply an identity to the current repository",
+	Long: `Apply an identity to the repository in the current directory, for repos
+that can't live in the identity's folders. The repo's .git/config gets the
+identity's user.name, user.email, user.signingkey and core.sshCommand, and
+SSH remotes on the identity's domain are pointed at its host alias.
+
+The identity is recorded in the repo, so 'zzk git where', audit, fix and
+remote fix use it instead of the folder patterns.
+
+Examples:
+  zzk git switch github-work      # Use github-work in this repo
+  zzk git switch github-work -n   # Show what would change`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+		identity, ok := config.GetIdentity(args[0])
+		if !ok {
+			return fmt.Errorf("identity '%s' not found", args[0])
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if !git.IsGitRepo(cwd) {
+			return fmt.Errorf("%s is not a git repository", cwd)
+		}
+		repo, err := git.RunGit(cwd, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return err
+		}
+
+		changes, err := git.SwitchChanges(identity, repo)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Printf("✓ %s already uses %s\n", repo, identity.Name)
+			return nil
+		}
+
+		fmt.Printf("%s → %s\n", repo, identity.Name)
+		for _, change := range changes {
+			if change.Got == "" {
+				fmt.Printf("  %s = %s\n", change.Field, change.Want)
+			} else {
+				fmt.Printf("  %s: %s → %s\n", change.Field, change.Got, change.Want)
+			}
+		}
+		if gitSwitchDryRun {
+			return nil
+		}
+
+		for _, change := range changes {
+			if err := git.ApplySwitchChange(repo, change); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println()
+		fmt.Printf("✓ Switched to %s\n", identity.Name)
+		if !git.SSHKeyExists(identity) {
+			fmt.Printf("⚠ SSH key missing, run 'zzk git sync %s'\n", identity.Name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitSwitchCmd.Flags().BoolVarP(&gitSwitchDryRun, "dry-run", "n", false, "Show changes without applying them")
+	gitSwitchCmd.ValidArgsFunction = gitCompleteIdentity(0)
+	gitCmd.AddCommand(gitSwitchCmd)
+}
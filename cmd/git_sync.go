@@ -1,24 +1,79 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ppowo/zzk/internal/git"
+	"github.com/ppowo/zzk/internal/notify"
+	"github.com/ppowo/zzk/internal/watch"
 	"github.com/spf13/cobra"
 )
 
+var (
+	gitSyncJSON       bool
+	gitSyncUploadKeys bool
+	gitSyncParallel   int
+	gitSyncWatch      bool
+	gitSyncNoHooks    bool
+	gitSyncPruneOnly  bool
+	gitSyncNoPrune    bool
+)
+
 var gitSyncCmd = &cobra.Command{
-	Use:   "sync",
+	Use:   "sync [identity]",
 	Short: "Synchronize git identities from config file",
-	Long: `Reads ~/.git-identities.json and synchronizes your system:
-  - Creates/updates SSH keys
+	Long: `Reads ~/.config/zzk/git-identities.json and synchronizes your system:
+  - Creates/updates SSH keys (several identities at once, see --parallel)
   - Updates git configs
   - Cleans up orphaned identities
-  - Verifies SSH connections
+  - Adds missing host keys to ~/.ssh/known_hosts (checked against the
+    published fingerprints for GitHub, GitLab, Bitbucket and Codeberg)
+  - Verifies SSH connections (in parallel, see --parallel)
+
+Run this command after editing ~/.config/zzk/git-identities.json
+
+With an identity name, only that identity's folders, key, git config and
+connection test are processed, and orphan cleanup is skipped.
+
+With --upload-keys, newly generated public keys are added to the forge
+accounts through the API (see 'zzk git push-key').
+
+With --json, the result (created, updated, verified, orphans removed and
+failures) is printed to stdout as JSON and progress goes to stderr.
 
-Run this command after editing ~/.git-identities.json`,
+Executable hooks in ~/.config/zzk/hooks run around each sync (skip them with
+--no-hooks). git-pre-sync runs first and aborts the sync if it fails;
+git-post-sync runs last with the result as JSON on stdin. Both get
+$ZZK_SYNC_IDENTITIES and $ZZK_GIT_CONFIG, and git-post-sync also
+$ZZK_SYNC_CREATED, $ZZK_SYNC_UPDATED, $ZZK_SYNC_REMOVED and $ZZK_SYNC_FAILED
+(identity names, newline-separated).
+
+Orphans are identities zzk set up that are no longer in the config; their
+files are backed up, then removed. --no-prune only reports them, for routine
+refreshes that must never delete anything, and --prune-only removes them and
+rewrites the global configs without touching the remaining identities.
+
+With --watch, zzk keeps running and syncs again whenever the config file is
+saved: only added or changed identities are synced, or everything when one
+was removed so its orphans are cleaned up. Config errors are reported and
+the previous state is kept until the file is fixed.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		var out io.Writer = os.Stdout
+		if gitSyncJSON {
+			out = os.Stderr
+		}
+
 		config, err := git.LoadConfig()
 		if err != nil {
 			// Check if the config file exists
@@ -27,10 +82,10 @@ Run this command after editing ~/.git-identities.json`,
 				// File exists but has errors - report them without overwriting
 				fmt.Fprintf(os.Stderr, "Error in %s:\n", configPath)
 				fmt.Fprintf(os.Stderr, "%v\n\n", err)
-				fmt.Println("Please fix the errors in the config file and run 'zzk git sync' again.")
-				fmt.Println()
-				fmt.Println("Example identity structure:")
-				fmt.Println(`{
+				fmt.Fprintln(out, "Please fix the errors in the config file and run 'zzk git sync' again.")
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Example identity structure:")
+				fmt.Fprintln(out, `{
   "identities": {
     "github-work": {
       "user": "your-username",
@@ -44,25 +99,152 @@ Run this command after editing ~/.git-identities.json`,
 			} else {
 				// File doesn't exist - create example config
 				fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
-				fmt.Println("Creating example configuration...")
+				fmt.Fprintln(out, "Creating example configuration...")
 				if err := git.CreateExampleConfig(); err != nil {
 					fmt.Fprintf(os.Stderr, "Failed to create example config: %v\n", err)
 					os.Exit(1)
 				}
-				fmt.Printf("Created example config at: %s\n\n", configPath)
-				fmt.Println("Please edit this file with your identities, then run 'zzk git sync' again.")
+				fmt.Fprintf(out, "Created example config at: %s\n\n", configPath)
+				fmt.Fprintln(out, "Please edit this file with your identities, then run 'zzk git sync' again.")
 				os.Exit(0)
 			}
 		}
 
-		_, err = git.Sync(config)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Sync failed: %v\n", err)
+		opts := git.SyncOptions{
+			Out:       out,
+			Verbosity: gitVerbosity(),
+			Parallel:  gitSyncParallel,
+			NoHooks:   gitSyncNoHooks,
+			PruneOnly: gitSyncPruneOnly,
+			NoPrune:   gitSyncNoPrune,
+		}
+		if len(args) == 1 {
+			if gitSyncPruneOnly {
+				fmt.Fprintln(os.Stderr, "--prune-only removes orphans of the whole config and can't be used with an identity")
+				os.Exit(1)
+			}
+			if !config.HasIdentity(args[0]) {
+				fmt.Fprintf(os.Stderr, "Identity '%s' not found\n\n", args[0])
+				fmt.Fprintf(os.Stderr, "Available identities:\n")
+				for name := range config.Identities {
+					fmt.Fprintf(os.Stderr, "  - %s\n", name)
+				}
+				os.Exit(1)
+			}
+			opts.Identities = []string{args[0]}
+		}
+
+		if err := gitSyncRun(config, opts, out); err != nil && !gitSyncWatch {
 			os.Exit(1)
 		}
+		if gitSyncWatch {
+			if err := gitSyncWatchConfig(config, opts, out); err != nil {
+				fmt.Fprintf(os.Stderr, "Watch failed: %v\n", err)
+				os.Exit(1)
+			}
+		}
 	},
 }
 
+// gitSyncRun syncs once, then uploads new keys and prints JSON as requested
+func gitSyncRun(config *git.Config, opts git.SyncOptions, out io.Writer) error {
+	started := time.Now()
+	result, err := git.Sync(config, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Sync failed: %v\n", err)
+		notify.Failed("Git sync failed", err, started)
+		return err
+	}
+
+	if gitSyncUploadKeys {
+		if gitQuiet {
+			out = io.Discard
+		}
+		for _, name := range result.Created {
+			identity, _ := config.GetIdentity(name)
+			if err := gitPushKey(out, identity, "", true); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ Warning: %v\n", err)
+			}
+		}
+	}
+
+	notify.Done("Git sync complete", fmt.Sprintf("%d identities synchronized", len(result.Created)+len(result.Updated)), started)
+
+	if gitSyncJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to encode result: %v\n", err)
+			return err
+		}
+		fmt.Println(string(data))
+	}
+	return nil
+}
+
+// gitSyncWatchConfig re-syncs whenever the config file changes, until
+// interrupted. Only identities that were added or changed are synced, unless
+// one was removed and its orphans need cleaning up.
+func gitSyncWatchConfig(config *git.Config, opts git.SyncOptions, out io.Writer) error {
+	path := git.ConfigPath()
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "\nWatching %s (Ctrl+C to stop)\n", path)
+
+	previous := config
+	// Editors often save by replacing the file, so watch its directory
+	return watch.Watch(ctx, watch.Options{
+		Paths:    []string{filepath.Dir(path)},
+		Debounce: 500 * time.Millisecond,
+		Shallow:  true,
+	}, func(changed []string) {
+		if !slices.Contains(changed, path) {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "\n[%s] %s changed\n", time.Now().Format("15:04:05"), filepath.Base(path))
+		config, err := git.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return
+		}
+
+		runOpts := opts
+		added, updated, removed := git.DiffConfigs(previous, config)
+		switch {
+		case len(removed) > 0:
+			fmt.Fprintf(os.Stderr, "ℹ Removed: %s; syncing everything\n", strings.Join(removed, ", "))
+			runOpts.Identities = nil
+		case len(added)+len(updated) == 0:
+			fmt.Fprintln(os.Stderr, "ℹ No identity changes")
+			previous = config
+			return
+		default:
+			runOpts.Identities = append(added, updated...)
+		}
+
+		if gitSyncRun(config, runOpts, out) == nil {
+			previous = config
+		}
+	})
+}
+
 func init() {
+	gitSyncCmd.Flags().BoolVar(&gitSyncJSON, "json", false, "Print the result as JSON on stdout, progress on stderr")
+	gitSyncCmd.Flags().BoolVar(&gitSyncUploadKeys, "upload-keys", false, "Upload newly generated public keys to the forges")
+	gitSyncCmd.Flags().IntVar(&gitSyncParallel, "parallel", 4, "Number of identities to set up and SSH connection tests to run at once")
+	gitSyncCmd.Flags().BoolVarP(&gitSyncWatch, "watch", "w", false, "Keep running and sync again when the config file changes")
+	gitSyncCmd.MarkFlagsMutuallyExclusive("watch", "json")
+	gitSyncCmd.Flags().BoolVar(&gitSyncPruneOnly, "prune-only", false, "Only remove orphaned identities and update the global configs")
+	gitSyncCmd.Flags().BoolVar(&gitSyncNoPrune, "no-prune", false, "Report orphaned identities but never remove them")
+	gitSyncCmd.MarkFlagsMutuallyExclusive("prune-only", "no-prune")
+	gitSyncCmd.MarkFlagsMutuallyExclusive("prune-only", "watch")
+	gitSyncCmd.MarkFlagsMutuallyExclusive("prune-only", "upload-keys")
+	gitSyncCmd.Flags().BoolVar(&gitSyncNoHooks, "no-hooks", false, "Don't run the git-pre-sync and git-post-sync hooks")
+	gitSyncCmd.ValidArgsFunction = gitCompleteIdentity(0)
 	gitCmd.AddCommand(gitSyncCmd)
 }
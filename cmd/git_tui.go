@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/clipboard"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var gitTuiDepth int
+
+var gitTuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and manage identities interactively",
+	Long: `Show identities with their status, last sync and the repositories in their
+folders, and act on the selected identity:
+
+  ↑/↓, j/k   Select an identity
+  s          Sync it
+  r          Rotate its SSH key (the old key is backed up, a new one generated)
+  p          Show its public key (c copies it to the clipboard)
+  d          Remove it, like 'zzk git rm'
+  R          Reload the config
+  q          Quit
+
+Examples:
+  zzk git tui
+  zzk git tui --depth 1    # Only look for repositories directly in each folder`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+			return fmt.Errorf("zzk git tui needs a terminal; use 'zzk git status' instead")
+		}
+
+		model := &gitTuiModel{}
+		if err := model.load(); err != nil {
+			return err
+		}
+		_, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		return err
+	},
+}
+
+func init() {
+	gitTuiCmd.Flags().IntVar(&gitTuiDepth, "depth", 3, "How deep to search each folder for repositories")
+	gitCmd.AddCommand(gitTuiCmd)
+}
+
+var (
+	gitTuiHeader   = lipgloss.NewStyle().Bold(true)
+	gitTuiSelected = lipgloss.NewStyle().Reverse(true)
+	gitTuiDim      = lipgloss.NewStyle().Faint(true)
+)
+
+// gitTuiReposMsg carries the repositories found in each identity's folders
+type gitTuiReposMsg map[string][]string
+
+// gitTuiDoneMsg reports the outcome of a sync, rotation or removal
+type gitTuiDoneMsg struct {
+	message string
+	err     error
+}
+
+type gitTuiModel struct {
+	config *git.Config
+	state  *git.State
+	names  []string
+	// repos is nil until the folders have been searched
+	repos  map[string][]string
+	cursor int
+
+	// pubKey is the selected identity's public key while it's shown
+	pubKey string
+	// confirm is the action waiting for y/n: "rotate" or "remove"
+	confirm string
+	// busy describes the running action; keys other than q are ignored
+	busy    string
+	message string
+}
+
+// load reads the config and state, keeping the selection if it still exists
+func (m *gitTuiModel) load() error {
+	config, err := git.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+	}
+	state, err := git.LoadState()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	selected := m.selected()
+	m.config, m.state = config, state
+	m.names = m.names[:0]
+	for _, identity := range config.SortedIdentities() {
+		m.names = append(m.names, identity.Name)
+	}
+	m.cursor = 0
+	for i, name := range m.names {
+		if name == selected {
+			m.cursor = i
+		}
+	}
+	m.pubKey = ""
+	return nil
+}
+
+// selected returns the name of the selected identity, or "" if there are none
+func (m *gitTuiModel) selected() string {
+	if m.cursor >= len(m.names) {
+		return ""
+	}
+	return m.names[m.cursor]
+}
+
+func (m *gitTuiModel) Init() tea.Cmd {
+	return m.findRepos()
+}
+
+// findRepos searches every identity's folders for repositories
+func (m *gitTuiModel) findRepos() tea.Cmd {
+	identities := m.config.SortedIdentities()
+	return func() tea.Msg {
+		repos := make(gitTuiReposMsg)
+		for _, identity := range identities {
+			repos[identity.Name] = git.IdentityRepos(identity, gitTuiDepth)
+		}
+		return repos
+	}
+}
+
+func (m *gitTuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case gitTuiReposMsg:
+		m.repos = msg
+		return m, nil
+
+	case gitTuiDoneMsg:
+		m.busy = ""
+		m.message = msg.message
+		if msg.err != nil {
+			m.message = "✗ " + msg.err.Error()
+		}
+		if err := m.load(); err != nil {
+			m.message = "✗ " + err.Error()
+			return m, nil
+		}
+		return m, m.findRepos()
+
+	case tea.KeyMsg:
+		return m.handleKey(msg.String())
+	}
+	return m, nil
+}
+
+func (m *gitTuiModel) handleKey(key string) (tea.Model, tea.Cmd) {
+	if key == "ctrl+c" || (key == "q" && m.confirm == "") {
+		return m, tea.Quit
+	}
+	if m.busy != "" {
+		return m, nil
+	}
+
+	name := m.selected()
+	if m.confirm != "" {
+		action := m.confirm
+		m.confirm = ""
+		if key != "y" || name == "" {
+			m.message = ""
+			return m, nil
+		}
+		identity, _ := m.config.GetIdentity(name)
+		if action == "rotate" {
+			return m, m.run("Rotating "+name+"'s key...", identity.IsSecurityKey(), m.rotate(name))
+		}
+		return m, m.run("Removing "+name+"...", false, m.remove(name))
+	}
+
+	switch key {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.pubKey = ""
+		}
+	case "down", "j":
+		if m.cursor < len(m.names)-1 {
+			m.cursor++
+			m.pubKey = ""
+		}
+	case "R":
+		m.message = ""
+		if err := m.load(); err != nil {
+			m.message = "✗ " + err.Error()
+			return m, nil
+		}
+		m.repos = nil
+		return m, m.findRepos()
+	}
+
+	if name == "" {
+		return m, nil
+	}
+	identity, _ := m.config.GetIdentity(name)
+	switch key {
+	case "s":
+		interactive := identity.IsSecurityKey() && !git.SSHKeyExists(identity)
+		return m, m.run("Syncing "+name+"...", interactive, m.sync(name))
+	case "r":
+		m.confirm = "rotate"
+		m.message = fmt.Sprintf("Rotate %s's SSH key? The old key is backed up and must be removed from %s yourself (y/N)", name, identity.Domain)
+	case "d":
+		m.confirm = "remove"
+		m.message = fmt.Sprintf("Remove %s, its key, gitconfig and SSH hosts? Files are backed up first (y/N)", name)
+	case "p":
+		if m.pubKey != "" {
+			m.pubKey = ""
+			return m, nil
+		}
+		data, err := os.ReadFile(git.ExpandPath(identity.SSHPubKeyPath()))
+		if err != nil {
+			m.message = "✗ No public key yet, sync the identity first"
+			return m, nil
+		}
+		m.pubKey = strings.TrimSpace(string(data))
+		m.message = ""
+	case "c":
+		if m.pubKey == "" {
+			return m, nil
+		}
+		if err := clipboard.Copy(m.pubKey); err != nil {
+			m.message = "✗ " + err.Error()
+		} else {
+			m.message = "✓ Copied " + name + "'s public key"
+		}
+	}
+	return m, nil
+}
+
+// run marks the model busy while action runs in the background. Interactive
+// actions get the terminal instead, as generating a security key needs it for
+// the touch prompt and PIN, and print sync's usual output.
+func (m *gitTuiModel) run(busy string, interactive bool, action func(out io.Writer) tea.Msg) tea.Cmd {
+	m.busy = busy
+	m.message = ""
+	if !interactive {
+		return func() tea.Msg { return action(io.Discard) }
+	}
+	exec := &gitTuiExec{action: action}
+	return tea.Exec(exec, func(error) tea.Msg { return exec.msg })
+}
+
+// gitTuiExec runs an action while the TUI has released the terminal
+type gitTuiExec struct {
+	action func(out io.Writer) tea.Msg
+	msg    tea.Msg
+}
+
+func (e *gitTuiExec) Run() error {
+	e.msg = e.action(os.Stdout)
+	return nil
+}
+
+func (e *gitTuiExec) SetStdin(io.Reader)  {}
+func (e *gitTuiExec) SetStdout(io.Writer) {}
+func (e *gitTuiExec) SetStderr(io.Writer) {}
+
+func (m *gitTuiModel) sync(name string) func(out io.Writer) tea.Msg {
+	config := m.config
+	return func(out io.Writer) tea.Msg {
+		result, err := git.Sync(config, git.SyncOptions{Identities: []string{name}, Out: out})
+		if err != nil {
+			return gitTuiDoneMsg{err: err}
+		}
+		return gitTuiDoneMsg{message: gitTuiSyncMessage(name, result)}
+	}
+}
+
+func (m *gitTuiModel) rotate(name string) func(out io.Writer) tea.Msg {
+	config := m.config
+	identity, _ := config.GetIdentity(name)
+	return func(out io.Writer) tea.Msg {
+		backup, err := git.RotateKey(identity)
+		if err != nil {
+			return gitTuiDoneMsg{err: err}
+		}
+		result, err := git.Sync(config, git.SyncOptions{Identities: []string{name}, Out: out})
+		if err == nil {
+			err = result.Failed[name]
+		}
+		if err != nil {
+			return gitTuiDoneMsg{err: fmt.Errorf("old key backed up to %s, but sync failed: %w", backup, err)}
+		}
+		return gitTuiDoneMsg{message: fmt.Sprintf("✓ Generated a new key for %s (old key backed up to %s); upload it with 'zzk git push-key %s'", name, backup, name)}
+	}
+}
+
+func (m *gitTuiModel) remove(name string) func(out io.Writer) tea.Msg {
+	config := m.config
+	return func(io.Writer) tea.Msg {
+		result, err := git.RemoveIdentity(config, name, false)
+		if err != nil {
+			return gitTuiDoneMsg{err: err}
+		}
+		if result.Backup != "" {
+			return gitTuiDoneMsg{message: fmt.Sprintf("✓ Removed %s (backed up to %s)", name, result.Backup)}
+		}
+		return gitTuiDoneMsg{message: "✓ Removed " + name}
+	}
+}
+
+// gitTuiSyncMessage summarizes a single-identity sync in one line
+func gitTuiSyncMessage(name string, result *git.SyncResult) string {
+	if err := result.Failed[name]; err != nil {
+		return fmt.Sprintf("✗ Failed to sync %s: %v", name, err)
+	}
+	if reason, ok := result.Unverified[name]; ok {
+		return fmt.Sprintf("⚠ Synced %s, but the connection failed: %s", name, reason)
+	}
+	for _, created := range result.Created {
+		if created == name {
+			return fmt.Sprintf("✓ Generated a key for %s; upload it with 'zzk git push-key %s'", name, name)
+		}
+	}
+	return fmt.Sprintf("✓ Synced %s, connection verified", name)
+}
+
+func (m *gitTuiModel) View() string {
+	var b strings.Builder
+	b.WriteString(gitTuiHeader.Render(fmt.Sprintf("%-20s %-28s %-16s %-15s %-15s %s",
+		"IDENTITY", "EMAIL", "DOMAIN", "STATUS", "LAST SYNC", "REPOS")))
+	b.WriteString("\n")
+
+	if len(m.names) == 0 {
+		b.WriteString(gitTuiDim.Render("No identities configured; add one with 'zzk git add'"))
+		b.WriteString("\n")
+	}
+	for i, name := range m.names {
+		identity, _ := m.config.GetIdentity(name)
+		repos := "…"
+		if m.repos != nil {
+			repos = fmt.Sprint(len(m.repos[name]))
+		}
+		row := fmt.Sprintf("%-20s %-28s %-16s %-15s %-15s %s",
+			truncate(name, 20),
+			truncate(identity.Email, 28),
+			truncate(identity.Domain, 16),
+			getIdentityStatus(identity, m.state),
+			m.lastSync(name),
+			repos)
+		if i == m.cursor {
+			row = gitTuiSelected.Render(row)
+		}
+		b.WriteString(row + "\n")
+	}
+
+	if name := m.selected(); name != "" {
+		b.WriteString("\n")
+		b.WriteString(m.details(name))
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.busy != "":
+		b.WriteString(m.busy + "\n")
+	case m.message != "":
+		b.WriteString(m.message + "\n")
+	default:
+		b.WriteString("\n")
+	}
+	b.WriteString(gitTuiDim.Render("↑/↓ select · s sync · r rotate key · p public key · d remove · R reload · q quit"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m *gitTuiModel) lastSync(name string) string {
+	if identityState, ok := m.state.Identities[name]; ok && !identityState.LastSync.IsZero() {
+		return humanize.Time(identityState.LastSync)
+	}
+	return "Never"
+}
+
+// details describes the selected identity: its folders, key and repositories
+func (m *gitTuiModel) details(name string) string {
+	identity, _ := m.config.GetIdentity(name)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s <%s>\n", identity.User, identity.Email)
+	fmt.Fprintf(&b, "Folders: %s\n", strings.Join(identity.Folders, ", "))
+	if fingerprint, err := git.SSHKeyFingerprint(identity); err == nil {
+		fmt.Fprintf(&b, "Key:     %s (%s)\n", identity.SSHKeyPath(), fingerprint)
+	} else {
+		fmt.Fprintf(&b, "Key:     %s (missing)\n", identity.SSHKeyPath())
+	}
+
+	if m.pubKey != "" {
+		b.WriteString("\n" + m.pubKey + "\n")
+		b.WriteString(gitTuiDim.Render("c copy · p hide") + "\n")
+		return b.String()
+	}
+
+	switch repos := m.repos[name]; {
+	case m.repos == nil:
+		b.WriteString(gitTuiDim.Render("Looking for repositories...") + "\n")
+	case len(repos) == 0:
+		b.WriteString(gitTuiDim.Render("No repositories") + "\n")
+	default:
+		const shown = 8
+		fmt.Fprintf(&b, "Repositories (%d):\n", len(repos))
+		for i, repo := range repos {
+			if i == shown {
+				fmt.Fprintf(&b, "  … and %d more\n", len(repos)-shown)
+				break
+			}
+			fmt.Fprintf(&b, "  %s\n", repo)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var gitVerifyCmd = &cobra.Command{
+	Use:   "verify [identity]",
+	Short: "Check that commit signing works for an identity",
+	Long: `Prove that SSH commit signing works end to end: in a throwaway repo inside
+the identity's folder, check that the identity's config applies, make a
+signed empty commit and verify it against ~/.ssh/allowed_signers. The repo
+is removed afterwards.
+
+Without an identity, every identity is checked. Security keys need a touch
+for each signature.
+
+Examples:
+  zzk git verify                  # Check all identities
+  zzk git verify github-work      # Check one identity`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", git.ConfigPath(), err)
+		}
+
+		identities := config.SortedIdentities()
+		if len(args) == 1 {
+			identity, ok := config.GetIdentity(args[0])
+			if !ok {
+				return fmt.Errorf("identity '%s' not found", args[0])
+			}
+			identities = []git.Identity{identity}
+		}
+
+		failed := 0
+		for i, identity := range identities {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s (%s)\n", identity.Name, identity.Email)
+			if identity.IsSecurityKey() {
+				fmt.Println("  ℹ Touch your security key when it blinks")
+			}
+			for _, check := range git.VerifySigning(identity) {
+				if check.Err != nil {
+					fmt.Printf("  ✗ %s: %v\n", check.Name, check.Err)
+					failed++
+					continue
+				}
+				if check.Detail != "" {
+					fmt.Printf("  ✓ %s: %s\n", check.Name, check.Detail)
+				} else {
+					fmt.Printf("  ✓ %s\n", check.Name)
+				}
+			}
+		}
+
+		fmt.Println()
+		if failed > 0 {
+			fmt.Printf("✗ Signing failed for %d of %d identities\n", failed, len(identities))
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Signing works for %d identities\n", len(identities))
+		return nil
+	},
+}
+
+func init() {
+	gitVerifyCmd.ValidArgsFunction = gitCompleteIdentity(0)
+	gitCmd.AddCommand(gitVerifyCmd)
+}
@@ -55,7 +55,11 @@ var gitWhereCmd = &cobra.Command{
 
 		fmt.Println()
 		fmt.Printf("Git config:  %s\n", identity.GitConfigPath())
-		fmt.Printf("Applied via: [includeIf \"gitdir:%s/\"]\n", matchedFolder)
+		if matchedFolder != "" {
+			fmt.Printf("Applied via: [includeIf \"gitdir:%s/\"]\n", matchedFolder)
+		} else {
+			fmt.Println("Applied via: zzk git switch (.git/config)")
+		}
 		fmt.Println()
 
 		fmt.Println("Verification:")
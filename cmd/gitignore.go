@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/gitignore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitignoreRefresh bool
+	gitignoreOutput  string
+)
+
+var gitignoreCmd = &cobra.Command{
+	Use:   "gitignore <template...>",
+	Short: "Merge gitignore templates into ./.gitignore",
+	Long: `Fetch templates from the github/gitignore collection and merge them into
+./.gitignore.
+
+Rules are appended under a "### <Template> ###" header. Existing content is
+never removed and rules that are already present are skipped, so running
+the command again is safe.
+
+Templates are cached in ~/.cache/zzk/gitignore. Use --refresh to re-download.
+
+Examples:
+  zzk gitignore go macos           # Go + macOS rules
+  zzk gitignore node vscode        # Node + VS Code rules
+  zzk gitignore python --refresh   # Re-fetch the Python template`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var templates []*gitignore.Template
+		for _, name := range args {
+			tmpl, err := gitignore.Fetch(name, gitignoreRefresh)
+			if err != nil {
+				return err
+			}
+			templates = append(templates, tmpl)
+		}
+
+		var existing string
+		if data, err := os.ReadFile(gitignoreOutput); err == nil {
+			existing = string(data)
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", gitignoreOutput, err)
+		}
+
+		content, added := gitignore.Merge(existing, templates)
+
+		total := 0
+		for _, tmpl := range templates {
+			count := added[tmpl.Name]
+			total += count
+			if count == 0 {
+				fmt.Printf("  %s: already up to date\n", tmpl.Name)
+			} else {
+				fmt.Printf("  %s: added %d rules\n", tmpl.Name, count)
+			}
+		}
+
+		if total == 0 {
+			fmt.Printf("✓ %s already contains all rules\n", gitignoreOutput)
+			return nil
+		}
+
+		if err := os.WriteFile(gitignoreOutput, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", gitignoreOutput, err)
+		}
+
+		fmt.Printf("✓ Updated %s (%d new rules)\n", gitignoreOutput, total)
+		return nil
+	},
+}
+
+func init() {
+	gitignoreCmd.Flags().BoolVar(&gitignoreRefresh, "refresh", false, "Re-download templates instead of using the cache")
+	gitignoreCmd.Flags().StringVarP(&gitignoreOutput, "output", "o", ".gitignore", "File to merge into")
+	rootCmd.AddCommand(gitignoreCmd)
+}
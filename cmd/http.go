@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/httpreq"
+	"github.com/spf13/cobra"
+)
+
+var (
+	httpHeaders  []string
+	httpData     string
+	httpProvider string
+	httpTiming   bool
+	httpVerbose  bool
+	httpBodyOnly bool
+	httpTimeout  time.Duration
+)
+
+var httpCmd = &cobra.Command{
+	Use:   "http [method] <url> [item...]",
+	Short: "Send HTTP requests and pretty-print responses",
+	Long: `A small curl replacement for poking at HTTP endpoints.
+
+The method defaults to GET, or POST when a body is given. Request items use
+httpie-style shorthand:
+  Header:value    Request header
+  key==value      Query parameter
+  key=value       JSON string field
+  key:=json       Raw JSON field (numbers, booleans, arrays, objects)
+
+JSON fields are sent as a JSON object with Content-Type: application/json.
+Use --data for a raw body ('@file' reads a file, '-' reads stdin).
+
+--provider injects the auth header of a configured Claude provider (the
+active one when no name is given) and resolves URLs starting with '/' against
+the provider's base URL.
+
+Examples:
+  zzk http example.com                                # GET, https:// assumed
+  zzk http localhost:8080/health                      # http:// for localhost
+  zzk http POST httpbin.org/post name=zzk count:=3    # JSON body
+  zzk http httpbin.org/get q==search Accept:text/plain
+  zzk http --timing https://example.com               # Timing breakdown
+  zzk http --provider /v1/models                      # Active Claude provider
+  zzk http --provider=openrouter POST /v1/messages model=x max_tokens:=16 messages:='[{"role":"user","content":"hi"}]'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method := ""
+		if httpreq.Methods[strings.ToUpper(args[0])] && len(args) > 1 {
+			method = strings.ToUpper(args[0])
+			args = args[1:]
+		}
+		target := args[0]
+
+		items, err := httpreq.ParseItems(args[1:])
+		if err != nil {
+			return err
+		}
+		for _, h := range httpHeaders {
+			key, value, ok := strings.Cut(h, ":")
+			if !ok {
+				return fmt.Errorf("invalid header %q (expected Name: value)", h)
+			}
+			items.Headers.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+		}
+
+		body, err := items.Body()
+		if err != nil {
+			return err
+		}
+		if httpData != "" {
+			if body != nil {
+				return fmt.Errorf("--data cannot be combined with JSON fields")
+			}
+			body, err = httpReadData(httpData)
+			if err != nil {
+				return err
+			}
+		} else if body != nil && items.Headers.Get("Content-Type") == "" {
+			items.Headers.Set("Content-Type", "application/json")
+		}
+
+		if httpProvider != "" {
+			target, err = httpApplyProvider(target, items.Headers)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !strings.Contains(target, "://") {
+			if strings.HasPrefix(target, "localhost") || strings.HasPrefix(target, "127.0.0.1") {
+				target = "http://" + target
+			} else {
+				target = "https://" + target
+			}
+		}
+
+		if method == "" {
+			method = http.MethodGet
+			if body != nil {
+				method = http.MethodPost
+			}
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequest(method, target, reader)
+		if err != nil {
+			return fmt.Errorf("invalid request: %w", err)
+		}
+		query := req.URL.Query()
+		for key, values := range items.Query {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+		for key, values := range items.Headers {
+			req.Header[key] = values
+		}
+		if req.Header.Get("User-Agent") == "" {
+			req.Header.Set("User-Agent", "zzk-http/1.0")
+		}
+
+		if httpVerbose {
+			fmt.Fprintf(os.Stderr, "> %s %s\n", req.Method, req.URL)
+			httpPrintHeaders(os.Stderr, "> ", req.Header)
+			fmt.Fprintln(os.Stderr)
+		}
+
+		client := &http.Client{Timeout: httpTimeout}
+		resp, err := httpreq.Do(client, req)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+
+		if !httpBodyOnly {
+			symbol := "✓"
+			if resp.StatusCode >= 400 {
+				symbol = "✗"
+			} else if resp.StatusCode >= 300 {
+				symbol = "ℹ"
+			}
+			fmt.Fprintf(os.Stderr, "%s %s %s\n", symbol, resp.Proto, resp.Status)
+			if httpVerbose {
+				httpPrintHeaders(os.Stderr, "", resp.Header)
+			}
+			fmt.Fprintln(os.Stderr)
+		}
+
+		os.Stdout.Write(resp.PrettyBody())
+		if len(resp.Body) > 0 && !bytes.HasSuffix(resp.Body, []byte("\n")) {
+			fmt.Println()
+		}
+
+		if httpTiming {
+			t := resp.Timing
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintf(os.Stderr, "  DNS lookup:   %8s\n", t.DNS.Round(time.Millisecond))
+			fmt.Fprintf(os.Stderr, "  TCP connect:  %8s\n", t.Connect.Round(time.Millisecond))
+			fmt.Fprintf(os.Stderr, "  TLS:          %8s\n", t.TLS.Round(time.Millisecond))
+			fmt.Fprintf(os.Stderr, "  Server:       %8s\n", t.Server.Round(time.Millisecond))
+			fmt.Fprintf(os.Stderr, "  Transfer:     %8s\n", t.Transfer.Round(time.Millisecond))
+			fmt.Fprintf(os.Stderr, "  Total:        %8s\n", t.Total.Round(time.Millisecond))
+		}
+
+		return nil
+	},
+}
+
+// httpApplyProvider adds the Claude provider's auth header and resolves relative URLs against its base URL
+func httpApplyProvider(target string, headers http.Header) (string, error) {
+	config, err := claude.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	id := httpProvider
+	if id == "active" {
+		if config.Active == "" {
+			return "", fmt.Errorf("no active provider - use --provider=<name> or 'zzk claude use <name>'")
+		}
+		id = config.Active
+	} else if id, err = claude.ResolveProviderID(id); err != nil {
+		return "", err
+	}
+
+	provider, err := config.ResolveKey(id)
+	if err != nil {
+		return "", err
+	}
+	tmpl, _ := claude.TemplateFor(id)
+
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimSuffix(provider.BaseURL(tmpl), "/") + target
+	}
+
+	if headers.Get("Authorization") == "" {
+		headers.Set("Authorization", "Bearer "+provider.APIKey)
+	}
+	if headers.Get("anthropic-version") == "" {
+		headers.Set("anthropic-version", "2023-06-01")
+	}
+	return target, nil
+}
+
+// httpReadData reads a raw body: '@file' from a file, '-' from stdin, otherwise the literal value
+func httpReadData(data string) ([]byte, error) {
+	switch {
+	case data == "-":
+		return io.ReadAll(os.Stdin)
+	case strings.HasPrefix(data, "@"):
+		content, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %w", err)
+		}
+		return content, nil
+	default:
+		return []byte(data), nil
+	}
+}
+
+func httpPrintHeaders(w io.Writer, prefix string, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := strings.Join(header[key], ", ")
+		if strings.EqualFold(key, "Authorization") || strings.EqualFold(key, "x-api-key") {
+			value = truncate(value, 14)
+		}
+		fmt.Fprintf(w, "%s%s: %s\n", prefix, key, value)
+	}
+}
+
+func init() {
+	httpCmd.Flags().StringArrayVarP(&httpHeaders, "header", "H", nil, "Request header 'Name: value' (repeatable)")
+	httpCmd.Flags().StringVarP(&httpData, "data", "d", "", "Raw request body ('@file' or '-' for stdin)")
+	httpCmd.Flags().StringVar(&httpProvider, "provider", "", "Inject auth for a Claude provider (default: active)")
+	httpCmd.Flags().Lookup("provider").NoOptDefVal = "active"
+	httpCmd.Flags().BoolVarP(&httpTiming, "timing", "t", false, "Show a timing breakdown")
+	httpCmd.Flags().BoolVarP(&httpVerbose, "verbose", "v", false, "Show request and response headers")
+	httpCmd.Flags().BoolVarP(&httpBodyOnly, "body", "b", false, "Print only the response body")
+	httpCmd.Flags().DurationVar(&httpTimeout, "timeout", 30*time.Second, "Request timeout")
+	rootCmd.AddCommand(httpCmd)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/jsonpath"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jsonIndent int
+	jsonQuoted bool
+)
+
+var jsonCmd = &cobra.Command{
+	Use:   "json [file]",
+	Short: "Pretty-print, minify and query JSON",
+	Long: `Pretty-print JSON from a file or stdin, preserving key order.
+
+Paths use a simple dotted syntax: .key.nested, .list[0], .list.0, and
+.["key.with.dots"] for keys containing dots. Negative indexes count from
+the end.
+
+Examples:
+  zzk json ~/.claude-providers.json                         # Pretty-print
+  curl -s api.example.com | zzk json                        # From stdin
+  zzk json min data.json                                    # Minify
+  zzk json get .identities.github-work.email ~/.config/zzk/git-identities.json
+  zzk json get '.items[-1].name' data.json                  # Last element
+  zzk json get --json .active ~/.claude-providers.json      # Keep quotes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jsonIndent < 0 {
+			return fmt.Errorf("--indent must not be negative")
+		}
+		data, err := jsonReadInput(args)
+		if err != nil {
+			return err
+		}
+		return jsonPrint(data)
+	},
+}
+
+var jsonMinCmd = &cobra.Command{
+	Use:   "min [file]",
+	Short: "Minify JSON",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := jsonReadInput(args)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		fmt.Println(buf.String())
+		return nil
+	},
+}
+
+var jsonGetCmd = &cobra.Command{
+	Use:   "get <path> [file]",
+	Short: "Extract a value by path (strings are printed unquoted)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jsonIndent < 0 {
+			return fmt.Errorf("--indent must not be negative")
+		}
+		data, err := jsonReadInput(args[1:])
+		if err != nil {
+			return err
+		}
+
+		value, err := jsonpath.Get(data, args[0])
+		if err != nil {
+			return err
+		}
+
+		if !jsonQuoted && strings.HasPrefix(string(bytes.TrimSpace(value)), `"`) {
+			s, err := jsonpath.Raw(value)
+			if err != nil {
+				return err
+			}
+			fmt.Println(s)
+			return nil
+		}
+		return jsonPrint(value)
+	},
+}
+
+// jsonReadInput reads the named file, or stdin if none (or "-") is given
+func jsonReadInput(args []string) ([]byte, error) {
+	if len(args) == 0 || args[0] == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	return data, nil
+}
+
+func jsonPrint(data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", strings.Repeat(" ", jsonIndent)); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	fmt.Println(buf.String())
+	return nil
+}
+
+func init() {
+	jsonCmd.PersistentFlags().IntVar(&jsonIndent, "indent", 2, "Spaces per indentation level")
+	jsonGetCmd.Flags().BoolVar(&jsonQuoted, "json", false, "Print strings as quoted JSON")
+	jsonCmd.AddCommand(jsonMinCmd)
+	jsonCmd.AddCommand(jsonGetCmd)
+	rootCmd.AddCommand(jsonCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/ppowo/zzk/internal/jump"
+	"github.com/spf13/cobra"
+)
+
+var jumpCmd = &cobra.Command{
+	Use:   "jump",
+	Short: "Directory bookmarks",
+	Long: `Bookmark directories and print their paths for quick navigation.
+
+Bookmarks are stored in ~/.config/zzk/jump.json.
+
+Add a tiny shell function to jump with one word:
+  j() { cd "$(zzk jump get "$1")"; }
+
+Examples:
+  zzk jump add work ~/Work/Github/big-repo   # Bookmark a directory
+  zzk jump add here                          # Bookmark the current directory
+  zzk jump get work                          # Print the path
+  zzk jump ls                                # List bookmarks with identities
+  zzk jump rm work                           # Remove a bookmark`,
+}
+
+var jumpAddCmd = &cobra.Command{
+	Use:   "add <name> [path]",
+	Short: "Bookmark a directory (default: current directory)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 2 {
+			dir = git.ExpandPath(args[1])
+		}
+
+		store, err := jump.Load()
+		if err != nil {
+			return err
+		}
+
+		_, existed := store.Get(args[0])
+		path, err := store.Add(args[0], dir)
+		if err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+
+		if existed {
+			fmt.Printf("✓ Updated %s → %s\n", args[0], path)
+		} else {
+			fmt.Printf("✓ Added %s → %s\n", args[0], path)
+		}
+		return nil
+	},
+}
+
+var jumpGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print the path of a bookmark",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := jump.Load()
+		if err != nil {
+			return err
+		}
+
+		path, ok := store.Get(args[0])
+		if !ok {
+			return fmt.Errorf("bookmark '%s' not found", args[0])
+		}
+
+		fmt.Println(path)
+		return nil
+	},
+}
+
+var jumpLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List bookmarks",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := jump.Load()
+		if err != nil {
+			return err
+		}
+
+		if len(store.Bookmarks) == 0 {
+			fmt.Println("No bookmarks (add one with 'zzk jump add <name> [path]')")
+			return nil
+		}
+
+		// Identity annotation is best-effort
+		config, _ := git.LoadConfig()
+
+		for _, name := range store.Names() {
+			path := store.Bookmarks[name]
+
+			identity := ""
+			if config != nil {
+				if id, err := git.DetectIdentity(config, path); err == nil {
+					identity = "[" + id.Name + "]"
+				}
+			}
+
+			missing := ""
+			if _, err := os.Stat(path); err != nil {
+				missing = "  ⚠ missing"
+			}
+
+			fmt.Printf("  %-15s %-20s %s%s\n", name, identity, path, missing)
+		}
+		return nil
+	},
+}
+
+var jumpRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a bookmark",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := jump.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := store.Remove(args[0]); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Removed %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	jumpCmd.AddCommand(jumpAddCmd)
+	jumpCmd.AddCommand(jumpGetCmd)
+	jumpCmd.AddCommand(jumpLsCmd)
+	jumpCmd.AddCommand(jumpRmCmd)
+	rootCmd.AddCommand(jumpCmd)
+}
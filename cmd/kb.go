@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/clipboard"
+	"github.com/ppowo/zzk/internal/kb"
+	"github.com/spf13/cobra"
+)
+
+var (
+	kbDescription string
+	kbTags        []string
+	kbTag         string
+	kbCopy        bool
+	kbYes         bool
+)
+
+var kbCmd = &cobra.Command{
+	Use:   "kb",
+	Short: "Store, search and run command snippets",
+	Long: `A personal cheatsheet of command snippets, stored in ~/.config/zzk/kb.json
+so it is backed up along with the rest of your zzk config.
+
+Examples:
+  zzk kb add ports 'lsof -iTCP -sTCP:LISTEN -n -P' -d "Listening ports" -t net
+  echo 'git log --oneline --graph --all' | zzk kb add graph -t git
+  zzk kb ls                        # All snippets
+  zzk kb ls -t git                 # Snippets tagged git
+  zzk kb search listen port        # Fuzzy search
+  zzk kb show ports --copy         # Print and copy to the clipboard
+  zzk kb run ports                 # Run after confirmation
+  zzk kb rm ports`,
+}
+
+var kbAddCmd = &cobra.Command{
+	Use:   "add <name> [command]",
+	Short: "Add or replace a snippet (command from stdin if omitted)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		command := ""
+		if len(args) == 2 {
+			command = args[1]
+		} else {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			command = strings.TrimSpace(string(data))
+		}
+
+		store, err := kb.Load()
+		if err != nil {
+			return err
+		}
+
+		_, existed := store.Get(args[0])
+		snippet := &kb.Snippet{Name: args[0], Command: command, Description: kbDescription, Tags: kbTags}
+		if err := store.Add(snippet); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save snippets: %w", err)
+		}
+
+		if existed {
+			fmt.Printf("✓ Updated snippet '%s'\n", snippet.Name)
+		} else {
+			fmt.Printf("✓ Added snippet '%s'\n", snippet.Name)
+		}
+		return nil
+	},
+}
+
+var kbLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List snippets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := kb.Load()
+		if err != nil {
+			return err
+		}
+
+		snippets := store.List(kbTag)
+		if len(snippets) == 0 {
+			fmt.Println("No snippets found")
+			return nil
+		}
+		kbPrintList(snippets)
+		return nil
+	},
+}
+
+var kbSearchCmd = &cobra.Command{
+	Use:     "search <query...>",
+	Aliases: []string{"find", "s"},
+	Short:   "Fuzzy-search snippets by name, tags, description and command",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := kb.Load()
+		if err != nil {
+			return err
+		}
+
+		snippets := store.Search(strings.Join(args, " "))
+		if len(snippets) == 0 {
+			fmt.Println("No matching snippets")
+			return nil
+		}
+		kbPrintList(snippets)
+		return nil
+	},
+}
+
+var kbShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a snippet's command",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, err := kbFind(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(snippet.Command)
+		if kbCopy {
+			if err := clipboard.Copy(snippet.Command); err != nil {
+				return fmt.Errorf("failed to copy to clipboard: %w", err)
+			}
+			fmt.Fprintln(os.Stderr, "✓ Copied to clipboard")
+		}
+		return nil
+	},
+}
+
+var kbRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a snippet in the shell after confirmation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, err := kbFind(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("$ %s\n", snippet.Command)
+		if !kbYes {
+			confirmed, err := claude.PromptYesNo("Run this command?", false)
+			if err != nil {
+				return fmt.Errorf("%w. Use -y to skip confirmation", err)
+			}
+			if !confirmed {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		var c *exec.Cmd
+		if runtime.GOOS == "windows" {
+			c = exec.Command("cmd", "/C", snippet.Command)
+		} else {
+			shell := os.Getenv("SHELL")
+			if shell == "" {
+				shell = "sh"
+			}
+			c = exec.Command(shell, "-c", snippet.Command)
+		}
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				os.Exit(exitErr.ExitCode())
+			}
+			return fmt.Errorf("failed to run snippet: %w", err)
+		}
+		return nil
+	},
+}
+
+var kbRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a snippet",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := kb.Load()
+		if err != nil {
+			return err
+		}
+		if err := store.Remove(args[0]); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save snippets: %w", err)
+		}
+		fmt.Printf("✓ Removed snippet '%s'\n", args[0])
+		return nil
+	},
+}
+
+// kbFind returns the named snippet, or the single best search match
+func kbFind(name string) (*kb.Snippet, error) {
+	store, err := kb.Load()
+	if err != nil {
+		return nil, err
+	}
+	if snippet, ok := store.Get(name); ok {
+		return snippet, nil
+	}
+
+	matches := store.Search(name)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("snippet '%s' not found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, 5)
+		for i, m := range matches {
+			if i == 5 {
+				break
+			}
+			names = append(names, m.Name)
+		}
+		return nil, fmt.Errorf("snippet '%s' not found - did you mean: %s", name, strings.Join(names, ", "))
+	}
+}
+
+func kbPrintList(snippets []*kb.Snippet) {
+	for _, snippet := range snippets {
+		tags := ""
+		if len(snippet.Tags) > 0 {
+			tags = " [" + strings.Join(snippet.Tags, ", ") + "]"
+		}
+		if snippet.Description != "" {
+			fmt.Printf("%s%s — %s\n", snippet.Name, tags, snippet.Description)
+		} else {
+			fmt.Printf("%s%s\n", snippet.Name, tags)
+		}
+		fmt.Printf("  $ %s\n", truncate(strings.ReplaceAll(snippet.Command, "\n", " ⏎ "), 100))
+	}
+}
+
+func init() {
+	kbAddCmd.Flags().StringVarP(&kbDescription, "description", "d", "", "Description")
+	kbAddCmd.Flags().StringSliceVarP(&kbTags, "tag", "t", nil, "Tags (comma-separated or repeatable)")
+	kbLsCmd.Flags().StringVarP(&kbTag, "tag", "t", "", "Only show snippets with this tag")
+	kbShowCmd.Flags().BoolVarP(&kbCopy, "copy", "c", false, "Copy the command to the clipboard")
+	kbRunCmd.Flags().BoolVarP(&kbYes, "yes", "y", false, "Run without confirmation")
+	kbCmd.AddCommand(kbAddCmd)
+	kbCmd.AddCommand(kbLsCmd)
+	kbCmd.AddCommand(kbSearchCmd)
+	kbCmd.AddCommand(kbShowCmd)
+	kbCmd.AddCommand(kbRunCmd)
+	kbCmd.AddCommand(kbRmCmd)
+	rootCmd.AddCommand(kbCmd)
+}
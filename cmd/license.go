@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/ppowo/zzk/internal/license"
+	"github.com/spf13/cobra"
+)
+
+var (
+	licenseAuthor string
+	licenseYear   int
+	licenseOutput string
+	licenseForce  bool
+	licenseList   bool
+)
+
+var licenseCmd = &cobra.Command{
+	Use:   "license [type]",
+	Short: "Write a LICENSE file for the current project",
+	Long: `Fetch a license template and write it to ./LICENSE with the current year
+and the author name filled in.
+
+The author defaults to the user of the git identity that governs the
+current directory, so work and personal projects get the right attribution.
+Outside identity folders, the global git user.name is used.
+
+Templates are fetched from the GitHub licenses API and cached locally.
+
+Examples:
+  zzk license                       # MIT license
+  zzk license apache                # Apache-2.0
+  zzk license gpl3 --author "Jane"  # GPL-3.0 with explicit author
+  zzk license --list                # Show available licenses`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if licenseList {
+			names := make([]string, 0, len(license.Aliases))
+			for name := range license.Aliases {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			fmt.Println("Available licenses:")
+			for _, name := range names {
+				fmt.Printf("  %-12s %s\n", name, license.Aliases[name])
+			}
+			return nil
+		}
+
+		name := "mit"
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		key, err := license.ResolveKey(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(licenseOutput); err == nil && !licenseForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", licenseOutput)
+		}
+
+		author := licenseAuthor
+		source := "--author"
+		if author == "" {
+			author, source = detectLicenseAuthor()
+		}
+		if author == "" {
+			return fmt.Errorf("could not determine author name - use --author")
+		}
+
+		text, err := license.Fetch(key)
+		if err != nil {
+			return err
+		}
+
+		content := license.Render(text, licenseYear, author)
+		if err := os.WriteFile(licenseOutput, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", licenseOutput, err)
+		}
+
+		fmt.Printf("✓ Wrote %s (%s, %d, %s)\n", licenseOutput, strings.ToUpper(key), licenseYear, author)
+		fmt.Printf("  Author from: %s\n", source)
+		return nil
+	},
+}
+
+func init() {
+	licenseCmd.Flags().StringVar(&licenseAuthor, "author", "", "Copyright holder (default: detected from git identity)")
+	licenseCmd.Flags().IntVar(&licenseYear, "year", time.Now().Year(), "Copyright year")
+	licenseCmd.Flags().StringVarP(&licenseOutput, "output", "o", "LICENSE", "Output file")
+	licenseCmd.Flags().BoolVarP(&licenseForce, "force", "f", false, "Overwrite an existing file")
+	licenseCmd.Flags().BoolVar(&licenseList, "list", false, "List available licenses")
+	rootCmd.AddCommand(licenseCmd)
+}
+
+// detectLicenseAuthor returns the author name and where it came from
+func detectLicenseAuthor() (string, string) {
+	if config, err := git.LoadConfig(); err == nil {
+		if identity, err := git.GetCurrentIdentity(config); err == nil {
+			return identity.User, fmt.Sprintf("identity %s", identity.Name)
+		}
+	}
+
+	cwd, _ := os.Getwd()
+	if name, err := git.RunGit(cwd, "config", "user.name"); err == nil && name != "" {
+		return name, "git config user.name"
+	}
+
+	return "", ""
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/ppowo/zzk/internal/mirror"
+	"github.com/ppowo/zzk/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mirrorInclude   []string
+	mirrorExclude   []string
+	mirrorNoExclude bool
+	mirrorDelete    bool
+	mirrorDryRun    bool
+	mirrorYes       bool
+	mirrorPort      int
+	mirrorIdentity  string
+)
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror <src> <dest>",
+	Short: "One-way sync a directory to a local path or SSH host",
+	Long: `Make <dest> a copy of the contents of <src>, copying only new and changed files.
+
+Files are compared by size and modification time, like rsync. Copies are
+written to a temporary name and renamed into place, keeping the source
+permissions and modification time.
+
+<dest> is a local path or an scp-style [user@]host:path. SSH destinations use
+SFTP with your ssh-agent, the IdentityFile keys from ~/.ssh/config and the
+zzk-managed git keys (or just one with --identity), plus the default
+~/.ssh/id_* keys. Host aliases are resolved like ssh does ('ssh -G'), so
+their HostName, User and Port apply. The host must already be in
+~/.ssh/known_hosts.
+
+Files missing from <src> are only removed from <dest> with --delete. Excluded
+files on <dest> are never deleted. The global backup exclude patterns apply
+unless --no-exclude. Include and exclude globs match file names or paths.
+
+Examples:
+  zzk mirror ~/Music /Volumes/NAS/Music            # Sync to a mounted share
+  zzk mirror ~/Music nas:/volume1/music -n         # Preview an SSH sync
+  zzk mirror ~/Music me@nas:music --delete         # Also remove extraneous files
+  zzk mirror ~/Music nas:music -i '*.flac' -i '*.m4a'
+  zzk mirror ~/Photos nas:photos -e '*.xmp' --identity personal`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, dest := args[0], args[1]
+		info, err := os.Stat(src)
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %w", src, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", src)
+		}
+
+		exclude := mirrorExclude
+		if !mirrorNoExclude {
+			exclude = append(append([]string{}, globalExcludeGlobs...), mirrorExclude...)
+		}
+		opts := mirror.Options{Include: mirrorInclude, Exclude: exclude, Delete: mirrorDelete}
+
+		dst, label, err := mirrorOpen(src, dest)
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+
+		fmt.Printf("ℹ Comparing %s → %s...\n", src, label)
+		changes, err := mirror.Plan(src, dst, opts)
+		if err != nil {
+			return fmt.Errorf("failed to compare directories: %w", err)
+		}
+		if len(changes) == 0 {
+			fmt.Println("✓ Already in sync")
+			return nil
+		}
+
+		var copies, deletes int
+		var bytes int64
+		for _, c := range changes {
+			if c.Action == mirror.ActionDelete {
+				deletes++
+			} else if !c.Dir {
+				copies++
+				bytes += c.Size
+			}
+		}
+
+		if mirrorDryRun {
+			for _, c := range changes {
+				mirrorPrintChange(c)
+			}
+			fmt.Println()
+			fmt.Printf("ℹ Dry run - would copy %d files (%s) and delete %d\n", copies, humanize.IBytes(uint64(bytes)), deletes)
+			return nil
+		}
+
+		if deletes > 0 && !mirrorYes {
+			mirrorPrintDeletes(changes)
+			confirmed, err := claude.PromptYesNo(fmt.Sprintf("Delete %d paths from %s?", deletes, label), false)
+			if err != nil {
+				return fmt.Errorf("%w. Use -y to skip confirmation", err)
+			}
+			if !confirmed {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		started := time.Now()
+		if err := mirror.Apply(src, dst, changes, mirrorPrintChange); err != nil {
+			notify.Failed("Mirror failed", err, started)
+			return err
+		}
+		fmt.Println()
+		fmt.Printf("✓ Copied %d files (%s), deleted %d\n", copies, humanize.IBytes(uint64(bytes)), deletes)
+		notify.Done("Mirror complete", fmt.Sprintf("%s → %s: copied %d files, deleted %d", src, label, copies, deletes), started)
+		return nil
+	},
+}
+
+// mirrorOpen returns the destination filesystem and a label for messages
+func mirrorOpen(src, dest string) (mirror.FS, string, error) {
+	if remote, ok := mirror.ParseRemote(dest); ok {
+		keys, err := mirrorKeyFiles()
+		if err != nil {
+			return nil, "", err
+		}
+		host := mirror.ResolveSSHHost(remote, mirrorPort)
+		if mirrorIdentity == "" {
+			keys = append(host.IdentityFiles, keys...)
+		}
+		dst, err := mirror.DialSFTP(remote, host, keys)
+		if err != nil {
+			return nil, "", err
+		}
+		return dst, remote.String(), nil
+	}
+
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return nil, "", err
+	}
+	absDest, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, "", err
+	}
+	if absDest == absSrc || strings.HasPrefix(absDest, absSrc+string(filepath.Separator)) {
+		return nil, "", fmt.Errorf("destination %s is inside the source", dest)
+	}
+	return &mirror.LocalFS{Root: absDest}, dest, nil
+}
+
+// mirrorKeyFiles lists private keys to try: the zzk-managed identity keys and the SSH defaults
+func mirrorKeyFiles() ([]string, error) {
+	managed, err := git.FindZZKManagedKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if mirrorIdentity != "" {
+		key, ok := managed[mirrorIdentity]
+		if !ok {
+			return nil, fmt.Errorf("no zzk-managed SSH key for identity '%s'", mirrorIdentity)
+		}
+		return []string{key}, nil
+	}
+
+	names := make([]string, 0, len(managed))
+	for name := range managed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []string
+	for _, name := range names {
+		keys = append(keys, managed[name])
+	}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		keys = append(keys, git.ExpandPath("~/.ssh/"+name))
+	}
+	return keys, nil
+}
+
+func mirrorPrintChange(c mirror.Change) {
+	name := c.Path
+	if c.Dir {
+		name += "/"
+	}
+	switch c.Action {
+	case mirror.ActionNew:
+		fmt.Printf("  + %s\n", name)
+	case mirror.ActionUpdate:
+		fmt.Printf("  ~ %s\n", name)
+	case mirror.ActionDelete:
+		fmt.Printf("  - %s\n", name)
+	}
+}
+
+func mirrorPrintDeletes(changes []mirror.Change) {
+	for _, c := range changes {
+		if c.Action == mirror.ActionDelete {
+			mirrorPrintChange(c)
+		}
+	}
+	fmt.Println()
+}
+
+func init() {
+	mirrorCmd.Flags().StringArrayVarP(&mirrorInclude, "include", "i", nil, "Only sync files matching this glob (repeatable)")
+	mirrorCmd.Flags().StringArrayVarP(&mirrorExclude, "exclude", "e", nil, "Skip files or directories matching this glob (repeatable)")
+	mirrorCmd.Flags().BoolVar(&mirrorNoExclude, "no-exclude", false, "Don't apply the global exclude patterns")
+	mirrorCmd.Flags().BoolVar(&mirrorDelete, "delete", false, "Delete files on the destination that are not in the source")
+	mirrorCmd.Flags().BoolVarP(&mirrorDryRun, "dry-run", "n", false, "Show what would change")
+	mirrorCmd.Flags().BoolVarP(&mirrorYes, "yes", "y", false, "Delete without confirmation")
+	mirrorCmd.Flags().IntVarP(&mirrorPort, "port", "p", 0, "SSH port (default: from ~/.ssh/config, else 22)")
+	mirrorCmd.Flags().StringVar(&mirrorIdentity, "identity", "", "Only use the SSH key of this zzk git identity")
+	rootCmd.AddCommand(mirrorCmd)
+}
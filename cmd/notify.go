@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	notifySound   bool
+	notifyUrgency string
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify <title> [body...]",
+	Short: "Show a desktop notification",
+	Long: `Show a desktop notification using osascript (macOS), notify-send (Linux)
+or a toast (Windows).
+
+zzk also notifies on its own when downloads, backups, git sync and mirror
+finish after running for 30 seconds or more, or when run unattended from
+zzk cron.
+
+Examples:
+  zzk notify "Build done"
+  zzk notify "Tests failed" "3 failures in ./cmd" -u critical
+  make release; zzk notify -s "Release" "make exited with $?"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n := notify.Notification{
+			Title:   args[0],
+			Body:    strings.Join(args[1:], " "),
+			Sound:   notifySound,
+			Urgency: notifyUrgency,
+		}
+		if err := notify.Send(n); err != nil {
+			return fmt.Errorf("failed to send notification: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	notifyCmd.Flags().BoolVarP(&notifySound, "sound", "s", false, "Play a sound")
+	notifyCmd.Flags().StringVarP(&notifyUrgency, "urgency", "u", notify.UrgencyNormal, "Urgency: low, normal or critical")
+	rootCmd.AddCommand(notifyCmd)
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/clipboard"
+	"github.com/ppowo/zzk/internal/random"
+	"github.com/spf13/cobra"
+)
+
+var (
+	randCopy  bool
+	randCount int
+	randV7    bool
+)
+
+var randCmd = &cobra.Command{
+	Use:   "rand",
+	Short: "Generate UUIDs, ULIDs, tokens, numbers and dice rolls",
+	Long: `Generate random identifiers and values using a cryptographic RNG.
+
+Use --count to generate several at once and --copy to also put the output
+on the clipboard.
+
+Examples:
+  zzk rand uuid                # UUIDv4
+  zzk rand uuid -7             # Time-ordered UUIDv7
+  zzk rand ulid                # ULID
+  zzk rand hex 64 --copy       # 64 hex characters, copied to the clipboard
+  zzk rand b58                 # 22-character base58 token
+  zzk rand int 1 100           # Number between 1 and 100 (inclusive)
+  zzk rand int -- -5 5         # Negative bounds need --
+  zzk rand dice 3d6+2          # Roll dice`,
+}
+
+var randUUIDCmd = &cobra.Command{
+	Use:   "uuid",
+	Short: "Generate a UUID (v4, or v7 with -7)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return randEmit(func() (string, error) {
+			if randV7 {
+				return random.UUIDv7(), nil
+			}
+			return random.UUIDv4(), nil
+		})
+	},
+}
+
+var randULIDCmd = &cobra.Command{
+	Use:   "ulid",
+	Short: "Generate a ULID",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return randEmit(func() (string, error) { return random.ULID(), nil })
+	},
+}
+
+var randHexCmd = &cobra.Command{
+	Use:   "hex [length]",
+	Short: "Generate a random hex token (default 32 characters)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := randLength(args, 32)
+		if err != nil {
+			return err
+		}
+		return randEmit(func() (string, error) { return random.Hex(n), nil })
+	},
+}
+
+var randBase58Cmd = &cobra.Command{
+	Use:     "base58 [length]",
+	Aliases: []string{"b58"},
+	Short:   "Generate a random base58 token (default 22 characters)",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := randLength(args, 22)
+		if err != nil {
+			return err
+		}
+		return randEmit(func() (string, error) { return random.Base58(n), nil })
+	},
+}
+
+var randIntCmd = &cobra.Command{
+	Use:   "int [min] <max>",
+	Short: "Generate a random integer in [min, max] (min defaults to 1)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bounds := []int64{1}
+		for _, arg := range args {
+			v, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid number: %s", arg)
+			}
+			bounds = append(bounds, v)
+		}
+		min, max := bounds[len(bounds)-2], bounds[len(bounds)-1]
+
+		return randEmit(func() (string, error) {
+			n, err := random.Int(min, max)
+			return strconv.FormatInt(n, 10), err
+		})
+	},
+}
+
+var randDiceCmd = &cobra.Command{
+	Use:   "dice [NdS+M]",
+	Short: "Roll dice, e.g. d20, 2d6, 3d8+2 (default 1d6)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := "1d6"
+		if len(args) > 0 {
+			spec = args[0]
+		}
+
+		return randEmit(func() (string, error) {
+			rolls, total, err := random.Roll(spec)
+			if err != nil {
+				return "", err
+			}
+			if len(rolls) == 1 && !strings.ContainsAny(spec, "+-") {
+				return strconv.FormatInt(total, 10), nil
+			}
+			parts := make([]string, len(rolls))
+			for i, r := range rolls {
+				parts[i] = strconv.FormatInt(r, 10)
+			}
+			fmt.Fprintf(os.Stderr, "%s: [%s]\n", spec, strings.Join(parts, " "))
+			return strconv.FormatInt(total, 10), nil
+		})
+	},
+}
+
+// randEmit prints --count values from gen, copying them to the clipboard with --copy
+func randEmit(gen func() (string, error)) error {
+	if randCount < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+
+	values := make([]string, 0, randCount)
+	for range randCount {
+		value, err := gen()
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		values = append(values, value)
+	}
+
+	if randCopy {
+		if err := clipboard.Copy(strings.Join(values, "\n")); err != nil {
+			return fmt.Errorf("failed to copy to clipboard: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "✓ Copied to clipboard")
+	}
+	return nil
+}
+
+// randLength parses an optional length argument
+func randLength(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > 4096 {
+		return 0, fmt.Errorf("invalid length %q (1-4096)", args[0])
+	}
+	return n, nil
+}
+
+func init() {
+	randCmd.PersistentFlags().BoolVarP(&randCopy, "copy", "c", false, "Copy the output to the clipboard")
+	randCmd.PersistentFlags().IntVarP(&randCount, "count", "n", 1, "Number of values to generate")
+	randUUIDCmd.Flags().BoolVarP(&randV7, "v7", "7", false, "Generate a time-ordered UUIDv7")
+	randCmd.AddCommand(randUUIDCmd)
+	randCmd.AddCommand(randULIDCmd)
+	randCmd.AddCommand(randHexCmd)
+	randCmd.AddCommand(randBase58Cmd)
+	randCmd.AddCommand(randIntCmd)
+	randCmd.AddCommand(randDiceCmd)
+	rootCmd.AddCommand(randCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/rename"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renameStyle      string
+	renameReplace    []string
+	renameRegex      bool
+	renameDate       bool
+	renameDateFormat string
+	renameDryRun     bool
+	renameYes        bool
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename [path...]",
+	Short: "Bulk rename files to clean, yt-dlp style names",
+	Long: `Rename files to clean names, showing a preview before applying.
+
+Directories are expanded to the (non-hidden) files directly inside them;
+the default is the current directory. Extensions are kept.
+
+Styles:
+  restrict  yt-dlp --restrict-filenames rules: accents stripped, spaces and
+            unsafe characters become underscores (default)
+  slug      lowercase-words-with-dashes
+  none      only apply --replace and --date
+
+Replacements apply to the name before normalization. --date prefixes the
+EXIF capture date for JPEGs, or the modification date otherwise.
+
+Examples:
+  zzk rename                                   # Clean up names in the current directory
+  zzk rename -s slug ~/Downloads/*.pdf         # Slugify
+  zzk rename --date ~/Pictures/import          # 2024-06-01_IMG_1234.jpg
+  zzk rename -r " (1)=" -r "final=v2" .        # Literal find/replace
+  zzk rename -E -r '^(\d+)\. =$1_' .            # Regex replace
+  zzk rename -n .                              # Preview only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := rename.Options{DatePrefix: renameDate, DateFormat: renameDateFormat}
+		switch renameStyle {
+		case "restrict":
+			opts.Style = rename.StyleRestrict
+		case "slug":
+			opts.Style = rename.StyleSlug
+		case "none":
+			opts.Style = rename.StyleNone
+		default:
+			return fmt.Errorf("unknown style %q (use restrict, slug or none)", renameStyle)
+		}
+		for _, r := range renameReplace {
+			replacement, err := rename.ParseReplacement(r, renameRegex)
+			if err != nil {
+				return err
+			}
+			opts.Replace = append(opts.Replace, replacement)
+		}
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		files, err := renameCollectFiles(args)
+		if err != nil {
+			return err
+		}
+
+		plans := rename.BuildPlan(files, opts)
+		if len(plans) == 0 {
+			fmt.Println("✓ Nothing to rename")
+			return nil
+		}
+
+		width := 0
+		for _, p := range plans {
+			width = max(width, len(filepath.Base(p.Old)))
+		}
+		for _, p := range plans {
+			fmt.Printf("  %-*s → %s\n", width, filepath.Base(p.Old), filepath.Base(p.New))
+		}
+		fmt.Println()
+
+		if renameDryRun {
+			fmt.Printf("ℹ Dry run - %d files would be renamed\n", len(plans))
+			return nil
+		}
+
+		if !renameYes {
+			confirmed, err := claude.PromptYesNo(fmt.Sprintf("Rename %d files?", len(plans)), false)
+			if err != nil {
+				return fmt.Errorf("%w. Use -y to skip confirmation", err)
+			}
+			if !confirmed {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		if err := rename.Apply(plans); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Renamed %d files\n", len(plans))
+		return nil
+	},
+}
+
+// renameCollectFiles expands directories to the regular, non-hidden files directly inside them
+func renameCollectFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", arg, err)
+		}
+		if !info.IsDir() {
+			files = append(files, filepath.Clean(arg))
+			continue
+		}
+
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+				continue
+			}
+			files = append(files, filepath.Join(arg, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+func init() {
+	renameCmd.Flags().StringVarP(&renameStyle, "style", "s", "restrict", "Naming style: restrict, slug or none")
+	renameCmd.Flags().StringArrayVarP(&renameReplace, "replace", "r", nil, "Find/replace 'find=replace' (repeatable)")
+	renameCmd.Flags().BoolVarP(&renameRegex, "regex", "E", false, "Treat --replace patterns as regular expressions")
+	renameCmd.Flags().BoolVar(&renameDate, "date", false, "Prefix names with the EXIF or modification date")
+	renameCmd.Flags().StringVar(&renameDateFormat, "date-format", "2006-01-02", "Go time layout for --date")
+	renameCmd.Flags().BoolVarP(&renameDryRun, "dry-run", "n", false, "Preview only")
+	renameCmd.Flags().BoolVarP(&renameYes, "yes", "y", false, "Rename without confirmation")
+	rootCmd.AddCommand(renameCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Remote repository operations",
+	Long: `Create and manage repositories on GitHub, GitLab and Codeberg using the
+git identity that governs the current directory.
+
+API tokens are read from the environment:
+  github.com    GITHUB_TOKEN
+  gitlab.com    GITLAB_TOKEN
+  codeberg.org  CODEBERG_TOKEN
+  others        GITEA_TOKEN
+
+Examples:
+  zzk repo new                    # Create a repo named after the current directory
+  zzk repo new my-tool --public   # Create a public repo named my-tool`,
+}
+
+func init() {
+	rootCmd.AddCommand(repoCmd)
+}
+
+// execGit returns a git command in dir wired to the terminal
+func execGit(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
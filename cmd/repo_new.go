@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ppowo/zzk/internal/forge"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	repoNewPublic      bool
+	repoNewDescription string
+	repoNewOrg         string
+	repoNewIdentity    string
+	repoNewNoPush      bool
+	repoNewReplace     bool
+)
+
+var repoNewCmd = &cobra.Command{
+	Use:   "new [name]",
+	Short: "Create a remote repository for the current directory",
+	Long: `Create a new repository on the forge of the identity detected from the
+current directory, then wire up the local repository:
+
+  1. Initializes a git repository if needed
+  2. Creates the remote repository via the forge API
+  3. Sets 'origin' to the SSH URL for the identity's host
+  4. Pushes the current branch (if there are commits)
+
+An existing 'origin' is only replaced with --replace-origin; without it
+nothing is created.
+
+The repository name defaults to the current directory name. Repositories
+are private unless --public is given.
+
+Examples:
+  zzk repo new                          # Private repo named after cwd
+  zzk repo new my-tool --public         # Public repo named my-tool
+  zzk repo new --org my-team            # Create under an organization/group
+  zzk repo new -i github-work           # Use a specific identity
+  zzk repo new --replace-origin         # Move a clone to a new repo`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load git identities: %w", err)
+		}
+
+		var identity *git.Identity
+		if repoNewIdentity != "" {
+			id, ok := config.GetIdentity(repoNewIdentity)
+			if !ok {
+				return fmt.Errorf("identity '%s' not found", repoNewIdentity)
+			}
+			identity = &id
+		} else {
+			identity, err = git.DetectIdentity(config, cwd)
+			if err != nil {
+				return fmt.Errorf("%w\nUse --identity to choose one explicitly", err)
+			}
+		}
+
+		name := filepath.Base(cwd)
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		// Checked before creating the repository, which would be left orphaned
+		if origin := git.GetRemoteURL(cwd, "origin"); origin != "" && !repoNewReplace {
+			return fmt.Errorf("origin is already set to %s - use --replace-origin to point it at the new repository", origin)
+		}
+
+		token, err := forge.LookupIdentityToken(identity.Domain, identity.Name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Identity: %s (%s)\n", identity.Name, identity.Domain)
+
+		if !git.IsGitRepo(cwd) {
+			if _, err := git.RunGit(cwd, "init"); err != nil {
+				return fmt.Errorf("failed to initialize repository: %w", err)
+			}
+			fmt.Println("✓ Initialized git repository")
+		}
+
+		client := forge.NewClient(identity.Domain, token)
+		repo, err := client.CreateRepo(forge.CreateRepoOptions{
+			Name:        name,
+			Description: repoNewDescription,
+			Private:     !repoNewPublic,
+			Org:         repoNewOrg,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create repository: %w", err)
+		}
+		fmt.Printf("✓ Created %s\n", repo.WebURL)
+
+		remoteURL := identity.RemoteURL(repo.FullName)
+		if err := git.SetRemoteURL(cwd, "origin", remoteURL); err != nil {
+			return fmt.Errorf("failed to set remote: %w", err)
+		}
+		fmt.Printf("✓ Set origin to %s\n", remoteURL)
+
+		if repoNewNoPush {
+			return nil
+		}
+
+		if !git.HasCommits(cwd) {
+			fmt.Println("ℹ No commits yet - skipping push")
+			return nil
+		}
+
+		push := execGit(cwd, "push", "-u", "origin", "HEAD")
+		if err := push.Run(); err != nil {
+			return fmt.Errorf("failed to push: %w", err)
+		}
+		fmt.Println("✓ Pushed to origin")
+
+		return nil
+	},
+}
+
+func init() {
+	repoNewCmd.Flags().BoolVar(&repoNewPublic, "public", false, "Create a public repository")
+	repoNewCmd.Flags().StringVarP(&repoNewDescription, "description", "d", "", "Repository description")
+	repoNewCmd.Flags().StringVar(&repoNewOrg, "org", "", "Create under an organization/group")
+	repoNewCmd.Flags().StringVarP(&repoNewIdentity, "identity", "i", "", "Identity to use instead of detecting from the current directory")
+	repoNewCmd.Flags().BoolVar(&repoNewNoPush, "no-push", false, "Do not push after creating the repository")
+	repoNewCmd.Flags().BoolVar(&repoNewReplace, "replace-origin", false, "Replace an existing 'origin' remote")
+	repoCmd.AddCommand(repoNewCmd)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/ppowo/zzk/internal/todo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	todoIdentity string
+	todoRepo     string
+	todoJSON     bool
+	todoSort     string
+	todoNoBlame  bool
+	todoDepth    int
+)
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "List TODO/FIXME/HACK comments across managed repos",
+	Long: `Walks the repositories in all git identity folders and lists TODO, FIXME
+and HACK comments with their location, author (from git blame) and age.
+
+Only tracked files are searched.
+
+Examples:
+  zzk todo                          # All repos of all identities
+  zzk todo --identity github-work   # Only repos of one identity
+  zzk todo --repo zzk               # Only repos whose path contains "zzk"
+  zzk todo --sort age               # Oldest first
+  zzk todo --json                   # Machine-readable output`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := git.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load git identities: %w", err)
+		}
+
+		if todoIdentity != "" && !config.HasIdentity(todoIdentity) {
+			return fmt.Errorf("identity '%s' not found", todoIdentity)
+		}
+
+		var items []todo.Item
+		for _, identity := range config.Identities {
+			if todoIdentity != "" && identity.Name != todoIdentity {
+				continue
+			}
+
+			for _, repo := range git.IdentityRepos(identity, todoDepth) {
+				if todoRepo != "" && !strings.Contains(repo, todoRepo) {
+					continue
+				}
+
+				found, err := todo.Scan(repo)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "⚠ Warning: failed to scan %s: %v\n", repo, err)
+					continue
+				}
+				if !todoNoBlame {
+					todo.Blame(repo, found)
+				}
+				for i := range found {
+					found[i].Identity = identity.Name
+				}
+				items = append(items, found...)
+			}
+		}
+
+		switch todoSort {
+		case "age":
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Date.Before(items[j].Date)
+			})
+		case "repo":
+			sort.SliceStable(items, func(i, j int) bool {
+				if items[i].Repo != items[j].Repo {
+					return items[i].Repo < items[j].Repo
+				}
+				if items[i].File != items[j].File {
+					return items[i].File < items[j].File
+				}
+				return items[i].Line < items[j].Line
+			})
+		default:
+			return fmt.Errorf("invalid --sort value '%s' (use repo or age)", todoSort)
+		}
+
+		if todoJSON {
+			if items == nil {
+				items = []todo.Item{}
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(items)
+		}
+
+		if len(items) == 0 {
+			fmt.Println("No TODO/FIXME/HACK comments found")
+			return nil
+		}
+
+		for _, item := range items {
+			age := "-"
+			if !item.Date.IsZero() {
+				age = humanize.Time(item.Date)
+			}
+			location := fmt.Sprintf("%s/%s:%d", filepath.Base(item.Repo), item.File, item.Line)
+			fmt.Printf("%-6s %-15s %-18s %s\n", item.Tag, age, truncate(item.Author, 18), location)
+			if item.Text != "" {
+				fmt.Printf("       %s\n", item.Text)
+			}
+		}
+
+		fmt.Printf("\n%d items\n", len(items))
+		return nil
+	},
+}
+
+func init() {
+	todoCmd.Flags().StringVarP(&todoIdentity, "identity", "i", "", "Only scan repos of this identity")
+	todoCmd.Flags().StringVarP(&todoRepo, "repo", "r", "", "Only scan repos whose path contains this string")
+	todoCmd.Flags().BoolVar(&todoJSON, "json", false, "Output as JSON")
+	todoCmd.Flags().StringVar(&todoSort, "sort", "repo", "Sort order: repo or age")
+	todoCmd.Flags().BoolVar(&todoNoBlame, "no-blame", false, "Skip git blame (faster, no author/age)")
+	todoCmd.Flags().IntVar(&todoDepth, "depth", 3, "Maximum folder depth to search for repos")
+	rootCmd.AddCommand(todoCmd)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/claude"
+	"github.com/ppowo/zzk/internal/clipboard"
+	"github.com/ppowo/zzk/internal/crypt"
+	"github.com/ppowo/zzk/internal/forge"
+	"github.com/ppowo/zzk/internal/git"
+	"github.com/ppowo/zzk/internal/vault"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	vaultCopy  bool
+	vaultForce bool
+)
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage secrets in the OS keychain",
+	Long: `Store secrets in the OS keychain (macOS Keychain, GNOME Keyring/KWallet,
+Windows Credential Manager), falling back to an age-encrypted file in
+~/.config/zzk/vault when no keychain is available. Set $ZZK_VAULT_BACKEND to
+"file" or "keychain" to choose explicitly before the first secret is stored.
+
+The file is encrypted with a passphrase, chosen when the first secret is
+stored and read from $ZZK_VAULT_PASSPHRASE or prompted for once per command.
+Vault files from older versions, encrypted to a key stored next to them, ask
+for a passphrase the next time a secret is stored or deleted.
+
+zzk reads these secrets itself:
+  claude/<provider>   API keys for 'zzk claude' providers
+  forge/<domain>      API tokens for 'zzk repo new' (e.g. forge/github.com),
+                      used when $GITHUB_TOKEN etc. are not set
+  crypt/passphrase    Passphrase for 'zzk crypt' and 'zzk compress -e'
+                      when $ZZK_CRYPT_PASSPHRASE is not set
+
+Examples:
+  zzk vault set forge/github.com     # Prompts for the value without echo
+  echo "$TOKEN" | zzk vault set forge/codeberg.org
+  zzk vault get forge/github.com -c  # Copy to clipboard
+  zzk vault ls
+  zzk vault rm crypt/passphrase
+  zzk vault migrate                  # Move plaintext keys and tokens into the vault`,
+}
+
+var vaultSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret (prompted, or read from stdin)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := vault.ValidateName(name); err != nil {
+			return err
+		}
+
+		value, err := vaultReadValue(name)
+		if err != nil {
+			return err
+		}
+
+		if err := vault.Set(name, value); err != nil {
+			return err
+		}
+		backend, _ := vault.Backend()
+		fmt.Printf("✓ Stored %s (%s)\n", name, backend)
+		return nil
+	},
+}
+
+var vaultGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := vault.Get(args[0])
+		if errors.Is(err, vault.ErrNotFound) {
+			return fmt.Errorf("secret '%s' not found", args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		if vaultCopy {
+			if err := clipboard.Copy(value); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "✓ Copied %s to clipboard\n", args[0])
+			return nil
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var vaultLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List stored secrets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := vault.List()
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("No secrets stored. Add one with: zzk vault set <name>")
+			return nil
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		backend, err := vault.Backend()
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "\nℹ Backend: %s\n", backend)
+		}
+		return nil
+	},
+}
+
+var vaultRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Delete a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !vaultForce {
+			confirmed, err := claude.PromptYesNo(fmt.Sprintf("Delete secret '%s'?", name), false)
+			if err != nil {
+				return fmt.Errorf("%w. Use -f to force", err)
+			}
+			if !confirmed {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		if err := vault.Delete(name); err != nil {
+			if errors.Is(err, vault.ErrNotFound) {
+				return fmt.Errorf("secret '%s' not found", name)
+			}
+			return err
+		}
+		fmt.Printf("✓ Deleted %s\n", name)
+		return nil
+	},
+}
+
+var vaultMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move plaintext Claude keys, forge tokens and the crypt passphrase into the vault",
+	Long: `Move secrets zzk used to keep elsewhere into the vault:
+  - API keys in ~/.claude-providers.json (and its plaintext backup)
+  - $GITHUB_TOKEN / $GITLAB_TOKEN / $CODEBERG_TOKEN / $GITEA_TOKEN for the
+    domains of your git identities
+  - $ZZK_CRYPT_PASSPHRASE
+
+Environment variables are copied; remove them from your shell profile afterwards.
+Existing vault secrets are not overwritten.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		migrated := 0
+
+		unlock, err := claude.LockConfig()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		config, err := claude.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load Claude config: %w", err)
+		}
+		if plaintext := config.PlaintextKeys(); len(plaintext) > 0 {
+			if err := claude.SaveConfig(config); err != nil {
+				return err
+			}
+			for _, name := range plaintext {
+				fmt.Printf("✓ %s → %s\n", name, claude.KeyRef(name))
+			}
+			migrated += len(plaintext)
+		}
+		if removed, err := claude.RemovePlaintextBackup(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: failed to remove %s.backup: %v\n", claude.ConfigPath(), err)
+		} else if removed {
+			fmt.Printf("✓ Removed plaintext backup %s.backup\n", claude.ConfigPath())
+		}
+
+		envSecrets := map[string]string{}
+		if gitConfig, err := git.LoadConfig(); err == nil {
+			for _, identity := range gitConfig.Identities {
+				envSecrets[forge.TokenSecret(identity.Domain)] = forge.TokenEnvVar(identity.Domain)
+			}
+		}
+		envSecrets[cryptPassphraseSecret] = crypt.PassphraseEnvVar
+
+		names := make([]string, 0, len(envSecrets))
+		for name := range envSecrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			envVar := envSecrets[name]
+			value := os.Getenv(envVar)
+			if value == "" {
+				continue
+			}
+			if _, err := vault.Get(name); err == nil {
+				fmt.Printf("ℹ %s already in the vault, skipping $%s\n", name, envVar)
+				continue
+			}
+			if err := vault.Set(name, value); err != nil {
+				return err
+			}
+			fmt.Printf("✓ $%s → %s\n", envVar, name)
+			migrated++
+		}
+
+		if migrated == 0 {
+			fmt.Println("✓ Nothing to migrate")
+			return nil
+		}
+		backend, _ := vault.Backend()
+		fmt.Printf("\n✓ Migrated %d secrets to the vault (%s)\n", migrated, backend)
+		return nil
+	},
+}
+
+// vaultReadValue prompts without echo, or reads stdin when it isn't a terminal
+func vaultReadValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+	value, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read value: %w", err)
+	}
+	return string(value), nil
+}
+
+func init() {
+	vaultGetCmd.Flags().BoolVarP(&vaultCopy, "copy", "c", false, "Copy to clipboard instead of printing")
+	vaultRmCmd.Flags().BoolVarP(&vaultForce, "force", "f", false, "Delete without confirmation")
+
+	vaultCmd.AddCommand(vaultSetCmd)
+	vaultCmd.AddCommand(vaultGetCmd)
+	vaultCmd.AddCommand(vaultLsCmd)
+	vaultCmd.AddCommand(vaultRmCmd)
+	vaultCmd.AddCommand(vaultMigrateCmd)
+	rootCmd.AddCommand(vaultCmd)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+var (
+	wallpaperRandom  string
+	wallpaperDisplay int
+)
+
+var wallpaperCmd = &cobra.Command{
+	Use:   "wallpaper [file|url]",
+	Short: "Set the desktop wallpaper from a file, URL or random image",
+	Long: `Set the desktop wallpaper.
+
+On macOS the wallpaper is set on every display, or a single one with
+--display. On Linux gsettings (GNOME, Unity, Budgie, Cinnamon),
+plasma-apply-wallpaperimage (KDE), swaymsg (sway) or feh is used.
+
+Downloaded images are kept in the zzk cache directory. --random picks an
+image from a directory (recursively), avoiding the current wallpaper;
+schedule it with 'zzk cron' for a rotating wallpaper.
+
+Examples:
+  zzk wallpaper ~/Pictures/mountains.jpg         # Set from a file
+  zzk wallpaper https://example.com/image.jpg    # Download and set
+  zzk wallpaper --random ~/Pictures/Wallpapers   # Random image from a directory
+  zzk wallpaper -d 2 ~/Pictures/side.png         # Second display only (macOS)
+  zzk cron add wallpaper daily -- wallpaper --random ~/Pictures/Wallpapers
+  zzk wallpaper                                  # Show the last wallpaper set by zzk`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var file string
+		switch {
+		case wallpaperRandom != "" && len(args) > 0:
+			return fmt.Errorf("give either a file or --random, not both")
+		case wallpaperRandom != "":
+			pick, err := wallpaper.Random(wallpaperRandom)
+			if err != nil {
+				return err
+			}
+			file = pick
+		case len(args) == 0:
+			if current := wallpaper.Current(); current != "" {
+				fmt.Println(current)
+				return nil
+			}
+			return fmt.Errorf("no wallpaper set by zzk yet - give a file, URL or --random <dir>")
+		case strings.HasPrefix(args[0], "http://") || strings.HasPrefix(args[0], "https://"):
+			fmt.Println("Downloading wallpaper...")
+			path, err := wallpaper.Download(args[0])
+			if err != nil {
+				return err
+			}
+			file = path
+		default:
+			file = args[0]
+		}
+
+		if err := wallpaper.Set(file, wallpaperDisplay); err != nil {
+			return fmt.Errorf("failed to set wallpaper: %w", err)
+		}
+		if err := wallpaper.Remember(file); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: failed to record wallpaper: %v\n", err)
+		}
+
+		fmt.Printf("✓ Wallpaper set to %s\n", file)
+		return nil
+	},
+}
+
+func init() {
+	wallpaperCmd.Flags().StringVarP(&wallpaperRandom, "random", "r", "", "Pick a random image from this directory")
+	wallpaperCmd.Flags().IntVarP(&wallpaperDisplay, "display", "d", 0, "Display number, 1-based (macOS only; default all)")
+	rootCmd.AddCommand(wallpaperCmd)
+}
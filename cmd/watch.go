@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ppowo/zzk/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchPaths    []string
+	watchIgnore   []string
+	watchDebounce time.Duration
+	watchClear    bool
+	watchRestart  bool
+	watchInitial  bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [flags] -- <command...>",
+	Short: "Run a command whenever files change",
+	Long: `Watch files and directories (recursively) and run a command when they change.
+
+Changes are debounced so a burst of writes (e.g. a git checkout) triggers a
+single run. The global backup exclude patterns (.git, node_modules, editor
+swap files, ...) are always ignored; add more with --ignore.
+
+The changed paths are available to the command in $ZZK_CHANGED (newline-separated).
+
+Examples:
+  zzk watch -- go test ./...                   # Re-run tests on any change
+  zzk watch -p internal -- go build ./...      # Only watch internal/
+  zzk watch --clear -i '*.log' -- make         # Clear screen, ignore logs
+  zzk watch --restart -- go run ./server       # Restart a long-running process`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() < 0 {
+			return fmt.Errorf("separate the command with --, e.g. zzk watch -- go test ./...")
+		}
+
+		paths := watchPaths
+		if len(paths) == 0 {
+			paths = []string{"."}
+		}
+
+		exclude := append([]string{}, globalExcludeGlobs...)
+		exclude = append(exclude, watchIgnore...)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		runner := &watchRunner{args: args, clear: watchClear, restart: watchRestart}
+		defer runner.stop()
+
+		fmt.Fprintf(os.Stderr, "Watching %s (Ctrl+C to stop)\n", strings.Join(paths, ", "))
+
+		if watchInitial {
+			runner.run(ctx, nil)
+		}
+
+		return watch.Watch(ctx, watch.Options{
+			Paths:    paths,
+			Exclude:  exclude,
+			Debounce: watchDebounce,
+		}, func(changed []string) {
+			runner.run(ctx, changed)
+		})
+	},
+}
+
+// watchRunner runs the watched command, optionally restarting it on change
+type watchRunner struct {
+	args    []string
+	clear   bool
+	restart bool
+	current *exec.Cmd
+	done    chan struct{}
+}
+
+func (r *watchRunner) run(ctx context.Context, changed []string) {
+	if r.restart {
+		r.stop()
+	}
+
+	if r.clear {
+		fmt.Print("\033[H\033[2J")
+	}
+
+	if len(changed) > 0 {
+		fmt.Fprintf(os.Stderr, "[%s] %d change(s): %s\n", time.Now().Format("15:04:05"), len(changed), summarizeChanges(changed))
+	}
+
+	c := exec.CommandContext(ctx, r.args[0], r.args[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), "ZZK_CHANGED="+strings.Join(changed, "\n"))
+
+	if err := c.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "✗ Failed to start command: %v\n", err)
+		return
+	}
+
+	if !r.restart {
+		reportExit(c.Wait())
+		return
+	}
+
+	r.current = c
+	r.done = make(chan struct{})
+	go func(done chan struct{}) {
+		reportExit(c.Wait())
+		close(done)
+	}(r.done)
+}
+
+// stop terminates the running process (restart mode only)
+func (r *watchRunner) stop() {
+	if r.current == nil {
+		return
+	}
+
+	select {
+	case <-r.done:
+	default:
+		_ = r.current.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-r.done:
+		case <-time.After(3 * time.Second):
+			_ = r.current.Process.Kill()
+			<-r.done
+		}
+	}
+	r.current = nil
+}
+
+func reportExit(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "✗ Command exited: %v\n", err)
+	} else {
+		fmt.Fprintln(os.Stderr, "✓ Command finished")
+	}
+}
+
+// summarizeChanges shows the first few changed paths
+func summarizeChanges(changed []string) string {
+	const maxShown = 3
+	if len(changed) <= maxShown {
+		return strings.Join(changed, ", ")
+	}
+	return fmt.Sprintf("%s, … (+%d more)", strings.Join(changed[:maxShown], ", "), len(changed)-maxShown)
+}
+
+func init() {
+	watchCmd.Flags().StringSliceVarP(&watchPaths, "path", "p", nil, "Paths to watch (default: current directory)")
+	watchCmd.Flags().StringSliceVarP(&watchIgnore, "ignore", "i", nil, "Additional glob patterns to ignore")
+	watchCmd.Flags().DurationVarP(&watchDebounce, "debounce", "d", 200*time.Millisecond, "Quiet period before running")
+	watchCmd.Flags().BoolVarP(&watchClear, "clear", "c", false, "Clear the screen before each run")
+	watchCmd.Flags().BoolVarP(&watchRestart, "restart", "r", false, "Restart the command if it is still running")
+	watchCmd.Flags().BoolVar(&watchInitial, "initial", false, "Run the command once at startup")
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ppowo/zzk/internal/weather"
+	"github.com/spf13/cobra"
+)
+
+var (
+	weatherShort    bool
+	weatherJSON     bool
+	weatherBackend  string
+	weatherImperial bool
+	weatherRefresh  bool
+	weatherSave     bool
+)
+
+var weatherCmd = &cobra.Command{
+	Use:   "weather [location]",
+	Short: "Show current conditions and a 3-day forecast",
+	Long: `Show the current weather and a short forecast.
+
+The location defaults to the one saved in ~/.config/zzk/weather.json, or is
+geolocated from your IP address if none is saved. Results are cached for 15
+minutes, so --short is cheap enough for a shell prompt.
+
+Backends: open-meteo (default) and wttr (wttr.in).
+
+Examples:
+  zzk weather                         # Saved or geolocated location
+  zzk weather Berlin                  # Specific location
+  zzk weather --save "New York" -I    # Save location and imperial units as default
+  zzk weather --short                 # One line: "Berlin ⛅ 18°C"
+  zzk weather -b wttr Tokyo           # Use wttr.in`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := weather.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		location := config.Location
+		if len(args) > 0 {
+			location = args[0]
+		}
+		backend := config.Backend
+		if cmd.Flags().Changed("backend") {
+			backend = weatherBackend
+		}
+		imperial := config.Imperial || weatherImperial
+
+		if weatherSave {
+			config.Location = location
+			config.Backend = backend
+			config.Imperial = imperial
+			if err := weather.SaveConfig(config); err != nil {
+				return fmt.Errorf("failed to save weather config: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "✓ Saved default location %q (%s)\n", location, backend)
+		}
+
+		report, err := weather.Fetch(backend, location, imperial, weatherRefresh)
+		if err != nil {
+			return err
+		}
+
+		if weatherJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+
+		unit := report.TempUnit()
+		if weatherShort {
+			fmt.Printf("%s %s %.0f%s\n", report.Location, report.Current.Icon, report.Current.Temp, unit)
+			return nil
+		}
+
+		c := report.Current
+		fmt.Printf("📍 %s\n\n", report.Location)
+		fmt.Printf("  %s  %s\n", c.Icon, c.Description)
+		fmt.Printf("     %.0f%s (feels like %.0f%s)\n", c.Temp, unit, c.FeelsLike, unit)
+		fmt.Printf("     Humidity %d%%, wind %.0f %s\n", c.Humidity, c.Wind, report.WindUnit())
+
+		if len(report.Days) > 0 {
+			fmt.Println()
+			for _, day := range report.Days {
+				label := day.Date
+				if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+					label = date.Format("Mon Jan 2")
+				}
+				fmt.Printf("  %-11s %s  %3.0f%s / %3.0f%s  💧%3d%%  %s\n",
+					label, day.Icon, day.Min, unit, day.Max, unit, day.PrecipChance, day.Description)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	weatherCmd.Flags().BoolVarP(&weatherShort, "short", "s", false, "One-line output for prompts and status bars")
+	weatherCmd.Flags().BoolVar(&weatherJSON, "json", false, "Output as JSON")
+	weatherCmd.Flags().StringVarP(&weatherBackend, "backend", "b", weather.OpenMeteo, "Weather backend: open-meteo or wttr")
+	weatherCmd.Flags().BoolVarP(&weatherImperial, "imperial", "I", false, "Use °F and mph")
+	weatherCmd.Flags().BoolVar(&weatherRefresh, "refresh", false, "Ignore the cache")
+	weatherCmd.Flags().BoolVar(&weatherSave, "save", false, "Save the location, backend and units as defaults")
+	rootCmd.AddCommand(weatherCmd)
+}
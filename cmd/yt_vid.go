@@ -5,7 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
+	"github.com/ppowo/zzk/internal/notify"
 	"github.com/spf13/cobra"
 )
 
@@ -44,10 +46,14 @@ var ytVidCmd = &cobra.Command{
 		ytCmd := exec.Command("yt-dlp", cmdArgs...)
 		ytCmd.Stdout = os.Stdout
 		ytCmd.Stderr = os.Stderr
+		started := time.Now()
 		if err := ytCmd.Run(); err != nil {
-			return fmt.Errorf("yt-dlp failed: %w", err)
+			err = fmt.Errorf("yt-dlp failed: %w", err)
+			notify.Failed("Download failed", err, started)
+			return err
 		}
 		fmt.Println("✓ Download completed successfully!")
+		notify.Done("Download complete", "Saved to "+destDir, started)
 		return nil
 	},
 }
@@ -0,0 +1,377 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bodgit/sevenzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Format identifies an archive format
+type Format string
+
+const (
+	FormatZip    Format = "zip"
+	Format7z     Format = "7z"
+	FormatTar    Format = "tar"
+	FormatTarGz  Format = "tar.gz"
+	FormatTarXz  Format = "tar.xz"
+	FormatTarZst Format = "tar.zst"
+	FormatTarBz2 Format = "tar.bz2"
+)
+
+// extensions maps file suffixes to formats, longest suffixes first
+var extensions = []struct {
+	suffix string
+	format Format
+}{
+	{".tar.gz", FormatTarGz},
+	{".tar.xz", FormatTarXz},
+	{".tar.zst", FormatTarZst},
+	{".tar.bz2", FormatTarBz2},
+	{".tgz", FormatTarGz},
+	{".txz", FormatTarXz},
+	{".tzst", FormatTarZst},
+	{".tbz2", FormatTarBz2},
+	{".tar", FormatTar},
+	{".zip", FormatZip},
+	{".7z", Format7z},
+}
+
+// Entry describes a single archive member
+type Entry struct {
+	Name  string
+	Size  int64
+	IsDir bool
+}
+
+// ProgressFunc is called after each extracted entry with the completion percentage
+type ProgressFunc func(name string, percent int)
+
+// DetectFormat determines the archive format from the file name, falling back to magic bytes
+func DetectFormat(path string) (Format, error) {
+	lower := strings.ToLower(path)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext.suffix) {
+			return ext.format, nil
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 6)
+	n, _ := io.ReadFull(f, magic)
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")):
+		return FormatZip, nil
+	case bytes.HasPrefix(magic, []byte("7z\xBC\xAF\x27\x1C")):
+		return Format7z, nil
+	case bytes.HasPrefix(magic, []byte{0x1F, 0x8B}):
+		return FormatTarGz, nil
+	case bytes.HasPrefix(magic, []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}):
+		return FormatTarXz, nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xB5, 0x2F, 0xFD}):
+		return FormatTarZst, nil
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return FormatTarBz2, nil
+	}
+
+	return "", fmt.Errorf("unrecognized archive format: %s", path)
+}
+
+// BaseName returns the archive file name without its archive extension
+func BaseName(path string) string {
+	name := filepath.Base(path)
+	lower := strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext.suffix) {
+			return name[:len(name)-len(ext.suffix)]
+		}
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// List returns the entries of an archive
+func List(path string) ([]Entry, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatZip:
+		r, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		entries := make([]Entry, 0, len(r.File))
+		for _, f := range r.File {
+			entries = append(entries, Entry{Name: f.Name, Size: int64(f.UncompressedSize64), IsDir: f.FileInfo().IsDir()})
+		}
+		return entries, nil
+
+	case Format7z:
+		r, err := sevenzip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		entries := make([]Entry, 0, len(r.File))
+		for _, f := range r.File {
+			info := f.FileInfo()
+			entries = append(entries, Entry{Name: f.Name, Size: info.Size(), IsDir: info.IsDir()})
+		}
+		return entries, nil
+
+	default:
+		stream, closer, err := openTarStream(path, format, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer closer.Close()
+
+		var entries []Entry
+		tr := tar.NewReader(stream)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return entries, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read archive: %w", err)
+			}
+			entries = append(entries, Entry{Name: header.Name, Size: header.Size, IsDir: header.Typeflag == tar.TypeDir})
+		}
+	}
+}
+
+// Extract extracts an archive into dest with path-traversal protection
+func Extract(path, dest string, progress ProgressFunc) error {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatZip:
+		return unzip(path, dest, progress)
+	case Format7z:
+		return un7z(path, dest, progress)
+	default:
+		counter := &countingReader{}
+		stream, closer, err := openTarStream(path, format, counter)
+		if err != nil {
+			return err
+		}
+		defer closer.Close()
+
+		var onEntry func(name string)
+		if progress != nil {
+			onEntry = func(name string) {
+				progress(name, counter.percent())
+			}
+		}
+		return extractTar(stream, dest, onEntry)
+	}
+}
+
+// Unzip extracts a zip archive into dest with zip-slip protection
+func Unzip(src, dest string) error {
+	return unzip(src, dest, nil)
+}
+
+func unzip(src, dest string, progress ProgressFunc) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	root, err := openDest(dest)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	for i, f := range r.File {
+		name, err := entryPath(root, f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := root.MkdirAll(name, 0755); err != nil {
+				return err
+			}
+		} else {
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = writeFile(root, name, rc, f.Mode().Perm())
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		if progress != nil {
+			progress(f.Name, (i+1)*100/len(r.File))
+		}
+	}
+	return nil
+}
+
+func un7z(src, dest string, progress ProgressFunc) error {
+	r, err := sevenzip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	root, err := openDest(dest)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	for i, f := range r.File {
+		name, err := entryPath(root, f.Name)
+		if err != nil {
+			return err
+		}
+
+		info := f.FileInfo()
+		if info.IsDir() {
+			if err := root.MkdirAll(name, 0755); err != nil {
+				return err
+			}
+		} else {
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			perm := info.Mode().Perm()
+			if perm == 0 {
+				perm = 0644
+			}
+			err = writeFile(root, name, rc, perm)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		if progress != nil {
+			progress(f.Name, (i+1)*100/len(r.File))
+		}
+	}
+	return nil
+}
+
+// openTarStream opens a (compressed) tar file and returns the decompressed stream.
+// If counter is non-nil it is wired up to track how much of the file has been read.
+func openTarStream(path string, format Format, counter *countingReader) (io.Reader, io.Closer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var src io.Reader = f
+	if counter != nil {
+		if info, err := f.Stat(); err == nil {
+			counter.total = info.Size()
+		}
+		counter.r = f
+		src = counter
+	}
+	src = bufio.NewReader(src)
+
+	var stream io.Reader
+	closer := io.Closer(f)
+
+	switch format {
+	case FormatTar:
+		stream = src
+	case FormatTarGz:
+		gz, err := gzip.NewReader(src)
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("invalid gzip data: %w", err)
+		}
+		stream = gz
+	case FormatTarXz:
+		xzr, err := xz.NewReader(src)
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("invalid xz data: %w", err)
+		}
+		stream = xzr
+	case FormatTarZst:
+		zr, err := zstd.NewReader(src)
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("invalid zstd data: %w", err)
+		}
+		stream = zr
+		closer = closerFunc(func() error {
+			zr.Close()
+			return f.Close()
+		})
+	case FormatTarBz2:
+		stream = bzip2.NewReader(src)
+	default:
+		f.Close()
+		return nil, nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	return stream, closer, nil
+}
+
+type closerFunc func() error
+
+func (c closerFunc) Close() error { return c() }
+
+// countingReader tracks how many bytes have been read from r
+type countingReader struct {
+	r     io.Reader
+	read  int64
+	total int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += int64(n)
+	return n, err
+}
+
+func (c *countingReader) percent() int {
+	if c.total <= 0 {
+		return 0
+	}
+	return int(c.read * 100 / c.total)
+}
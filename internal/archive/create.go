@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Write writes an archive of paths (relative to baseDir) to w in the given format
+func Write(w io.Writer, format Format, baseDir string, paths []string, exclude []string) error {
+	if format == FormatZip {
+		return writeZip(w, baseDir, paths, exclude)
+	}
+
+	compressor, err := newCompressor(w, format)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteTar(compressor, baseDir, paths, exclude); err != nil {
+		compressor.Close()
+		return err
+	}
+
+	return compressor.Close()
+}
+
+// newCompressor wraps w in the compressor for a tar-based format
+func newCompressor(w io.Writer, format Format) (io.WriteCloser, error) {
+	switch format {
+	case FormatTar:
+		return nopWriteCloser{w}, nil
+	case FormatTarGz:
+		return gzip.NewWriter(w), nil
+	case FormatTarXz:
+		return xz.NewWriter(w)
+	case FormatTarZst:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("cannot create %s archives", format)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func writeZip(w io.Writer, baseDir string, paths []string, exclude []string) error {
+	zw := zip.NewWriter(w)
+
+	err := walkPaths(baseDir, paths, exclude, func(path, name string, info os.FileInfo) error {
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil // Zip has no portable symlink support
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+
+		entry, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(entry, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Estimate returns the number of files and total uncompressed bytes that
+// Write would archive
+func Estimate(baseDir string, paths []string, exclude []string) (int, int64, error) {
+	files := 0
+	var size int64
+	err := walkPaths(baseDir, paths, exclude, func(path, name string, info os.FileInfo) error {
+		if info.Mode().IsRegular() {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size, err
+}
+
+// FormatFromName returns the format implied by an output file name
+func FormatFromName(name string) (Format, bool) {
+	lower := strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext.suffix) {
+			return ext.format, true
+		}
+	}
+	return "", false
+}
+
+// Extension returns the canonical file extension for a format
+func Extension(format Format) string {
+	return "." + string(format)
+}
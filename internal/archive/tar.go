@@ -0,0 +1,255 @@
+package archive
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// WriteTar writes paths (relative to baseDir) to w as a tar stream. Entry
+// names are relative to baseDir. Paths with a component matching one of the
+// exclude globs are skipped; absolute globs are matched against the whole
+// path instead, e.g. to leave out the archive being written.
+func WriteTar(w io.Writer, baseDir string, paths []string, exclude []string) error {
+	tw := tar.NewWriter(w)
+
+	err := walkPaths(baseDir, paths, exclude, func(path, name string, info os.FileInfo) error {
+		return addTarEntry(tw, path, name, info)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// walkPaths walks each path under baseDir, calling fn with the absolute path,
+// the slash-separated name relative to baseDir, and the file info (lstat).
+func walkPaths(baseDir string, paths []string, exclude []string, fn func(path, name string, info os.FileInfo) error) error {
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		root := filepath.Join(baseDir, p)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(baseDir, path)
+			if err != nil {
+				return err
+			}
+
+			if path != root && (fileutil.ExcludedPath(rel, exclude) || excludedFile(filepath.Join(absBase, rel), exclude)) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			return fn(path, filepath.ToSlash(rel), info)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excludedFile reports whether one of the absolute exclude globs matches path
+func excludedFile(path string, exclude []string) bool {
+	for _, glob := range exclude {
+		if filepath.IsAbs(glob) {
+			if ok, _ := filepath.Match(glob, path); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addTarEntry writes a single file, directory or symlink to the tar writer
+func addTarEntry(tw *tar.Writer, path, name string, info os.FileInfo) error {
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		link = target
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// ExtractTar extracts a tar stream into dest, rejecting entries that would
+// escape dest (tar-slip protection).
+func ExtractTar(r io.Reader, dest string) error {
+	return extractTar(r, dest, nil)
+}
+
+// extractTar extracts a tar stream, calling onEntry after each entry
+func extractTar(r io.Reader, dest string, onEntry func(name string)) error {
+	root, err := openDest(dest)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name, err := entryPath(root, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := root.MkdirAll(name, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(root, name, tr, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := root.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return err
+			}
+			if !safeSymlink(root, name, header.Linkname) {
+				return fmt.Errorf("illegal symlink target: %s -> %s", header.Name, header.Linkname)
+			}
+			root.Remove(name)
+			if err := root.Symlink(header.Linkname, name); err != nil {
+				return err
+			}
+		default:
+			// Skip devices, fifos and other special entries
+		}
+
+		if onEntry != nil {
+			onEntry(header.Name)
+		}
+	}
+}
+
+// SafeJoin joins name onto dest and fails if the result escapes dest
+func SafeJoin(dest, name string) (string, error) {
+	cleanDest, err := filepath.Abs(dest)
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(cleanDest, name)
+	if target != cleanDest && !strings.HasPrefix(target, cleanDest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path: %s", name)
+	}
+	return target, nil
+}
+
+// openDest creates dest and opens it as the root entries are extracted into.
+// Going through the root, writes can't follow symlinks out of dest, even
+// ones created by earlier entries of the same archive.
+func openDest(dest string) (*os.Root, error) {
+	cleanDest, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cleanDest, 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenRoot(cleanDest)
+}
+
+// entryPath returns the path of an archive entry relative to root, failing
+// if it escapes root
+func entryPath(root *os.Root, name string) (string, error) {
+	target, err := SafeJoin(root.Name(), name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(root.Name(), target)
+}
+
+// safeSymlink reports whether a symlink at name (relative to root) pointing
+// to linkname stays inside root. The link's directory is resolved on disk,
+// since it may itself be reached through a symlink, and ".." is only allowed
+// at the start of linkname, where it can't step back out of a symlink.
+func safeSymlink(root *os.Root, name, linkname string) bool {
+	if filepath.IsAbs(linkname) {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(linkname), "/")
+	for i, part := range parts {
+		if part == ".." && i > 0 && parts[i-1] != ".." {
+			return false
+		}
+	}
+
+	dest, err := filepath.EvalSymlinks(root.Name())
+	if err != nil {
+		return false
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(root.Name(), filepath.Dir(name)))
+	if err != nil {
+		return false
+	}
+	resolved := filepath.Join(dir, linkname)
+	return resolved == dest || strings.HasPrefix(resolved, dest+string(os.PathSeparator))
+}
+
+// writeFile writes r to name inside root, creating parent directories
+func writeFile(root *os.Root, name string, r io.Reader, perm os.FileMode) error {
+	if err := root.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	out, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
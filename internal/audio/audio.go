@@ -0,0 +1,223 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReplayGainReference is the ReplayGain 2.0 reference loudness in LUFS
+const ReplayGainReference = -18.0
+
+// Extensions are the audio file types processed
+var Extensions = map[string]bool{
+	".mp3": true, ".m4a": true, ".opus": true, ".ogg": true,
+	".flac": true, ".wav": true, ".webm": true, ".mka": true,
+}
+
+// Loudness is an EBU R128 measurement of a file
+type Loudness struct {
+	Integrated float64 // LUFS
+	TruePeak   float64 // dBTP
+	Range      float64 // LU
+	Threshold  float64 // LUFS
+	Offset     float64 // LU, from loudnorm's first pass
+}
+
+// TrackGain returns the ReplayGain track gain in dB
+func (l Loudness) TrackGain() float64 {
+	return ReplayGainReference - l.Integrated
+}
+
+// PeakRatio returns the true peak as a linear ratio, as stored in ReplayGain peak tags
+func (l Loudness) PeakRatio() float64 {
+	return math.Pow(10, l.TruePeak/20)
+}
+
+// Find returns audio files under root (or root itself if it is a file), sorted
+func Find(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && path != root {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && Extensions[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// Analyze measures a file's loudness with ffmpeg's loudnorm filter
+func Analyze(path string, target float64) (Loudness, error) {
+	filter := fmt.Sprintf("loudnorm=I=%.1f:TP=-1.0:LRA=11:print_format=json", target)
+	cmd := exec.Command("ffmpeg", "-hide_banner", "-nostats", "-i", path, "-af", filter, "-f", "null", "-")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Loudness{}, fmt.Errorf("ffmpeg failed: %w\n%s", err, lastLines(stderr.String(), 3))
+	}
+
+	// The measurement is the last JSON object ffmpeg prints
+	out := stderr.String()
+	start := strings.LastIndex(out, "{")
+	end := strings.LastIndex(out, "}")
+	if start < 0 || end < start {
+		return Loudness{}, fmt.Errorf("no loudness measurement in ffmpeg output")
+	}
+
+	var raw struct {
+		InputI       string `json:"input_i"`
+		InputTP      string `json:"input_tp"`
+		InputLRA     string `json:"input_lra"`
+		InputThresh  string `json:"input_thresh"`
+		TargetOffset string `json:"target_offset"`
+	}
+	if err := json.Unmarshal([]byte(out[start:end+1]), &raw); err != nil {
+		return Loudness{}, fmt.Errorf("invalid loudness measurement: %w", err)
+	}
+
+	var l Loudness
+	var errs [5]error
+	l.Integrated, errs[0] = strconv.ParseFloat(raw.InputI, 64)
+	l.TruePeak, errs[1] = strconv.ParseFloat(raw.InputTP, 64)
+	l.Range, errs[2] = strconv.ParseFloat(raw.InputLRA, 64)
+	l.Threshold, errs[3] = strconv.ParseFloat(raw.InputThresh, 64)
+	l.Offset, errs[4] = strconv.ParseFloat(raw.TargetOffset, 64)
+	for _, err := range errs {
+		if err != nil {
+			return Loudness{}, fmt.Errorf("file is silent or too short to measure")
+		}
+	}
+	if math.IsInf(l.Integrated, 0) {
+		return Loudness{}, fmt.Errorf("file is silent or too short to measure")
+	}
+	return l, nil
+}
+
+// AlbumLoudness combines track measurements into an album loudness by
+// averaging their energy, and takes the highest peak
+func AlbumLoudness(tracks []Loudness) Loudness {
+	if len(tracks) == 0 {
+		return Loudness{}
+	}
+	var energy float64
+	album := Loudness{TruePeak: math.Inf(-1)}
+	for _, t := range tracks {
+		energy += math.Pow(10, t.Integrated/10)
+		album.TruePeak = math.Max(album.TruePeak, t.TruePeak)
+	}
+	album.Integrated = 10 * math.Log10(energy/float64(len(tracks)))
+	return album
+}
+
+// HasReplayGain reports whether a file already carries a ReplayGain track gain tag
+func HasReplayGain(path string) bool {
+	cmd := exec.Command("ffprobe", "-v", "error", "-show_entries", "format_tags:stream_tags", "-of", "json", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(bytes.ToLower(output), []byte(`"replaygain_track_gain"`))
+}
+
+// WriteReplayGain stores track (and album, if non-nil) ReplayGain tags by
+// remuxing the file without re-encoding
+func WriteReplayGain(path string, track Loudness, album *Loudness) error {
+	args := []string{
+		"-metadata", fmt.Sprintf("REPLAYGAIN_TRACK_GAIN=%.2f dB", track.TrackGain()),
+		"-metadata", fmt.Sprintf("REPLAYGAIN_TRACK_PEAK=%.6f", track.PeakRatio()),
+	}
+	if album != nil {
+		args = append(args,
+			"-metadata", fmt.Sprintf("REPLAYGAIN_ALBUM_GAIN=%.2f dB", album.TrackGain()),
+			"-metadata", fmt.Sprintf("REPLAYGAIN_ALBUM_PEAK=%.6f", album.PeakRatio()),
+		)
+	}
+	args = append(args, "-map", "0", "-c", "copy")
+	if strings.EqualFold(filepath.Ext(path), ".m4a") {
+		// MP4 only keeps arbitrary tags with this flag
+		args = append(args, "-movflags", "use_metadata_tags")
+	}
+	return rewrite(path, args)
+}
+
+// Normalize re-encodes a file to the target loudness using loudnorm's
+// second pass with the given first-pass measurement
+func Normalize(path string, measured Loudness, target float64) error {
+	filter := fmt.Sprintf(
+		"loudnorm=I=%.1f:TP=-1.0:LRA=11:measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:offset=%.2f:linear=true",
+		target, measured.Integrated, measured.TruePeak, measured.Range, measured.Threshold, measured.Offset,
+	)
+
+	args := []string{"-map", "0:a", "-map", "0:v?", "-c:v", "copy", "-af", filter}
+	args = append(args, encoderArgs(filepath.Ext(path))...)
+	return rewrite(path, args)
+}
+
+// encoderArgs picks an encoder matching the file's existing format
+func encoderArgs(ext string) []string {
+	switch strings.ToLower(ext) {
+	case ".mp3":
+		return []string{"-c:a", "libmp3lame", "-q:a", "2"}
+	case ".m4a":
+		return []string{"-c:a", "aac", "-b:a", "256k"}
+	case ".opus", ".webm", ".mka":
+		return []string{"-c:a", "libopus", "-b:a", "160k"}
+	case ".ogg":
+		return []string{"-c:a", "libvorbis", "-q:a", "6"}
+	case ".flac":
+		return []string{"-c:a", "flac"}
+	default:
+		return []string{"-c:a", "pcm_s16le"}
+	}
+}
+
+// rewrite runs ffmpeg on path with args into a temp file in the same
+// directory, then replaces the original
+func rewrite(path string, args []string) error {
+	ext := filepath.Ext(path)
+	tmp := strings.TrimSuffix(path, ext) + ".zzk-tmp" + ext
+
+	full := append([]string{"-hide_banner", "-nostats", "-loglevel", "error", "-y", "-i", path}, args...)
+	full = append(full, "-map_metadata", "0", tmp)
+
+	cmd := exec.Command("ffmpeg", full...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("ffmpeg failed: %w\n%s", err, lastLines(string(output), 3))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
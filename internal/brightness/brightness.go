@@ -0,0 +1,215 @@
+package brightness
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Display is a screen whose brightness can be controlled
+type Display struct {
+	ID    string
+	Name  string
+	Level int // 0-100
+	// raw sysfs value range on Linux
+	max int
+}
+
+// sysfsDir is where Linux exposes backlight devices
+const sysfsDir = "/sys/class/backlight"
+
+// List returns the controllable displays, the default one first.
+//
+// Platform-specific behavior:
+//   - macOS: the brightness tool (brew install brightness)
+//   - Linux: /sys/class/backlight, writing through brightnessctl when sysfs is not writable
+func List() ([]Display, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return listDarwin()
+	case "linux":
+		return listLinux()
+	default:
+		return nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+// Find returns the display with the given ID, or the default display when id is empty
+func Find(id string) (Display, error) {
+	displays, err := List()
+	if err != nil {
+		return Display{}, err
+	}
+	if len(displays) == 0 {
+		return Display{}, fmt.Errorf("no displays with brightness control found")
+	}
+	if id == "" {
+		return displays[0], nil
+	}
+	for _, d := range displays {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return Display{}, fmt.Errorf("display '%s' not found (see 'zzk brightness --list')", id)
+}
+
+// Set changes a display's brightness to level (0-100)
+func Set(d Display, level int) error {
+	level = max(0, min(100, level))
+	switch runtime.GOOS {
+	case "darwin":
+		return run("brightness", "-d", d.ID, strconv.FormatFloat(float64(level)/100, 'f', 2, 64))
+	case "linux":
+		return setLinux(d, level)
+	default:
+		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+// darwinDisplayRegex matches "display 0: main, active, ..." and "display 0: brightness 0.750000"
+var darwinDisplayRegex = regexp.MustCompile(`^display (\d+): (.*)$`)
+
+func listDarwin() ([]Display, error) {
+	if _, err := exec.LookPath("brightness"); err != nil {
+		return nil, fmt.Errorf("brightness is not installed. Install it with: brew install brightness")
+	}
+
+	output, err := exec.Command("brightness", "-l").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list displays: %w", err)
+	}
+
+	var displays []Display
+	byID := make(map[string]int)
+	mainID := ""
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		m := darwinDisplayRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil {
+			continue
+		}
+		id, rest := m[1], m[2]
+
+		if value, ok := strings.CutPrefix(rest, "brightness "); ok {
+			i, known := byID[id]
+			level, err := strconv.ParseFloat(value, 64)
+			if known && err == nil {
+				displays[i].Level = int(math.Round(level * 100))
+			}
+			continue
+		}
+
+		name := "external"
+		if strings.Contains(rest, "built-in") {
+			name = "built-in"
+		}
+		if strings.Contains(rest, "main") {
+			mainID = id
+		}
+		byID[id] = len(displays)
+		displays = append(displays, Display{ID: id, Name: name, Level: -1})
+	}
+
+	// Displays without a brightness line don't support it (e.g. most external monitors)
+	var usable []Display
+	for _, d := range displays {
+		if d.Level < 0 {
+			continue
+		}
+		if d.ID == mainID {
+			usable = append([]Display{d}, usable...)
+		} else {
+			usable = append(usable, d)
+		}
+	}
+	return usable, nil
+}
+
+func listLinux() ([]Display, error) {
+	entries, err := os.ReadDir(sysfsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", sysfsDir, err)
+	}
+
+	var displays []Display
+	for _, entry := range entries {
+		dir := filepath.Join(sysfsDir, entry.Name())
+		current, err := readInt(filepath.Join(dir, "brightness"))
+		if err != nil {
+			continue
+		}
+		maxLevel, err := readInt(filepath.Join(dir, "max_brightness"))
+		if err != nil || maxLevel <= 0 {
+			continue
+		}
+
+		d := Display{
+			ID:    entry.Name(),
+			Name:  readString(filepath.Join(dir, "type")),
+			Level: int(math.Round(float64(current) * 100 / float64(maxLevel))),
+			max:   maxLevel,
+		}
+		// Prefer firmware/platform interfaces over raw, like the kernel does
+		if d.Name == "firmware" || d.Name == "platform" {
+			displays = append([]Display{d}, displays...)
+		} else {
+			displays = append(displays, d)
+		}
+	}
+	return displays, nil
+}
+
+func setLinux(d Display, level int) error {
+	raw := int(math.Round(float64(level) * float64(d.max) / 100))
+	path := filepath.Join(sysfsDir, d.ID, "brightness")
+
+	err := os.WriteFile(path, []byte(strconv.Itoa(raw)+"\n"), 0644)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	// brightnessctl goes through logind, which allows the active session to change it
+	if _, lookErr := exec.LookPath("brightnessctl"); lookErr != nil {
+		return fmt.Errorf("permission denied writing %s. Install brightnessctl or add yourself to the video group", path)
+	}
+	return run("brightnessctl", "-q", "-d", d.ID, "set", strconv.Itoa(raw))
+}
+
+func readInt(path string) (int, error) {
+	return strconv.Atoi(readString(path))
+}
+
+func readString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func run(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s failed: %s", name, msg)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
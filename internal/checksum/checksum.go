@@ -0,0 +1,227 @@
+package checksum
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+	"lukechampine.com/blake3"
+)
+
+// Algorithm names
+const (
+	SHA256 = "sha256"
+	BLAKE3 = "blake3"
+)
+
+// Entry is a single path and its hex digest
+type Entry struct {
+	Path string
+	Hash string
+}
+
+// NewHash returns a hash for the algorithm
+func NewHash(algo string) (hash.Hash, error) {
+	switch strings.ToLower(algo) {
+	case SHA256:
+		return sha256.New(), nil
+	case BLAKE3, "b3":
+		return blake3.New(32, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm '%s' (use sha256 or blake3)", algo)
+	}
+}
+
+// DetectAlgorithm guesses the algorithm from a manifest file name
+func DetectAlgorithm(manifestPath string) string {
+	name := strings.ToLower(filepath.Base(manifestPath))
+	if strings.Contains(name, "b3") || strings.Contains(name, "blake3") {
+		return BLAKE3
+	}
+	return SHA256
+}
+
+// HashReader returns the hex digest of everything read from r
+func HashReader(r io.Reader, algo string) (string, error) {
+	h, err := NewHash(algo)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFile returns the hex digest of a file
+func HashFile(path, algo string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return HashReader(f, algo)
+}
+
+// HashTree hashes every regular file under root. Paths in the result are
+// relative to root, slash-separated and sorted.
+func HashTree(root, algo string, exclude []string) ([]Entry, error) {
+	var entries []Entry
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if path != root && fileutil.ExcludedPath(rel, exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		sum, err := HashFile(path, algo)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		entries = append(entries, Entry{Path: filepath.ToSlash(rel), Hash: sum})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// WriteManifest writes entries in the sha256sum/b3sum format ("<hash>  <path>")
+func WriteManifest(w io.Writer, entries []Entry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s  %s\n", entry.Hash, entry.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseManifest reads a manifest in the sha256sum/b3sum format
+func ParseManifest(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hashPart, pathPart, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: malformed manifest entry", lineNo)
+		}
+		// Binary-mode marker ("<hash> *<path>") as written by sha256sum -b
+		pathPart = strings.TrimPrefix(strings.TrimLeft(pathPart, " "), "*")
+
+		entries = append(entries, Entry{Path: pathPart, Hash: strings.ToLower(hashPart)})
+	}
+	return entries, scanner.Err()
+}
+
+// VerifyResult summarizes a manifest verification
+type VerifyResult struct {
+	OK       []string
+	Mismatch []string
+	Missing  []string
+}
+
+// Verify checks each manifest entry against the files under baseDir
+func Verify(entries []Entry, baseDir, algo string) (*VerifyResult, error) {
+	result := &VerifyResult{}
+	for _, entry := range entries {
+		path := filepath.Join(baseDir, filepath.FromSlash(entry.Path))
+		sum, err := HashFile(path, algo)
+		if err != nil {
+			if os.IsNotExist(err) {
+				result.Missing = append(result.Missing, entry.Path)
+				continue
+			}
+			return nil, fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		if sum == entry.Hash {
+			result.OK = append(result.OK, entry.Path)
+		} else {
+			result.Mismatch = append(result.Mismatch, entry.Path)
+		}
+	}
+	return result, nil
+}
+
+// CompareResult describes the differences between two trees
+type CompareResult struct {
+	OnlyLeft  []string
+	OnlyRight []string
+	Differ    []string
+	Same      int
+}
+
+// Equal reports whether the trees have identical content
+func (r *CompareResult) Equal() bool {
+	return len(r.OnlyLeft) == 0 && len(r.OnlyRight) == 0 && len(r.Differ) == 0
+}
+
+// CompareTrees hashes two directory trees and reports their differences
+func CompareTrees(left, right, algo string, exclude []string) (*CompareResult, error) {
+	leftEntries, err := HashTree(left, algo, exclude)
+	if err != nil {
+		return nil, err
+	}
+	rightEntries, err := HashTree(right, algo, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	rightMap := make(map[string]string, len(rightEntries))
+	for _, entry := range rightEntries {
+		rightMap[entry.Path] = entry.Hash
+	}
+
+	result := &CompareResult{}
+	for _, entry := range leftEntries {
+		rightHash, ok := rightMap[entry.Path]
+		if !ok {
+			result.OnlyLeft = append(result.OnlyLeft, entry.Path)
+			continue
+		}
+		delete(rightMap, entry.Path)
+		if rightHash == entry.Hash {
+			result.Same++
+		} else {
+			result.Differ = append(result.Differ, entry.Path)
+		}
+	}
+	for path := range rightMap {
+		result.OnlyRight = append(result.OnlyRight, path)
+	}
+	sort.Strings(result.OnlyRight)
+
+	return result, nil
+}
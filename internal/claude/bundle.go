@@ -0,0 +1,83 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/ppowo/zzk/internal/crypt"
+)
+
+// BundleVersion is the export bundle layout this version of zzk writes
+const BundleVersion = 1
+
+// Bundle is a set of providers, API keys included, exported to move them to
+// another machine
+type Bundle struct {
+	Version   int                 `json:"version"`
+	Providers map[string]Provider `json:"providers"`
+}
+
+// NewBundle bundles the providers ids of config, or all of them if ids is
+// empty. API keys are included in the bundle instead of vault references.
+func NewBundle(config *Config, ids []string) (*Bundle, error) {
+	if len(ids) == 0 {
+		ids = slices.Sorted(maps.Keys(config.Providers))
+	}
+	bundle := &Bundle{Version: BundleVersion, Providers: make(map[string]Provider, len(ids))}
+	for _, id := range ids {
+		provider, err := config.ResolveKey(id)
+		if err != nil {
+			return nil, err
+		}
+		provider.APIKeyRef = ""
+		bundle.Providers[id] = provider
+	}
+	return bundle, nil
+}
+
+// Encrypt returns the bundle encrypted to passphrase with age
+func (b *Bundle) Encrypt(passphrase string) ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := crypt.Encrypt(&buf, bytes.NewReader(data), crypt.Options{Passphrase: passphrase}); err != nil {
+		return nil, fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// OpenBundle decrypts and validates a bundle written by Bundle.Encrypt
+func OpenBundle(data []byte, passphrase string) (*Bundle, error) {
+	identity, err := crypt.PassphraseIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	r, err := crypt.NewDecryptor(bytes.NewReader(data), identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bundle (wrong passphrase?): %w", err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(plain, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than supported (%d), update zzk", bundle.Version, BundleVersion)
+	}
+	for id, provider := range bundle.Providers {
+		// References point into the exporting machine's vault
+		provider.APIKeyRef = ""
+		bundle.Providers[id] = provider
+	}
+	return &bundle, nil
+}
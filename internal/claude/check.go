@@ -0,0 +1,93 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrKeyRejected is returned by CheckAPIKey when the provider refuses the key
+var ErrKeyRejected = errors.New("API key rejected")
+
+// CheckAPIKey sends an empty Messages API request to baseURL with the key.
+// Providers authenticate before looking at the body, so a rejected key gets
+// 401 or 403 and an accepted one 400 for the missing fields, without using
+// any tokens. Failures other than ErrKeyRejected mean the key couldn't be
+// checked. The request goes through client, see Provider.HTTPClient.
+func CheckAPIKey(ctx context.Context, client *http.Client, baseURL, apiKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := newEmptyMessagesRequest(ctx, baseURL, apiKey)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return checkResponse(req.URL.String(), resp, body)
+}
+
+// newEmptyMessagesRequest builds the request CheckAPIKey sends
+func newEmptyMessagesRequest(ctx context.Context, baseURL, apiKey string) (*http.Request, error) {
+	url := strings.TrimSuffix(baseURL, "/") + "/v1/messages"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader("{}"))
+	if err != nil {
+		return nil, err
+	}
+	// Claude Code sends ANTHROPIC_AUTH_TOKEN as a bearer token
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Anthropic-Version", "2023-06-01")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zzk")
+	return req, nil
+}
+
+// checkResponse interprets the response to an empty Messages API request
+// to url
+func checkResponse(url string, resp *http.Response, body []byte) error {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		if message := apiErrorMessage(body); message != "" {
+			return fmt.Errorf("%w: %s", ErrKeyRejected, message)
+		}
+		return fmt.Errorf("%w (%s)", ErrKeyRejected, resp.Status)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s not found - is the base URL right?", url)
+	case resp.StatusCode < 300 || resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		return nil
+	default:
+		if message := apiErrorMessage(body); message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, message)
+		}
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+}
+
+// apiErrorMessage extracts the message of an Anthropic-style error response
+// ({"error": {"message": ...}}) or an OpenAI-style one ({"error": "..."})
+func apiErrorMessage(body []byte) string {
+	var structured struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &structured) == nil && structured.Error.Message != "" {
+		return structured.Error.Message
+	}
+	var plain struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &plain) == nil {
+		return plain.Error
+	}
+	return ""
+}
@@ -2,17 +2,35 @@ package claude
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ppowo/zzk/internal/fileutil"
+	"github.com/ppowo/zzk/internal/vault"
 )
 
 // Config represents the ~/.claude-providers.json configuration file
 type Config struct {
 	Providers map[string]Provider `json:"providers"`
 	Active    string              `json:"active,omitempty"`
+	// KeyMaxAgeDays is the API key age after which 'zzk claude ls' suggests
+	// rotating it: DefaultKeyMaxAgeDays if 0, never if negative
+	KeyMaxAgeDays int `json:"key_max_age_days,omitempty"`
+
+	EnvSettings             // Timeout and telemetry for all providers
+	Official    EnvSettings `json:"official,omitzero"` // Timeout and telemetry for the official API
+
+	cipher    *configCipher     // Set when the config is kept encrypted
+	vaultKeys map[string]string // API keys as read from or written to the vault
 }
 
 // ConfigPath returns the path to the config file
@@ -39,12 +57,62 @@ func EnsureConfigDir() error {
 	return os.MkdirAll(dir, 0755)
 }
 
+// LockPath returns the path to the lock file of commands changing the config
+func LockPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "zzk", "claude.lock")
+	}
+	return filepath.Join(home, ".config", "zzk", "claude.lock")
+}
+
+// lockTimeout is how long LockConfig waits for another command to finish
+const lockTimeout = 10 * time.Second
+
+// LockConfig takes the lock on the config and env files, so commands
+// changing them can't overwrite each other's changes, e.g. when several
+// terminals start at once. Take it before LoadConfig and hold it until the
+// config is saved and the env file written; the returned function releases
+// it.
+func LockConfig() (unlock func(), err error) {
+	if err := EnsureConfigDir(); err != nil {
+		return nil, err
+	}
+	lock, err := fileutil.Lock(LockPath(), lockTimeout)
+	if errors.Is(err, fileutil.ErrLocked) {
+		return nil, fmt.Errorf("another zzk claude command is changing the config (%s is locked) - try again once it's done", LockPath())
+	}
+	if err != nil {
+		return nil, err
+	}
+	configLocked.Store(true)
+	return func() {
+		configLocked.Store(false)
+		lock.Unlock()
+	}, nil
+}
+
+// configLocked is set while this process holds the config lock, so
+// LoadConfig knows whether it may write the config
+var configLocked atomic.Bool
+
 // LoadConfig loads the configuration from ~/.claude-providers.json
 func LoadConfig() (*Config, error) {
+	if err := LoadTemplates(); err != nil {
+		return nil, err
+	}
 	path := ConfigPath()
 
-	// If file doesn't exist, return empty config
+	// The config is kept encrypted when only the .age file exists
 	stat, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		path = EncryptedConfigPath()
+		stat, err = os.Stat(path)
+	} else if _, encErr := os.Stat(EncryptedConfigPath()); encErr == nil {
+		return nil, fmt.Errorf("both %s and %s exist - remove the stale one", ConfigPath(), EncryptedConfigPath())
+	}
+
+	// If file doesn't exist, return empty config
 	if os.IsNotExist(err) {
 		return &Config{
 			Providers: make(map[string]Provider),
@@ -64,29 +132,62 @@ func LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
+	var cipher *configCipher
+	if path == EncryptedConfigPath() {
+		if data, cipher, err = decryptConfig(data); err != nil {
+			return nil, err
+		}
+	}
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("invalid JSON in config file: %w", err)
 	}
 
+	config.cipher = cipher
+
 	// Initialize map if nil
 	if config.Providers == nil {
 		config.Providers = make(map[string]Provider)
 	}
 
-	// Check for old config format (has base_url or api_token fields)
-	if err := config.detectOldFormat(data); err != nil {
+	// Migrate the old config format (has base_url or api_token fields). It's
+	// only saved under the lock; without it the migrated config is used as
+	// is, and saved by the next command that changes the config.
+	migrated, err := config.migrateOldFormat(data)
+	if err != nil {
 		return nil, err
 	}
+	if migrated != nil && configLocked.Load() {
+		if err := backupOldFormat(path); err != nil {
+			return nil, err
+		}
+		if err := SaveConfig(&config); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+		migrated.print(path + ".pre-migration")
+	}
 
-	// Validate all provider keys are valid template IDs
+	// Validate all provider keys are valid template IDs, or template:profile
 	for name := range config.Providers {
-		if !IsValidTemplate(name) {
+		if !IsValidProviderID(name) {
 			return nil, fmt.Errorf("unknown provider '%s' in config - valid providers: %v", name, TemplateIDs())
 		}
 	}
 
+	// Timeouts out of bounds would be written to the env file as they are
+	if err := config.EnvSettings.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.Official.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: official: %w", err)
+	}
+	for name, provider := range config.Providers {
+		if err := provider.EnvSettings.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config: provider '%s': %w", name, err)
+		}
+	}
+
 	// Auto-fix broken active reference
 	if config.Active != "" {
 		if _, exists := config.Providers[config.Active]; !exists {
@@ -98,72 +199,188 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
-// detectOldFormat checks if the config uses the old format (with base_url field)
-func (c *Config) detectOldFormat(data []byte) error {
-	// Parse as raw JSON to check for old fields
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil // Let the main parser handle JSON errors
+// migrationReport lists what migrateOldFormat did with each old provider
+type migrationReport struct {
+	mapped   []string // "old -> new" lines
+	unmapped []string // Providers left out, with the reason
+}
+
+// print reports a migration on stderr, like other fixes LoadConfig makes
+func (r *migrationReport) print(backup string) {
+	fmt.Fprintf(os.Stderr, "Migrated %s from the old provider format\n", ConfigPath())
+	fmt.Fprintf(os.Stderr, "  The original, API keys included, is in %s - delete it once all is well\n", backup)
+	for _, line := range r.mapped {
+		fmt.Fprintf(os.Stderr, "  ✓ %s\n", line)
+	}
+	for _, line := range r.unmapped {
+		fmt.Fprintf(os.Stderr, "  ⚠ %s\n", line)
 	}
+	if len(r.unmapped) > 0 {
+		fmt.Fprintln(os.Stderr, "  Configure those again with 'zzk claude set <provider>'")
+	}
+}
 
-	providersRaw, ok := raw["providers"]
-	if !ok {
-		return nil // No providers, nothing to check
+// migrateOldFormat converts a config in the old format, whose providers had
+// any name, a base_url and an api_token, in place: each provider is keyed by
+// the template with its base URL, or the template it is named after with
+// the URL as an override, and api_token becomes api_key. A second provider
+// of a template becomes a profile named after it. Returns nil if data isn't
+// in the old format.
+func (c *Config) migrateOldFormat(data []byte) (*migrationReport, error) {
+	var raw struct {
+		Providers map[string]map[string]any `json:"providers"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil // Let the main parser handle JSON errors
 	}
 
-	var providers map[string]json.RawMessage
-	if err := json.Unmarshal(providersRaw, &providers); err != nil {
-		return nil
+	old := false
+	for _, fields := range raw.Providers {
+		_, hasBaseURL := fields["base_url"]
+		_, hasAPIToken := fields["api_token"]
+		old = old || hasBaseURL || hasAPIToken
+	}
+	if !old {
+		return nil, nil
 	}
 
-	for name, providerRaw := range providers {
-		var fields map[string]interface{}
-		if err := json.Unmarshal(providerRaw, &fields); err != nil {
-			continue
+	report := &migrationReport{}
+	providers := make(map[string]Provider)
+	active := ""
+	for _, name := range slices.Sorted(maps.Keys(raw.Providers)) {
+		fields := raw.Providers[name]
+		if token, ok := fields["api_token"]; ok {
+			if _, hasKey := fields["api_key"]; !hasKey {
+				fields["api_key"] = token
+			}
+			delete(fields, "api_token")
 		}
+		baseURL, _ := fields["base_url"].(string)
+		delete(fields, "base_url")
 
-		// Check for old format fields
-		if _, hasBaseURL := fields["base_url"]; hasBaseURL {
-			return fmt.Errorf(`config file uses old format with 'base_url' field
+		var provider Provider
+		if fieldData, err := json.Marshal(fields); err != nil || json.Unmarshal(fieldData, &provider) != nil {
+			report.unmapped = append(report.unmapped, fmt.Sprintf("%s: unreadable settings", name))
+			continue
+		}
 
-The provider configuration format has changed. Provider URLs are now hardcoded.
+		templateID := oldFormatTemplate(name, baseURL)
+		if templateID == "" {
+			report.unmapped = append(report.unmapped, fmt.Sprintf("%s: no provider template has base URL %s", name, baseURL))
+			continue
+		}
+		if tmpl, _ := GetTemplate(templateID); baseURL != "" && strings.TrimSuffix(baseURL, "/") != strings.TrimSuffix(tmpl.BaseURL, "/") {
+			if err := ValidateBaseURL(baseURL); err != nil {
+				report.unmapped = append(report.unmapped, fmt.Sprintf("%s: base URL %s: %v", name, baseURL, err))
+				continue
+			}
+			provider.BaseURLOverride = baseURL
+		}
 
-To migrate, delete %s and reconfigure your providers:
-  rm %s
-  zzk claude add synthetic    # for Synthetic
-  zzk claude add openrouter   # for OpenRouter
-  zzk claude add zai          # for Z.AI
+		id := templateID
+		if _, taken := providers[id]; taken {
+			profile := strings.Trim(profileNameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+			id = templateID + ProfileSeparator + profile
+			if _, taken := providers[id]; taken || !templateIDRegex.MatchString(profile) {
+				report.unmapped = append(report.unmapped, fmt.Sprintf("%s: %s is already configured", name, templateID))
+				continue
+			}
+		}
+		if err := provider.Validate(id); err != nil {
+			report.unmapped = append(report.unmapped, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
 
-Your old provider '%s' had a custom URL which is no longer supported.`, ConfigPath(), ConfigPath(), name)
+		providers[id] = provider
+		line := fmt.Sprintf("%s -> %s", name, id)
+		if provider.BaseURLOverride != "" {
+			line += " (base URL " + provider.BaseURLOverride + " kept as an override)"
 		}
+		report.mapped = append(report.mapped, line)
+		if name == c.Active {
+			active = id
+		}
+	}
 
-		if _, hasAPIToken := fields["api_token"]; hasAPIToken {
-			return fmt.Errorf(`config file uses old format with 'api_token' field
+	c.Providers = providers
+	c.Active = active
+	return report, nil
+}
 
-The provider configuration format has changed. The field is now 'api_key'.
+// profileNameRegex matches runs of characters profile names can't have
+var profileNameRegex = regexp.MustCompile(`[^a-z0-9_-]+`)
 
-To migrate, delete %s and reconfigure your providers:
-  rm %s
-  zzk claude add synthetic    # for Synthetic
-  zzk claude add openrouter   # for OpenRouter
-  zzk claude add zai          # for Z.AI`, ConfigPath(), ConfigPath())
+// oldFormatTemplate returns the template of an old-format provider: the
+// one with its base URL, else the one it is named after, else ""
+func oldFormatTemplate(name, baseURL string) string {
+	for _, tmpl := range ListTemplates() {
+		if baseURL != "" && strings.TrimSuffix(tmpl.BaseURL, "/") == strings.TrimSuffix(baseURL, "/") {
+			return tmpl.ID
 		}
 	}
+	if IsValidTemplate(name) {
+		return name
+	}
+	return ""
+}
 
-	return nil
+// backupOldFormat keeps a copy of a config in the old format before it is
+// migrated
+func backupOldFormat(path string) error {
+	backup := path + ".pre-migration"
+	if _, err := os.Stat(backup); err == nil {
+		return nil // Keep the first original
+	}
+	if err := fileutil.CopyFile(path, backup); err != nil {
+		return fmt.Errorf("failed to back up the old config before migrating it: %w", err)
+	}
+	return os.Chmod(backup, 0600)
 }
 
-// SaveConfig saves the configuration to ~/.claude-providers.json
+// SaveConfig saves the configuration to ~/.claude-providers.json.
+// API keys are moved to the vault and only referenced from the file.
 func SaveConfig(config *Config) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+	stored := Config{Providers: make(map[string]Provider, len(config.Providers)), Active: config.Active, KeyMaxAgeDays: config.KeyMaxAgeDays,
+		EnvSettings: config.EnvSettings, Official: config.Official}
+	for name, provider := range config.Providers {
+		// Only keys that differ from the vault's copy are written
+		if provider.APIKey != "" && provider.APIKey != config.vaultKeys[name] {
+			ref := KeyRef(name)
+			if err := vault.Set(ref, provider.APIKey); err != nil {
+				return fmt.Errorf("failed to store API key for '%s' in the vault: %w", name, err)
+			}
+			provider.APIKeyRef = ref
+			config.Providers[name] = provider
+			if config.vaultKeys == nil {
+				config.vaultKeys = make(map[string]string)
+			}
+			config.vaultKeys[name] = provider.APIKey
+		}
+		provider.APIKey = ""
+		stored.Providers[name] = provider
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	if config.cipher != nil {
+		encrypted, err := config.cipher.encrypt(data)
+		if err != nil {
+			return err
+		}
+		if err := fileutil.AtomicWrite(EncryptedConfigPath(), encrypted, 0600); err != nil {
+			return err
+		}
+		// Don't leave plaintext copies next to it
+		return removeFiles(ConfigPath(), ConfigPath()+".backup")
+	}
+
 	path := ConfigPath()
 
-	// Create backup if original exists
-	if _, err := os.Stat(path); err == nil {
+	// Create backup if original exists, unless it would keep plaintext keys around
+	if existing, err := os.ReadFile(path); err == nil && !hasPlaintextKeys(existing) {
 		backup := path + ".backup"
 		if err := fileutil.CopyFile(path, backup); err != nil {
 			// Non-fatal: warn but continue
@@ -171,7 +388,103 @@ func SaveConfig(config *Config) error {
 		}
 	}
 
-	return fileutil.AtomicWrite(path, data, 0600)
+	if err := fileutil.AtomicWrite(path, data, 0600); err != nil {
+		return err
+	}
+	return removeFiles(EncryptedConfigPath())
+}
+
+// removeFiles removes paths, ignoring those that don't exist
+func removeFiles(paths ...string) error {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// KeyRef returns the vault secret name holding a provider's API key:
+// claude/<template>, or claude/<template>/<profile>
+func KeyRef(templateID string) string {
+	return "claude/" + strings.Replace(templateID, ProfileSeparator, "/", 1)
+}
+
+// ResolveKey returns a configured provider with its API key read from the
+// vault. LoadConfig leaves that to the commands that use the key, so the
+// others don't open the vault.
+func (c *Config) ResolveKey(id string) (Provider, error) {
+	provider, ok := c.Providers[id]
+	if !ok {
+		return Provider{}, fmt.Errorf("provider '%s' not configured", id)
+	}
+	if provider.APIKey != "" || provider.APIKeyRef == "" {
+		return provider, nil
+	}
+	key, err := vault.Get(provider.APIKeyRef)
+	if err != nil {
+		return Provider{}, fmt.Errorf("failed to read API key for '%s' from the vault: %w", id, err)
+	}
+	provider.APIKey = key
+	c.Providers[id] = provider
+	if c.vaultKeys == nil {
+		c.vaultKeys = make(map[string]string)
+	}
+	c.vaultKeys[id] = key
+	return provider, nil
+}
+
+// PlaintextKeys lists providers whose API key is still stored in the config file
+func (c *Config) PlaintextKeys() []string {
+	var names []string
+	for name, provider := range c.Providers {
+		if provider.APIKey != "" && provider.APIKeyRef == "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RemovePlaintextBackup deletes the config backup if it still holds plaintext
+// API keys, returning whether anything was removed
+func RemovePlaintextBackup() (bool, error) {
+	backup := ConfigPath() + ".backup"
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !hasPlaintextKeys(data) {
+		return false, nil
+	}
+	return true, os.Remove(backup)
+}
+
+// hasPlaintextKeys reports whether config file data holds API keys in plaintext
+func hasPlaintextKeys(data []byte) bool {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return false
+	}
+	if len(config.PlaintextKeys()) > 0 {
+		return true
+	}
+	// The old format kept them as api_token
+	var old struct {
+		Providers map[string]struct {
+			APIToken string `json:"api_token"`
+		} `json:"providers"`
+	}
+	json.Unmarshal(data, &old)
+	for _, provider := range old.Providers {
+		if provider.APIToken != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // HasProvider checks if a provider exists in the config
@@ -186,10 +499,56 @@ func (c *Config) GetProvider(templateID string) (Provider, bool) {
 	return provider, ok
 }
 
+// DefaultKeyMaxAgeDays is the API key age after which rotating it is
+// suggested, unless the config sets another
+const DefaultKeyMaxAgeDays = 90
+
+// KeyMaxAge returns the API key age after which rotating it is suggested,
+// and false if it never is
+func (c *Config) KeyMaxAge() (time.Duration, bool) {
+	days := c.KeyMaxAgeDays
+	if days < 0 {
+		return 0, false
+	}
+	if days == 0 {
+		days = DefaultKeyMaxAgeDays
+	}
+	return time.Duration(days) * 24 * time.Hour, true
+}
+
+// StaleKey reports whether a provider's API key is older than KeyMaxAge,
+// returning its age in days
+func (c *Config) StaleKey(id string) (int, bool) {
+	provider, ok := c.Providers[id]
+	if !ok {
+		return 0, false
+	}
+	age, known := provider.KeyAge()
+	maxAge, enabled := c.KeyMaxAge()
+	if !known || !enabled || age < maxAge {
+		return 0, false
+	}
+	return int(age.Hours() / 24), true
+}
+
+// Profiles returns the IDs of the configured providers of a template, its
+// unnamed profile first, then the named ones sorted
+func (c *Config) Profiles(templateID string) []string {
+	var ids []string
+	for id := range c.Providers {
+		if tmpl, _ := SplitProviderID(id); tmpl == templateID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids) // "synthetic" sorts before "synthetic:..."
+	return ids
+}
+
 // AddProvider adds or updates a provider in the config.
-// The templateID must be a valid template from the registry.
+// The templateID must be a valid template from the registry, optionally
+// followed by a profile name ("synthetic:work").
 func (c *Config) AddProvider(templateID string, provider Provider) error {
-	if !IsValidTemplate(templateID) {
+	if !IsValidProviderID(templateID) {
 		return fmt.Errorf("unknown provider template: %s (valid: %v)", templateID, TemplateIDs())
 	}
 	if err := provider.Validate(templateID); err != nil {
@@ -205,6 +564,7 @@ func (c *Config) RemoveProvider(templateID string) error {
 		return fmt.Errorf("provider '%s' not configured", templateID)
 	}
 	delete(c.Providers, templateID)
+	delete(c.vaultKeys, templateID)
 
 	// Clear active if this was the active provider
 	if c.Active == templateID {
@@ -2,15 +2,21 @@ package claude
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+
+	"golang.org/x/term"
 )
 
 // PromptForProvider prompts the user for provider configuration.
 // If existingProvider is not nil, it pre-fills with existing values.
-func PromptForProvider(templateID string, existingProvider *Provider) (*Provider, error) {
-	tmpl, ok := GetTemplate(templateID)
+// If modelsURL is set and stdin is a terminal, model overrides are picked
+// from the models listed there, fetched through client.
+func PromptForProvider(templateID string, existingProvider *Provider, modelsURL string, client *http.Client) (*Provider, error) {
+	tmpl, ok := TemplateFor(templateID)
 	if !ok {
 		return nil, fmt.Errorf("unknown provider template: %s", templateID)
 	}
@@ -29,7 +35,15 @@ func PromptForProvider(templateID string, existingProvider *Provider) (*Provider
 
 	// Prompt for model overrides if the template allows it
 	if tmpl.AllowModels {
-		models, err := promptForModels(reader, tmpl, existingProvider)
+		var available []Model
+		if modelsURL != "" && term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Println("\nFetching models...")
+			available, err = ListModels(context.Background(), client, modelsURL, apiKey)
+			if err != nil {
+				fmt.Printf("⚠ Could not list models, type their IDs instead: %v\n", err)
+			}
+		}
+		models, err := promptForModels(reader, tmpl, existingProvider, available)
 		if err != nil {
 			return nil, err
 		}
@@ -47,19 +61,20 @@ func PromptForProvider(templateID string, existingProvider *Provider) (*Provider
 	return provider, nil
 }
 
-// promptForAPIKey prompts for and reads the API key
+// promptForAPIKey prompts for and reads the API key, without echo on a
+// terminal
 func promptForAPIKey(reader *bufio.Reader, existing *Provider) (string, error) {
 	var defaultVal string
 	if existing != nil && existing.APIKey != "" {
 		// Show masked version of existing key
-		maskedKey := maskAPIKey(existing.APIKey)
+		maskedKey := MaskAPIKey(existing.APIKey)
 		fmt.Printf("API key [current: %s]: ", maskedKey)
 		defaultVal = existing.APIKey
 	} else {
 		fmt.Print("API key: ")
 	}
 
-	line, err := reader.ReadString('\n')
+	line, err := readSecret(reader)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
@@ -75,8 +90,49 @@ func promptForAPIKey(reader *bufio.Reader, existing *Provider) (string, error) {
 	return line, nil
 }
 
-// maskAPIKey returns a masked version of an API key for display
-func maskAPIKey(key string) string {
+// readSecret reads a line without echo if stdin is a terminal, else from
+// reader
+func readSecret(reader *bufio.Reader) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+		return line, nil
+	}
+
+	data, err := term.ReadPassword(fd)
+	fmt.Println() // The user's Enter isn't echoed either
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PromptForNewAPIKey reads an API key replacing current, without echo on a
+// terminal, or as a line from stdin otherwise
+func PromptForNewAPIKey(current string) (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Printf("New API key [current: %s]: ", MaskAPIKey(current))
+	}
+	key, err := readSecret(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	key = strings.TrimSpace(key)
+	switch key {
+	case "":
+		return "", fmt.Errorf("API key is required")
+	case current:
+		return "", fmt.Errorf("the new API key is the current one")
+	}
+	return key, nil
+}
+
+// MaskAPIKey returns a masked version of an API key for display
+func MaskAPIKey(key string) string {
 	if len(key) <= 8 {
 		return "********"
 	}
@@ -91,8 +147,9 @@ type modelConfig struct {
 	SubagentModel string
 }
 
-// promptForModels prompts for model overrides
-func promptForModels(reader *bufio.Reader, tmpl *ProviderTemplate, existing *Provider) (modelConfig, error) {
+// promptForModels prompts for model overrides, with the picker if the
+// provider's models are available
+func promptForModels(reader *bufio.Reader, tmpl *ProviderTemplate, existing *Provider, available []Model) (modelConfig, error) {
 	var models modelConfig
 	var err error
 
@@ -106,28 +163,39 @@ func promptForModels(reader *bufio.Reader, tmpl *ProviderTemplate, existing *Pro
 		return tmpl.DefaultModel, false // template default
 	}
 
-	fmt.Println("\nModel overrides (leave empty to keep shown value):")
+	prompt := func(label, defaultVal string, isCurrent bool) (string, error) {
+		if available == nil {
+			return promptForModelWithSource(reader, label, defaultVal, isCurrent)
+		}
+		return pickModelWithSource(label, available, defaultVal, isCurrent)
+	}
+
+	if available == nil {
+		fmt.Println("\nModel overrides (leave empty to keep shown value):")
+	} else {
+		fmt.Println("\nModel overrides (esc keeps the shown value):")
+	}
 
 	opusVal, opusIsCurrent := getDefaultWithSource("opus")
-	models.OpusModel, err = promptForModelWithSource(reader, "Opus model", opusVal, opusIsCurrent)
+	models.OpusModel, err = prompt("Opus model", opusVal, opusIsCurrent)
 	if err != nil {
 		return models, err
 	}
 
 	sonnetVal, sonnetIsCurrent := getDefaultWithSource("sonnet")
-	models.SonnetModel, err = promptForModelWithSource(reader, "Sonnet model", sonnetVal, sonnetIsCurrent)
+	models.SonnetModel, err = prompt("Sonnet model", sonnetVal, sonnetIsCurrent)
 	if err != nil {
 		return models, err
 	}
 
 	haikuVal, haikuIsCurrent := getDefaultWithSource("haiku")
-	models.HaikuModel, err = promptForModelWithSource(reader, "Haiku model", haikuVal, haikuIsCurrent)
+	models.HaikuModel, err = prompt("Haiku model", haikuVal, haikuIsCurrent)
 	if err != nil {
 		return models, err
 	}
 
 	subagentVal, subagentIsCurrent := getDefaultWithSource("subagent")
-	models.SubagentModel, err = promptForModelWithSource(reader, "Subagent model", subagentVal, subagentIsCurrent)
+	models.SubagentModel, err = prompt("Subagent model", subagentVal, subagentIsCurrent)
 	if err != nil {
 		return models, err
 	}
@@ -153,6 +221,21 @@ func getExistingModel(existing *Provider, modelType string) string {
 	return ""
 }
 
+// pickModelWithSource has the user pick a single model override, printing
+// the choice as the text prompt would show it
+func pickModelWithSource(label string, available []Model, defaultVal string, isCurrent bool) (string, error) {
+	value, err := pickModel(label, available, defaultVal, isCurrent)
+	if err != nil {
+		return "", err
+	}
+	if value == "default" {
+		fmt.Printf("  %s: (template default)\n", label)
+		return "", nil // Reset to template default
+	}
+	fmt.Printf("  %s: %s\n", label, value)
+	return value, nil
+}
+
 // promptForModelWithSource prompts for a single model override, showing source label
 func promptForModelWithSource(reader *bufio.Reader, label string, defaultVal string, isCurrent bool) (string, error) {
 	if defaultVal != "" {
@@ -0,0 +1,142 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/ppowo/zzk/internal/crypt"
+)
+
+// EncryptedConfigPath returns where the config is kept when encrypted
+func EncryptedConfigPath() string {
+	return ConfigPath() + ".age"
+}
+
+// ConfigKeyPath returns the age key the config is encrypted to, unless a
+// passphrase is used
+func ConfigKeyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "claude-key.txt"
+	}
+	return filepath.Join(home, ".config", "zzk", "claude-key.txt")
+}
+
+// configCipher encrypts the config at rest, to a passphrase or to the key at
+// ConfigKeyPath
+type configCipher struct {
+	passphrase string
+	identity   *age.X25519Identity
+}
+
+// cachedPassphrase saves asking twice when one command loads the config
+// more than once
+var cachedPassphrase string
+
+// newConfigCipher returns a cipher using a passphrase (read from
+// $ZZK_CRYPT_PASSPHRASE or prompted) or the key at ConfigKeyPath, created if
+// needed
+func newConfigCipher(passphrase, confirm bool) (*configCipher, error) {
+	if !passphrase {
+		if err := EnsureConfigDir(); err != nil {
+			return nil, err
+		}
+		id, err := crypt.LoadOrCreateKey(ConfigKeyPath())
+		if err != nil {
+			return nil, err
+		}
+		return &configCipher{identity: id}, nil
+	}
+
+	if cachedPassphrase == "" {
+		value, err := crypt.ReadPassphrase(confirm)
+		if err != nil {
+			return nil, err
+		}
+		cachedPassphrase = value
+	}
+	return &configCipher{passphrase: cachedPassphrase}, nil
+}
+
+// usesPassphrase reports whether age data was encrypted to a passphrase,
+// from the scrypt stanza in its header
+func usesPassphrase(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "-> scrypt ") {
+			return true
+		}
+		if strings.HasPrefix(line, "---") {
+			break
+		}
+	}
+	return false
+}
+
+// decryptConfig decrypts an encrypted config file, returning the cipher to
+// encrypt it again with
+func decryptConfig(data []byte) ([]byte, *configCipher, error) {
+	cipher, err := newConfigCipher(usesPassphrase(data), false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var identity age.Identity = cipher.identity
+	if cipher.passphrase != "" {
+		if identity, err = crypt.PassphraseIdentity(cipher.passphrase); err != nil {
+			return nil, nil, err
+		}
+	}
+	r, err := crypt.NewDecryptor(bytes.NewReader(data), identity)
+	if err != nil {
+		cachedPassphrase = ""
+		return nil, nil, fmt.Errorf("failed to decrypt %s (wrong passphrase or key?): %w", EncryptedConfigPath(), err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt %s: %w", EncryptedConfigPath(), err)
+	}
+	return plain, cipher, nil
+}
+
+// encrypt encrypts config file data
+func (c *configCipher) encrypt(data []byte) ([]byte, error) {
+	opts := crypt.Options{Passphrase: c.passphrase}
+	if c.identity != nil {
+		opts.Recipients = []age.Recipient{c.identity.Recipient()}
+	}
+
+	var buf bytes.Buffer
+	if err := crypt.Encrypt(&buf, bytes.NewReader(data), opts); err != nil {
+		return nil, fmt.Errorf("failed to encrypt config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Encrypted reports whether the config is kept encrypted
+func (c *Config) Encrypted() bool {
+	return c.cipher != nil
+}
+
+// Encrypt makes SaveConfig write the config encrypted to EncryptedConfigPath,
+// with a passphrase or the key at ConfigKeyPath
+func (c *Config) Encrypt(passphrase bool) error {
+	cipher, err := newConfigCipher(passphrase, true)
+	if err != nil {
+		return err
+	}
+	c.cipher = cipher
+	return nil
+}
+
+// Decrypt makes SaveConfig write the config in plaintext again
+func (c *Config) Decrypt() {
+	c.cipher = nil
+}
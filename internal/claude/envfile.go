@@ -0,0 +1,60 @@
+package claude
+
+import "strings"
+
+// envFileExt returns the env file extension for shell: nushell and
+// PowerShell can't source POSIX exports
+func envFileExt(shell string) string {
+	switch shell {
+	case "nu":
+		return ".nu"
+	case "pwsh", "powershell":
+		return ".ps1"
+	default:
+		return ".sh"
+	}
+}
+
+// envFileContent returns the commands setting vars, and unsetting those
+// without a value, in shell's syntax
+func envFileContent(shell string, vars []EnvVar) string {
+	var buf strings.Builder
+	switch envFileExt(shell) {
+	case ".nu":
+		var set []EnvVar
+		for _, v := range vars {
+			if v.Value == "" {
+				buf.WriteString("hide-env -i " + v.Key + "\n")
+			} else {
+				set = append(set, v)
+			}
+		}
+		buf.WriteString("load-env {\n")
+		for _, v := range set {
+			buf.WriteString("    " + v.Key + ": " + nuQuote(v.Value) + "\n")
+		}
+		buf.WriteString("}\n")
+	case ".ps1":
+		for _, v := range vars {
+			if v.Value == "" {
+				buf.WriteString("Remove-Item Env:" + v.Key + " -ErrorAction SilentlyContinue\n")
+			} else {
+				buf.WriteString("$env:" + v.Key + " = " + powerShellQuote(v.Value) + "\n")
+			}
+		}
+	default:
+		buf.WriteString(ShellExports(vars))
+	}
+	return buf.String()
+}
+
+// nuQuote quotes s as a nushell double-quoted string
+func nuQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellQuote quotes s as a PowerShell single-quoted string, in which
+// only quotes are special
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
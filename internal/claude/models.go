@@ -0,0 +1,89 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Model is a model offered by a provider
+type Model struct {
+	ID   string
+	Name string // Display name, if the provider has one
+}
+
+// ModelsURL returns the URL listing the provider's models: the template's
+// models_url, or <base URL>/v1/models. An overridden base URL always uses
+// the latter.
+func (p *Provider) ModelsURL(tmpl *ProviderTemplate) string {
+	if tmpl.ModelsURL != "" && p.BaseURLOverride == "" {
+		return tmpl.ModelsURL
+	}
+	return strings.TrimSuffix(p.BaseURL(tmpl), "/") + "/v1/models"
+}
+
+// ListModels fetches the models listed at url, which answers in the format
+// shared by the Anthropic and OpenAI APIs ({"data": [{"id": ...}]}), sorted
+// by ID. The request goes through client, see Provider.HTTPClient.
+func ListModels(ctx context.Context, client *http.Client, url, apiKey string) ([]Model, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Anthropic-Version", "2023-06-01")
+	req.Header.Set("User-Agent", "zzk")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// OpenRouter lists hundreds of models with long descriptions
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if message := apiErrorMessage(body); message != "" {
+			return nil, fmt.Errorf("%s: %s", resp.Status, message)
+		}
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	var list struct {
+		Data []struct {
+			ID          string `json:"id"`
+			Name        string `json:"name"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid model list: %w", err)
+	}
+
+	models := make([]Model, 0, len(list.Data))
+	for _, entry := range list.Data {
+		if entry.ID == "" {
+			continue
+		}
+		name := entry.DisplayName
+		if name == "" {
+			name = entry.Name
+		}
+		models = append(models, Model{ID: entry.ID, Name: name})
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("%s lists no models", url)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
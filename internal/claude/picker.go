@@ -0,0 +1,190 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pickerRows is how many models the picker shows at once
+const pickerRows = 10
+
+var (
+	pickerSelected = lipgloss.NewStyle().Reverse(true)
+	pickerDim      = lipgloss.NewStyle().Faint(true)
+)
+
+// modelPicker lets the user choose a model from a list by typing to filter
+// it. Enter picks the selected model or, if none matches, the typed text;
+// Esc keeps the shown value.
+type modelPicker struct {
+	label  string
+	shown  string // Value kept on Esc
+	source string // "current" or "default"
+	models []Model
+
+	filter  string
+	matches []Model
+	cursor  int
+	offset  int
+
+	chosen    string
+	done      bool
+	cancelled bool
+}
+
+// pickModel runs the picker, returning the chosen model ID, shown if the
+// user kept it, or "default" to reset it to the template default
+func pickModel(label string, models []Model, shown string, isCurrent bool) (string, error) {
+	m := &modelPicker{label: label, shown: shown, source: "default", models: models}
+	if isCurrent {
+		m.source = "current"
+	}
+	m.applyFilter()
+	for i, model := range m.matches {
+		if model.ID == shown {
+			m.cursor = i
+			m.scroll()
+		}
+	}
+
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		return "", fmt.Errorf("model picker failed: %w", err)
+	}
+	if m.cancelled {
+		return "", fmt.Errorf("cancelled")
+	}
+	return m.chosen, nil
+}
+
+// applyFilter keeps the models whose ID or name contains every word of the
+// filter, ignoring case
+func (m *modelPicker) applyFilter() {
+	terms := strings.Fields(strings.ToLower(m.filter))
+	m.matches = m.matches[:0]
+	for _, model := range m.models {
+		text := strings.ToLower(model.ID + " " + model.Name)
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			m.matches = append(m.matches, model)
+		}
+	}
+	m.cursor, m.offset = 0, 0
+}
+
+// scroll keeps the cursor within the visible rows
+func (m *modelPicker) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+pickerRows {
+		m.offset = m.cursor - pickerRows + 1
+	}
+}
+
+func (m *modelPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (m *modelPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.Type {
+	case tea.KeyCtrlC:
+		m.cancelled = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.chosen, m.done = m.shown, true
+		return m, tea.Quit
+	case tea.KeyEnter:
+		switch {
+		case len(m.matches) > 0:
+			m.chosen = m.matches[m.cursor].ID
+		case strings.TrimSpace(m.filter) != "":
+			m.chosen = strings.TrimSpace(m.filter)
+		default:
+			m.chosen = m.shown
+		}
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case tea.KeyPgUp:
+		m.cursor = max(m.cursor-pickerRows, 0)
+	case tea.KeyPgDown:
+		m.cursor = max(min(m.cursor+pickerRows, len(m.matches)-1), 0)
+	case tea.KeyBackspace:
+		if m.filter != "" {
+			runes := []rune(m.filter)
+			m.filter = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	case tea.KeyCtrlU:
+		m.filter = ""
+		m.applyFilter()
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(key.Runes)
+		if key.Type == tea.KeySpace {
+			m.filter += " "
+		}
+		m.applyFilter()
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m *modelPicker) View() string {
+	// Leave nothing behind; pickModel's caller prints the choice
+	if m.done || m.cancelled {
+		return ""
+	}
+
+	var b strings.Builder
+	if m.shown != "" {
+		fmt.Fprintf(&b, "  %s [%s: %s]\n", m.label, m.source, m.shown)
+	} else {
+		fmt.Fprintf(&b, "  %s\n", m.label)
+	}
+	fmt.Fprintf(&b, "  > %s█\n", m.filter)
+
+	if len(m.matches) == 0 {
+		hint := "No matching models"
+		if strings.TrimSpace(m.filter) != "" {
+			hint += fmt.Sprintf(" - enter uses %q", strings.TrimSpace(m.filter))
+		}
+		b.WriteString("    " + pickerDim.Render(hint) + "\n")
+	}
+	for i := m.offset; i < len(m.matches) && i < m.offset+pickerRows; i++ {
+		model := m.matches[i]
+		row := model.ID
+		if i == m.cursor {
+			row = pickerSelected.Render(row)
+		}
+		if model.Name != "" && model.Name != model.ID {
+			row += " " + pickerDim.Render("("+model.Name+")")
+		}
+		b.WriteString("    " + row + "\n")
+	}
+
+	fmt.Fprintf(&b, "  %s\n", pickerDim.Render(fmt.Sprintf(
+		"%d of %d models · type to filter · ↑/↓ select · enter pick · esc keep shown · 'default' resets",
+		len(m.matches), len(m.models))))
+	return b.String()
+}
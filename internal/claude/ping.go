@@ -0,0 +1,67 @@
+package claude
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// Ping is the timing of one empty Messages API request to a provider. The
+// connection phases are zero when an open connection was reused, so the
+// first ping shows the cost of reaching the provider and the following ones
+// mostly the provider itself.
+type Ping struct {
+	Status string // HTTP status, e.g. "400 Bad Request"
+	Err    error  // From CheckAPIKey's interpretation of the response
+
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	Server  time.Duration // Request written to first response byte
+	Total   time.Duration
+}
+
+// PingProvider sends the empty Messages API request CheckAPIKey does and
+// times its phases. The error is only set if no response came back; a
+// response saying the key or URL is wrong is in Ping.Err.
+func PingProvider(ctx context.Context, client *http.Client, baseURL, apiKey string) (*Ping, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var ping Ping
+	var dnsStart, connectStart, tlsStart, wrote time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { ping.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { ping.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { ping.TLS = time.Since(tlsStart) },
+		WroteRequest:      func(httptrace.WroteRequestInfo) { wrote = time.Now() },
+		GotFirstResponseByte: func() {
+			if !wrote.IsZero() {
+				ping.Server = time.Since(wrote)
+			}
+		},
+	}
+
+	req, err := newEmptyMessagesRequest(httptrace.WithClientTrace(ctx, trace), baseURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	ping.Total = time.Since(start)
+
+	ping.Status = resp.Status
+	ping.Err = checkResponse(req.URL.String(), resp, body)
+	return &ping, nil
+}
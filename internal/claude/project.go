@@ -0,0 +1,85 @@
+package claude
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectFile names a project's provider. It is looked up in the current
+// directory and its parents.
+const ProjectFile = ".zzk-claude"
+
+// ProviderEnv names the environment variable that selects the provider,
+// e.g. exported from a direnv .envrc. It takes precedence over ProjectFile.
+const ProviderEnv = "ZZK_CLAUDE_PROVIDER"
+
+// OfficialProvider selects the official Anthropic API in ProjectFile or
+// ProviderEnv
+const OfficialProvider = "anthropic"
+
+// ProjectProvider returns the provider selected for dir by ProviderEnv or
+// the nearest ProjectFile, and where it was found. id is OfficialProvider
+// for the official API, or empty if nothing selects a provider.
+func ProjectProvider(dir string) (id, source string, err error) {
+	if value := strings.TrimSpace(os.Getenv(ProviderEnv)); value != "" {
+		id, err := resolveProjectProvider(value)
+		if err != nil {
+			return "", "", fmt.Errorf("$%s: %w", ProviderEnv, err)
+		}
+		return id, "$" + ProviderEnv, nil
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		value, err := readProjectFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return "", "", err
+		}
+		if err == nil {
+			id, err := resolveProjectProvider(value)
+			if err != nil {
+				return "", "", fmt.Errorf("%s: %w", path, err)
+			}
+			return id, path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+// readProjectFile returns the first line of a ProjectFile that isn't empty
+// or a # comment
+func readProjectFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s names no provider", path)
+}
+
+// resolveProjectProvider resolves a provider prefix, keeping OfficialProvider
+func resolveProjectProvider(value string) (string, error) {
+	if value == OfficialProvider {
+		return value, nil
+	}
+	return ResolveProviderID(value)
+}
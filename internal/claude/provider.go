@@ -2,17 +2,42 @@ package claude
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 )
 
 // Provider represents a user's configuration for a Claude API provider.
-// The provider template (ID, base URL) is determined by the template registry.
+// The provider template (ID, base URL) is determined by the template registry;
+// BaseURLOverride replaces the template's base URL, e.g. for a regional
+// endpoint or a staging gateway.
+// APIKey is only written to the config file by versions that predate the
+// vault; it is now stored in the vault under APIKeyRef and read by Config.ResolveKey.
+// KeyRotatedAt is when the API key was last set, zero for keys set by
+// versions that didn't record it. HTTPSProxy and NoProxy are exported for
+// Claude Code, e.g. for a gateway only reachable through a corporate proxy.
 type Provider struct {
-	APIKey        string `json:"api_key"`
+	APIKey        string `json:"api_key,omitempty"`
+	APIKeyRef     string `json:"api_key_ref,omitempty"`
 	OpusModel     string `json:"opus_model,omitempty"`
 	SonnetModel   string `json:"sonnet_model,omitempty"`
 	HaikuModel    string `json:"haiku_model,omitempty"`
 	SubagentModel string `json:"subagent_model,omitempty"`
+
+	BaseURLOverride string    `json:"base_url_override,omitempty"`
+	KeyRotatedAt    time.Time `json:"key_rotated_at,omitzero"`
+	HTTPSProxy      string    `json:"https_proxy,omitempty"`
+	NoProxy         string    `json:"no_proxy,omitempty"`
+
+	EnvSettings // Timeout and telemetry for this provider
+}
+
+// KeyAge returns how long ago the API key was set, and false if unknown
+func (p *Provider) KeyAge() (time.Duration, bool) {
+	if p.KeyRotatedAt.IsZero() {
+		return 0, false
+	}
+	return time.Since(p.KeyRotatedAt), true
 }
 
 // Validate validates a provider configuration.
@@ -36,7 +61,7 @@ func (p *Provider) Validate(templateID string) error {
 
 	// Check if template allows model overrides
 	if templateID != "" {
-		tmpl, ok := GetTemplate(templateID)
+		tmpl, ok := TemplateFor(templateID)
 		if !ok {
 			return fmt.Errorf("unknown provider template: %s", templateID)
 		}
@@ -45,6 +70,23 @@ func (p *Provider) Validate(templateID string) error {
 		}
 	}
 
+	if p.BaseURLOverride != "" {
+		if err := ValidateBaseURL(p.BaseURLOverride); err != nil {
+			return fmt.Errorf("base_url_override: %w", err)
+		}
+	}
+	if p.HTTPSProxy != "" {
+		if err := ValidateProxyURL(p.HTTPSProxy); err != nil {
+			return fmt.Errorf("https_proxy: %w", err)
+		}
+	}
+	if err := validateNoProxy(p.NoProxy); err != nil {
+		return fmt.Errorf("no_proxy: %w", err)
+	}
+	if err := p.EnvSettings.Validate(); err != nil {
+		return err
+	}
+
 	// Validate model names if provided
 	if err := validateModelName("opus_model", p.OpusModel); err != nil {
 		return err
@@ -67,6 +109,47 @@ func (p *Provider) HasModelOverrides() bool {
 	return p.OpusModel != "" || p.SonnetModel != "" || p.HaikuModel != "" || p.SubagentModel != ""
 }
 
+// BaseURL returns the URL requests for this provider go to: the override if
+// set, else the template's base URL.
+func (p *Provider) BaseURL(tmpl *ProviderTemplate) string {
+	if p.BaseURLOverride != "" {
+		return p.BaseURLOverride
+	}
+	return tmpl.BaseURL
+}
+
+// ValidateBaseURL checks that a base URL override is an absolute http(s) URL
+// without credentials, query or fragment. Plain http is only accepted for
+// local gateways, since the API key is sent with every request.
+func ValidateBaseURL(baseURL string) error {
+	if strings.ContainsAny(baseURL, "\n\r\x00\"'`$ ") {
+		return fmt.Errorf("URL contains invalid characters")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("URL must be absolute, e.g. https://eu.api.example.com/anthropic")
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if host := u.Hostname(); host != "localhost" && host != "127.0.0.1" && host != "::1" {
+			return fmt.Errorf("plain http is only allowed for localhost; use https")
+		}
+	default:
+		return fmt.Errorf("URL must start with https://")
+	}
+	if u.User != nil {
+		return fmt.Errorf("URL must not contain credentials")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("URL must not have a query or fragment")
+	}
+	return nil
+}
+
 // validateModelName validates a model name doesn't contain problematic characters
 func validateModelName(fieldName, modelName string) error {
 	if modelName == "" {
@@ -91,19 +174,24 @@ func validateModelName(fieldName, modelName string) error {
 	return nil
 }
 
-// ToShellExports returns shell export commands for this provider.
-// The templateID is required to look up the base URL from the template registry.
-func (p *Provider) ToShellExports(templateID string) (string, error) {
-	tmpl, ok := GetTemplate(templateID)
+// EnvVar is a variable in a provider's environment; an empty Value means the
+// variable is unset.
+type EnvVar struct {
+	Key   string
+	Value string
+}
+
+// Env returns the environment variables Claude Code reads for this
+// provider, in the order they are written to the env file, with settings
+// for the timeout and telemetry (see Config.Env for the ones that apply).
+// The provider ID (a template ID, or "template:profile") is required to look
+// up the base URL from the template registry.
+func (p *Provider) Env(templateID string, settings EnvSettings) ([]EnvVar, error) {
+	tmpl, ok := TemplateFor(templateID)
 	if !ok {
-		return "", fmt.Errorf("unknown provider template: %s", templateID)
+		return nil, fmt.Errorf("unknown provider template: %s", templateID)
 	}
 
-	var buf strings.Builder
-
-	fmt.Fprintf(&buf, "export ANTHROPIC_BASE_URL=%q\n", tmpl.BaseURL)
-	fmt.Fprintf(&buf, "export ANTHROPIC_AUTH_TOKEN=%q\n", p.APIKey)
-
 	// Helper to get model value: use provider value if set, else template default
 	getModel := func(providerModel string) string {
 		if providerModel != "" {
@@ -112,31 +200,47 @@ func (p *Provider) ToShellExports(templateID string) (string, error) {
 		return tmpl.DefaultModel
 	}
 
-	// Model variables: export if we have a value (from provider or template default), else unset
-	if model := getModel(p.OpusModel); model != "" {
-		fmt.Fprintf(&buf, "export ANTHROPIC_DEFAULT_OPUS_MODEL=%q\n", model)
-	} else {
-		buf.WriteString("unset ANTHROPIC_DEFAULT_OPUS_MODEL\n")
-	}
-	if model := getModel(p.SonnetModel); model != "" {
-		fmt.Fprintf(&buf, "export ANTHROPIC_DEFAULT_SONNET_MODEL=%q\n", model)
-	} else {
-		buf.WriteString("unset ANTHROPIC_DEFAULT_SONNET_MODEL\n")
-	}
-	if model := getModel(p.HaikuModel); model != "" {
-		fmt.Fprintf(&buf, "export ANTHROPIC_DEFAULT_HAIKU_MODEL=%q\n", model)
-	} else {
-		buf.WriteString("unset ANTHROPIC_DEFAULT_HAIKU_MODEL\n")
-	}
-	if model := getModel(p.SubagentModel); model != "" {
-		fmt.Fprintf(&buf, "export CLAUDE_CODE_SUBAGENT_MODEL=%q\n", model)
-	} else {
-		buf.WriteString("unset CLAUDE_CODE_SUBAGENT_MODEL\n")
-	}
+	// Model variables are set if we have a value (from provider or template
+	// default), else unset
+	return append([]EnvVar{
+		{"ANTHROPIC_BASE_URL", p.BaseURL(tmpl)},
+		{"ANTHROPIC_AUTH_TOKEN", p.APIKey},
+		{"ANTHROPIC_DEFAULT_OPUS_MODEL", getModel(p.OpusModel)},
+		{"ANTHROPIC_DEFAULT_SONNET_MODEL", getModel(p.SonnetModel)},
+		{"ANTHROPIC_DEFAULT_HAIKU_MODEL", getModel(p.HaikuModel)},
+		{"CLAUDE_CODE_SUBAGENT_MODEL", getModel(p.SubagentModel)},
+	}, settings.vars()...), nil
+}
 
-	// Always export hardcoded values for timeout and telemetry
-	fmt.Fprintf(&buf, "export API_TIMEOUT_MS=%d\n", 6000000)
-	buf.WriteString("export CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC=1\n")
+// OfficialEnv returns the environment for the official Anthropic API with
+// the built-in settings: the provider variables Env sets are unset, timeout
+// and telemetry kept. Config.Env(OfficialProvider) applies configured ones.
+func OfficialEnv() []EnvVar {
+	return officialEnv(EnvSettings{})
+}
 
-	return buf.String(), nil
+// officialEnv returns the environment for the official Anthropic API
+func officialEnv(settings EnvSettings) []EnvVar {
+	return append([]EnvVar{
+		{"ANTHROPIC_BASE_URL", ""},
+		{"ANTHROPIC_AUTH_TOKEN", ""},
+		{"ANTHROPIC_DEFAULT_OPUS_MODEL", ""},
+		{"ANTHROPIC_DEFAULT_SONNET_MODEL", ""},
+		{"ANTHROPIC_DEFAULT_HAIKU_MODEL", ""},
+		{"CLAUDE_CODE_SUBAGENT_MODEL", ""},
+	}, settings.vars()...)
+}
+
+// ShellExports returns export commands for vars, and unset commands for
+// those without a value.
+func ShellExports(vars []EnvVar) string {
+	var buf strings.Builder
+	for _, v := range vars {
+		if v.Value == "" {
+			fmt.Fprintf(&buf, "unset %s\n", v.Key)
+		} else {
+			fmt.Fprintf(&buf, "export %s=%q\n", v.Key, v.Value)
+		}
+	}
+	return buf.String()
 }
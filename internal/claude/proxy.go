@@ -0,0 +1,81 @@
+package claude
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ValidateProxyURL checks an HTTPS_PROXY value: an absolute http or https
+// URL of the proxy, optionally with credentials
+func ValidateProxyURL(proxyURL string) error {
+	if strings.ContainsAny(proxyURL, "\n\r\x00\"'`$ ") {
+		return fmt.Errorf("URL contains invalid characters")
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL must start with http:// or https://, e.g. http://proxy.corp.example:3128")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("URL must have a host, e.g. http://proxy.corp.example:3128")
+	}
+	return nil
+}
+
+// validateNoProxy checks a NO_PROXY value: a comma-separated list of hosts,
+// domains and CIDR ranges
+func validateNoProxy(noProxy string) error {
+	if strings.ContainsAny(noProxy, "\n\r\x00\"'`$ ") {
+		return fmt.Errorf("must be a comma-separated list of hosts without spaces")
+	}
+	return nil
+}
+
+// RedactedProxy returns the provider's proxy URL with its password hidden
+func (p *Provider) RedactedProxy() string {
+	u, err := url.Parse(p.HTTPSProxy)
+	if err != nil {
+		return p.HTTPSProxy
+	}
+	return u.Redacted()
+}
+
+// HTTPClient returns the client for zzk's own requests to the provider,
+// going through its proxy like Claude Code does
+func (p *Provider) HTTPClient() *http.Client {
+	if p.HTTPSProxy == "" {
+		return http.DefaultClient
+	}
+	proxy := (&httpproxy.Config{HTTPSProxy: p.HTTPSProxy, HTTPProxy: p.HTTPSProxy, NoProxy: p.NoProxy}).ProxyFunc()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+	return &http.Client{Transport: transport}
+}
+
+// proxyVars returns the proxy variables for a provider, unset for one
+// without a proxy so it doesn't inherit another provider's
+func (p *Provider) proxyVars() []EnvVar {
+	return []EnvVar{
+		{"HTTPS_PROXY", p.HTTPSProxy},
+		{"NO_PROXY", p.NoProxy},
+	}
+}
+
+// usesProxy reports whether any provider has a proxy. Only then do the env
+// files manage the proxy variables, leaving a proxy set elsewhere alone.
+func (c *Config) usesProxy() bool {
+	for _, provider := range c.Providers {
+		if provider.HTTPSProxy != "" {
+			return true
+		}
+	}
+	return false
+}
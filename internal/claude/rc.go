@@ -0,0 +1,143 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// rcBlockBegin and rcBlockEnd delimit the lines 'zzk claude setup' adds to
+// the shell RC file
+const (
+	rcBlockBegin = "# BEGIN zzk claude - remove with 'zzk claude setup --remove'"
+	rcBlockEnd   = "# END zzk claude"
+)
+
+var rcBlockRegex = regexp.MustCompile(`(?s)\n?# BEGIN zzk claude[^\n]*\n.*?# END zzk claude(?:\n|$)`)
+
+// sourceLineFor returns the line sourcing the env file in shell
+func sourceLineFor(shell string) string {
+	envPath := EnvFilePathFor(shell)
+	switch envFileExt(shell) {
+	case ".nu":
+		// source needs the file to exist when config.nu is parsed
+		return "source " + nuQuote(envPath)
+	case ".ps1":
+		return fmt.Sprintf("if (Test-Path %s) { . %s }", powerShellQuote(envPath), powerShellQuote(envPath))
+	}
+	if shell == "fish" {
+		return fmt.Sprintf("[ -f %s ]; and source %s", envPath, envPath)
+	}
+	return fmt.Sprintf("[ -f %s ] && source %s", envPath, envPath)
+}
+
+// rcTarget returns the RC file for shell, following symlinks so files kept
+// in a dotfiles repo are edited in place
+func rcTarget(shell string) (string, error) {
+	rcFile := GetRCFilePath(shell)
+	if rcFile == "" {
+		return "", fmt.Errorf("unsupported shell: %s (supported: zsh, bash, fish, nu, pwsh)", shell)
+	}
+	if resolved, err := filepath.EvalSymlinks(rcFile); err == nil {
+		return resolved, nil
+	}
+	return rcFile, nil
+}
+
+// SetupRCFile adds a managed block sourcing the env file to shell's RC file,
+// or updates it, and reports whether the file changed. A file sourcing the
+// env file without the block is left as is.
+func SetupRCFile(shell string) (string, bool, error) {
+	rcFile, err := rcTarget(shell)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(rcFile)
+	if err != nil && !os.IsNotExist(err) {
+		return rcFile, false, fmt.Errorf("failed to read RC file: %w", err)
+	}
+
+	block := rcBlockBegin + "\n" + sourceLineFor(shell) + "\n" + rcBlockEnd + "\n"
+	content := string(data)
+	var updated string
+	if loc := rcBlockRegex.FindStringIndex(content); loc != nil {
+		existing := content[loc[0]:loc[1]]
+		if strings.TrimPrefix(existing, "\n") == block {
+			return rcFile, false, nil
+		}
+		prefix := ""
+		if strings.HasPrefix(existing, "\n") {
+			prefix = "\n"
+		}
+		updated = content[:loc[0]] + prefix + block + content[loc[1]:]
+	} else if sourcesEnvFile(data, shell) {
+		return rcFile, false, nil
+	} else {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		updated = content + block
+	}
+
+	// nushell fails to start if a sourced file is missing
+	if envFileExt(shell) == ".nu" {
+		if _, err := os.Stat(EnvFilePathFor(shell)); os.IsNotExist(err) {
+			if err := ClearEnvFile(&Config{}); err != nil {
+				return rcFile, false, err
+			}
+		}
+	}
+
+	if err := writeRCFile(rcFile, []byte(updated)); err != nil {
+		return rcFile, false, err
+	}
+	return rcFile, true, nil
+}
+
+// RemoveRCFile removes the managed block from shell's RC file and reports
+// whether there was one
+func RemoveRCFile(shell string) (string, bool, error) {
+	rcFile, err := rcTarget(shell)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(rcFile)
+	if os.IsNotExist(err) {
+		return rcFile, false, nil
+	}
+	if err != nil {
+		return rcFile, false, fmt.Errorf("failed to read RC file: %w", err)
+	}
+
+	content := string(data)
+	if !rcBlockRegex.MatchString(content) {
+		return rcFile, false, nil
+	}
+	// The block takes the blank line SetupRCFile put before it along
+	if err := writeRCFile(rcFile, []byte(rcBlockRegex.ReplaceAllString(content, ""))); err != nil {
+		return rcFile, false, err
+	}
+	return rcFile, true, nil
+}
+
+// writeRCFile replaces an RC file, keeping its permissions
+func writeRCFile(rcFile string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(rcFile); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(rcFile), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(rcFile), err)
+	}
+	if err := fileutil.AtomicWrite(rcFile, data, perm); err != nil {
+		return fmt.Errorf("failed to write RC file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package claude
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// RegistryURL is where releases publish the template registry, with its
+// signature at RegistryURL+".sig"
+const RegistryURL = "https://github.com/ppowo/zzk/releases/latest/download/claude-templates.json"
+
+// RegistryPublicKey is the base64 Ed25519 public key the template registry
+// is signed with ('mage registry' signs it with the matching private key)
+const RegistryPublicKey = "lku1lqBZwHLZJLPyehiPQ5qNYNNhrafnd9mirrPhDoQ="
+
+// Registry is a signed list of provider templates, published with releases
+// so new providers and base URL changes don't need a new binary. Its
+// templates replace the built-in ones with the same ID, and are replaced by
+// the user's.
+type Registry struct {
+	Updated   time.Time          `json:"updated"`
+	Templates []ProviderTemplate `json:"templates"`
+}
+
+// RegistryPath returns the path to the cached template registry
+func RegistryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "zzk", "claude-registry.json")
+	}
+	return filepath.Join(home, ".config", "zzk", "claude-registry.json")
+}
+
+// CachedRegistry returns the registry last downloaded, nil if there is none
+func CachedRegistry() (*Registry, error) {
+	return readRegistry(RegistryPath())
+}
+
+// readRegistry reads a cached registry, nil if there is none. Its signature
+// is cached next to it and checked on every read, so editing the cache can't
+// change the templates' base URLs.
+func readRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("signature missing: %w", err)
+	}
+	if err := verifyRegistry(data, sig); err != nil {
+		return nil, err
+	}
+	return parseRegistry(data)
+}
+
+// verifyRegistry checks a registry against its base64 signature
+func verifyRegistry(data, sig []byte) error {
+	key, err := base64.StdEncoding.DecodeString(RegistryPublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid registry key built into zzk")
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(key), data, signature) {
+		return fmt.Errorf("signature doesn't match - refusing the templates")
+	}
+	return nil
+}
+
+// parseRegistry decodes and validates a registry
+func parseRegistry(data []byte) (*Registry, error) {
+	var registry Registry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if registry.Updated.IsZero() {
+		return nil, fmt.Errorf("registry has no update time")
+	}
+	if err := validateTemplates(registry.Templates); err != nil {
+		return nil, err
+	}
+	for i := range registry.Templates {
+		registry.Templates[i].FromRegistry = true
+	}
+	return &registry, nil
+}
+
+// UpdateRegistry downloads the registry at url and its signature at
+// url+".sig", verifies it against RegistryPublicKey and caches both. It
+// returns the registry cached before, nil if none, and the new one. A
+// registry older than the cached one is refused, so an old copy can't be
+// replayed to bring back a base URL that was moved away from.
+func UpdateRegistry(ctx context.Context, url string) (old, updated *Registry, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	data, err := fetchRegistryFile(ctx, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	sigData, err := fetchRegistryFile(ctx, url+".sig")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifyRegistry(data, sigData); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	if updated, err = parseRegistry(data); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", url, err)
+	}
+	// A broken cache is replaced by the verified registry
+	old, _ = readRegistry(RegistryPath())
+	if old != nil && updated.Updated.Before(old.Updated) {
+		return nil, nil, fmt.Errorf("%s is from %s, older than the cached registry from %s", url,
+			updated.Updated.Format(time.DateOnly), old.Updated.Format(time.DateOnly))
+	}
+
+	if err := EnsureConfigDir(); err != nil {
+		return nil, nil, err
+	}
+	// The signature goes first: a registry without a matching one is refused
+	if err := fileutil.AtomicWrite(RegistryPath()+".sig", sigData, 0644); err != nil {
+		return nil, nil, err
+	}
+	if err := fileutil.AtomicWrite(RegistryPath(), data, 0644); err != nil {
+		return nil, nil, err
+	}
+	return old, updated, nil
+}
+
+// fetchRegistryFile downloads a registry file
+func fetchRegistryFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "zzk")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected response: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// TemplateChange is a template added, changed or removed by a registry update
+type TemplateChange struct {
+	ID      string
+	Old     *ProviderTemplate // nil if added
+	New     *ProviderTemplate // nil if removed
+	Details []string          // What changed, e.g. "base URL"
+}
+
+// RegistryChanges compares the templates the built-ins and the old registry
+// (nil for none) resulted in with those of the updated one
+func RegistryChanges(old, updated *Registry) []TemplateChange {
+	var oldList []ProviderTemplate
+	if old != nil {
+		oldList = old.Templates
+	}
+	before := mergeTemplates(Templates, oldList)
+	after := mergeTemplates(Templates, updated.Templates)
+	find := func(list []ProviderTemplate, id string) *ProviderTemplate {
+		for i := range list {
+			if list[i].ID == id {
+				return &list[i]
+			}
+		}
+		return nil
+	}
+
+	var changes []TemplateChange
+	for i := range after {
+		tmpl := &after[i]
+		prev := find(before, tmpl.ID)
+		if prev == nil {
+			changes = append(changes, TemplateChange{ID: tmpl.ID, New: tmpl})
+			continue
+		}
+		var details []string
+		if prev.BaseURL != tmpl.BaseURL {
+			details = append(details, "base URL")
+		}
+		if prev.ModelsURL != tmpl.ModelsURL {
+			details = append(details, "models URL")
+		}
+		if prev.DefaultModel != tmpl.DefaultModel || prev.AllowModels != tmpl.AllowModels {
+			details = append(details, "models")
+		}
+		if prev.Name != tmpl.Name {
+			details = append(details, "name")
+		}
+		if len(details) > 0 {
+			changes = append(changes, TemplateChange{ID: tmpl.ID, Old: prev, New: tmpl, Details: details})
+		}
+	}
+	for i := range before {
+		if find(after, before[i].ID) == nil {
+			changes = append(changes, TemplateChange{ID: before[i].ID, Old: &before[i]})
+		}
+	}
+	return changes
+}
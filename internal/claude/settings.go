@@ -0,0 +1,92 @@
+package claude
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Built-in Claude Code settings written to the env file
+const (
+	DefaultTimeoutMS = 6000000 // 100 minutes, for slow models behind gateways
+	minTimeoutMS     = 10000
+	maxTimeoutMS     = 24 * 60 * 60 * 1000
+)
+
+// EnvSettings are the Claude Code settings written to the env file besides
+// the provider's URL, key and models. They can be set per provider, for the
+// official API and globally; unset fields fall back in that order to the
+// built-in defaults.
+type EnvSettings struct {
+	// TimeoutMS is API_TIMEOUT_MS, 0 if unset
+	TimeoutMS int `json:"timeout_ms,omitempty"`
+	// DisableTraffic is CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC, which turns
+	// off telemetry, error reporting and auto-updates; nil if unset
+	DisableTraffic *bool `json:"disable_nonessential_traffic,omitempty"`
+}
+
+// Validate checks the settings are within sane bounds
+func (s EnvSettings) Validate() error {
+	if s.TimeoutMS != 0 && (s.TimeoutMS < minTimeoutMS || s.TimeoutMS > maxTimeoutMS) {
+		return fmt.Errorf("timeout_ms must be between %d (10s) and %d (24h), got %d", minTimeoutMS, maxTimeoutMS, s.TimeoutMS)
+	}
+	return nil
+}
+
+// Or returns s with its unset fields taken from fallback
+func (s EnvSettings) Or(fallback EnvSettings) EnvSettings {
+	if s.TimeoutMS == 0 {
+		s.TimeoutMS = fallback.TimeoutMS
+	}
+	if s.DisableTraffic == nil {
+		s.DisableTraffic = fallback.DisableTraffic
+	}
+	return s
+}
+
+// IsZero reports whether no setting is set
+func (s EnvSettings) IsZero() bool {
+	return s.TimeoutMS == 0 && s.DisableTraffic == nil
+}
+
+// vars returns the env variables for the settings, using the built-in
+// defaults for unset ones. Enabled traffic unsets the variable.
+func (s EnvSettings) vars() []EnvVar {
+	timeout := s.TimeoutMS
+	if timeout == 0 {
+		timeout = DefaultTimeoutMS
+	}
+	disable := "1"
+	if s.DisableTraffic != nil && !*s.DisableTraffic {
+		disable = ""
+	}
+	return []EnvVar{
+		{"API_TIMEOUT_MS", strconv.Itoa(timeout)},
+		{"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC", disable},
+	}
+}
+
+// Env returns the environment of a configured provider, or of the official
+// API for OfficialProvider or "", with the settings that apply to it and,
+// once any provider has a proxy, its proxy variables
+func (c *Config) Env(id string) ([]EnvVar, error) {
+	var vars []EnvVar
+	var provider Provider
+	if id == "" || id == OfficialProvider {
+		vars = officialEnv(c.Official.Or(c.EnvSettings))
+	} else {
+		if !c.HasProvider(id) {
+			return nil, fmt.Errorf("provider '%s' not configured. Use 'zzk claude set %s' to configure it", id, id)
+		}
+		var err error
+		if provider, err = c.ResolveKey(id); err != nil {
+			return nil, err
+		}
+		if vars, err = provider.Env(id, provider.EnvSettings.Or(c.EnvSettings)); err != nil {
+			return nil, err
+		}
+	}
+	if c.usesProxy() {
+		vars = append(vars, provider.proxyVars()...)
+	}
+	return vars, nil
+}
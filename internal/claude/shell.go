@@ -1,72 +1,85 @@
 package claude
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"al.essio.dev/pkg/shellescape"
 	"github.com/ppowo/zzk/internal/fileutil"
 )
 
-// EnvFilePath returns the path to the environment file
+// EnvFilePath returns the path to the environment file for the current shell
 func EnvFilePath() string {
+	return EnvFilePathFor(DetectShell())
+}
+
+// EnvFilePathFor returns the path to the environment file for shell:
+// claude-env.sh, or claude-env.nu or .ps1 for nushell and PowerShell
+func EnvFilePathFor(shell string) string {
+	name := "claude-env" + envFileExt(shell)
 	home, err := os.UserHomeDir()
 	if err != nil {
 		// Fallback to current directory if home unavailable
 		cwd, _ := os.Getwd()
 		if cwd != "" {
-			return filepath.Join(cwd, ".config", "zzk", "claude-env.sh")
+			return filepath.Join(cwd, ".config", "zzk", name)
 		}
 		// Last resort fallback
-		return filepath.Join("/tmp", "zzk-claude-env.sh")
+		return filepath.Join("/tmp", "zzk-"+name)
 	}
-	return filepath.Join(home, ".config", "zzk", "claude-env.sh")
+	return filepath.Join(home, ".config", "zzk", name)
 }
 
-// WriteEnvFile writes the provider configuration to the env file.
-// The templateID is used to look up the base URL from the template registry.
-func WriteEnvFile(templateID string, provider Provider) error {
-	if err := EnsureConfigDir(); err != nil {
-		return err
+// WriteEnvFile writes the configuration of a configured provider to the env
+// file, or that of the official API if id is empty
+func WriteEnvFile(config *Config, id string) error {
+	if id == "" {
+		return ClearEnvFile(config)
 	}
-
-	exports, err := provider.ToShellExports(templateID)
+	vars, err := config.Env(id)
 	if err != nil {
 		return err
 	}
+	return writeEnvFiles("# Generated for Claude Code provider configuration\n", vars)
+}
 
-	var buf bytes.Buffer
-	buf.WriteString("# Managed by zzk - do not edit manually\n")
-	buf.WriteString("# Generated for Claude Code provider configuration\n\n")
-	buf.WriteString(exports)
-
-	return fileutil.AtomicWrite(EnvFilePath(), buf.Bytes(), 0600)
+// ClearEnvFile clears the environment file, leaving the official API's
+// timeout and telemetry settings
+func ClearEnvFile(config *Config) error {
+	vars, _ := config.Env(OfficialProvider)
+	return writeEnvFiles("# No active provider - using official Anthropic API\n# Default environment configuration\n", vars)
 }
 
-// ClearEnvFile clears the environment file
-func ClearEnvFile() error {
+// writeEnvFiles writes vars to the env file of the current shell, and to
+// those of other shells that already have one so they stay in sync
+func writeEnvFiles(header string, vars []EnvVar) error {
 	if err := EnsureConfigDir(); err != nil {
 		return err
 	}
 
-	// Default environment configuration for official Anthropic API
-	// Always use hardcoded values for timeout and telemetry
-	content := `# No active provider - using official Anthropic API
-# Default environment configuration
-unset ANTHROPIC_BASE_URL
-unset ANTHROPIC_AUTH_TOKEN
-unset ANTHROPIC_DEFAULT_OPUS_MODEL
-unset ANTHROPIC_DEFAULT_SONNET_MODEL
-unset ANTHROPIC_DEFAULT_HAIKU_MODEL
-unset CLAUDE_CODE_SUBAGENT_MODEL
-export API_TIMEOUT_MS=6000000
-export CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC=1
-`
-
-	return fileutil.AtomicWrite(EnvFilePath(), []byte(content), 0600)
+	current := DetectShell()
+	written := make(map[string]bool)
+	for _, shell := range []string{current, "bash", "nu", "pwsh"} {
+		path := EnvFilePathFor(shell)
+		if written[path] {
+			continue
+		}
+		written[path] = true
+		if shell != current {
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+		}
+
+		content := "# Managed by zzk - do not edit manually\n" + header + "\n" + envFileContent(shell, vars)
+		if err := fileutil.AtomicWrite(path, []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DetectShell detects the current shell
@@ -76,6 +89,11 @@ func DetectShell() string {
 		return filepath.Base(shell)
 	}
 
+	// Windows has no $SHELL; PowerShell is the default there
+	if runtime.GOOS == "windows" {
+		return "pwsh"
+	}
+
 	// Final fallback to bash (more common than sh)
 	return "bash"
 }
@@ -98,6 +116,18 @@ func GetRCFilePath(shell string) string {
 		return filepath.Join(home, ".bash_profile")
 	case "fish":
 		return filepath.Join(home, ".config", "fish", "config.fish")
+	case "nu":
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(configDir, "nushell", "config.nu")
+	case "pwsh", "powershell":
+		// $PROFILE for the current user and host
+		if runtime.GOOS == "windows" {
+			return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1")
+		}
+		return filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1")
 	default:
 		return ""
 	}
@@ -120,8 +150,13 @@ func CheckRCFileSetup() (bool, string, error) {
 		}
 		return false, rcFile, fmt.Errorf("failed to read RC file: %w", err)
 	}
+	return rcBlockRegex.Match(data) || sourcesEnvFile(data, shell), rcFile, nil
+}
 
-	envPath := EnvFilePath()
+// sourcesEnvFile reports whether RC file data has a line sourcing shell's
+// env file outside a managed block
+func sourcesEnvFile(data []byte, shell string) bool {
+	envPath := EnvFilePathFor(shell)
 	// Check for actual source command, not just substring match
 	// This prevents false positives from comments or other strings
 	lines := strings.SplitSeq(string(data), "\n")
@@ -131,6 +166,15 @@ func CheckRCFileSetup() (bool, string, error) {
 		if strings.HasPrefix(trimmed, "#") {
 			continue
 		}
+		// nushell and PowerShell source the file quoted, PowerShell usually
+		// inside an if (Test-Path ...)
+		if envFileExt(shell) != ".sh" {
+			if strings.Contains(trimmed, envPath) && (strings.HasPrefix(trimmed, "source ") || strings.Contains(trimmed, ". ")) {
+				return true
+			}
+			continue
+		}
+
 		// Check for source command with our env file (exact match with word boundaries)
 		// Match patterns:
 		// - "source <path>", ". <path>"
@@ -146,22 +190,16 @@ func CheckRCFileSetup() (bool, string, error) {
 			strings.HasPrefix(trimmed, dotSourceLine+";") ||
 			trimmed == conditionalSourceLine || strings.HasPrefix(trimmed, conditionalSourceLine+" ") ||
 			strings.HasPrefix(trimmed, conditionalSourceLine+";") {
-			return true, rcFile, nil
+			return true
 		}
 	}
 
-	return false, rcFile, nil
+	return false
 }
 
 // GetSourceLine returns the appropriate source line for the current shell
 func GetSourceLine() string {
-	shell := DetectShell()
-	envPath := EnvFilePath()
-
-	if shell == "fish" {
-		return fmt.Sprintf("[ -f %s ]; and source %s", envPath, envPath)
-	}
-	return fmt.Sprintf("[ -f %s ] && source %s", envPath, envPath)
+	return sourceLineFor(DetectShell())
 }
 
 // shellQuote quotes a string for safe use in shell commands using POSIX shell quoting
@@ -196,17 +234,27 @@ func ShowSetupInstructions() {
 
 // GetReloadInstructions returns instructions for reloading the shell environment
 func GetReloadInstructions() string {
+	return fmt.Sprintf(`ACTION REQUIRED: Reload your shell to apply changes
+
+Run this command:
+  %s`, ReloadCommand())
+}
+
+// ReloadCommand returns the command reloading the current shell's RC file
+func ReloadCommand() string {
 	shell := DetectShell()
-	rcFile := GetRCFilePath(shell)
+	switch envFileExt(shell) {
+	case ".nu":
+		return "exec nu"
+	case ".ps1":
+		return ". $PROFILE"
+	}
 
+	rcFile := GetRCFilePath(shell)
 	if rcFile == "" {
 		rcFile = "your shell config file"
 	}
-
-	return fmt.Sprintf(`ACTION REQUIRED: Reload your shell to apply changes
-
-Run this command:
-  source %s`, rcFile)
+	return "source " + rcFile
 }
 
 // ResetToOfficialAPI resets the Claude environment to use the official Anthropic API.
@@ -219,7 +267,7 @@ func ResetToOfficialAPI() error {
 	}
 
 	// Clear env file
-	if err := ClearEnvFile(); err != nil {
+	if err := ClearEnvFile(config); err != nil {
 		return fmt.Errorf("failed to clear env file: %w", err)
 	}
 
@@ -250,9 +298,8 @@ func ResetToOfficialAPI() error {
 
 	if !isSetup {
 		// One-time setup needed
-		fmt.Println("\nOne-time setup: Add this line to your", rcFile)
-		fmt.Printf("  [ -f %s ] && source %s\n", EnvFilePath(), EnvFilePath())
-		fmt.Println("\nThen reload your shell.")
+		fmt.Printf("\nOne-time setup: run 'zzk claude setup' to source the env file from %s,\n", rcFile)
+		fmt.Println("then reload your shell.")
 	}
 
 	return nil
@@ -260,24 +307,28 @@ func ResetToOfficialAPI() error {
 
 // ReloadClaudeEnvironment reloads the Claude environment when a provider is activated.
 // It writes the env file, checks shell sync, and shows warnings if needed.
-func ReloadClaudeEnvironment(templateID string, provider Provider) error {
+func ReloadClaudeEnvironment(templateID string) error {
 	// Get template for display
-	tmpl, ok := GetTemplate(templateID)
+	tmpl, ok := TemplateFor(templateID)
 	if !ok {
 		return fmt.Errorf("unknown provider template: %s", templateID)
 	}
 
-	// Write env file
-	if err := WriteEnvFile(templateID, provider); err != nil {
-		return fmt.Errorf("failed to write env file: %w", err)
-	}
-
-	// Update active in config
 	config, err := LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	provider, ok := config.GetProvider(templateID)
+	if !ok {
+		return fmt.Errorf("provider '%s' not configured", templateID)
+	}
 
+	// Write env file
+	if err := WriteEnvFile(config, templateID); err != nil {
+		return fmt.Errorf("failed to write env file: %w", err)
+	}
+
+	// Update active in config
 	if err := config.SetActive(templateID); err != nil {
 		return fmt.Errorf("failed to set active provider: %w", err)
 	}
@@ -286,8 +337,11 @@ func ReloadClaudeEnvironment(templateID string, provider Provider) error {
 		return fmt.Errorf("failed to update config: %w", err)
 	}
 
-	fmt.Printf("Switched to provider: %s\n", tmpl.Name)
-	fmt.Printf("  Base URL: %s\n", tmpl.BaseURL)
+	fmt.Printf("Switched to provider: %s\n", ProviderName(templateID))
+	fmt.Printf("  Base URL: %s\n", provider.BaseURL(tmpl))
+	if provider.BaseURLOverride != "" {
+		fmt.Printf("  ⚠ Overrides the %s default (%s)\n", tmpl.Name, tmpl.BaseURL)
+	}
 	fmt.Println(GetReloadInstructions())
 
 	// Check if RC file is set up
@@ -300,9 +354,8 @@ func ReloadClaudeEnvironment(templateID string, provider Provider) error {
 
 	if !isSetup {
 		// One-time setup needed
-		fmt.Println("\nOne-time setup: Add this line to your", rcFile)
-		fmt.Printf("  [ -f %s ] && source %s\n", EnvFilePath(), EnvFilePath())
-		fmt.Println("\nThen reload your shell.")
+		fmt.Printf("\nOne-time setup: run 'zzk claude setup' to source the env file from %s,\n", rcFile)
+		fmt.Println("then reload your shell.")
 	}
 
 	return nil
@@ -0,0 +1,251 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EnvState is a set of provider environment variables and the configured
+// provider they belong to
+type EnvState struct {
+	// Provider is the configured provider with the same base URL and API
+	// key, OfficialProvider if no base URL is set, or empty if none matches
+	Provider string
+	// Current is true if the variables are exactly what Provider's current
+	// settings produce
+	Current bool
+	// Vars holds the variables Env sets, empty if unset
+	Vars map[string]string
+}
+
+// Drift is a mismatch between the config, the env file and the shell
+type Drift struct {
+	Message string
+	Fix     string // Command that repairs it
+}
+
+// Status compares the active provider in the config, the env file of the
+// current shell and the provider variables of this process's environment
+type Status struct {
+	Active   string // Active provider, or OfficialProvider
+	FilePath string
+	File     *EnvState // nil if the env file doesn't exist
+	Shell    EnvState
+	RCSetup  bool
+	RCFile   string
+	Drift    []Drift
+}
+
+// GetStatus reads the env file and the environment and reports how they
+// drifted from the config
+func GetStatus(config *Config) (*Status, error) {
+	status := &Status{Active: config.Active, FilePath: EnvFilePath()}
+	if status.Active == "" {
+		status.Active = OfficialProvider
+	}
+
+	expected, err := config.Env(config.Active)
+	if err != nil {
+		return nil, err
+	}
+	activate := "zzk claude reset"
+	if config.Active != "" {
+		activate = "zzk claude use " + config.Active
+	}
+
+	data, err := os.ReadFile(status.FilePath)
+	switch {
+	case os.IsNotExist(err):
+		status.Drift = append(status.Drift, Drift{"env file doesn't exist", activate})
+	case err != nil:
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	default:
+		file := config.identifyEnv(parseEnvFile(DetectShell(), string(data)))
+		status.File = &file
+		if !envEqual(file.Vars, expected) {
+			message := fmt.Sprintf("env file is for %s but the active provider is %s", describeEnv(file), status.Active)
+			if file.Provider == status.Active {
+				message = fmt.Sprintf("env file has outdated settings for %s", status.Active)
+			}
+			status.Drift = append(status.Drift, Drift{message, activate})
+		}
+	}
+
+	live := make(map[string]string)
+	for _, v := range OfficialEnv() {
+		live[v.Key] = os.Getenv(v.Key)
+	}
+	status.Shell = config.identifyEnv(live)
+	if status.File != nil && !sameEnv(live, status.File.Vars) {
+		message := fmt.Sprintf("env file says %s but your shell still exports %s", describeEnv(*status.File), describeEnv(status.Shell))
+		if status.Shell.Provider == status.File.Provider && status.Shell.Provider != "" {
+			message = fmt.Sprintf("your shell has older settings for %s than the env file", status.Shell.Provider)
+		}
+		status.Drift = append(status.Drift, Drift{message, ReloadCommand()})
+	}
+
+	status.RCSetup, status.RCFile, err = CheckRCFileSetup()
+	if err == nil && !status.RCSetup {
+		status.Drift = append(status.Drift, Drift{status.RCFile + " doesn't source the env file", "zzk claude setup"})
+	}
+
+	return status, nil
+}
+
+// ShellProvider names the provider the Claude Code variables of this
+// process's environment point at, for shell prompts: OfficialProvider if
+// ANTHROPIC_BASE_URL is unset, else the one configured provider with that
+// base URL, the template if several profiles share it, or the URL's host.
+// Unlike GetStatus it neither reads API keys from the vault nor decrypts
+// the config, so it is quick enough to run for every prompt.
+func ShellProvider() string {
+	baseURL := strings.TrimSuffix(os.Getenv("ANTHROPIC_BASE_URL"), "/")
+	if baseURL == "" {
+		return OfficialProvider
+	}
+	LoadTemplates()
+
+	// An encrypted or unreadable config leaves the templates to go by
+	var config Config
+	if data, err := os.ReadFile(ConfigPath()); err == nil {
+		json.Unmarshal(data, &config)
+	}
+	var matches []string
+	for id, provider := range config.Providers {
+		tmpl, ok := TemplateFor(id)
+		if ok && strings.TrimSuffix(provider.BaseURL(tmpl), "/") == baseURL {
+			matches = append(matches, id)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+
+	for _, tmpl := range ListTemplates() {
+		if strings.TrimSuffix(tmpl.BaseURL, "/") == baseURL {
+			return tmpl.ID
+		}
+	}
+	if len(matches) > 0 {
+		templateID, _ := SplitProviderID(matches[0])
+		return templateID
+	}
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return baseURL
+}
+
+// identifyEnv finds the configured provider vars belong to
+func (c *Config) identifyEnv(vars map[string]string) EnvState {
+	state := EnvState{Vars: vars}
+	if vars["ANTHROPIC_BASE_URL"] == "" {
+		// Timeout and telemetry don't depend on the provider
+		state.Provider = OfficialProvider
+		state.Current = true
+		return state
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(c.Providers)) {
+		// Only providers at that URL need their key read from the vault
+		provider := c.Providers[id]
+		if tmpl, ok := TemplateFor(id); !ok || provider.BaseURL(tmpl) != vars["ANTHROPIC_BASE_URL"] {
+			continue
+		}
+		expected, err := c.Env(id)
+		if err != nil {
+			continue
+		}
+		want := envVars(expected)
+		if vars["ANTHROPIC_BASE_URL"] == want["ANTHROPIC_BASE_URL"] && vars["ANTHROPIC_AUTH_TOKEN"] == want["ANTHROPIC_AUTH_TOKEN"] {
+			state.Provider = id
+			state.Current = envEqual(vars, expected)
+			return state
+		}
+	}
+	return state
+}
+
+// describeEnv names the provider of an env state, or its base URL
+func describeEnv(state EnvState) string {
+	if state.Provider != "" {
+		return state.Provider
+	}
+	return "an unknown provider (" + state.Vars["ANTHROPIC_BASE_URL"] + ")"
+}
+
+// envVars turns a variable list into a map
+func envVars(vars []EnvVar) map[string]string {
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		m[v.Key] = v.Value
+	}
+	return m
+}
+
+// envEqual reports whether got sets the variables in want to the same
+// values, missing ones counting as unset
+func envEqual(got map[string]string, want []EnvVar) bool {
+	for _, v := range want {
+		if got[v.Key] != v.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// sameEnv reports whether a and b set the provider variables alike
+func sameEnv(a, b map[string]string) bool {
+	for _, v := range OfficialEnv() {
+		if a[v.Key] != b[v.Key] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseEnvFile reads the variables an env file written by writeEnvFiles
+// sets, in shell's syntax; unset variables are empty
+func parseEnvFile(shell, content string) map[string]string {
+	vars := make(map[string]string)
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		var key, value string
+		var ok bool
+		switch envFileExt(shell) {
+		case ".nu":
+			if key, ok = strings.CutPrefix(line, "hide-env -i "); !ok {
+				key, value, ok = strings.Cut(line, ": ")
+				value = strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(strings.Trim(value, `"`))
+			}
+		case ".ps1":
+			if rest, found := strings.CutPrefix(line, "Remove-Item Env:"); found {
+				key, _, _ = strings.Cut(rest, " ")
+				ok = true
+			} else if rest, found := strings.CutPrefix(line, "$env:"); found {
+				key, value, ok = strings.Cut(rest, " = ")
+				value = strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'"), "''", "'")
+			}
+		default:
+			if key, ok = strings.CutPrefix(line, "unset "); !ok {
+				var rest string
+				if rest, ok = strings.CutPrefix(line, "export "); ok {
+					key, value, ok = strings.Cut(rest, "=")
+					if unquoted, err := strconv.Unquote(value); err == nil {
+						value = unquoted
+					}
+				}
+			}
+		}
+		if ok && key != "" && !strings.HasPrefix(key, "#") {
+			vars[key] = value
+		}
+	}
+	return vars
+}
@@ -1,20 +1,30 @@
 package claude
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 )
 
-// ProviderTemplate represents a hardcoded Claude API provider.
+// ProviderTemplate represents a Claude API provider, built in, from the
+// template registry or defined in ~/.config/zzk/claude-templates.json.
 type ProviderTemplate struct {
-	ID           string // Unique identifier (e.g., "synthetic", "openrouter")
-	Name         string // Display name
-	BaseURL      string // Fixed API base URL
-	AllowModels  bool   // Whether model overrides are allowed
-	DefaultModel string // Default model for all model types (used when user doesn't specify)
+	ID           string `json:"id"`                      // Unique identifier (e.g., "synthetic", "openrouter")
+	Name         string `json:"name,omitempty"`          // Display name
+	BaseURL      string `json:"base_url"`                // Fixed API base URL
+	AllowModels  bool   `json:"allow_models,omitempty"`  // Whether model overrides are allowed
+	DefaultModel string `json:"default_model,omitempty"` // Default model for all model types (used when user doesn't specify)
+	ModelsURL    string `json:"models_url,omitempty"`    // Model list, if not at <base URL>/v1/models
+	Custom       bool   `json:"-"`                       // Defined in the user's templates file
+	FromRegistry bool   `json:"-"`                       // Published in the template registry
 }
 
-// Templates is the registry of all known Claude API providers.
+// Templates is the registry of built-in Claude API providers.
 var Templates = []ProviderTemplate{
 	{
 		ID:           "synthetic",
@@ -39,20 +49,144 @@ var Templates = []ProviderTemplate{
 	},
 }
 
+// templateIDRegex matches template IDs, which are also provider keys in the
+// config and typed on the command line
+var templateIDRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var (
+	templatesOnce sync.Once
+	templates     []ProviderTemplate
+	templatesErr  error
+)
+
+// TemplatesPath returns the path to the user's provider templates file
+func TemplatesPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "zzk", "claude-templates.json")
+	}
+	return filepath.Join(home, ".config", "zzk", "claude-templates.json")
+}
+
+// LoadTemplates reads the cached template registry and the user's templates
+// file, once, and merges them with the built-in Templates: a template with
+// an ID already defined replaces it, others are added after them, the
+// user's file winning over the registry. On error only the templates read
+// so far are available.
+func LoadTemplates() error {
+	templatesOnce.Do(func() {
+		templates = Templates
+		registry, err := readRegistry(RegistryPath())
+		if err != nil {
+			templatesErr = fmt.Errorf("%s: %w (run 'zzk claude templates update')", RegistryPath(), err)
+			return
+		}
+		if registry != nil {
+			templates = mergeTemplates(templates, registry.Templates)
+		}
+
+		custom, err := readTemplates(TemplatesPath())
+		if err != nil {
+			templatesErr = fmt.Errorf("%s: %w", TemplatesPath(), err)
+			return
+		}
+		templates = mergeTemplates(templates, custom)
+	})
+	return templatesErr
+}
+
+// mergeTemplates returns base with the templates of overrides replacing the
+// ones with the same ID and the others appended
+func mergeTemplates(base, overrides []ProviderTemplate) []ProviderTemplate {
+	merged := append([]ProviderTemplate(nil), base...)
+	for _, tmpl := range overrides {
+		replaced := false
+		for i := range merged {
+			if merged[i].ID == tmpl.ID {
+				merged[i] = tmpl
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, tmpl)
+		}
+	}
+	return merged
+}
+
+// readTemplates reads and validates a templates file, which holds a
+// "templates" list. A missing file has no templates.
+func readTemplates(path string) ([]ProviderTemplate, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Templates []ProviderTemplate `json:"templates"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := validateTemplates(file.Templates); err != nil {
+		return nil, err
+	}
+	for i := range file.Templates {
+		file.Templates[i].Custom = true
+	}
+	return file.Templates, nil
+}
+
+// validateTemplates checks the IDs and URLs of a list of templates and
+// defaults their names to their IDs
+func validateTemplates(list []ProviderTemplate) error {
+	seen := make(map[string]bool)
+	for i := range list {
+		tmpl := &list[i]
+		if !templateIDRegex.MatchString(tmpl.ID) {
+			return fmt.Errorf("template %d: invalid id %q (use lowercase letters, digits, - and _)", i+1, tmpl.ID)
+		}
+		if seen[tmpl.ID] {
+			return fmt.Errorf("template '%s' is defined twice", tmpl.ID)
+		}
+		seen[tmpl.ID] = true
+
+		u, err := url.Parse(tmpl.BaseURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("template '%s': base_url must be an http(s) URL", tmpl.ID)
+		}
+		if tmpl.ModelsURL != "" {
+			if u, err := url.Parse(tmpl.ModelsURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("template '%s': models_url must be an http(s) URL", tmpl.ID)
+			}
+		}
+		if tmpl.Name == "" {
+			tmpl.Name = tmpl.ID
+		}
+	}
+	return nil
+}
+
 // GetTemplate returns a provider template by ID.
 // Returns nil and false if the template doesn't exist.
 func GetTemplate(id string) (*ProviderTemplate, bool) {
-	for i := range Templates {
-		if Templates[i].ID == id {
-			return &Templates[i], true
+	all := ListTemplates()
+	for i := range all {
+		if all[i].ID == id {
+			return &all[i], true
 		}
 	}
 	return nil, false
 }
 
-// ListTemplates returns all available provider templates.
+// ListTemplates returns all available provider templates, built-in, from
+// the registry and user-defined.
 func ListTemplates() []ProviderTemplate {
-	return Templates
+	LoadTemplates()
+	return templates
 }
 
 // IsValidTemplate checks if a template ID exists.
@@ -63,8 +197,9 @@ func IsValidTemplate(id string) bool {
 
 // TemplateIDs returns a list of all valid template IDs.
 func TemplateIDs() []string {
-	ids := make([]string, len(Templates))
-	for i, t := range Templates {
+	all := ListTemplates()
+	ids := make([]string, len(all))
+	for i, t := range all {
 		ids[i] = t.ID
 	}
 	return ids
@@ -80,7 +215,7 @@ func ResolveTemplateID(prefix string) (string, error) {
 
 	// Try prefix matching
 	var matches []string
-	for _, t := range Templates {
+	for _, t := range ListTemplates() {
 		if len(prefix) <= len(t.ID) && t.ID[:len(prefix)] == prefix {
 			matches = append(matches, t.ID)
 		}
@@ -97,3 +232,61 @@ func ResolveTemplateID(prefix string) (string, error) {
 			prefix, strings.Join(matches, ", "))
 	}
 }
+
+// ProfileSeparator separates the template ID from the profile name in the
+// IDs of providers configured more than once, e.g. "synthetic:work"
+const ProfileSeparator = ":"
+
+// SplitProviderID splits a provider ID into its template ID and profile
+// name, which is empty for the template's unnamed profile
+func SplitProviderID(id string) (templateID, profile string) {
+	templateID, profile, _ = strings.Cut(id, ProfileSeparator)
+	return templateID, profile
+}
+
+// TemplateFor returns the template of a provider ID.
+// Returns nil and false if the template doesn't exist.
+func TemplateFor(providerID string) (*ProviderTemplate, bool) {
+	templateID, _ := SplitProviderID(providerID)
+	return GetTemplate(templateID)
+}
+
+// IsValidProviderID checks if a provider ID names an existing template and,
+// if it has one, a well-formed profile
+func IsValidProviderID(id string) bool {
+	templateID, profile := SplitProviderID(id)
+	if strings.Contains(id, ProfileSeparator) && !templateIDRegex.MatchString(profile) {
+		return false
+	}
+	return IsValidTemplate(templateID)
+}
+
+// ResolveProviderID resolves a provider ID whose template part may be a
+// prefix, e.g. "syn:work" to "synthetic:work"
+func ResolveProviderID(arg string) (string, error) {
+	prefix, profile, hasProfile := strings.Cut(arg, ProfileSeparator)
+	templateID, err := ResolveTemplateID(prefix)
+	if err != nil {
+		return "", err
+	}
+	if !hasProfile {
+		return templateID, nil
+	}
+	if !templateIDRegex.MatchString(profile) {
+		return "", fmt.Errorf("invalid profile name %q (use lowercase letters, digits, - and _)", profile)
+	}
+	return templateID + ProfileSeparator + profile, nil
+}
+
+// ProviderName returns the display name of a provider ID, e.g.
+// "Synthetic (work)"
+func ProviderName(id string) string {
+	tmpl, ok := TemplateFor(id)
+	if !ok {
+		return id
+	}
+	if _, profile := SplitProviderID(id); profile != "" {
+		return tmpl.Name + " (" + profile + ")"
+	}
+	return tmpl.Name
+}
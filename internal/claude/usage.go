@@ -0,0 +1,202 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Usage is what a provider reports about a key's balance and spend
+type Usage struct {
+	Balance string // Remaining credits or quota
+	Spend   string // Recent spend or usage
+	Note    string // E.g. when a quota resets
+}
+
+// usageFetchers query the usage endpoints of built-in providers. They are
+// given the provider's base URL, whose host serves the endpoints, so regional
+// or overridden URLs are queried too.
+var usageFetchers = map[string]func(ctx context.Context, client *http.Client, baseURL, apiKey string) (*Usage, error){
+	"openrouter": openRouterUsage,
+	"synthetic":  syntheticUsage,
+	"zai":        zaiUsage,
+}
+
+// SupportsUsage reports whether GetUsage can query a provider
+func SupportsUsage(tmpl *ProviderTemplate) bool {
+	_, ok := usageFetchers[tmpl.ID]
+	return ok && !tmpl.Custom
+}
+
+// GetUsage queries a provider's balance and recent spend for its API key
+func GetUsage(ctx context.Context, tmpl *ProviderTemplate, provider Provider) (*Usage, error) {
+	if !SupportsUsage(tmpl) {
+		return nil, fmt.Errorf("%s has no usage endpoint zzk knows", tmpl.Name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	return usageFetchers[tmpl.ID](ctx, provider.HTTPClient(), provider.BaseURL(tmpl), provider.APIKey)
+}
+
+// originURL returns the scheme and host of baseURL joined with path
+func originURL(baseURL, path string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL + path
+	}
+	return u.Scheme + "://" + u.Host + path
+}
+
+// getJSON decodes the JSON response to a GET of url authenticated with key
+func getJSON(ctx context.Context, client *http.Client, url, apiKey string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("User-Agent", "zzk")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if message := apiErrorMessage(body); message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, message)
+		}
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// dollars formats an amount in US dollars
+func dollars(amount float64) string {
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// openRouterUsage reads the account's credits and the key's spend
+func openRouterUsage(ctx context.Context, client *http.Client, baseURL, apiKey string) (*Usage, error) {
+	base := strings.TrimSuffix(baseURL, "/")
+	var key struct {
+		Data *struct {
+			Usage          float64  `json:"usage"`
+			UsageDaily     float64  `json:"usage_daily"`
+			UsageWeekly    float64  `json:"usage_weekly"`
+			UsageMonthly   float64  `json:"usage_monthly"`
+			Limit          *float64 `json:"limit"`
+			LimitRemaining *float64 `json:"limit_remaining"`
+			IsFreeTier     bool     `json:"is_free_tier"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, client, base+"/v1/key", apiKey, &key); err != nil {
+		return nil, err
+	}
+	if key.Data == nil {
+		return nil, fmt.Errorf("unexpected response from %s/v1/key", base)
+	}
+
+	usage := &Usage{Spend: fmt.Sprintf("%s today · %s this week · %s this month",
+		dollars(key.Data.UsageDaily), dollars(key.Data.UsageWeekly), dollars(key.Data.UsageMonthly))}
+	if key.Data.Limit != nil && key.Data.LimitRemaining != nil {
+		usage.Note = fmt.Sprintf("key limit: %s of %s left", dollars(*key.Data.LimitRemaining), dollars(*key.Data.Limit))
+	}
+	if key.Data.IsFreeTier {
+		usage.Note = strings.TrimPrefix(usage.Note+", free tier", ", ")
+	}
+
+	// Credits are per account; keys limited to some models can't read them
+	var credits struct {
+		Data *struct {
+			TotalCredits float64 `json:"total_credits"`
+			TotalUsage   float64 `json:"total_usage"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, client, base+"/v1/credits", apiKey, &credits); err != nil || credits.Data == nil {
+		usage.Balance = "unknown"
+	} else {
+		usage.Balance = fmt.Sprintf("%s of %s credits",
+			dollars(credits.Data.TotalCredits-credits.Data.TotalUsage), dollars(credits.Data.TotalCredits))
+	}
+	return usage, nil
+}
+
+// syntheticUsage reads the subscription's request quota
+func syntheticUsage(ctx context.Context, client *http.Client, baseURL, apiKey string) (*Usage, error) {
+	var quotas struct {
+		Subscription *struct {
+			Limit    float64   `json:"limit"`
+			Requests float64   `json:"requests"`
+			RenewsAt time.Time `json:"renewsAt"`
+		} `json:"subscription"`
+	}
+	if err := getJSON(ctx, client, originURL(baseURL, "/v2/quotas"), apiKey, &quotas); err != nil {
+		return nil, err
+	}
+	sub := quotas.Subscription
+	if sub == nil {
+		return nil, fmt.Errorf("no subscription quota (pay-per-use keys report no balance)")
+	}
+
+	usage := &Usage{
+		Balance: fmt.Sprintf("%g of %g requests", max(sub.Limit-sub.Requests, 0), sub.Limit),
+		Spend:   fmt.Sprintf("%g requests this period", sub.Requests),
+	}
+	if !sub.RenewsAt.IsZero() {
+		usage.Note = "renews " + humanize.Time(sub.RenewsAt)
+	}
+	return usage, nil
+}
+
+// zaiUsage reads the GLM Coding Plan quotas: a token quota per 5-hour window
+// and a monthly quota of web search and reader tool calls
+func zaiUsage(ctx context.Context, client *http.Client, baseURL, apiKey string) (*Usage, error) {
+	var quota struct {
+		Data *struct {
+			Limits []struct {
+				Type          string  `json:"type"`
+				Usage         float64 `json:"usage"`
+				CurrentValue  float64 `json:"currentValue"`
+				Percentage    float64 `json:"percentage"`
+				NextResetTime int64   `json:"nextResetTime"`
+			} `json:"limits"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, client, originURL(baseURL, "/api/monitor/usage/quota/limit"), apiKey, &quota); err != nil {
+		return nil, err
+	}
+	if quota.Data == nil || len(quota.Data.Limits) == 0 {
+		return nil, fmt.Errorf("no Coding Plan quota (pay-per-use keys report no balance)")
+	}
+
+	usage := &Usage{Balance: "unknown"}
+	var tokens, tools string
+	for _, limit := range quota.Data.Limits {
+		switch limit.Type {
+		case "TOKENS_LIMIT":
+			usage.Balance = fmt.Sprintf("%g%% of 5h token quota", 100-limit.Percentage)
+			tokens = fmt.Sprintf("%g%% of tokens", limit.Percentage)
+			if limit.NextResetTime > 0 {
+				usage.Note = "resets " + humanize.Time(time.UnixMilli(limit.NextResetTime))
+			}
+		case "TIME_LIMIT":
+			tools = fmt.Sprintf("%g of %g tool calls", limit.CurrentValue, limit.Usage)
+		}
+	}
+	usage.Spend = strings.Trim(tokens+" · "+tools, " ·")
+	return usage, nil
+}
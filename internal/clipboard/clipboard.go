@@ -0,0 +1,55 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Copy puts text on the system clipboard using the platform's clipboard tool
+func Copy(text string) error {
+	name, args, err := copyCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w\n%s", name, err, output)
+	}
+	return nil
+}
+
+// copyCommand finds a clipboard command for this platform
+func copyCommand() (string, []string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "pbcopy", nil, nil
+	case "windows":
+		return "clip", nil, nil
+	}
+
+	candidates := []struct {
+		name string
+		args []string
+	}{
+		{"xclip", []string{"-selection", "clipboard"}},
+		{"xsel", []string{"--clipboard", "--input"}},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([]struct {
+			name string
+			args []string
+		}{{"wl-copy", nil}}, candidates...)
+	}
+
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c.name); err == nil {
+			return c.name, c.args, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+}
@@ -0,0 +1,82 @@
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DecodeBase64 decodes standard or URL-safe base64, with or without padding
+func DecodeBase64(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	s = strings.TrimRight(s, "=")
+
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+
+	data, err := encoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	return data, nil
+}
+
+// JWT is a decoded (unverified) JSON Web Token
+type JWT struct {
+	Header    json.RawMessage
+	Claims    json.RawMessage
+	Signature string
+}
+
+// ParseJWT decodes the header and claims of a token without verifying the signature
+func ParseJWT(token string) (*JWT, error) {
+	token = strings.TrimSpace(token)
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("not a JWT: expected 3 dot-separated parts, got %d", len(parts))
+	}
+
+	header, err := decodeSegment(parts[0], "header")
+	if err != nil {
+		return nil, err
+	}
+	claims, err := decodeSegment(parts[1], "claims")
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWT{Header: header, Claims: claims, Signature: parts[2]}, nil
+}
+
+func decodeSegment(segment, name string) (json.RawMessage, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT %s encoding: %w", name, err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("JWT %s is not valid JSON", name)
+	}
+	return data, nil
+}
+
+// Times returns the registered time claims (iat, nbf, exp) that are present
+func (j *JWT) Times() map[string]time.Time {
+	var claims map[string]any
+	if err := json.Unmarshal(j.Claims, &claims); err != nil {
+		return nil
+	}
+
+	times := make(map[string]time.Time)
+	for _, name := range []string{"iat", "nbf", "exp"} {
+		if value, ok := claims[name].(float64); ok {
+			times[name] = time.Unix(int64(value), 0)
+		}
+	}
+	return times
+}
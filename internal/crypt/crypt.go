@@ -0,0 +1,237 @@
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+	"golang.org/x/term"
+)
+
+// PassphraseEnvVar can hold a passphrase for non-interactive use
+const PassphraseEnvVar = "ZZK_CRYPT_PASSPHRASE"
+
+// Options configures encryption
+type Options struct {
+	Passphrase string          // Used when no recipients are given
+	Recipients []age.Recipient // Public-key recipients
+	Armor      bool            // PEM-style ASCII output
+}
+
+// NewEncryptor returns a writer that encrypts to dst. Close must be called to
+// flush the final chunk.
+func NewEncryptor(dst io.Writer, opts Options) (io.WriteCloser, error) {
+	recipients := opts.Recipients
+	if len(recipients) == 0 {
+		if opts.Passphrase == "" {
+			return nil, fmt.Errorf("no recipients or passphrase given")
+		}
+		r, err := age.NewScryptRecipient(opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		recipients = []age.Recipient{r}
+	}
+
+	if !opts.Armor {
+		return age.Encrypt(dst, recipients...)
+	}
+
+	armored := armor.NewWriter(dst)
+	w, err := age.Encrypt(armored, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	return &chainCloser{Writer: w, closers: []io.Closer{w, armored}}, nil
+}
+
+// chainCloser closes several writers in order
+type chainCloser struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (c *chainCloser) Close() error {
+	for _, closer := range c.closers {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewDecryptor returns a reader of the plaintext in src. Armored input is detected automatically.
+func NewDecryptor(src io.Reader, identities ...age.Identity) (io.Reader, error) {
+	buffered := bufio.NewReader(src)
+	if peek, _ := buffered.Peek(len(armor.Header)); string(peek) == armor.Header {
+		return age.Decrypt(armor.NewReader(buffered), identities...)
+	}
+	return age.Decrypt(buffered, identities...)
+}
+
+// Encrypt encrypts src into dst
+func Encrypt(dst io.Writer, src io.Reader, opts Options) error {
+	w, err := NewEncryptor(dst, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return w.Close()
+}
+
+// Decrypt decrypts src into dst
+func Decrypt(dst io.Writer, src io.Reader, identities ...age.Identity) error {
+	r, err := NewDecryptor(src, identities...)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt: %w", err)
+	}
+	if _, err := io.Copy(dst, r); err != nil {
+		return fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return nil
+}
+
+// ParseRecipient parses an age (age1...) or SSH public key recipient
+func ParseRecipient(s string) (age.Recipient, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "ssh-") {
+		return agessh.ParseRecipient(s)
+	}
+	return age.ParseX25519Recipient(s)
+}
+
+// ParseRecipients parses recipient strings; values that name a file are read
+// as recipients files (one recipient per line, # comments allowed).
+func ParseRecipients(values []string) ([]age.Recipient, error) {
+	var recipients []age.Recipient
+	for _, value := range values {
+		if data, err := os.ReadFile(value); err == nil {
+			for line := range strings.SplitSeq(string(data), "\n") {
+				line = strings.TrimSpace(line)
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+				r, err := ParseRecipient(line)
+				if err != nil {
+					return nil, fmt.Errorf("invalid recipient in %s: %w", value, err)
+				}
+				recipients = append(recipients, r)
+			}
+			continue
+		}
+
+		r, err := ParseRecipient(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", value, err)
+		}
+		recipients = append(recipients, r)
+	}
+	return recipients, nil
+}
+
+// LoadIdentities reads age identity files or unencrypted SSH private keys
+func LoadIdentities(paths []string) ([]age.Identity, error) {
+	var identities []age.Identity
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity %s: %w", path, err)
+		}
+
+		if bytes.Contains(data, []byte("PRIVATE KEY-----")) {
+			id, err := agessh.ParseIdentity(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse SSH key %s: %w", path, err)
+			}
+			identities = append(identities, id)
+			continue
+		}
+
+		ids, err := age.ParseIdentities(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse identity %s: %w", path, err)
+		}
+		identities = append(identities, ids...)
+	}
+	return identities, nil
+}
+
+// PassphraseIdentity returns an identity for passphrase-encrypted files
+func PassphraseIdentity(passphrase string) (age.Identity, error) {
+	return age.NewScryptIdentity(passphrase)
+}
+
+// ReadPassphrase returns the passphrase from $ZZK_CRYPT_PASSPHRASE or prompts
+// for it without echo. When confirm is true the user must enter it twice.
+func ReadPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("cannot prompt for passphrase in non-interactive mode (set %s)", PassphraseEnvVar)
+	}
+
+	fmt.Fprint(os.Stderr, "Passphrase: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(first) == 0 {
+		return "", fmt.Errorf("passphrase must not be empty")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		second, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if string(first) != string(second) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return string(first), nil
+}
+
+// LoadOrCreateKey loads the age identity at path, generating a new one
+// (readable only by the owner) if the file does not exist
+func LoadOrCreateKey(path string) (*age.X25519Identity, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		ids, err := age.ParseIdentities(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+		}
+		id, ok := ids[0].(*age.X25519Identity)
+		if !ok {
+			return nil, fmt.Errorf("key %s is not an X25519 identity", path)
+		}
+		return id, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read key %s: %w", path, err)
+	}
+
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	content := fmt.Sprintf("# public key: %s\n%s\n", id.Recipient(), id)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write key %s: %w", path, err)
+	}
+	return id, nil
+}
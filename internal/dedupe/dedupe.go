@@ -0,0 +1,271 @@
+package dedupe
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+	"lukechampine.com/blake3"
+)
+
+// partialSize is how much of each file is hashed before a full hash is needed
+const partialSize = 64 * 1024
+
+// File is a candidate duplicate
+type File struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	// Links are other paths hardlinked to the same inode
+	Links []string
+	info  os.FileInfo
+}
+
+// Group is a set of files with identical content
+type Group struct {
+	Size  int64
+	Hash  string
+	Files []File
+}
+
+// Wasted is the space that would be reclaimed by keeping one copy
+func (g Group) Wasted() int64 {
+	return g.Size * int64(len(g.Files)-1)
+}
+
+// Keep rules
+const (
+	KeepNewest   = "newest"
+	KeepOldest   = "oldest"
+	KeepShortest = "shortest"
+	KeepFirst    = "first"
+)
+
+// KeepRules lists the valid --keep values
+var KeepRules = []string{KeepNewest, KeepOldest, KeepShortest, KeepFirst}
+
+// Find walks the roots and returns groups of identical files, largest waste first.
+// Files smaller than minSize are ignored, and hardlinks to the same inode are
+// counted once since they take no extra space.
+func Find(roots []string, exclude []string, minSize int64) ([]Group, error) {
+	bySize := make(map[int64][]File)
+	seen := make(map[string]bool)
+
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ Warning: %v\n", err)
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if path != root && fileutil.ExcludedPath(rel, exclude) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if seen[abs] {
+				return nil
+			}
+			seen[abs] = true
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			if info.Size() < max(minSize, 1) {
+				return nil
+			}
+			bySize[info.Size()] = append(bySize[info.Size()], File{Path: abs, Size: info.Size(), ModTime: info.ModTime(), info: info})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var groups []Group
+	for size, files := range bySize {
+		files = uniqueInodes(files)
+		if len(files) < 2 {
+			continue
+		}
+
+		// Cheap pass over the first block, then a full hash of what still collides
+		for _, partial := range splitByHash(files, partialSize) {
+			if size <= partialSize {
+				groups = append(groups, partial)
+				continue
+			}
+			groups = append(groups, splitByHash(partial.Files, -1)...)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Wasted() != groups[j].Wasted() {
+			return groups[i].Wasted() > groups[j].Wasted()
+		}
+		return groups[i].Files[0].Path < groups[j].Files[0].Path
+	})
+	return groups, nil
+}
+
+// uniqueInodes folds hardlinks of an earlier file into its Links
+func uniqueInodes(files []File) []File {
+	var unique []File
+	for _, f := range files {
+		linked := false
+		for i := range unique {
+			if os.SameFile(f.info, unique[i].info) {
+				unique[i].Links = append(unique[i].Links, f.Path)
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			unique = append(unique, f)
+		}
+	}
+	return unique
+}
+
+// splitByHash groups files by the hash of their first limit bytes (all of it if limit < 0)
+func splitByHash(files []File, limit int64) []Group {
+	byHash := make(map[string][]File)
+	var order []string
+	for _, f := range files {
+		sum, err := hashFile(f.Path, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Warning: failed to hash %s: %v\n", f.Path, err)
+			continue
+		}
+		if _, ok := byHash[sum]; !ok {
+			order = append(order, sum)
+		}
+		byHash[sum] = append(byHash[sum], f)
+	}
+
+	var groups []Group
+	for _, sum := range order {
+		if len(byHash[sum]) < 2 {
+			continue
+		}
+		group := Group{Size: byHash[sum][0].Size, Hash: sum, Files: byHash[sum]}
+		sort.Slice(group.Files, func(i, j int) bool { return group.Files[i].Path < group.Files[j].Path })
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func hashFile(path string, limit int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if limit >= 0 {
+		r = io.LimitReader(f, limit)
+	}
+	h := blake3.New(32, nil)
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Keep returns the index of the file to keep under the given rule
+func Keep(g Group, rule string) (int, error) {
+	keep := 0
+	for i, f := range g.Files[1:] {
+		i++
+		k := g.Files[keep]
+		switch rule {
+		case KeepNewest:
+			if f.ModTime.After(k.ModTime) {
+				keep = i
+			}
+		case KeepOldest:
+			if f.ModTime.Before(k.ModTime) {
+				keep = i
+			}
+		case KeepShortest:
+			if len(f.Path) < len(k.Path) {
+				keep = i
+			}
+		case KeepFirst:
+		default:
+			return 0, fmt.Errorf("unknown keep rule %q (use newest, oldest, shortest or first)", rule)
+		}
+	}
+	return keep, nil
+}
+
+// Delete removes dup and its hardlinks after checking it still matches the kept file
+func Delete(keep, dup File) error {
+	if err := verify(keep, dup); err != nil {
+		return err
+	}
+	for _, path := range append([]string{dup.Path}, dup.Links...) {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Hardlink replaces dup and its hardlinks with hardlinks to keep. Each link
+// is created alongside and renamed over the old path so it is never missing.
+func Hardlink(keep, dup File) error {
+	if err := verify(keep, dup); err != nil {
+		return err
+	}
+
+	for _, path := range append([]string{dup.Path}, dup.Links...) {
+		tmp := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.zzk-dedupe", filepath.Base(path)))
+		os.Remove(tmp)
+		if err := os.Link(keep.Path, tmp); err != nil {
+			return fmt.Errorf("failed to link %s: %w", path, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// verify guards against files that changed since they were scanned
+func verify(keep, dup File) error {
+	for _, f := range []File{keep, dup} {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return err
+		}
+		if info.Size() != f.Size || !info.ModTime().Equal(f.ModTime) {
+			return fmt.Errorf("%s changed since it was scanned", f.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Record is a single answer record
+type Record struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   uint32 `json:"ttl,omitempty"`
+	Value string `json:"value"`
+}
+
+// Types are the supported record types
+var Types = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"MX":    dnsmessage.TypeMX,
+	"TXT":   dnsmessage.TypeTXT,
+	"CNAME": dnsmessage.TypeCNAME,
+	"NS":    dnsmessage.TypeNS,
+}
+
+// DoHServers are shorthand names for well-known DNS-over-HTTPS endpoints
+var DoHServers = map[string]string{
+	"cloudflare": "https://cloudflare-dns.com/dns-query",
+	"google":     "https://dns.google/dns-query",
+	"quad9":      "https://dns.quad9.net/dns-query",
+}
+
+const timeout = 5 * time.Second
+
+// Query looks up records of the given type. An empty server uses the system
+// resolver; an https:// URL (or a DoHServers name) uses DNS-over-HTTPS;
+// anything else is treated as host[:port] of a plain DNS server.
+func Query(ctx context.Context, name, recordType, server string) ([]Record, error) {
+	recordType = strings.ToUpper(recordType)
+	qtype, ok := Types[recordType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if server == "" {
+		return querySystem(ctx, name, recordType)
+	}
+
+	if url, ok := DoHServers[server]; ok {
+		server = url
+	}
+
+	query, err := buildQuery(name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []byte
+	if strings.HasPrefix(server, "https://") {
+		response, err = exchangeHTTPS(ctx, server, query)
+	} else {
+		response, err = exchangeServer(ctx, server, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResponse(response)
+}
+
+// querySystem uses the system resolver, which does not expose TTLs
+func querySystem(ctx context.Context, name, recordType string) ([]Record, error) {
+	resolver := net.DefaultResolver
+	fqdn := ensureDot(name)
+
+	var records []Record
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, notFoundIsEmpty(err)
+		}
+		for _, ip := range ips {
+			records = append(records, Record{Name: fqdn, Type: recordType, Value: ip.String()})
+		}
+	case "MX":
+		mxs, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, notFoundIsEmpty(err)
+		}
+		for _, mx := range mxs {
+			records = append(records, Record{Name: fqdn, Type: recordType, Value: fmt.Sprintf("%d %s", mx.Pref, mx.Host)})
+		}
+	case "TXT":
+		txts, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, notFoundIsEmpty(err)
+		}
+		for _, txt := range txts {
+			records = append(records, Record{Name: fqdn, Type: recordType, Value: txt})
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, notFoundIsEmpty(err)
+		}
+		if cname != fqdn {
+			records = append(records, Record{Name: fqdn, Type: recordType, Value: cname})
+		}
+	case "NS":
+		nss, err := resolver.LookupNS(ctx, name)
+		if err != nil {
+			return nil, notFoundIsEmpty(err)
+		}
+		for _, ns := range nss {
+			records = append(records, Record{Name: fqdn, Type: recordType, Value: ns.Host})
+		}
+	}
+
+	return records, nil
+}
+
+// notFoundIsEmpty treats NXDOMAIN / no data as an empty answer rather than an error
+func notFoundIsEmpty(err error) error {
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		return nil
+	}
+	// LookupIP reports an address error when only the other family exists
+	if _, ok := err.(*net.AddrError); ok {
+		return nil
+	}
+	return err
+}
+
+func ensureDot(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+func buildQuery(name string, qtype dnsmessage.Type) ([]byte, error) {
+	qname, err := dnsmessage.NewName(ensureDot(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %q: %w", name, err)
+	}
+
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: uint16(time.Now().UnixNano()), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  qname,
+			Type:  qtype,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	return msg.Pack()
+}
+
+// exchangeServer sends a query over UDP, retrying over TCP if the answer was truncated
+func exchangeServer(ctx context.Context, server string, query []byte) ([]byte, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, fmt.Errorf("failed to send query: %w", err)
+	}
+
+	buf := make([]byte, 65535)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("no response from %s: %w", server, err)
+	}
+
+	var parser dnsmessage.Parser
+	if header, err := parser.Start(buf[:n]); err == nil && header.Truncated {
+		return exchangeTCP(ctx, server, query)
+	}
+
+	return buf[:n], nil
+}
+
+func exchangeTCP(ctx context.Context, server string, query []byte) ([]byte, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// TCP messages are prefixed with a two-byte length
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+	if _, err := conn.Write(append(framed, query...)); err != nil {
+		return nil, fmt.Errorf("failed to send query: %w", err)
+	}
+
+	var length uint16
+	if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("no response from %s: %w", server, err)
+	}
+	response := make([]byte, length)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, fmt.Errorf("truncated response from %s: %w", server, err)
+	}
+	return response, nil
+}
+
+// exchangeHTTPS sends a query using DNS-over-HTTPS (RFC 8484)
+func exchangeHTTPS(ctx context.Context, url string, query []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("DoH request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 65535))
+}
+
+func parseResponse(response []byte) ([]Record, error) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(response); err != nil {
+		return nil, fmt.Errorf("invalid DNS response: %w", err)
+	}
+
+	switch msg.RCode {
+	case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+	default:
+		return nil, fmt.Errorf("server returned %s", strings.TrimPrefix(msg.RCode.String(), "RCode"))
+	}
+
+	var records []Record
+	for _, answer := range msg.Answers {
+		record := Record{
+			Name: answer.Header.Name.String(),
+			Type: strings.TrimPrefix(answer.Header.Type.String(), "Type"),
+			TTL:  answer.Header.TTL,
+		}
+
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			record.Value = net.IP(body.A[:]).String()
+		case *dnsmessage.AAAAResource:
+			record.Value = net.IP(body.AAAA[:]).String()
+		case *dnsmessage.MXResource:
+			record.Value = fmt.Sprintf("%d %s", body.Pref, body.MX.String())
+		case *dnsmessage.TXTResource:
+			record.Value = strings.Join(body.TXT, "")
+		case *dnsmessage.CNAMEResource:
+			record.Value = body.CNAME.String()
+		case *dnsmessage.NSResource:
+			record.Value = body.NS.String()
+		default:
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,165 @@
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Var is a single KEY=value assignment
+type Var struct {
+	Key   string
+	Value string
+}
+
+var keyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// Parse parses a .env file. Blank lines and # comments are ignored, an
+// optional "export " prefix is accepted, single-quoted values are literal and
+// double-quoted values support \n, \t, \" and \\ escapes. Order is preserved;
+// later assignments of the same key win.
+func Parse(data []byte) ([]Var, error) {
+	var vars []Var
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if !keyRegex.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid key %q", lineNo, key)
+		}
+
+		value, err := parseValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		if i, exists := index[key]; exists {
+			vars[i].Value = value
+			continue
+		}
+		index[key] = len(vars)
+		vars = append(vars, Var{Key: key, Value: value})
+	}
+
+	return vars, scanner.Err()
+}
+
+func parseValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return raw[1 : end+1], nil
+	case strings.HasPrefix(raw, `"`):
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			switch {
+			case c == '"':
+				return b.String(), nil
+			case c == '\\' && i+1 < len(raw):
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'r':
+					b.WriteByte('\r')
+				default:
+					b.WriteByte(raw[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double quote")
+	default:
+		// Unquoted values end at an inline comment
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+// Format renders vars as a .env file, quoting values where needed
+func Format(vars []Var) []byte {
+	var buf bytes.Buffer
+	for _, v := range vars {
+		fmt.Fprintf(&buf, "%s=%s\n", v.Key, quote(v.Value))
+	}
+	return buf.Bytes()
+}
+
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\r\"'#$\\`") {
+		replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+		return `"` + replacer.Replace(value) + `"`
+	}
+	return value
+}
+
+// Map converts vars to a map
+func Map(vars []Var) map[string]string {
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		m[v.Key] = v.Value
+	}
+	return m
+}
+
+// Diff lists keys only in a, only in b, and present in both with different values
+type Diff struct {
+	OnlyA   []string
+	OnlyB   []string
+	Changed []string
+}
+
+// Empty reports whether there are no differences
+func (d Diff) Empty() bool {
+	return len(d.OnlyA) == 0 && len(d.OnlyB) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns the differences between two sets of vars
+func Compare(a, b []Var) Diff {
+	ma, mb := Map(a), Map(b)
+	var d Diff
+	for key, va := range ma {
+		vb, ok := mb[key]
+		switch {
+		case !ok:
+			d.OnlyA = append(d.OnlyA, key)
+		case va != vb:
+			d.Changed = append(d.Changed, key)
+		}
+	}
+	for key := range mb {
+		if _, ok := ma[key]; !ok {
+			d.OnlyB = append(d.OnlyB, key)
+		}
+	}
+	sort.Strings(d.OnlyA)
+	sort.Strings(d.OnlyB)
+	sort.Strings(d.Changed)
+	return d
+}
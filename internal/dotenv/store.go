@@ -0,0 +1,166 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/ppowo/zzk/internal/crypt"
+	"github.com/ppowo/zzk/internal/fileutil"
+	"github.com/ppowo/zzk/internal/vault"
+)
+
+// Stored copies live in ~/.config/zzk/env/<project>.env.age, encrypted to a
+// key generated on first use and kept in the vault
+const storeSuffix = ".env.age"
+
+// KeySecret is the vault secret holding the store key. Versions before the
+// vault kept it in key.txt next to the stored copies.
+const KeySecret = "env/key"
+
+// StoreDir returns the directory holding the encrypted copies
+func StoreDir() (string, error) {
+	base, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "env")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create env store: %w", err)
+	}
+	return dir, nil
+}
+
+func storeKey() (*age.X25519Identity, string, error) {
+	dir, err := StoreDir()
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := vaultKey()
+	return id, dir, err
+}
+
+// vaultKey reads the store key from the vault once per process, moving the
+// key.txt of older versions there or generating a key on first use
+var vaultKey = sync.OnceValues(func() (*age.X25519Identity, error) {
+	dir, err := StoreDir()
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := vault.Get(KeySecret)
+	if err == nil {
+		id, err := age.ParseX25519Identity(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in the vault: %w", KeySecret, err)
+		}
+		return id, nil
+	}
+	if !errors.Is(err, vault.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read the env store key from the vault: %w", err)
+	}
+
+	legacy := filepath.Join(dir, "key.txt")
+	var id *age.X25519Identity
+	if _, err := os.Stat(legacy); err == nil {
+		id, err = crypt.LoadOrCreateKey(legacy)
+		if err != nil {
+			return nil, err
+		}
+	} else if id, err = age.GenerateX25519Identity(); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	if err := vault.Set(KeySecret, id.String()); err != nil {
+		return nil, fmt.Errorf("failed to store the env store key in the vault: %w", err)
+	}
+	if err := os.Remove(legacy); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return id, nil
+})
+
+// Load decrypts the stored copy for project. It returns nil vars and no
+// error if nothing is stored yet.
+func Load(project string) ([]Var, error) {
+	id, dir, err := storeKey()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, project+storeSuffix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := crypt.NewDecryptor(f, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt stored env for %s: %w", project, err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt stored env for %s: %w", project, err)
+	}
+	return Parse(data)
+}
+
+// Save encrypts vars as the stored copy for project
+func Save(project string, vars []Var) error {
+	id, dir, err := storeKey()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := crypt.NewEncryptor(&buf, crypt.Options{Recipients: []age.Recipient{id.Recipient()}})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(Format(vars)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return fileutil.AtomicWrite(filepath.Join(dir, project+storeSuffix), buf.Bytes(), 0600)
+}
+
+// Remove deletes the stored copy for project
+func Remove(project string) error {
+	dir, err := StoreDir()
+	if err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(dir, project+storeSuffix))
+}
+
+// Projects lists the projects with a stored copy
+func Projects() ([]string, error) {
+	dir, err := StoreDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), storeSuffix); ok {
+			projects = append(projects, name)
+		}
+	}
+	sort.Strings(projects)
+	return projects, nil
+}
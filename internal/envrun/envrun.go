@@ -0,0 +1,88 @@
+package envrun
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// Merge returns base (KEY=value entries) with overrides applied. Overrides
+// replace existing keys in place and new keys are appended in order.
+func Merge(base []string, overrides map[string]string, order []string) []string {
+	env := make([]string, 0, len(base)+len(overrides))
+	seen := make(map[string]bool)
+
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if value, ok := overrides[key]; ok {
+			env = append(env, key+"="+value)
+			seen[key] = true
+			continue
+		}
+		env = append(env, entry)
+	}
+
+	for _, key := range order {
+		if seen[key] {
+			continue
+		}
+		if value, ok := overrides[key]; ok {
+			env = append(env, key+"="+value)
+			seen[key] = true
+		}
+	}
+
+	return env
+}
+
+// Run runs a command with the given environment and the current stdio,
+// forwarding interrupt and termination signals to it. It returns the
+// command's exit code; err is only set if the command could not be started.
+func Run(args []string, env []string) (int, error) {
+	if len(args) == 0 {
+		return 0, fmt.Errorf("no command given")
+	}
+
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return 127, fmt.Errorf("command not found: %s", args[0])
+	}
+
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return 1, fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	// The child shares our terminal and gets Ctrl-C directly; forward the
+	// signals anyway so it also stops when we are signalled on our own
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code, nil
+		}
+		return 1, nil // Killed by a signal
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
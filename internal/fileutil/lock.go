@@ -0,0 +1,57 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrLocked is returned by Lock when another process holds the lock for
+// longer than the timeout
+var ErrLocked = errors.New("locked by another process")
+
+// errWouldBlock is returned by tryLock when the lock is held elsewhere
+var errWouldBlock = errors.New("lock held")
+
+// FileLock is an exclusive advisory lock on a file, released by Unlock or
+// when the process exits
+type FileLock struct {
+	file *os.File
+}
+
+// Lock takes an exclusive advisory lock on path, creating the file if
+// needed, and waits up to timeout for another process holding it to let go.
+// It only excludes other callers of Lock, not plain readers and writers.
+func Lock(path string, timeout time.Duration) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLock(file)
+		if err == nil {
+			return &FileLock{file: file}, nil
+		}
+		if !errors.Is(err, errWouldBlock) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%s: %w", path, ErrLocked)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() error {
+	unlockErr := unlock(l.file)
+	if err := l.file.Close(); err != nil && unlockErr == nil {
+		return err
+	}
+	return unlockErr
+}
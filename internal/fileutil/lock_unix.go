@@ -0,0 +1,23 @@
+//go:build unix
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes a flock on file without waiting
+func tryLock(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return errWouldBlock
+	}
+	return err
+}
+
+// unlock releases a flock taken by tryLock
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,22 @@
+package fileutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ExcludedPath reports whether any component of path matches one of the globs.
+// This mirrors tar's --exclude semantics used by the backup commands.
+func ExcludedPath(path string, globs []string) bool {
+	for part := range strings.SplitSeq(filepath.ToSlash(path), "/") {
+		if part == "" || part == "." {
+			continue
+		}
+		for _, glob := range globs {
+			if ok, _ := filepath.Match(glob, part); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/vault"
+)
+
+// Repo describes a repository created on a forge
+type Repo struct {
+	FullName string // owner/name
+	SSHURL   string
+	WebURL   string
+}
+
+// CreateRepoOptions holds the parameters for creating a repository
+type CreateRepoOptions struct {
+	Name        string
+	Description string
+	Private     bool
+	Org         string // Optional organization/group namespace
+}
+
+// SSHKeyOptions holds the parameters for adding an SSH public key to an account
+type SSHKeyOptions struct {
+	Title   string
+	Key     string // Public key line: type, base64 and optional comment
+	Signing bool   // Also register the key for commit signature verification
+}
+
+// ErrKeyExists is returned by AddSSHKey when the account already has the key
+var ErrKeyExists = errors.New("key already added")
+
+// Client creates repositories and manages SSH keys on a forge
+type Client interface {
+	CreateRepo(opts CreateRepoOptions) (*Repo, error)
+	AddSSHKey(opts SSHKeyOptions) error
+}
+
+// Kind identifies the forge API flavor for a domain
+type Kind string
+
+const (
+	KindGitHub Kind = "github"
+	KindGitLab Kind = "gitlab"
+	KindGitea  Kind = "gitea"
+)
+
+// DetectKind returns the API flavor for a domain.
+// Unknown domains containing "gitlab" are treated as GitLab, everything else
+// that isn't github.com is assumed to be Gitea/Forgejo (e.g. codeberg.org).
+func DetectKind(domain string) Kind {
+	switch {
+	case domain == "github.com":
+		return KindGitHub
+	case strings.Contains(domain, "gitlab"):
+		return KindGitLab
+	default:
+		return KindGitea
+	}
+}
+
+// TokenEnvVar returns the environment variable holding the API token for a domain
+func TokenEnvVar(domain string) string {
+	switch DetectKind(domain) {
+	case KindGitHub:
+		return "GITHUB_TOKEN"
+	case KindGitLab:
+		return "GITLAB_TOKEN"
+	}
+	if domain == "codeberg.org" {
+		return "CODEBERG_TOKEN"
+	}
+	return "GITEA_TOKEN"
+}
+
+// TokenSecret returns the vault secret name holding the API token for a domain
+func TokenSecret(domain string) string {
+	return "forge/" + domain
+}
+
+// IdentityTokenSecret returns the vault secret holding the API token of one
+// identity, for domains with several accounts
+func IdentityTokenSecret(domain, identity string) string {
+	return TokenSecret(domain) + "/" + identity
+}
+
+// LookupIdentityToken returns the identity's own token from the vault, else
+// the domain token from LookupToken
+func LookupIdentityToken(domain, identity string) (string, error) {
+	token, err := vault.Get(IdentityTokenSecret(domain, identity))
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, vault.ErrNotFound) {
+		return "", fmt.Errorf("failed to read API token for %s from the vault: %w", identity, err)
+	}
+	return LookupToken(domain)
+}
+
+// LookupToken returns the API token for a domain from the environment or the vault
+func LookupToken(domain string) (string, error) {
+	envVar := TokenEnvVar(domain)
+	if token := os.Getenv(envVar); token != "" {
+		return token, nil
+	}
+	token, err := vault.Get(TokenSecret(domain))
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, vault.ErrNotFound) {
+		return "", fmt.Errorf("failed to read API token for %s from the vault: %w", domain, err)
+	}
+	return "", fmt.Errorf("no API token for %s - set %s or run 'zzk vault set %s'", domain, envVar, TokenSecret(domain))
+}
+
+// NewClient returns a client for the forge hosted at domain
+func NewClient(domain, token string) Client {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	switch DetectKind(domain) {
+	case KindGitHub:
+		return &githubClient{http: httpClient, token: token}
+	case KindGitLab:
+		return &gitlabClient{http: httpClient, token: token, baseURL: "https://" + domain + "/api/v4"}
+	default:
+		return &giteaClient{http: httpClient, token: token, baseURL: "https://" + domain + "/api/v1"}
+	}
+}
+
+// doJSON sends a JSON request and decodes a JSON response into out
+func doJSON(client *http.Client, req *http.Request, body any, out any) error {
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "zzk")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &apiError{
+			method:  req.Method,
+			path:    req.URL.Path,
+			status:  resp.Status,
+			code:    resp.StatusCode,
+			message: apiErrorMessage(respBody),
+		}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// apiError is a non-2xx API response
+type apiError struct {
+	method, path, status string
+	code                 int
+	message              string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: %s: %s", e.method, e.path, e.status, e.message)
+}
+
+// isAlreadyExists reports whether err is a validation error for a key that
+// is already on the account
+func isAlreadyExists(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || (apiErr.code != http.StatusUnprocessableEntity && apiErr.code != http.StatusBadRequest) {
+		return false
+	}
+	msg := strings.ToLower(apiErr.message)
+	return strings.Contains(msg, "already") || strings.Contains(msg, "taken") || strings.Contains(msg, "in use") || strings.Contains(msg, "has been used")
+}
+
+// apiErrorMessage extracts a human-readable message from an API error body
+func apiErrorMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"` // GitHub validation details
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Message != "" {
+			msg := payload.Message
+			for _, detail := range payload.Errors {
+				if detail.Message != "" {
+					msg += ": " + detail.Message
+				}
+			}
+			return msg
+		}
+		if payload.Error != "" {
+			return payload.Error
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,67 @@
+package forge
+
+import (
+	"net/http"
+)
+
+// giteaClient talks to Gitea/Forgejo instances such as codeberg.org
+type giteaClient struct {
+	http    *http.Client
+	token   string
+	baseURL string
+}
+
+func (c *giteaClient) newRequest(method, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "token "+c.token)
+	return req, nil
+}
+
+func (c *giteaClient) CreateRepo(opts CreateRepoOptions) (*Repo, error) {
+	path := "/user/repos"
+	if opts.Org != "" {
+		path = "/orgs/" + opts.Org + "/repos"
+	}
+
+	req, err := c.newRequest(http.MethodPost, path)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{
+		"name":        opts.Name,
+		"description": opts.Description,
+		"private":     opts.Private,
+	}
+
+	var resp struct {
+		FullName string `json:"full_name"`
+		SSHURL   string `json:"ssh_url"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := doJSON(c.http, req, body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &Repo{FullName: resp.FullName, SSHURL: resp.SSHURL, WebURL: resp.HTMLURL}, nil
+}
+
+// AddSSHKey adds the key to the account. Gitea has no separate signing keys;
+// signatures are verified against the account's keys.
+func (c *giteaClient) AddSSHKey(opts SSHKeyOptions) error {
+	req, err := c.newRequest(http.MethodPost, "/user/keys")
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"title": opts.Title, "key": opts.Key}
+	if err := doJSON(c.http, req, body, nil); err != nil {
+		if isAlreadyExists(err) {
+			return ErrKeyExists
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package forge
+
+import (
+	"net/http"
+)
+
+type githubClient struct {
+	http  *http.Client
+	token string
+}
+
+func (c *githubClient) newRequest(method, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, "https://api.github.com"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return req, nil
+}
+
+func (c *githubClient) CreateRepo(opts CreateRepoOptions) (*Repo, error) {
+	path := "/user/repos"
+	if opts.Org != "" {
+		path = "/orgs/" + opts.Org + "/repos"
+	}
+
+	req, err := c.newRequest(http.MethodPost, path)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{
+		"name":        opts.Name,
+		"description": opts.Description,
+		"private":     opts.Private,
+	}
+
+	var resp struct {
+		FullName string `json:"full_name"`
+		SSHURL   string `json:"ssh_url"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := doJSON(c.http, req, body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &Repo{FullName: resp.FullName, SSHURL: resp.SSHURL, WebURL: resp.HTMLURL}, nil
+}
+
+// AddSSHKey adds an authentication key and, with opts.Signing, the same key
+// as a signing key. The token needs the admin:public_key and
+// admin:ssh_signing_key scopes (or "Git SSH keys" and "SSH signing keys"
+// write permission for fine-grained tokens).
+func (c *githubClient) AddSSHKey(opts SSHKeyOptions) error {
+	paths := []string{"/user/keys"}
+	if opts.Signing {
+		paths = append(paths, "/user/ssh_signing_keys")
+	}
+
+	existing := 0
+	for _, path := range paths {
+		req, err := c.newRequest(http.MethodPost, path)
+		if err != nil {
+			return err
+		}
+		body := map[string]any{"title": opts.Title, "key": opts.Key}
+		if err := doJSON(c.http, req, body, nil); err != nil {
+			if !isAlreadyExists(err) {
+				return err
+			}
+			existing++
+		}
+	}
+
+	if existing == len(paths) {
+		return ErrKeyExists
+	}
+	return nil
+}
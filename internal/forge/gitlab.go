@@ -0,0 +1,96 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type gitlabClient struct {
+	http    *http.Client
+	token   string
+	baseURL string
+}
+
+func (c *gitlabClient) newRequest(method, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	return req, nil
+}
+
+// namespaceID resolves a group path to its numeric namespace ID
+func (c *gitlabClient) namespaceID(group string) (int, error) {
+	req, err := c.newRequest(http.MethodGet, "/namespaces/"+url.PathEscape(group))
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		ID int `json:"id"`
+	}
+	if err := doJSON(c.http, req, nil, &resp); err != nil {
+		return 0, fmt.Errorf("failed to resolve group %s: %w", group, err)
+	}
+	return resp.ID, nil
+}
+
+func (c *gitlabClient) CreateRepo(opts CreateRepoOptions) (*Repo, error) {
+	visibility := "public"
+	if opts.Private {
+		visibility = "private"
+	}
+
+	body := map[string]any{
+		"name":        opts.Name,
+		"path":        opts.Name,
+		"description": opts.Description,
+		"visibility":  visibility,
+	}
+	if opts.Org != "" {
+		id, err := c.namespaceID(opts.Org)
+		if err != nil {
+			return nil, err
+		}
+		body["namespace_id"] = id
+	}
+
+	req, err := c.newRequest(http.MethodPost, "/projects")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		SSHURLToRepo      string `json:"ssh_url_to_repo"`
+		WebURL            string `json:"web_url"`
+	}
+	if err := doJSON(c.http, req, body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &Repo{FullName: resp.PathWithNamespace, SSHURL: resp.SSHURLToRepo, WebURL: resp.WebURL}, nil
+}
+
+// AddSSHKey adds the key for authentication and, with opts.Signing, signing
+func (c *gitlabClient) AddSSHKey(opts SSHKeyOptions) error {
+	usage := "auth"
+	if opts.Signing {
+		usage = "auth_and_signing"
+	}
+
+	req, err := c.newRequest(http.MethodPost, "/user/keys")
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"title": opts.Title, "key": opts.Key, "usage_type": usage}
+	if err := doJSON(c.http, req, body, nil); err != nil {
+		if isAlreadyExists(err) {
+			return ErrKeyExists
+		}
+		return err
+	}
+	return nil
+}
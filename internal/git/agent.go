@@ -0,0 +1,80 @@
+package git
+
+import (
+	"maps"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Agent values with special meaning; anything else is a socket path
+const (
+	AgentNone      = "none"
+	Agent1Password = "1password"
+	AgentGPG       = "gpg-agent"
+)
+
+// gpgAgentSocket asks gpgconf once for gpg-agent's ssh socket
+var gpgAgentSocket = sync.OnceValue(func() string {
+	output, err := exec.Command("gpgconf", "--list-dirs", "agent-ssh-socket").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+})
+
+// AgentSocket returns the IdentityAgent for the identity's Agent: "none",
+// a socket path, or "" to use SSH_AUTH_SOCK
+func (i *Identity) AgentSocket() string {
+	switch i.Agent {
+	case "":
+		return ""
+	case Agent1Password:
+		if runtime.GOOS == "darwin" {
+			return "~/Library/Group Containers/2BUA8C4S2C.com.1password/t/agent.sock"
+		}
+		return "~/.1password/agent.sock"
+	case AgentGPG:
+		return gpgAgentSocket()
+	default:
+		return i.Agent
+	}
+}
+
+// UsesSSHAdd reports whether sync should add the key to the identity's
+// agent. 1Password manages its own keys, and "none" means no agent.
+func (i *Identity) UsesSSHAdd() bool {
+	return i.Agent != AgentNone && i.Agent != Agent1Password
+}
+
+// sshOptions returns the identity's SSH options including IdentityAgent
+func (i *Identity) sshOptions() map[string]string {
+	socket := i.AgentSocket()
+	if socket == "" {
+		return i.SSHOptions
+	}
+	options := map[string]string{"IdentityAgent": socket}
+	maps.Copy(options, i.SSHOptions)
+	return options
+}
+
+// usesKeychain reports whether the key's passphrase is kept in the macOS
+// keychain, which only the system agent supports
+func (i *Identity) usesKeychain() bool {
+	return runtime.GOOS == "darwin" && i.Agent == ""
+}
+
+// hostOptions returns the options for the identity's Host entries. With the
+// macOS keychain, ssh loads the key from it after a reboot instead of asking
+// for the passphrase again. IgnoreUnknown keeps ssh builds without
+// UseKeychain working.
+func (i *Identity) hostOptions() map[string]string {
+	options := i.sshOptions()
+	if !i.usesKeychain() {
+		return options
+	}
+	merged := map[string]string{"AddKeysToAgent": "yes", "IgnoreUnknown": "UseKeychain", "UseKeychain": "yes"}
+	maps.Copy(merged, options)
+	return merged
+}
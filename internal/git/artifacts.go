@@ -0,0 +1,220 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// RemoveResult lists what RemoveIdentity removed
+type RemoveResult struct {
+	Artifacts
+	// Backup is the archive holding the removed files
+	Backup string
+}
+
+// conventionalArtifacts guesses an identity's artifacts from zzk's naming
+// conventions, for state written before artifacts were recorded
+func conventionalArtifacts(name string) Artifacts {
+	caser := cases.Title(language.English)
+	return Artifacts{
+		Files: []string{
+			fmt.Sprintf("~/.ssh/%s_key", name),
+			fmt.Sprintf("~/.ssh/%s_key.pub", name),
+			fmt.Sprintf("~/%s_key.pub", name),
+			fmt.Sprintf("~/.gitconfig-%s", name),
+		},
+		Folders: []string{"~/" + caser.String(name)},
+	}
+}
+
+// identityArtifacts returns the artifacts recorded for name, falling back to
+// the conventional paths when nothing was recorded
+func identityArtifacts(state *State, name string) Artifacts {
+	if identityState, ok := state.Identities[name]; ok && !identityState.Artifacts.IsZero() {
+		return identityState.Artifacts
+	}
+	return conventionalArtifacts(name)
+}
+
+// recordArtifacts updates what is recorded for an identity after a sync.
+// Files and folders recorded earlier are kept while they still exist.
+func recordArtifacts(identityState *IdentityState, identity Identity, hosts, createdFolders, createdFiles []string) {
+	previous := identityState.Artifacts
+
+	files := []string{identity.SSHKeyPath(), identity.SSHPubKeyPath(), identity.GitConfigPath()}
+	if identity.CommitTemplate != "" {
+		files = append(files, identity.CommitTemplatePath())
+	}
+	files = append(files, createdFiles...)
+	files = append(files, previous.Files...)
+
+	folders := append(slices.Clone(createdFolders), previous.Folders...)
+
+	identityState.Artifacts = Artifacts{
+		Files:       existingPaths(files),
+		Folders:     existingPaths(folders),
+		SSHHosts:    hosts,
+		GitIncludes: identity.IncludeConditions(),
+	}
+}
+
+// existingPaths returns the paths that exist, without duplicates
+func existingPaths(paths []string) []string {
+	var existing []string
+	for _, path := range paths {
+		if slices.Contains(existing, path) {
+			continue
+		}
+		if _, err := os.Stat(ExpandPath(path)); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
+
+// cleanupIdentity removes the identity's files, and its folders if empty
+func cleanupIdentity(artifacts Artifacts) error {
+	var errs []error
+	for _, file := range artifacts.Files {
+		if err := os.Remove(ExpandPath(file)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	for _, folder := range artifacts.Folders {
+		os.Remove(ExpandPath(folder)) // Only succeeds if empty
+	}
+	return errors.Join(errs...)
+}
+
+// backupArtifacts archives the identity's files that exist, returning the
+// backup path or "" if there was nothing to back up
+func backupArtifacts(artifacts Artifacts, reason string) (string, error) {
+	files := existingPaths(artifacts.Files)
+	if len(files) == 0 {
+		return "", nil
+	}
+	expanded := make([]string, len(files))
+	for i, file := range files {
+		expanded[i] = ExpandPath(file)
+	}
+
+	backupPath, err := BackupFiles(expanded, reason)
+	if err != nil {
+		return "", err
+	}
+	home, _ := os.UserHomeDir()
+	if err := RotateBackups(filepath.Join(home, ".config", "zzk", "backups"), 10); err != nil {
+		return backupPath, fmt.Errorf("failed to rotate backups: %w", err)
+	}
+	return backupPath, nil
+}
+
+// IdentityArtifacts returns what 'zzk git rm' would remove for an identity:
+// the recorded files, folders, SSH hosts and includes, or for identities
+// synced before these were recorded, the conventional paths
+func IdentityArtifacts(config *Config, name string) (Artifacts, error) {
+	identity, ok := config.GetIdentity(name)
+	if !ok {
+		return Artifacts{}, fmt.Errorf("identity '%s' not found", name)
+	}
+	state, err := LoadState()
+	if err != nil {
+		return Artifacts{}, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	artifacts := identityArtifacts(state, name)
+	artifacts.Files = existingPaths(artifacts.Files)
+	artifacts.Folders = existingPaths(artifacts.Folders)
+	if len(artifacts.SSHHosts) == 0 {
+		artifacts.SSHHosts = config.SSHHosts()[name]
+	}
+	if len(artifacts.GitIncludes) == 0 {
+		artifacts.GitIncludes = identity.IncludeConditions()
+	}
+	return artifacts, nil
+}
+
+// RemoveIdentity deletes an identity from the config, backs up and removes
+// its artifacts, and rewrites the global configs without it. With keepKey,
+// the SSH key pair in ~/.ssh is left in place.
+func RemoveIdentity(config *Config, name string, keepKey bool) (*RemoveResult, error) {
+	identity, ok := config.GetIdentity(name)
+	if !ok {
+		return nil, fmt.Errorf("identity '%s' not found", name)
+	}
+
+	artifacts, err := IdentityArtifacts(config, name)
+	if err != nil {
+		return nil, err
+	}
+	if keepKey {
+		artifacts.Files = slices.DeleteFunc(artifacts.Files, func(file string) bool {
+			return file == identity.SSHKeyPath() || file == identity.SSHPubKeyPath()
+		})
+	}
+
+	result := &RemoveResult{Artifacts: artifacts}
+	result.Backup, err = backupArtifacts(artifacts, "rm")
+	if err != nil && result.Backup == "" {
+		return nil, fmt.Errorf("failed to back up %s: %w", name, err)
+	}
+
+	delete(config.Identities, name)
+	config.markSharedDomains()
+	if err := SaveConfig(config); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if err := cleanupIdentity(artifacts); err != nil {
+		return nil, fmt.Errorf("failed to remove files: %w", err)
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	delete(state.Identities, name)
+	if err := state.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	if err := UpdateGlobalGitConfig(config); err != nil {
+		return nil, fmt.Errorf("failed to update global git config: %w", err)
+	}
+	if _, err := UpdateSSHConfig(config); err != nil {
+		return nil, fmt.Errorf("failed to update SSH config: %w", err)
+	}
+	if err := UpdateAllowedSigners(config); err != nil {
+		return nil, fmt.Errorf("failed to update allowed signers: %w", err)
+	}
+
+	return result, nil
+}
+
+// RotateKey backs up and removes the identity's SSH key pair and the public
+// key copy in ~, so the next sync generates a new key. Returns the backup path.
+func RotateKey(identity Identity) (string, error) {
+	artifacts := Artifacts{Files: []string{
+		identity.SSHKeyPath(),
+		identity.SSHPubKeyPath(),
+		fmt.Sprintf("~/%s_key.pub", identity.Name),
+	}}
+	if !SSHKeyExists(identity) {
+		return "", fmt.Errorf("%s has no SSH key to rotate", identity.Name)
+	}
+
+	backup, err := backupArtifacts(artifacts, "rotate")
+	if err != nil && backup == "" {
+		return "", fmt.Errorf("failed to back up %s's key: %w", identity.Name, err)
+	}
+	if err := cleanupIdentity(artifacts); err != nil {
+		return backup, fmt.Errorf("failed to remove the old key: %w", err)
+	}
+	return backup, nil
+}
@@ -0,0 +1,138 @@
+package git
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// AuditIssue is a setting in a repository that doesn't match its identity
+type AuditIssue struct {
+	Field   string `json:"field"`
+	Got     string `json:"got"`
+	Want    string `json:"want"`
+	Local   bool   `json:"local,omitempty"` // Overridden in the repo's own config
+	Message string `json:"message"`
+}
+
+// AuditRepo compares a repository's effective config and remotes with the
+// identity whose folder it is in
+func AuditRepo(config *Config, identity Identity, repo string) []AuditIssue {
+	var issues []AuditIssue
+
+	checkConfig := func(key, want string) {
+		got, _ := RunGit(repo, "config", key)
+		if got == want {
+			return
+		}
+		_, err := RunGit(repo, "config", "--local", key)
+		issue := AuditIssue{Field: key, Got: got, Want: want, Local: err == nil}
+		if got == "" {
+			issue.Message = fmt.Sprintf("%s is not set, expected %s", key, want)
+		} else {
+			issue.Message = fmt.Sprintf("%s is %s, expected %s", key, got, want)
+			if key == "user.email" && identity.EnforceNoreply && !IsNoreplyEmail(got) {
+				issue.Message += ", not a noreply address"
+			}
+			if owner := identityWith(config, key, got); owner != "" {
+				issue.Message += fmt.Sprintf(" (belongs to %s)", owner)
+			}
+		}
+		if issue.Local {
+			issue.Message += " [set in .git/config]"
+		}
+		issues = append(issues, issue)
+	}
+
+	checkConfig("user.email", identity.Email)
+	checkConfig("user.name", identity.User)
+	checkConfig("user.signingkey", identity.SSHKeyPath())
+
+	remotes, _ := Remotes(repo)
+	for _, name := range slices.Sorted(maps.Keys(remotes)) {
+		url := remotes[name]
+		host, _, ok := ParseSSHRemote(url)
+		if !ok || host == identity.SSHHost() {
+			continue
+		}
+
+		field := "remote." + name + ".url"
+		for _, other := range config.SortedIdentities() {
+			if other.Name != identity.Name && other.SSHHost() == host && other.SSHHost() != other.Domain {
+				issues = append(issues, AuditIssue{
+					Field: field, Got: url, Want: identity.SSHHost(), Local: true,
+					Message: fmt.Sprintf("remote %s uses %s's host %s", name, other.Name, host),
+				})
+				break
+			}
+		}
+		if host == identity.Domain {
+			issues = append(issues, AuditIssue{
+				Field: field, Got: url, Want: identity.SSHHost(), Local: true,
+				Message: fmt.Sprintf("remote %s uses %s instead of the alias %s", name, host, identity.SSHHost()),
+			})
+		}
+	}
+
+	if identity.EnforceNoreply {
+		issues = append(issues, realEmailCommits(repo)...)
+	}
+
+	return issues
+}
+
+// identityWith returns the identity whose user.email, user.name or signing
+// key is value, if any
+func identityWith(config *Config, key, value string) string {
+	for _, identity := range config.SortedIdentities() {
+		var own string
+		switch strings.TrimPrefix(key, "user.") {
+		case "email":
+			own = identity.Email
+		case "name":
+			own = identity.User
+		case "signingkey":
+			own = identity.SSHKeyPath()
+		}
+		if own == value {
+			return identity.Name
+		}
+	}
+	return ""
+}
+
+// FixIssue repairs one audit issue in repo and describes what it changed.
+// Config values set in the repo are removed so the identity's include
+// applies; if the value still doesn't match, it is set in the repo instead.
+func FixIssue(identity Identity, repo string, issue AuditIssue) (string, error) {
+	if issue.Field == "commits" {
+		return "", fmt.Errorf("amend them after fixing user.email: git rebase -r --exec 'git commit --amend --no-edit --reset-author' <base>")
+	}
+	if name, ok := strings.CutPrefix(issue.Field, "remote."); ok {
+		name = strings.TrimSuffix(name, ".url")
+		_, repoPath, ok := ParseSSHRemote(issue.Got)
+		if !ok {
+			return "", fmt.Errorf("cannot parse remote %s: %s", name, issue.Got)
+		}
+		url := identity.RemoteURL(repoPath)
+		if err := SetRemoteURL(repo, name, url); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("remote %s → %s", name, url), nil
+	}
+
+	if issue.Local {
+		if _, err := RunGit(repo, "config", "--local", "--unset-all", issue.Field); err != nil {
+			return "", err
+		}
+		if got, _ := RunGit(repo, "config", issue.Field); got == issue.Want {
+			return fmt.Sprintf("removed %s override (%s)", issue.Field, issue.Got), nil
+		}
+	}
+
+	if _, err := RunGit(repo, "config", "--local", issue.Field, issue.Want); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("set %s = %s", issue.Field, issue.Want), nil
+}
@@ -7,10 +7,18 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"time"
+
+	"github.com/ppowo/zzk/internal/archive"
+	"github.com/ppowo/zzk/internal/fileutil"
 )
 
+// backupTimeFormat is the timestamp in backup file names
+const backupTimeFormat = "20060102-150405"
+
 // BackupFiles creates a tar.gz archive of the given files
 func BackupFiles(files []string, reason string) (string, error) {
 	if len(files) == 0 {
@@ -27,7 +35,7 @@ func BackupFiles(files []string, reason string) (string, error) {
 		return "", err
 	}
 
-	timestamp := time.Now().Format("20060102-150405")
+	timestamp := time.Now().Format(backupTimeFormat)
 	backupPath := filepath.Join(backupDir, fmt.Sprintf("git-orphans-%s.tar.gz", timestamp))
 
 	// Create the tar.gz file
@@ -73,8 +81,13 @@ func addFileToTar(tarWriter *tar.Writer, filename string) error {
 		return err
 	}
 
-	// Use base name for the header
+	// Store the path relative to home so the file can be restored to where it was
 	header.Name = filepath.Base(filename)
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			header.Name = filepath.ToSlash(rel)
+		}
+	}
 
 	if err := tarWriter.WriteHeader(header); err != nil {
 		return err
@@ -115,3 +128,224 @@ func RotateBackups(dir string, keep int) error {
 
 	return nil
 }
+
+// Backup is an archive written by BackupFiles
+type Backup struct {
+	Path string
+	// Timestamp identifies the backup, e.g. 20250102-150405
+	Timestamp string
+	Created   time.Time
+	Files     []BackupFile
+}
+
+// BackupFile is a file in a backup and where it is restored to
+type BackupFile struct {
+	Name   string
+	Target string
+	Size   int64
+	Mode   os.FileMode
+}
+
+// backupTarget returns the ~/ path an archived file is restored to. Older
+// backups stored only base names, so keys go back to ~/.ssh and gitconfigs
+// to the home directory.
+func backupTarget(name string) string {
+	if strings.Contains(name, "/") || strings.HasPrefix(name, ".gitconfig-") {
+		return "~/" + name
+	}
+	return "~/.ssh/" + name
+}
+
+// ListBackups returns the backups in ~/.config/zzk/backups, newest first
+func ListBackups() ([]Backup, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(homeDir, ".config", "zzk", "backups", "git-orphans-*.tar.gz"))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, path := range paths {
+		backup, err := readBackup(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+		}
+		backups = append(backups, *backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp > backups[j].Timestamp
+	})
+	return backups, nil
+}
+
+// FindBackup returns the backup whose timestamp starts with the given one
+func FindBackup(timestamp string) (*Backup, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Backup
+	for _, backup := range backups {
+		if backup.Timestamp == timestamp {
+			return &backup, nil
+		}
+		if strings.HasPrefix(backup.Timestamp, timestamp) {
+			matches = append(matches, backup)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no backup matches '%s'", timestamp)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("'%s' matches %d backups, use the full timestamp", timestamp, len(matches))
+	}
+}
+
+// readBackup lists the files in a backup archive
+func readBackup(path string) (*Backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "git-orphans-"), ".tar.gz")
+	backup := &Backup{Path: path, Timestamp: timestamp}
+	if created, err := time.ParseInLocation(backupTimeFormat, timestamp, time.Local); err == nil {
+		backup.Created = created
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		backup.Files = append(backup.Files, BackupFile{
+			Name:   header.Name,
+			Target: backupTarget(header.Name),
+			Size:   header.Size,
+			Mode:   header.FileInfo().Mode().Perm(),
+		})
+	}
+	return backup, nil
+}
+
+// matches reports whether a restore selection refers to this file, by
+// archive name, base name or target path
+func (f BackupFile) matches(selection string) bool {
+	return selection == f.Name || selection == filepath.Base(f.Name) || selection == f.Target || ExpandPath(selection) == ExpandPath(f.Target)
+}
+
+// RestoreBackup extracts files from a backup to their original locations.
+// With no selection every file is restored. Existing files are only
+// overwritten with force.
+func RestoreBackup(backup *Backup, selection []string, force bool) ([]BackupFile, error) {
+	for _, selected := range selection {
+		if !slices.ContainsFunc(backup.Files, func(f BackupFile) bool { return f.matches(selected) }) {
+			return nil, fmt.Errorf("%s is not in backup %s", selected, backup.Timestamp)
+		}
+	}
+
+	var wanted []BackupFile
+	for _, file := range backup.Files {
+		if len(selection) == 0 || slices.ContainsFunc(selection, file.matches) {
+			wanted = append(wanted, file)
+		}
+	}
+
+	// Names come from the archive, so a tampered one mustn't write outside home
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	targets := make(map[string]string)
+	for _, file := range wanted {
+		rel := strings.TrimPrefix(file.Target, "~/")
+		if filepath.IsAbs(rel) {
+			return nil, fmt.Errorf("refusing to restore %s: absolute path", file.Name)
+		}
+		target, err := archive.SafeJoin(home, rel)
+		if err != nil {
+			return nil, fmt.Errorf("refusing to restore %s: %w", file.Name, err)
+		}
+		targets[file.Name] = target
+	}
+
+	if !force {
+		for _, file := range wanted {
+			if _, err := os.Stat(targets[file.Name]); err == nil {
+				return nil, fmt.Errorf("%s already exists, use --force to overwrite", file.Target)
+			}
+		}
+	}
+
+	f, err := os.Open(backup.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var restored []BackupFile
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return restored, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		i := slices.IndexFunc(wanted, func(f BackupFile) bool { return f.Name == header.Name })
+		if i < 0 {
+			continue
+		}
+		file := wanted[i]
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return restored, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		target := targets[file.Name]
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return restored, err
+		}
+		if err := fileutil.AtomicWrite(target, data, file.Mode); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", file.Target, err)
+		}
+		restored = append(restored, file)
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,221 @@
+package git
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BundleVersion is the format version written to bundle manifests
+const BundleVersion = 1
+
+// Bundle is a portable copy of the identity configuration: config, public
+// keys and sync state. Private keys are never included.
+type Bundle struct {
+	Manifest   BundleManifest
+	Config     *Config
+	State      *State
+	PublicKeys map[string]string // Identity name → public key line
+}
+
+// BundleManifest describes where and when a bundle was made
+type BundleManifest struct {
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	Hostname string    `json:"hostname"`
+}
+
+// NewBundle collects the current config, state and public keys
+func NewBundle(config *Config) (*Bundle, error) {
+	state, err := LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	hostname, _ := os.Hostname()
+
+	b := &Bundle{
+		Manifest:   BundleManifest{Version: BundleVersion, Created: time.Now(), Hostname: hostname},
+		Config:     config,
+		State:      state,
+		PublicKeys: make(map[string]string),
+	}
+	for name, identity := range config.Identities {
+		if data, err := os.ReadFile(ExpandPath(identity.SSHPubKeyPath())); err == nil {
+			b.PublicKeys[name] = strings.TrimSpace(string(data))
+		}
+	}
+	return b, nil
+}
+
+// Write writes the bundle as a tar.gz archive
+func (b *Bundle) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: b.Manifest.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, append(data, '\n'))
+	}
+
+	if err := addJSON("manifest.json", b.Manifest); err != nil {
+		return err
+	}
+	if err := addJSON("identities.json", b.Config); err != nil {
+		return err
+	}
+	if err := addJSON("state.json", b.State); err != nil {
+		return err
+	}
+	for name, key := range b.PublicKeys {
+		if err := add("keys/"+name+".pub", []byte(key+"\n")); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadBundle reads a bundle written by Bundle.Write
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a zzk identity bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	b := &Bundle{PublicKeys: make(map[string]string)}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		// Bundles hold a few small JSON files and keys
+		data, err := io.ReadAll(io.LimitReader(tr, 1<<20))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		switch name := hdr.Name; {
+		case name == "manifest.json":
+			err = json.Unmarshal(data, &b.Manifest)
+		case name == "identities.json":
+			err = json.Unmarshal(data, &b.Config)
+		case name == "state.json":
+			err = json.Unmarshal(data, &b.State)
+		case strings.HasPrefix(name, "keys/") && strings.HasSuffix(name, ".pub"):
+			b.PublicKeys[strings.TrimSuffix(path.Base(name), ".pub")] = strings.TrimSpace(string(data))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in bundle: %w", hdr.Name, err)
+		}
+	}
+
+	if b.Manifest.Version == 0 || b.Config == nil {
+		return nil, fmt.Errorf("not a zzk identity bundle")
+	}
+	if b.Manifest.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than supported (%d), update zzk", b.Manifest.Version, BundleVersion)
+	}
+	for name, identity := range b.Config.Identities {
+		identity.Name = name
+		if err := identity.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid identity %s in bundle: %w", name, err)
+		}
+		b.Config.Identities[name] = identity
+	}
+	return b, nil
+}
+
+// signersDir holds allowed_signers entries for keys of other machines, so
+// commits signed there still verify here
+func signersDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "zzk", "git-signers"), nil
+}
+
+// SaveBundleSigners records the bundle's public keys as trusted signers for
+// the identities' emails. They are added to ~/.ssh/allowed_signers on sync.
+func SaveBundleSigners(b *Bundle) error {
+	dir, err := signersDir()
+	if err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+	for _, name := range slices.Sorted(maps.Keys(b.PublicKeys)) {
+		key := b.PublicKeys[name]
+		identity, ok := b.Config.Identities[name]
+		parts := strings.Fields(key)
+		if !ok || len(parts) < 2 {
+			continue
+		}
+		fmt.Fprintln(&content, identity.signerEntry(parts[0], parts[1]))
+	}
+	if content.Len() == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	host := b.Manifest.Hostname
+	if host == "" {
+		host = "unknown"
+	}
+	return os.WriteFile(filepath.Join(dir, host+".allowed_signers"), content.Bytes(), 0644)
+}
+
+// importedSigners returns the allowed_signers lines saved from bundles,
+// tagged with the machine they came from
+func importedSigners() []string {
+	dir, err := signersDir()
+	if err != nil {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.allowed_signers"))
+
+	var lines []string
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			continue
+		}
+		host := strings.TrimSuffix(filepath.Base(match), ".allowed_signers")
+		for line := range strings.SplitSeq(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, fmt.Sprintf("%s [zzk-import:%s]", line, host))
+			}
+		}
+	}
+	return lines
+}
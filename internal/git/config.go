@@ -1,29 +1,132 @@
 package git
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+	"gopkg.in/yaml.v3"
 )
 
-// Config represents the ~/.git-identities.json configuration file
+// Config represents the git identities configuration file
 type Config struct {
-	Identities map[string]Identity `json:"identities"`
+	// Version is the config layout, upgraded to ConfigVersion on load
+	Version    int                 `json:"version" yaml:"version"`
+	Identities map[string]Identity `json:"identities" yaml:"identities"`
+	// Overrides change identity fields on one machine, keyed by Hostname
+	// and identity name
+	Overrides map[string]map[string]IdentityOverride `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+
+	overlays     map[string]overlay // Identities changed by Overrides
+	migratedFrom int                // Version in the file before migrating
+}
+
+// configNames are the accepted config file names in ~/.config/zzk; JSON is
+// the default. The legacy location is the same names as dotfiles in ~.
+var configNames = []string{"git-identities.json", "git-identities.yaml", "git-identities.yml"}
+
+// ConfigEnv names the environment variable that overrides the config path
+const ConfigEnv = "ZZK_GIT_CONFIG"
+
+// configOverride is the path set with --config, taking precedence over ConfigEnv
+var configOverride string
+
+// SetConfigPath makes ConfigPath return path instead of searching for the
+// config file
+func SetConfigPath(path string) {
+	configOverride = path
 }
 
-// ConfigPath returns the path to the config file
+// ConfigPath returns the path to the config file: the --config or
+// ZZK_GIT_CONFIG path if set, else the existing one of configNames in
+// ~/.config/zzk or, for older setups, in ~ as a dotfile. With no config yet
+// it is ~/.config/zzk/git-identities.json.
 func ConfigPath() string {
+	if configOverride != "" {
+		return ExpandPath(configOverride)
+	}
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return ExpandPath(path)
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return "~/.git-identities.json"
+		return "~/.config/zzk/" + configNames[0]
+	}
+	if found := existingConfigs(home); len(found) > 0 {
+		return found[0]
+	}
+	return filepath.Join(home, ".config", "zzk", configNames[0])
+}
+
+// existingConfigs returns the config files that exist, those in
+// ~/.config/zzk first
+func existingConfigs(home string) []string {
+	var found []string
+	for _, dir := range []string{filepath.Join(home, ".config", "zzk"), home} {
+		for _, name := range configNames {
+			if dir == home {
+				name = "." + name
+			}
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				found = append(found, path)
+			}
+		}
 	}
-	return filepath.Join(home, ".git-identities.json")
+	return found
 }
 
-// LoadConfig loads the configuration from ~/.git-identities.json
+// MigrateLegacyConfig moves ~/.git-identities.json (or .yaml) to
+// ~/.config/zzk, returning the old path if it was moved. Nothing is moved
+// when the path is overridden or a config already exists in ~/.config/zzk.
+func MigrateLegacyConfig() (string, error) {
+	if configOverride != "" || os.Getenv(ConfigEnv) != "" {
+		return "", nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil
+	}
+
+	found := existingConfigs(home)
+	if len(found) != 1 || filepath.Dir(found[0]) != home {
+		return "", nil
+	}
+
+	legacy := found[0]
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(legacy, filepath.Join(dir, strings.TrimPrefix(filepath.Base(legacy), "."))); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", legacy, err)
+	}
+	return legacy, nil
+}
+
+// isYAMLConfig reports whether the config file at path is YAML
+func isYAMLConfig(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// LoadConfig loads the configuration from ConfigPath
 func LoadConfig() (*Config, error) {
 	path := ConfigPath()
+	if err := checkSingleConfig(); err != nil {
+		return nil, err
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -32,34 +135,115 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	migrated, version, err := migrateConfigData(data, isYAMLConfig(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", describeParseError(data, err))
+	}
+
+	config := Config{migratedFrom: version}
+	if isYAMLConfig(path) {
+		err = yaml.Unmarshal(migrated, &config)
+	} else {
+		err = json.Unmarshal(migrated, &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", describeParseError(migrated, err))
 	}
 
 	if config.Identities == nil {
 		return nil, fmt.Errorf("no identities defined in config")
 	}
 
+	lines := configLines(data, isYAMLConfig(path))
+	if err := config.applyOverrides(Hostname()); err != nil {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			return nil, fmt.Errorf("%s: %s at line %d", fieldErr.Field, fieldErr.Message, locateField(lines, fieldErr.Field))
+		}
+		return nil, err
+	}
+
+	// Report every invalid identity, in file order, with the line to fix
+	type invalid struct {
+		line int
+		err  error
+	}
+	var problems []invalid
 	for name, identity := range config.Identities {
 		identity.Name = name
 		if err := identity.Validate(); err != nil {
-			return nil, fmt.Errorf("invalid identity %s: %w", name, err)
+			field := "identities." + name
+			var fieldErr *FieldError
+			if errors.As(err, &fieldErr) {
+				// Point at the override if it set the invalid field
+				if host, keys := config.Overridden(name); slices.Contains(keys, topField(fieldErr.Field)) {
+					field = fmt.Sprintf("overrides.%s.%s", host, name)
+				}
+				field += "." + fieldErr.Field
+				err = errors.New(fieldErr.Message)
+			}
+			line := locateField(lines, field)
+			problems = append(problems, invalid{line, fmt.Errorf("%s: %v at line %d", field, err, line)})
 		}
 		config.Identities[name] = identity
 	}
+	if len(problems) > 0 {
+		slices.SortFunc(problems, func(a, b invalid) int { return a.line - b.line })
+		errs := make([]error, len(problems))
+		for i, problem := range problems {
+			errs[i] = problem.err
+		}
+		return nil, errors.Join(errs...)
+	}
+	config.markSharedDomains()
 
 	return &config, nil
 }
 
-// SaveConfig saves the configuration to ~/.git-identities.json
+// checkSingleConfig fails if more than one config file exists, since only
+// one of them would be used
+func checkSingleConfig() error {
+	if configOverride != "" || os.Getenv(ConfigEnv) != "" {
+		return nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	found := existingConfigs(home)
+	if len(found) > 1 {
+		for i, path := range found {
+			found[i] = "~" + strings.TrimPrefix(path, home)
+		}
+		return fmt.Errorf("found %s; keep only one of them", strings.Join(found, " and "))
+	}
+	return nil
+}
+
+// SaveConfig saves the configuration to its file. YAML files keep their
+// comments and key order.
 func SaveConfig(config *Config) error {
 	path := ConfigPath()
-	data, err := json.MarshalIndent(config, "", "  ")
+
+	// Identities keep the values overridden for this machine out of the file
+	saved := *config
+	saved.Version = ConfigVersion
+	saved.Identities = config.withoutOverrides()
+
+	var data []byte
+	var err error
+	if isYAMLConfig(path) {
+		data, err = marshalYAMLConfig(&saved, path)
+	} else {
+		data, err = json.MarshalIndent(&saved, "", "  ")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
 	if err := os.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
@@ -67,11 +251,94 @@ func SaveConfig(config *Config) error {
 	return nil
 }
 
+// marshalYAMLConfig encodes config as YAML, carrying over the comments,
+// key order and flow style of the existing file at path
+func marshalYAMLConfig(config *Config, path string) ([]byte, error) {
+	var value yaml.Node
+	if err := value.Encode(config); err != nil {
+		return nil, err
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&value}}
+
+	if data, err := os.ReadFile(path); err == nil {
+		var existing yaml.Node
+		if yaml.Unmarshal(data, &existing) == nil && len(existing.Content) > 0 {
+			doc.HeadComment = existing.HeadComment
+			doc.FootComment = existing.FootComment
+			mergeYAMLNode(existing.Content[0], &value)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeYAMLNode copies comments and style from old onto the matching parts
+// of updated, and orders mapping keys as they were in old
+func mergeYAMLNode(old, updated *yaml.Node) {
+	updated.HeadComment = old.HeadComment
+	updated.LineComment = old.LineComment
+	updated.FootComment = old.FootComment
+	if old.Kind != updated.Kind {
+		return
+	}
+	updated.Style = old.Style
+
+	switch updated.Kind {
+	case yaml.MappingNode:
+		oldPairs := make(map[string][2]*yaml.Node)
+		var order []string
+		for i := 0; i+1 < len(old.Content); i += 2 {
+			oldPairs[old.Content[i].Value] = [2]*yaml.Node{old.Content[i], old.Content[i+1]}
+			order = append(order, old.Content[i].Value)
+		}
+
+		newPairs := make(map[string][2]*yaml.Node)
+		for i := 0; i+1 < len(updated.Content); i += 2 {
+			key, value := updated.Content[i], updated.Content[i+1]
+			if pair, ok := oldPairs[key.Value]; ok {
+				mergeYAMLNode(pair[0], key)
+				mergeYAMLNode(pair[1], value)
+			} else {
+				order = append(order, key.Value)
+			}
+			newPairs[key.Value] = [2]*yaml.Node{key, value}
+		}
+
+		content := make([]*yaml.Node, 0, len(updated.Content))
+		for _, key := range order {
+			if pair, ok := newPairs[key]; ok {
+				content = append(content, pair[0], pair[1])
+				delete(newPairs, key)
+			}
+		}
+		updated.Content = content
+	case yaml.SequenceNode:
+		for _, item := range updated.Content {
+			for _, oldItem := range old.Content {
+				if oldItem.Kind == yaml.ScalarNode && oldItem.Value == item.Value {
+					mergeYAMLNode(oldItem, item)
+					break
+				}
+			}
+		}
+	}
+}
+
 // CreateExampleConfig creates an example configuration file
 func CreateExampleConfig() error {
 	path := ConfigPath()
 
 	exampleConfig := `{
+  "version": 1,
   "identities": {
     "github-work": {
       "user": "Your GitHub Work Username",
@@ -109,6 +376,9 @@ func CreateExampleConfig() error {
 }
 `
 
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
 	if err := os.WriteFile(path, []byte(exampleConfig), 0644); err != nil {
 		return fmt.Errorf("failed to create example config: %w", err)
 	}
@@ -122,6 +392,69 @@ func (c *Config) HasIdentity(name string) bool {
 	return ok
 }
 
+// DiffConfigs returns the names of identities added, changed and removed
+// between two configs, sorted
+func DiffConfigs(old, new *Config) (added, updated, removed []string) {
+	for _, name := range slices.Sorted(maps.Keys(new.Identities)) {
+		previous, ok := old.Identities[name]
+		switch {
+		case !ok:
+			added = append(added, name)
+		case !reflect.DeepEqual(previous, new.Identities[name]):
+			updated = append(updated, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(old.Identities)) {
+		if !new.HasIdentity(name) {
+			removed = append(removed, name)
+		}
+	}
+	return added, updated, removed
+}
+
+// markSharedDomains flags identities whose domain is used by another
+// identity, so SSHHost returns a per-identity alias for them
+func (c *Config) markSharedDomains() {
+	counts := make(map[string]int)
+	for _, identity := range c.Identities {
+		counts[identity.Domain]++
+	}
+	for name, identity := range c.Identities {
+		identity.sharedDomain = counts[identity.Domain] > 1
+		c.Identities[name] = identity
+	}
+}
+
+// SSHHosts returns the Host entries written to ~/.ssh/config for each
+// identity. The plain domain goes to the first identity (by name); identities
+// sharing a domain also get their own alias.
+func (c *Config) SSHHosts() map[string][]string {
+	hosts := make(map[string][]string)
+	domains := make(map[string]bool)
+	for _, identity := range c.SortedIdentities() {
+		if !domains[identity.Domain] {
+			hosts[identity.Name] = append(hosts[identity.Name], identity.Domain)
+			domains[identity.Domain] = true
+		}
+		if identity.SSHHost() != identity.Domain {
+			hosts[identity.Name] = append(hosts[identity.Name], identity.SSHHost())
+		}
+	}
+	return hosts
+}
+
+// SortedIdentities returns the identities ordered by name
+func (c *Config) SortedIdentities() []Identity {
+	identities := make([]Identity, 0, len(c.Identities))
+	for _, identity := range c.Identities {
+		identities = append(identities, identity)
+	}
+	sort.Slice(identities, func(a, b int) bool {
+		return identities[a].Name < identities[b].Name
+	})
+	return identities
+}
+
 // GetIdentity returns an identity by name
 func (c *Config) GetIdentity(name string) (Identity, bool) {
 	identity, ok := c.Identities[name]
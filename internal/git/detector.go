@@ -7,7 +7,9 @@ import (
 	"strings"
 )
 
-// DetectIdentity detects which identity applies to the given directory
+// DetectIdentity detects which identity applies to the given directory: the
+// one switched to in its repository, else one whose remotes patterns match a
+// remote of the repository, else the one whose folder contains it
 func DetectIdentity(config *Config, dir string) (*Identity, error) {
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
@@ -16,6 +18,24 @@ func DetectIdentity(config *Config, dir string) (*Identity, error) {
 
 	absDir = filepath.Clean(absDir)
 
+	// An identity applied with 'zzk git switch' wins over folder patterns
+	if name := SwitchedIdentity(absDir); name != "" {
+		if identity, ok := config.GetIdentity(name); ok {
+			return &identity, nil
+		}
+	}
+
+	// Remote patterns are included after folders, so they win in git too
+	if remotes, err := Remotes(absDir); err == nil && len(remotes) > 0 {
+		for _, identity := range config.SortedIdentities() {
+			for _, url := range remotes {
+				if identity.MatchesRemote(url) {
+					return &identity, nil
+				}
+			}
+		}
+	}
+
 	for _, identity := range config.Identities {
 		for _, folder := range identity.Folders {
 			expandedFolder := ExpandPath(folder)
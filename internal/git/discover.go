@@ -0,0 +1,135 @@
+package git
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// skipDirs are directories never descended into during repository discovery
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// FindRepos returns git repositories under root, searching at most maxDepth levels deep.
+// Repositories are not searched for nested repositories.
+func FindRepos(root string, maxDepth int) []string {
+	var repos []string
+	findRepos(root, 0, maxDepth, &repos)
+	return repos
+}
+
+func findRepos(dir string, depth, maxDepth int, repos *[]string) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		*repos = append(*repos, dir)
+		return
+	}
+
+	if depth >= maxDepth {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || skipDirs[name] {
+			continue
+		}
+		findRepos(filepath.Join(dir, name), depth+1, maxDepth, repos)
+	}
+}
+
+// IdentityRepos returns the repositories found in all folders of an identity
+func IdentityRepos(identity Identity, maxDepth int) []string {
+	var repos []string
+	for _, folder := range identity.Folders {
+		repos = append(repos, FindRepos(ExpandPath(folder), maxDepth)...)
+	}
+	return repos
+}
+
+// RepoStats summarizes the health of one repository
+type RepoStats struct {
+	Path string
+	// Size is the disk usage of the work tree including .git, in bytes
+	Size int64
+	// Dirty means the work tree has uncommitted or untracked changes
+	Dirty bool
+	// Ahead and Behind count commits relative to the upstream branch, as of
+	// the last fetch
+	Ahead, Behind int
+	// Upstream is false when the current branch doesn't track one
+	Upstream bool
+	Err      error
+}
+
+// CollectRepoStats gathers the stats of repos, at most parallel at a time. The
+// results are in the same order as repos.
+func CollectRepoStats(repos []string, parallel int) []RepoStats {
+	if parallel <= 0 {
+		parallel = 8
+	}
+
+	stats := make([]RepoStats, len(repos))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			stats[i] = repoStats(repo)
+		}()
+	}
+	wg.Wait()
+	return stats
+}
+
+func repoStats(repo string) RepoStats {
+	stats := RepoStats{Path: repo, Size: diskUsage(repo)}
+
+	output, err := RunGit(repo, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		stats.Err = err
+		return stats
+	}
+	for line := range strings.SplitSeq(output, "\n") {
+		if ab, ok := strings.CutPrefix(line, "# branch.ab "); ok {
+			stats.Upstream = true
+			fmt.Sscanf(ab, "+%d -%d", &stats.Ahead, &stats.Behind)
+		} else if line != "" && !strings.HasPrefix(line, "#") {
+			stats.Dirty = true
+		}
+	}
+	return stats
+}
+
+// diskUsage returns the total size of the regular files under dir
+func diskUsage(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
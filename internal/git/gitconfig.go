@@ -2,25 +2,51 @@ package git
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/ppowo/zzk/internal/fileutil"
 )
 
 func CreateIdentityGitConfig(identity Identity) error {
 	path := ExpandPath(identity.GitConfigPath())
+	templatePath := ExpandPath(identity.CommitTemplatePath())
 
 	content := fmt.Sprintf(`# zzk-managed: %s
-# Generated by zzk - Edit ~/.git-identities.json and run 'zzk git sync'
+# Generated by zzk - Edit ~/.config/zzk/git-identities.json and run 'zzk git sync'
 [user]
   name = %s
   email = %s
   signingkey = %s
 
 [core]
-  sshCommand = "ssh -i %s"
-`, identity.Name, identity.User, identity.Email, identity.SSHKeyPath(), identity.SSHKeyPath())
+  sshCommand = %s
+`, identity.Name, identity.User, identity.Email, identity.SSHKeyPath(), gitConfigQuote(identity.SSHCommand()))
+
+	if identity.CommitTemplate != "" {
+		template := identity.CommitTemplate
+		if !strings.HasSuffix(template, "\n") {
+			template += "\n"
+		}
+		if err := fileutil.AtomicWrite(templatePath, []byte(template), 0644); err != nil {
+			return fmt.Errorf("failed to write commit template: %w", err)
+		}
+		content += fmt.Sprintf("\n[commit]\n  template = %s\n", identity.CommitTemplatePath())
+	} else if previous, err := os.ReadFile(path); err == nil && strings.Contains(string(previous), "template = "+identity.CommitTemplatePath()) {
+		// The template was removed from the config since the last sync
+		os.Remove(templatePath)
+	}
+
+	if len(identity.Aliases) > 0 {
+		content += "\n[alias]\n"
+		for _, alias := range slices.Sorted(maps.Keys(identity.Aliases)) {
+			content += fmt.Sprintf("  %s = %s\n", alias, gitConfigValue(identity.Aliases[alias]))
+		}
+	}
 
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write git config: %w", err)
@@ -29,6 +55,22 @@ func CreateIdentityGitConfig(identity Identity) error {
 	return nil
 }
 
+// gitConfigValue quotes a value for a gitconfig file when it has characters
+// git would otherwise treat as comments, escapes or trimmed whitespace
+func gitConfigValue(value string) string {
+	if !strings.ContainsAny(value, "\"\\;#") && strings.TrimSpace(value) == value {
+		return value
+	}
+	return gitConfigQuote(value)
+}
+
+// gitConfigQuote returns value as a quoted gitconfig string
+func gitConfigQuote(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	return "\"" + value + "\""
+}
+
 func IsZZKManagedGitConfig(configPath string) (bool, string) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -86,6 +128,23 @@ func FindZZKManagedGitConfigs() (map[string]string, error) {
 	return managedConfigs, nil
 }
 
+// GlobalGitConfigPath returns the file holding zzk's global git settings,
+// which ~/.gitconfig includes from a marked block
+func GlobalGitConfigPath() (string, error) {
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitconfig"), nil
+}
+
+// gitBlockRegex matches the managed block in ~/.gitconfig
+var gitBlockRegex = regexp.MustCompile(`(?s)# BEGIN zzk managed block[^\n]*\n.*?# END zzk managed block(?:\n|$)`)
+
+// UpdateGlobalGitConfig writes signing defaults, URL rewrites and the
+// per-folder includes to GlobalGitConfigPath, and makes sure ~/.gitconfig
+// includes it. Only the marked block in ~/.gitconfig is touched, so aliases,
+// tools and credential settings are left as they are.
 func UpdateGlobalGitConfig(config *Config) error {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -93,56 +152,99 @@ func UpdateGlobalGitConfig(config *Config) error {
 	}
 
 	gitConfigPath := filepath.Join(home, ".gitconfig")
+	// Write through symlinks, e.g. into a dotfiles repo
+	if resolved, err := filepath.EvalSymlinks(gitConfigPath); err == nil {
+		gitConfigPath = resolved
+	}
 
-	var existingContent string
+	includePath, err := GlobalGitConfigPath()
+	if err != nil {
+		return err
+	}
+
+	var original string
 	if data, err := os.ReadFile(gitConfigPath); err == nil {
-		existingContent = string(data)
+		original = string(data)
 	}
 
-	existingContent = removeZZKSections(existingContent)
+	// Sections written into ~/.gitconfig by older versions
+	existingContent := removeLegacyEntries(removeZZKSections(original), config)
+	if existingContent != original {
+		existingContent = regexp.MustCompile(`\n{3,}`).ReplaceAllString(existingContent, "\n\n")
+	}
 
-	existingContent = removeLegacyEntries(existingContent, config)
+	before, after := existingContent, ""
+	if loc := gitBlockRegex.FindStringIndex(existingContent); loc != nil {
+		before, after = existingContent[:loc[0]], existingContent[loc[1]:]
+	}
+	before = strings.TrimRight(before, "\n")
+	after = strings.TrimLeft(after, "\n")
+	userContent := before + "\n" + after
 
 	var zzkContent strings.Builder
+	zzkContent.WriteString("# Generated by zzk - Edit ~/.config/zzk/git-identities.json and run 'zzk git sync'\n\n")
 
-	zzkContent.WriteString("\n# zzk:begin:globals\n")
-	if !strings.Contains(existingContent, "[gpg]") {
+	// Signing defaults, unless set by hand
+	if !strings.Contains(userContent, "[gpg]") {
 		zzkContent.WriteString("[gpg]\n  format = ssh\n\n")
 	}
-	if !strings.Contains(existingContent, "[gpg \"ssh\"]") {
+	if !strings.Contains(userContent, "[gpg \"ssh\"]") {
 		zzkContent.WriteString("[gpg \"ssh\"]\n  allowedSignersFile = ~/.ssh/allowed_signers\n\n")
 	}
-	if !strings.Contains(existingContent, "[commit]") {
-		zzkContent.WriteString("[commit]\n  gpgsign = true\n")
+	if !strings.Contains(userContent, "[commit]") {
+		zzkContent.WriteString("[commit]\n  gpgsign = true\n\n")
 	}
-	zzkContent.WriteString("# zzk:end:globals\n\n")
 
-	zzkContent.WriteString("# zzk:begin:url-rewrites\n")
 	domains := make(map[string]bool)
-	for _, identity := range config.Identities {
+	for _, identity := range config.SortedIdentities() {
 		if !domains[identity.Domain] {
 			zzkContent.WriteString(fmt.Sprintf("[url \"ssh://git@%s/\"]\n", identity.Domain))
 			zzkContent.WriteString(fmt.Sprintf("  insteadOf = https://%s/\n\n", identity.Domain))
 			domains[identity.Domain] = true
 		}
 	}
-	zzkContent.WriteString("# zzk:end:url-rewrites\n\n")
 
-	zzkContent.WriteString("# zzk:begin:includes\n")
-	for _, identity := range config.Identities {
-		for _, folder := range identity.Folders {
-			if !strings.HasSuffix(folder, "/") {
-				folder = folder + "/"
+	// Folder includes first: later includes win, and a repo's remote is a
+	// more specific match than the folder it is in
+	for _, remote := range []bool{false, true} {
+		for _, identity := range config.SortedIdentities() {
+			for _, condition := range identity.IncludeConditions() {
+				if strings.HasPrefix(condition, "hasconfig:") != remote {
+					continue
+				}
+				zzkContent.WriteString(fmt.Sprintf("[includeIf \"%s\"]\n", condition))
+				zzkContent.WriteString(fmt.Sprintf("  path = %s\n\n", identity.GitConfigPath()))
 			}
-			zzkContent.WriteString(fmt.Sprintf("[includeIf \"gitdir:%s\"]\n", folder))
-			zzkContent.WriteString(fmt.Sprintf("  path = %s\n\n", identity.GitConfigPath()))
 		}
 	}
-	zzkContent.WriteString("# zzk:end:includes\n")
 
-	finalContent := strings.TrimSpace(existingContent) + "\n" + zzkContent.String()
+	if err := fileutil.AtomicWrite(includePath, []byte(zzkContent.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", includePath, err)
+	}
+
+	// The block goes last so the per-folder identities override a global [user]
+	includeRef := includePath
+	if rel, err := filepath.Rel(home, includePath); err == nil && !strings.HasPrefix(rel, "..") {
+		includeRef = "~/" + filepath.ToSlash(rel)
+	}
+	block := fmt.Sprintf("%s\n[include]\n  path = %s\n%s\n", managedBlockBegin, includeRef, managedBlockEnd)
+
+	var finalContent strings.Builder
+	if before != "" {
+		finalContent.WriteString(before + "\n\n")
+	}
+	finalContent.WriteString(block)
+	if after != "" {
+		finalContent.WriteString("\n" + after)
+		if !strings.HasSuffix(after, "\n") {
+			finalContent.WriteString("\n")
+		}
+	}
 
-	if err := os.WriteFile(gitConfigPath, []byte(finalContent), 0644); err != nil {
+	if finalContent.String() == original {
+		return nil
+	}
+	if err := fileutil.AtomicWrite(gitConfigPath, []byte(finalContent.String()), 0644); err != nil {
 		return fmt.Errorf("failed to write global git config: %w", err)
 	}
 
@@ -157,119 +259,131 @@ func removeZZKSections(content string) string {
 	return strings.TrimSpace(content)
 }
 
+// removeLegacyEntries removes url rewrites and includeIfs that older
+// versions wrote into ~/.gitconfig without markers. Only entries exactly as
+// zzk wrote them are removed.
 func removeLegacyEntries(content string, config *Config) string {
-	lines := strings.Split(content, "\n")
-	var result []string
-
-	managedDomains := make(map[string]bool)
-	managedFolders := make(map[string]bool)
+	legacy := make(map[string]bool)
 	for _, identity := range config.Identities {
-		managedDomains[identity.Domain] = true
+		legacy[fmt.Sprintf("[url \"ssh://git@%s/\"]\ninsteadOf = https://%s/", identity.Domain, identity.Domain)] = true
 		for _, folder := range identity.Folders {
-			normalizedFolder := folder
-			if !strings.HasSuffix(normalizedFolder, "/") {
-				normalizedFolder += "/"
+			if !strings.HasSuffix(folder, "/") {
+				folder += "/"
 			}
-			managedFolders[normalizedFolder] = true
+			legacy[fmt.Sprintf("[includeIf \"gitdir:%s\"]\npath = %s", folder, identity.GitConfigPath())] = true
 		}
 	}
 
-	skip := false
-	skipLines := 0
-
-	for i := range lines {
-		line := lines[i]
-		trimmed := strings.TrimSpace(line)
-
-		if strings.HasPrefix(trimmed, "[url \"ssh://git@") {
-			for domain := range managedDomains {
-				if strings.Contains(trimmed, domain) {
-					skip = true
-					skipLines = 2
-					break
-				}
-			}
-		}
-
-		if strings.HasPrefix(trimmed, "[includeIf \"gitdir:") {
-			for folder := range managedFolders {
-				if strings.Contains(trimmed, folder) {
-					skip = true
-					skipLines = 2
-					break
-				}
-			}
-		}
-
-		if skip {
-			skipLines--
-			if skipLines <= 0 {
-				skip = false
-			}
+	lines := strings.Split(content, "\n")
+	var result []string
+	for i := 0; i < len(lines); i++ {
+		if i+1 < len(lines) && legacy[strings.TrimSpace(lines[i])+"\n"+strings.TrimSpace(lines[i+1])] {
+			i++
 			continue
 		}
-
-		result = append(result, line)
+		result = append(result, lines[i])
 	}
 
 	return strings.Join(result, "\n")
 }
 
-// removeLegacySSHHosts removes old Host entries for domains we manage
-func removeLegacySSHHosts(content string, config *Config) string {
-	lines := strings.Split(content, "\n")
-	var result []string
+// Markers around the part of ~/.ssh/config and ~/.gitconfig zzk owns.
+// Everything outside them is left untouched on sync.
+const (
+	managedBlockBegin = "# BEGIN zzk managed block - edit ~/.config/zzk/git-identities.json and run 'zzk git sync'"
+	managedBlockEnd   = "# END zzk managed block"
+)
+
+// sshBlockRegex matches the managed block, including the older
+// "# zzk:begin"/"# zzk:end" markers
+var sshBlockRegex = regexp.MustCompile(`(?s)(?:# BEGIN zzk managed block[^\n]*|# zzk:begin)\n.*?(?:# END zzk managed block|# zzk:end)(?:\n|$)`)
+
+// splitSSHHostBlocks splits content into chunks that each start at a Host or
+// Match line (the first chunk holds anything before the first one)
+func splitSSHHostBlocks(content string) []string {
+	var blocks []string
+	var current strings.Builder
+	for line := range strings.SplitSeq(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (strings.EqualFold(fields[0], "Host") || strings.EqualFold(fields[0], "Match")) {
+			blocks = append(blocks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	return append(blocks, current.String())
+}
 
-	managedDomains := make(map[string]bool)
+// removeLegacySSHHosts removes the Host entries zzk wrote before it used
+// markers. Only blocks exactly as zzk generated them are removed; hand-written
+// ones stay, even if they use a zzk key.
+func removeLegacySSHHosts(content string, config *Config) string {
+	legacy := make(map[string]bool)
 	for _, identity := range config.Identities {
-		managedDomains[identity.Domain] = true
+		legacy[fmt.Sprintf("Host %s\nHostName %s\nUser git\nIdentityFile %s\nIdentitiesOnly yes",
+			identity.Domain, identity.Domain, identity.SSHKeyPath())] = true
 	}
 
-	skip := false
-	skipUntilBlank := false
+	var result strings.Builder
+	for _, block := range splitSSHHostBlocks(content) {
+		var lines []string
+		for line := range strings.SplitSeq(block, "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				lines = append(lines, strings.Join(fields, " "))
+			}
+		}
+		if !legacy[strings.Join(lines, "\n")] {
+			result.WriteString(block)
+		}
+	}
+	return strings.TrimSuffix(result.String(), "\n")
+}
 
-	for i := 0; i < len(lines); i++ {
-		line := lines[i]
-		trimmed := strings.TrimSpace(line)
+// sshHostOverrides returns managed hosts that a hand-written Host line before
+// the managed block also matches exactly; ssh uses the first value it finds,
+// so those settings win over zzk's
+func sshHostOverrides(before string, config *Config) []string {
+	managed := make(map[string]bool)
+	for _, identity := range config.Identities {
+		managed[identity.Domain] = true
+		managed[identity.SSHHost()] = true
+	}
 
-		if skipUntilBlank && trimmed == "" {
-			skipUntilBlank = false
-			skip = false
+	var overrides []string
+	for line := range strings.SplitSeq(before, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "Host") {
 			continue
 		}
-
-		if strings.HasPrefix(trimmed, "Host ") && !skip {
-			for domain := range managedDomains {
-				if strings.Contains(trimmed, domain) {
-					skip = true
-					skipUntilBlank = true
-					break
-				}
+		for _, host := range fields[1:] {
+			if managed[host] && !slices.Contains(overrides, host) {
+				overrides = append(overrides, host)
 			}
 		}
-
-		if skip {
-			continue
-		}
-
-		result = append(result, line)
 	}
-
-	return strings.Join(result, "\n")
+	return overrides
 }
 
-// UpdateSSHConfig updates ~/.ssh/config with host entries
-func UpdateSSHConfig(config *Config) error {
+// UpdateSSHConfig rewrites the zzk managed block in ~/.ssh/config, keeping
+// its position, and returns managed hosts that hand-written entries above
+// the block override
+func UpdateSSHConfig(config *Config) ([]string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
 	sshDir := filepath.Join(home, ".ssh")
 	sshConfigPath := filepath.Join(sshDir, "config")
 
 	if err := os.MkdirAll(sshDir, 0700); err != nil {
-		return fmt.Errorf("failed to create .ssh directory: %w", err)
+		return nil, fmt.Errorf("failed to create .ssh directory: %w", err)
+	}
+
+	// Write through symlinks, e.g. into a dotfiles repo
+	if resolved, err := filepath.EvalSymlinks(sshConfigPath); err == nil {
+		sshConfigPath = resolved
 	}
 
 	var existingContent string
@@ -277,42 +391,109 @@ func UpdateSSHConfig(config *Config) error {
 		existingContent = string(data)
 	}
 
-	re := regexp.MustCompile(`(?s)# zzk:begin\n.*?# zzk:end\n`)
-	existingContent = re.ReplaceAllString(existingContent, "")
-	existingContent = strings.TrimSpace(existingContent)
+	var zzkContent strings.Builder
+	zzkContent.WriteString(managedBlockBegin + "\n")
+
+	// The plain domain uses the first identity (by name); identities sharing
+	// a domain also get their own alias
+	writeHost := func(host string, identity Identity) {
+		zzkContent.WriteString(fmt.Sprintf("Host %s\n", host))
+		zzkContent.WriteString(fmt.Sprintf("  HostName %s\n", identity.Domain))
+		zzkContent.WriteString("  User git\n")
+		zzkContent.WriteString(fmt.Sprintf("  IdentityFile %s\n", identity.SSHKeyPath()))
+		zzkContent.WriteString("  IdentitiesOnly yes\n")
+		options := identity.hostOptions()
+		for _, option := range slices.Sorted(maps.Keys(options)) {
+			zzkContent.WriteString(fmt.Sprintf("  %s %s\n", option, sshConfigValue(options[option])))
+		}
+		zzkContent.WriteString("\n")
+	}
+
+	hosts := config.SSHHosts()
+	for _, identity := range config.SortedIdentities() {
+		for _, host := range hosts[identity.Name] {
+			writeHost(host, identity)
+		}
+	}
 
-	existingContent = removeLegacySSHHosts(existingContent, config)
+	zzkContent.WriteString(managedBlockEnd + "\n")
 
-	var zzkContent strings.Builder
-	zzkContent.WriteString("\n\n# zzk:begin\n")
+	// Replace the block where it is, or append it
+	before, after := existingContent, ""
+	if loc := sshBlockRegex.FindStringIndex(existingContent); loc != nil {
+		before, after = existingContent[:loc[0]], existingContent[loc[1]:]
+	} else {
+		// No block yet: this is the first sync, or the first one since zzk
+		// wrote unmarked entries, so clean those up once
+		before = removeLegacySSHHosts(before, config)
+	}
+	before = strings.TrimRight(before, "\n")
+	after = strings.TrimLeft(after, "\n")
 
-	domainIdentities := make(map[string]Identity)
-	for _, identity := range config.Identities {
-		if _, exists := domainIdentities[identity.Domain]; !exists {
-			domainIdentities[identity.Domain] = identity
+	var finalContent strings.Builder
+	if before != "" {
+		finalContent.WriteString(before + "\n\n")
+	}
+	finalContent.WriteString(zzkContent.String())
+	if after != "" {
+		finalContent.WriteString("\n" + after)
+		if !strings.HasSuffix(after, "\n") {
+			finalContent.WriteString("\n")
 		}
 	}
 
-	for domain, identity := range domainIdentities {
-		zzkContent.WriteString(fmt.Sprintf("Host %s\n", domain))
-		zzkContent.WriteString(fmt.Sprintf("  HostName %s\n", domain))
-		zzkContent.WriteString("  User git\n")
-		zzkContent.WriteString(fmt.Sprintf("  IdentityFile %s\n", identity.SSHKeyPath()))
-		zzkContent.WriteString("  IdentitiesOnly yes\n\n")
+	if err := fileutil.AtomicWrite(sshConfigPath, []byte(finalContent.String()), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write SSH config: %w", err)
 	}
 
-	zzkContent.WriteString("# zzk:end\n")
+	return sshHostOverrides(before, config), nil
+}
+
+// allowedSignerTagRegex matches the comment zzk appends to lines it manages
+// in ~/.ssh/allowed_signers
+var allowedSignerTagRegex = regexp.MustCompile(`\s\[zzk(?:-import)?:[^\]]+\]\s*$`)
+
+// AllowedSignerEntry returns the identity's ~/.ssh/allowed_signers line,
+// without zzk's tag, or false if it has no public key yet
+func AllowedSignerEntry(identity Identity) (string, bool) {
+	data, err := os.ReadFile(ExpandPath(identity.SSHPubKeyPath()))
+	if err != nil {
+		return "", false
+	}
+	parts := strings.Fields(string(data))
+	if len(parts) < 2 {
+		return "", false
+	}
+	return identity.signerEntry(parts[0], parts[1]), true
+}
 
-	finalContent := existingContent + zzkContent.String()
+// signerEntry builds an allowed_signers line for a public key: principals,
+// options, key type and key
+func (i *Identity) signerEntry(keyType, key string) string {
+	principals := i.Email
+	if len(i.Signer.Principals) > 0 {
+		principals = strings.Join(i.Signer.Principals, ",")
+	}
 
-	if err := os.WriteFile(sshConfigPath, []byte(finalContent), 0600); err != nil {
-		return fmt.Errorf("failed to write SSH config: %w", err)
+	var options []string
+	if len(i.Signer.Namespaces) > 0 {
+		options = append(options, fmt.Sprintf("namespaces=\"%s\"", strings.Join(i.Signer.Namespaces, ",")))
+	}
+	if i.Signer.ValidAfter != "" {
+		options = append(options, fmt.Sprintf("valid-after=\"%s\"", i.Signer.ValidAfter))
 	}
 
-	return nil
+	fields := []string{principals}
+	if len(options) > 0 {
+		fields = append(fields, strings.Join(options, ","))
+	}
+	return strings.Join(append(fields, keyType, key), " ")
 }
 
-// UpdateAllowedSigners updates ~/.ssh/allowed_signers with all identity keys
+// UpdateAllowedSigners merges identity keys into ~/.ssh/allowed_signers.
+// Managed lines end with a [zzk:<identity>] comment and are regenerated on
+// every sync, so lines of removed identities disappear; untagged lines, such
+// as coworkers' keys, are kept.
 func UpdateAllowedSigners(config *Config) error {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -320,24 +501,43 @@ func UpdateAllowedSigners(config *Config) error {
 	}
 
 	allowedSignersPath := filepath.Join(home, ".ssh", "allowed_signers")
+	if resolved, err := filepath.EvalSymlinks(allowedSignersPath); err == nil {
+		allowedSignersPath = resolved
+	}
 
-	var content strings.Builder
-
-	for _, identity := range config.Identities {
-		pubKeyPath := ExpandPath(identity.SSHPubKeyPath())
-		pubKeyData, err := os.ReadFile(pubKeyPath)
-		if err != nil {
+	var managed []string
+	managedKeys := make(map[string]bool)
+	for _, identity := range config.SortedIdentities() {
+		entry, ok := AllowedSignerEntry(identity)
+		if !ok {
 			continue
 		}
+		managed = append(managed, fmt.Sprintf("%s [zzk:%s]", entry, identity.Name))
+		fields := strings.Fields(entry)
+		managedKeys[strings.Join(fields[len(fields)-2:], " ")] = true
+	}
+
+	// Keys of other machines imported from bundles
+	managed = append(managed, importedSigners()...)
 
-		pubKey := strings.TrimSpace(string(pubKeyData))
-		parts := strings.SplitN(pubKey, " ", 3)
-		if len(parts) >= 2 {
-			content.WriteString(fmt.Sprintf("%s %s %s\n", identity.Email, parts[0], parts[1]))
+	var kept []string
+	if data, err := os.ReadFile(allowedSignersPath); err == nil {
+		for line := range strings.SplitSeq(strings.TrimRight(string(data), "\n"), "\n") {
+			if allowedSignerTagRegex.MatchString(line) {
+				continue
+			}
+			// Untagged copies of our keys were written by older versions
+			if fields := strings.Fields(line); len(fields) == 3 && managedKeys[fields[1]+" "+fields[2]] {
+				continue
+			}
+			kept = append(kept, line)
 		}
 	}
 
-	if err := os.WriteFile(allowedSignersPath, []byte(content.String()), 0600); err != nil {
+	content := strings.Join(append(kept, managed...), "\n")
+	content = strings.TrimLeft(content, "\n") + "\n"
+
+	if err := fileutil.AtomicWrite(allowedSignersPath, []byte(content), 0600); err != nil {
 		return fmt.Errorf("failed to write allowed_signers: %w", err)
 	}
 
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Sync hooks, run from HooksDir if they exist and are executable
+const (
+	HookPreSync  = "git-pre-sync"
+	HookPostSync = "git-post-sync"
+)
+
+// HooksDir returns ~/.config/zzk/hooks
+func HooksDir() (string, error) {
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hooks"), nil
+}
+
+// hookPath returns the path of an executable hook, or "" if there is none
+func hookPath(name string) string {
+	dir, err := HooksDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+		return ""
+	}
+	return path
+}
+
+// hookEnv describes the sync to a hook. Lists are newline-separated.
+func hookEnv(identities []string, result *SyncResult) []string {
+	env := append(os.Environ(),
+		"ZZK_GIT_CONFIG="+ConfigPath(),
+		"ZZK_SYNC_IDENTITIES="+strings.Join(identities, "\n"),
+	)
+	if result != nil {
+		failed := slices.Sorted(maps.Keys(result.Failed))
+		env = append(env,
+			"ZZK_SYNC_CREATED="+strings.Join(result.Created, "\n"),
+			"ZZK_SYNC_UPDATED="+strings.Join(result.Updated, "\n"),
+			"ZZK_SYNC_REMOVED="+strings.Join(result.OrphansRemoved, "\n"),
+			"ZZK_SYNC_FAILED="+strings.Join(failed, "\n"),
+		)
+	}
+	return env
+}
+
+// runHook runs the hook at path, reporting its command and output. The
+// post-sync hook also gets the result as JSON on stdin.
+func runHook(r Reporter, path string, identities []string, result *SyncResult) error {
+	cmd := exec.Command(path)
+	cmd.Env = hookEnv(identities, result)
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		cmd.Stdin = bytes.NewReader(data)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	reportCommand(r, "", cmd)
+	err := cmd.Run()
+	reportOutput(r, "", stdout.Bytes())
+	reportOutput(r, "", stderr.Bytes())
+	if err != nil {
+		// The hook's last error line says more than its exit status
+		return commandError(err, stderr.Bytes())
+	}
+	return nil
+}
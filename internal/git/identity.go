@@ -2,44 +2,212 @@ package git
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
+	"strings"
 )
 
 type Identity struct {
-	Name    string   `json:"-"` // Identity name (map key)
-	User    string   `json:"user"`
-	Email   string   `json:"email"`
-	Domain  string   `json:"domain"`
-	Folders []string `json:"folders"`
+	Name    string   `json:"-" yaml:"-"` // Identity name (map key)
+	User    string   `json:"user" yaml:"user"`
+	Email   string   `json:"email" yaml:"email"`
+	Domain  string   `json:"domain" yaml:"domain"`
+	Folders []string `json:"folders" yaml:"folders"`
+	// Remotes are repository path patterns on the identity's domain, e.g.
+	// "acme/*" or "acme/**", selecting it for repos cloned outside Folders
+	Remotes []string `json:"remotes,omitempty" yaml:"remotes,omitempty"`
+
+	// KeyType is one of KeyTypes; empty means ed25519
+	KeyType string `json:"key_type,omitempty" yaml:"key_type,omitempty"`
+	// KeyOptions are passed to ssh-keygen as -O, e.g. "resident" or
+	// "verify-required" for FIDO2 keys
+	KeyOptions []string `json:"key_options,omitempty" yaml:"key_options,omitempty"`
+
+	// CommitTemplate is written to CommitTemplatePath and used as
+	// commit.template in the identity's folders
+	CommitTemplate string `json:"commit_template,omitempty" yaml:"commit_template,omitempty"`
+	// Aliases are git aliases for the identity's folders, e.g.
+	// "wip": "commit -m wip"
+	Aliases map[string]string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// SSHOptions are extra ssh_config options for the identity's Host
+	// entries, e.g. "ProxyJump": "bastion.example.com" or "Port": "2222"
+	SSHOptions map[string]string `json:"ssh_options,omitempty" yaml:"ssh_options,omitempty"`
+	// Agent is the SSH agent holding the key: empty for the one in
+	// SSH_AUTH_SOCK, "none", "1password", "gpg-agent" or a socket path
+	Agent string `json:"agent,omitempty" yaml:"agent,omitempty"`
+	// EnforceNoreply requires a forge noreply email: sync warns if Email
+	// isn't one, and audit flags unpushed commits using a real address
+	EnforceNoreply bool `json:"enforce_noreply,omitempty" yaml:"enforce_noreply,omitempty"`
+	// Signer sets the principals and options of the key's line in
+	// ~/.ssh/allowed_signers
+	Signer Signer `json:"signer,omitzero" yaml:"signer,omitempty"`
+
+	sharedDomain bool // Another identity uses the same domain
+}
+
+// Signer configures an identity's ~/.ssh/allowed_signers entry
+type Signer struct {
+	// Principals the key may sign as; empty means the identity's email
+	Principals []string `json:"principals,omitempty" yaml:"principals,omitempty"`
+	// Namespaces restricts the key to these signature namespaces, e.g. "git"
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	// ValidAfter is when the key's signatures start being trusted, as
+	// YYYYMMDD or YYYYMMDDHHMM[SS] with an optional Z for UTC
+	ValidAfter string `json:"valid_after,omitempty" yaml:"valid_after,omitempty"`
+}
+
+// Supported SSH key types
+const (
+	KeyTypeEd25519   = "ed25519"
+	KeyTypeEd25519SK = "ed25519-sk"
+	KeyTypeEcdsaSK   = "ecdsa-sk"
+	KeyTypeRSA4096   = "rsa-4096"
+)
+
+// KeyTypes lists the supported SSH key types, default first
+var KeyTypes = []string{KeyTypeEd25519, KeyTypeEd25519SK, KeyTypeEcdsaSK, KeyTypeRSA4096}
+
+// keyAlgorithms maps key types to the algorithm name in the public key
+var keyAlgorithms = map[string]string{
+	KeyTypeEd25519:   "ssh-ed25519",
+	KeyTypeEd25519SK: "sk-ssh-ed25519@openssh.com",
+	KeyTypeEcdsaSK:   "sk-ecdsa-sha2-nistp256@openssh.com",
+	KeyTypeRSA4096:   "ssh-rsa",
 }
 
+// managedSSHOptions are written by zzk in every Host entry and can't be overridden
+var managedSSHOptions = []string{"host", "hostname", "user", "identityfile", "identitiesonly"}
+
+// sshOptionRegex matches ssh_config option names
+var sshOptionRegex = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// signerForbidden are characters that would break an allowed_signers line
+const signerForbidden = ",\"\\ \t\n\r"
+
+// signerTimeRegex matches the times ssh-keygen accepts for valid-after
+var signerTimeRegex = regexp.MustCompile(`^\d{8}(\d{4}(\d{2})?)?Z?$`)
+
+// aliasNameRegex matches names git accepts in the [alias] section
+var aliasNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+// Validate checks the identity's fields, returning a *FieldError naming the
+// first invalid one
 func (i *Identity) Validate() error {
 	if i.User == "" {
-		return fmt.Errorf("user must not be empty")
+		return fieldErrorf("user", "must not be empty")
 	}
 	if i.Email == "" {
-		return fmt.Errorf("email must not be empty")
+		return fieldErrorf("email", "must not be empty")
 	}
 	if i.Domain == "" {
-		return fmt.Errorf("domain must not be empty")
+		return fieldErrorf("domain", "must not be empty")
 	}
 	if len(i.Folders) == 0 {
-		return fmt.Errorf("at least one folder must be specified")
+		return fieldErrorf("folders", "at least one folder must be specified")
 	}
 
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(i.Email) {
-		return fmt.Errorf("invalid email address: %s", i.Email)
+		return fieldErrorf("email", "invalid address %q", i.Email)
+	}
+
+	for n, folder := range i.Folders {
+		if folder == "" {
+			return fieldErrorf(fmt.Sprintf("folders[%d]", n), "folder path must not be empty")
+		}
+	}
+
+	for n, pattern := range i.Remotes {
+		field := fmt.Sprintf("remotes[%d]", n)
+		if strings.Trim(pattern, "/") == "" || strings.HasPrefix(pattern, "/") {
+			return fieldErrorf(field, "must be a repository path pattern like owner/*")
+		}
+		if strings.ContainsAny(pattern, "\"\\ \t\n\r:") {
+			return fieldErrorf(field, "must not contain quotes, colons or whitespace")
+		}
+	}
+
+	if i.KeyType != "" && !slices.Contains(KeyTypes, i.KeyType) {
+		return fieldErrorf("key_type", "invalid key type %q (use %s)", i.KeyType, strings.Join(KeyTypes, ", "))
+	}
+	if len(i.KeyOptions) > 0 && !i.IsSecurityKey() {
+		return fieldErrorf("key_options", "only supported for security key types (ed25519-sk, ecdsa-sk)")
+	}
+
+	for _, option := range slices.Sorted(maps.Keys(i.SSHOptions)) {
+		field := "ssh_options." + option
+		if !sshOptionRegex.MatchString(option) {
+			return fieldErrorf(field, "invalid option name")
+		}
+		if slices.Contains(managedSSHOptions, strings.ToLower(option)) {
+			return fieldErrorf(field, "can't be set, zzk manages it")
+		}
+		if value := i.SSHOptions[option]; strings.TrimSpace(value) == "" || strings.ContainsAny(value, "\n\r") {
+			return fieldErrorf(field, "must be a single non-empty line")
+		}
+	}
+
+	if i.Agent != "" {
+		switch {
+		case i.Agent != AgentNone && i.Agent != Agent1Password && i.Agent != AgentGPG &&
+			!strings.HasPrefix(i.Agent, "/") && !strings.HasPrefix(i.Agent, "~"):
+			return fieldErrorf("agent", "use none, 1password, gpg-agent or a socket path")
+		case strings.ContainsAny(i.Agent, "\n\r"):
+			return fieldErrorf("agent", "must be a single line")
+		}
+		for option := range i.SSHOptions {
+			if strings.EqualFold(option, "IdentityAgent") {
+				return fieldErrorf("agent", "can't be combined with ssh_options.%s", option)
+			}
+		}
+	}
+
+	for n, principal := range i.Signer.Principals {
+		if principal == "" || strings.ContainsAny(principal, signerForbidden) {
+			return fieldErrorf(fmt.Sprintf("signer.principals[%d]", n), "must not be empty or contain commas, quotes or whitespace")
+		}
+	}
+	for n, namespace := range i.Signer.Namespaces {
+		if namespace == "" || strings.ContainsAny(namespace, signerForbidden) {
+			return fieldErrorf(fmt.Sprintf("signer.namespaces[%d]", n), "must not be empty or contain commas, quotes or whitespace")
+		}
+	}
+	if i.Signer.ValidAfter != "" && !signerTimeRegex.MatchString(i.Signer.ValidAfter) {
+		return fieldErrorf("signer.valid_after", "use YYYYMMDD or YYYYMMDDHHMM[SS], optionally ending in Z")
 	}
 
-	if slices.Contains(i.Folders, "") {
-		return fmt.Errorf("folder path must not be empty")
+	for _, alias := range slices.Sorted(maps.Keys(i.Aliases)) {
+		field := "aliases." + alias
+		if !aliasNameRegex.MatchString(alias) {
+			return fieldErrorf(field, "invalid alias name (use letters, digits and dashes)")
+		}
+		if command := i.Aliases[alias]; strings.TrimSpace(command) == "" || strings.ContainsAny(command, "\n\r") {
+			return fieldErrorf(field, "must be a single non-empty line")
+		}
 	}
 
 	return nil
 }
 
+// SSHKeyType returns the configured key type, defaulting to ed25519
+func (i *Identity) SSHKeyType() string {
+	if i.KeyType == "" {
+		return KeyTypeEd25519
+	}
+	return i.KeyType
+}
+
+// IsSecurityKey reports whether the key lives on a FIDO2 security key
+func (i *Identity) IsSecurityKey() bool {
+	return strings.HasSuffix(i.SSHKeyType(), "-sk")
+}
+
+// SSHKeyAlgorithm returns the algorithm name public keys of this type start with
+func (i *Identity) SSHKeyAlgorithm() string {
+	return keyAlgorithms[i.SSHKeyType()]
+}
+
 func (i *Identity) SSHKeyPath() string {
 	return fmt.Sprintf("~/.ssh/%s_key", i.Name)
 }
@@ -52,6 +220,149 @@ func (i *Identity) GitConfigPath() string {
 	return fmt.Sprintf("~/.gitconfig-%s", i.Name)
 }
 
+// SSHOptionArgs returns the identity's SSH options as ssh -o arguments, for
+// connections that don't go through its Host entry
+func (i *Identity) SSHOptionArgs() []string {
+	var args []string
+	options := i.sshOptions()
+	for _, option := range slices.Sorted(maps.Keys(options)) {
+		args = append(args, "-o", option+"="+sshConfigValue(options[option]))
+	}
+	return args
+}
+
+// sshConfigValue quotes values with spaces, as ssh_config and -o need
+func sshConfigValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// SSHCommand returns the ssh command line git should use for this identity
+func (i *Identity) SSHCommand() string {
+	args := append([]string{"ssh", "-i", i.SSHKeyPath()}, i.SSHOptionArgs()...)
+	for n, arg := range args {
+		if strings.ContainsAny(arg, " '\"\\$`") {
+			args[n] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// hasCustomRoute reports whether the options change how the domain is
+// reached, so scanning its host keys directly would be wrong
+func (i *Identity) hasCustomRoute() bool {
+	for option := range i.SSHOptions {
+		switch strings.ToLower(option) {
+		case "port", "proxyjump", "proxycommand", "hostkeyalias":
+			return true
+		}
+	}
+	return false
+}
+
+// CommitTemplatePath is where the identity's commit template is written
+func (i *Identity) CommitTemplatePath() string {
+	return fmt.Sprintf("~/.gitmessage-%s", i.Name)
+}
+
+// IncludeConditions returns the includeIf conditions that select this
+// identity's gitconfig: one per folder, then the remote URL forms of each
+// Remotes pattern
+func (i *Identity) IncludeConditions() []string {
+	conditions := make([]string, 0, len(i.Folders))
+	for _, folder := range i.Folders {
+		if !strings.HasSuffix(folder, "/") {
+			folder = folder + "/"
+		}
+		conditions = append(conditions, "gitdir:"+folder)
+	}
+	for _, url := range i.remoteURLPatterns() {
+		conditions = append(conditions, "hasconfig:remote.*.url:"+url)
+	}
+	return conditions
+}
+
+// remoteURLPatterns expands Remotes into the URL globs git matches against
+// remote.*.url: SSH URLs on the domain and the identity's Host alias, and
+// HTTPS URLs. Patterns not ending in a wildcard also match with .git.
+func (i *Identity) remoteURLPatterns() []string {
+	hosts := []string{i.Domain}
+	if i.SSHHost() != i.Domain {
+		hosts = append(hosts, i.SSHHost())
+	}
+
+	var urls []string
+	for _, pattern := range i.Remotes {
+		pattern = strings.Trim(pattern, "/")
+		paths := []string{pattern}
+		if !strings.HasSuffix(pattern, "*") {
+			paths = append(paths, pattern+".git")
+		}
+		for _, path := range paths {
+			for _, host := range hosts {
+				urls = append(urls, fmt.Sprintf("git@%s:%s", host, path), fmt.Sprintf("ssh://git@%s/%s", host, path))
+			}
+			urls = append(urls, fmt.Sprintf("https://%s/%s", i.Domain, path))
+		}
+	}
+	return urls
+}
+
+// MatchesRemote reports whether a remote URL is on the identity's domain or
+// Host alias and its repository path matches one of Remotes
+func (i *Identity) MatchesRemote(url string) bool {
+	host, repoPath, ok := ParseRepoURL(url)
+	if !ok || (host != i.Domain && host != i.SSHHost()) {
+		return false
+	}
+	for _, pattern := range i.Remotes {
+		if matchRepoPattern(strings.Trim(pattern, "/"), repoPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRepoPattern matches a repository path against a pattern where "*"
+// matches within one path segment and "**" across segments, as in git's
+// hasconfig conditions
+func matchRepoPattern(pattern, repoPath string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for n := 0; n < len(pattern); n++ {
+		switch {
+		case strings.HasPrefix(pattern[n:], "**"):
+			expr.WriteString(".*")
+			n++
+		case pattern[n] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[n] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[n : n+1]))
+		}
+	}
+	expr.WriteString("$")
+	matched, _ := regexp.MatchString(expr.String(), repoPath)
+	return matched
+}
+
 func (i *Identity) SSHKeyComment() string {
 	return fmt.Sprintf("%s [zzk:%s]", i.Email, i.Name)
 }
+
+// SSHHost returns the SSH host used in remotes for this identity. Identities
+// sharing a domain get a Host alias in ~/.ssh/config, e.g. github.com-work.
+func (i *Identity) SSHHost() string {
+	if i.sharedDomain {
+		return i.Domain + "-" + i.Name
+	}
+	return i.Domain
+}
+
+// RemoteURL returns the SSH remote URL for a repository path (owner/name)
+func (i *Identity) RemoteURL(repoPath string) string {
+	return fmt.Sprintf("git@%s:%s.git", i.SSHHost(), repoPath)
+}
@@ -0,0 +1,261 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ImportCandidate is an identity reconstructed from an existing git and SSH setup
+type ImportCandidate struct {
+	Identity Identity
+	KeyFile  string   // Existing private key, if found
+	Source   string   // Included git config it came from
+	Notes    []string // Guesses the user should check
+}
+
+// sshHostBlock is a Host entry from ~/.ssh/config
+type sshHostBlock struct {
+	Hosts        []string
+	HostName     string
+	IdentityFile string
+}
+
+var includeIfRegex = regexp.MustCompile(`^\[includeIf\s+"gitdir(?:/i)?:([^"]+)"\]`)
+
+var sshCommandKeyRegex = regexp.MustCompile(`-i\s+("[^"]+"|\S+)`)
+
+var importNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// FindImportCandidates reads includeIf sections from ~/.gitconfig and Host
+// blocks from ~/.ssh/config and proposes an identity per included config.
+// Configs already managed by zzk are skipped.
+func FindImportCandidates() ([]ImportCandidate, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".gitconfig"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read ~/.gitconfig: %w", err)
+	}
+	includes := parseIncludeIfs(removeZZKSections(string(data)))
+
+	hosts, err := parseSSHConfig(filepath.Join(home, ".ssh", "config"))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(includes))
+	for path := range includes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var candidates []ImportCandidate
+	for _, path := range paths {
+		// Relative include paths are relative to ~/.gitconfig
+		expanded := ExpandPath(path)
+		if !filepath.IsAbs(expanded) {
+			expanded = filepath.Join(home, expanded)
+		}
+		if managed, _ := IsZZKManagedGitConfig(expanded); managed {
+			continue
+		}
+		if _, err := os.Stat(expanded); err != nil {
+			continue
+		}
+		candidates = append(candidates, buildCandidate(path, expanded, includes[path], hosts))
+	}
+	return candidates, nil
+}
+
+// parseIncludeIfs maps each included config path to the gitdir folders including it
+func parseIncludeIfs(content string) map[string][]string {
+	includes := make(map[string][]string)
+	folder := ""
+	for line := range strings.SplitSeq(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if m := includeIfRegex.FindStringSubmatch(trimmed); m != nil {
+			folder = m[1]
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			folder = ""
+			continue
+		}
+		if key, value, ok := strings.Cut(trimmed, "="); ok && folder != "" && strings.TrimSpace(key) == "path" {
+			path := strings.Trim(strings.TrimSpace(value), `"`)
+			includes[path] = append(includes[path], folder)
+		}
+	}
+	return includes
+}
+
+// parseSSHConfig reads Host blocks outside zzk's managed section
+func parseSSHConfig(path string) ([]sshHostBlock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := sshBlockRegex.ReplaceAllString(string(data), "")
+
+	var blocks []sshHostBlock
+	var current *sshHostBlock
+	for line := range strings.SplitSeq(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		key, value := strings.ToLower(fields[0]), strings.Trim(fields[1], `"`)
+		switch {
+		case key == "host":
+			blocks = append(blocks, sshHostBlock{Hosts: fields[1:]})
+			current = &blocks[len(blocks)-1]
+		case key == "match":
+			current = nil
+		case current == nil:
+		case key == "hostname":
+			current.HostName = value
+		case key == "identityfile" && current.IdentityFile == "":
+			current.IdentityFile = value
+		}
+	}
+	return blocks, nil
+}
+
+func buildCandidate(path, file string, folders []string, hosts []sshHostBlock) ImportCandidate {
+	get := func(key string) string {
+		value, _ := RunGit("", "config", "-f", file, key)
+		return value
+	}
+
+	c := ImportCandidate{Source: path}
+	c.Identity.User = get("user.name")
+	c.Identity.Email = get("user.email")
+	for _, folder := range folders {
+		c.Identity.Folders = append(c.Identity.Folders, strings.TrimSuffix(folder, "/"))
+	}
+
+	if m := sshCommandKeyRegex.FindStringSubmatch(get("core.sshCommand")); m != nil {
+		c.KeyFile = strings.Trim(m[1], `"`)
+	} else if key := get("user.signingkey"); strings.HasPrefix(key, "~/") || strings.HasPrefix(key, "/") {
+		c.KeyFile = strings.TrimSuffix(key, ".pub")
+	}
+
+	// The domain comes from the Host block using the same key, else from the
+	// remotes of repos in the folders
+	for _, block := range hosts {
+		if c.KeyFile != "" && ExpandPath(block.IdentityFile) == ExpandPath(c.KeyFile) {
+			c.Identity.Domain = block.HostName
+			if c.Identity.Domain == "" {
+				c.Identity.Domain = block.Hosts[0]
+			}
+			break
+		}
+	}
+	if c.Identity.Domain == "" {
+		c.Identity.Domain = remoteDomain(c.Identity.Folders, hosts)
+	}
+	if c.Identity.Domain == "" {
+		c.Identity.Domain = "github.com"
+		c.Notes = append(c.Notes, "domain not found in ~/.ssh/config or remotes, assumed github.com")
+	}
+
+	if c.KeyFile == "" {
+		c.Notes = append(c.Notes, "no SSH key found, sync will generate one")
+	} else if _, err := os.Stat(ExpandPath(c.KeyFile)); err != nil {
+		c.Notes = append(c.Notes, fmt.Sprintf("key %s does not exist, sync will generate one", c.KeyFile))
+		c.KeyFile = ""
+	}
+
+	name := strings.TrimPrefix(filepath.Base(path), ".gitconfig-")
+	if name == filepath.Base(path) && len(folders) > 0 {
+		name = filepath.Base(strings.TrimSuffix(folders[0], "/"))
+	}
+	c.Identity.Name = strings.ToLower(strings.Trim(importNameRegex.ReplaceAllString(name, "-"), "-."))
+	return c
+}
+
+// remoteDomain returns the most common SSH remote host of repos in folders,
+// resolving ~/.ssh/config aliases to their HostName
+func remoteDomain(folders []string, hosts []sshHostBlock) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, folder := range folders {
+		for _, repo := range FindRepos(ExpandPath(folder), 2) {
+			remotes, _ := Remotes(repo)
+			for _, url := range remotes {
+				host, _, ok := ParseRepoURL(url)
+				if !ok {
+					continue
+				}
+				for _, block := range hosts {
+					if block.HostName != "" && slices.Contains(block.Hosts, host) {
+						host = block.HostName
+						break
+					}
+				}
+				counts[host]++
+				if counts[host] > counts[best] {
+					best = host
+				}
+			}
+		}
+	}
+	return best
+}
+
+// ImportKey copies an existing key pair to the identity's key paths, tagging
+// the public key comment so zzk recognises it as managed
+func ImportKey(identity Identity, keyFile string) error {
+	src := ExpandPath(keyFile)
+	private, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", keyFile, err)
+	}
+	public, err := os.ReadFile(src + ".pub")
+	if err != nil {
+		return fmt.Errorf("failed to read %s.pub: %w", keyFile, err)
+	}
+
+	fields := strings.Fields(string(public))
+	if len(fields) < 2 {
+		return fmt.Errorf("invalid public key %s.pub", keyFile)
+	}
+	pub := fmt.Sprintf("%s %s %s\n", fields[0], fields[1], identity.SSHKeyComment())
+
+	keyPath := ExpandPath(identity.SSHKeyPath())
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return fmt.Errorf("failed to create .ssh directory: %w", err)
+	}
+	if err := os.WriteFile(keyPath, private, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", identity.SSHKeyPath(), err)
+	}
+	if err := os.WriteFile(ExpandPath(identity.SSHPubKeyPath()), []byte(pub), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", identity.SSHPubKeyPath(), err)
+	}
+	return nil
+}
+
+// KeyTypeOf returns the key_type matching a public key algorithm, or "" for
+// the default or unknown algorithms
+func KeyTypeOf(algorithm string) string {
+	for keyType, alg := range keyAlgorithms {
+		if alg == algorithm && keyType != KeyTypeEd25519 {
+			return keyType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,203 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// pinnedHostKeys are the SHA256 host key fingerprints the major forges
+// publish. Scanned keys for these domains are only trusted if they match.
+var pinnedHostKeys = map[string][]string{
+	"github.com": {
+		"SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU",
+		"SHA256:p2QAMXNIC1TJYWeIOttrVc98/R1BUFWu3/LiyKgUfQM",
+		"SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s",
+	},
+	"gitlab.com": {
+		"SHA256:eUXGGm1YGsMAS7vkcx6JOJdOGHPem5gQp4taiCfCLB8",
+		"SHA256:HbW3g8zUjNSksFbqTiUWPWg2Bq1x8xdGUrliXFzSnUw",
+		"SHA256:ROQFvPThGrW4RuWLoL9tq9I9zJ42fK4XywyRtbOz/EQ",
+	},
+	"bitbucket.org": {
+		"SHA256:ybgmFkzwOSotHTHLJgHO0QN8L0xErw6vd0VhFA9m3SM",
+		"SHA256:FC73VB6C4OQLSCrjEayhMp9UMxS97caD/Yyi2bhW/J0",
+		"SHA256:46OSHA1Rmj8E8ERTC6xkNcmGOw9oFxYr0WF6zWW8l1E",
+	},
+	"codeberg.org": {
+		"SHA256:mIlxA9k46MmM6qdJOdMnAQpzGxF4WIVVL+fj+wZbw0g",
+		"SHA256:T9FYDEHELhVkulEKKwge5aVhVTbqCW0MIRwAfpARs/E",
+		"SHA256:6QQmYi4ppFS4/+zSZ5S4IU+4sa6rwvQ4PbhCtPEBekQ",
+	},
+}
+
+// ErrHostKeyMismatch means a forge's scanned host keys don't match its
+// published fingerprints
+var ErrHostKeyMismatch = errors.New("host keys don't match the published fingerprints")
+
+// KnownHostResult is the outcome of seeding one domain's host keys
+type KnownHostResult struct {
+	Domain string
+	// Added is how many keys were written to known_hosts
+	Added int
+	// AlreadyKnown means known_hosts had the domain and nothing was scanned
+	AlreadyKnown bool
+	// Pinned means the added keys matched the published fingerprints
+	Pinned bool
+	Err    error
+}
+
+// KnownHostsPath returns ~/.ssh/known_hosts
+func KnownHostsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".ssh", "known_hosts"), nil
+}
+
+// SeedKnownHosts adds the host keys of domains missing from
+// ~/.ssh/known_hosts, so the first clone doesn't prompt. Keys are fetched
+// with ssh-keyscan; for forges in pinnedHostKeys only keys matching the
+// published fingerprints are added, and nothing is added if none match.
+func SeedKnownHosts(ctx context.Context, domains []string) []KnownHostResult {
+	path, err := KnownHostsPath()
+	if err != nil {
+		return []KnownHostResult{{Err: err}}
+	}
+
+	var results []KnownHostResult
+	for _, domain := range domains {
+		result := KnownHostResult{Domain: domain}
+		if knownHost(path, domain) {
+			result.AlreadyKnown = true
+			results = append(results, result)
+			continue
+		}
+
+		result.Pinned = len(pinnedHostKeys[domain]) > 0
+		result.Added, result.Err = seedHost(ctx, path, domain)
+		results = append(results, result)
+	}
+	return results
+}
+
+// seedHost scans domain's host keys, verifies pinned ones and appends them
+func seedHost(ctx context.Context, path, domain string) (int, error) {
+	lines, err := scanHostKeys(ctx, domain)
+	if err != nil {
+		return 0, err
+	}
+	if len(pinnedHostKeys[domain]) > 0 {
+		if lines, err = verifyPinnedHostKeys(domain, lines); err != nil {
+			return 0, err
+		}
+	}
+	if err := appendKnownHosts(path, lines); err != nil {
+		return 0, err
+	}
+	return len(lines), nil
+}
+
+// knownHost reports whether known_hosts has an entry for host, hashed or not
+func knownHost(path, host string) bool {
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+	return exec.Command("ssh-keygen", "-F", host, "-f", path).Run() == nil
+}
+
+// scanHostKeys returns the known_hosts lines ssh-keyscan prints for domain
+func scanHostKeys(ctx context.Context, domain string) ([]string, error) {
+	output, err := exec.CommandContext(ctx, "ssh-keyscan", "-T", "10", "-t", "ed25519,ecdsa,rsa", domain).Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out scanning %s", domain)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		if err != nil {
+			return nil, fmt.Errorf("ssh-keyscan failed for %s: %w", domain, err)
+		}
+		return nil, fmt.Errorf("no host keys found for %s", domain)
+	}
+	return lines, nil
+}
+
+// verifyPinnedHostKeys keeps the scanned keys whose fingerprints are pinned
+// for domain, failing if none are
+func verifyPinnedHostKeys(domain string, lines []string) ([]string, error) {
+	var verified, seen []string
+	for _, line := range lines {
+		fingerprint, err := hostKeyFingerprint(line)
+		if err != nil {
+			continue
+		}
+		seen = append(seen, fingerprint)
+		if slices.Contains(pinnedHostKeys[domain], fingerprint) {
+			verified = append(verified, line)
+		}
+	}
+	if len(verified) == 0 {
+		return nil, fmt.Errorf("%w (got %s)", ErrHostKeyMismatch, strings.Join(seen, ", "))
+	}
+	return verified, nil
+}
+
+// hostKeyFingerprint returns the SHA256 fingerprint of a "host type key" line
+func hostKeyFingerprint(line string) (string, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return "", fmt.Errorf("malformed host key line")
+	}
+	data, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return "", err
+	}
+	key, err := ssh.ParsePublicKey(data)
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(key), nil
+}
+
+// appendKnownHosts adds lines to the end of known_hosts, creating it if needed
+func appendKnownHosts(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create .ssh directory: %w", err)
+	}
+
+	var content strings.Builder
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		content.WriteString("\n")
+	}
+	for _, line := range lines {
+		content.WriteString(line + "\n")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts: %w", err)
+	}
+	if _, err := f.WriteString(content.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write known_hosts: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigVersion is the config layout this version of zzk reads and writes
+const ConfigVersion = 1
+
+// configMigrations upgrade a decoded config one version at a time:
+// configMigrations[i] turns version i into version i+1
+var configMigrations = []func(raw map[string]any) error{
+	migrateConfigV1,
+}
+
+// migrateConfigV1 upgrades unversioned configs, which were written by hand:
+// an identities list with a "name" in each entry becomes the map keyed by
+// name, "folder" or a single "folders" string becomes the folders list, and
+// "username" becomes "user"
+func migrateConfigV1(raw map[string]any) error {
+	if list, ok := raw["identities"].([]any); ok {
+		identities := make(map[string]any, len(list))
+		for i, value := range list {
+			identity, _ := value.(map[string]any)
+			name, _ := identity["name"].(string)
+			if name == "" {
+				return fmt.Errorf("identities[%d]: missing name", i)
+			}
+			if _, exists := identities[name]; exists {
+				return fmt.Errorf("identities[%d]: duplicate name %q", i, name)
+			}
+			delete(identity, "name")
+			identities[name] = identity
+		}
+		raw["identities"] = identities
+	}
+
+	identities, _ := raw["identities"].(map[string]any)
+	for name, value := range identities {
+		identity, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := renameConfigKey(identity, "username", "user"); err != nil {
+			return fmt.Errorf("identities.%s: %w", name, err)
+		}
+		if err := renameConfigKey(identity, "folder", "folders"); err != nil {
+			return fmt.Errorf("identities.%s: %w", name, err)
+		}
+		if folder, ok := identity["folders"].(string); ok {
+			identity["folders"] = []any{folder}
+		}
+	}
+	return nil
+}
+
+// renameConfigKey moves m[from] to m[to], failing if both are set
+func renameConfigKey(m map[string]any, from, to string) error {
+	value, ok := m[from]
+	if !ok {
+		return nil
+	}
+	if _, exists := m[to]; exists {
+		return fmt.Errorf("both %s and %s are set; keep only %s", from, to, to)
+	}
+	m[to] = value
+	delete(m, from)
+	return nil
+}
+
+// migrateConfigData upgrades config file data to ConfigVersion, returning
+// it unchanged if it is current, along with the version it had
+func migrateConfigData(data []byte, isYAML bool) ([]byte, int, error) {
+	unmarshal := json.Unmarshal
+	marshal := func(v any) ([]byte, error) { return json.MarshalIndent(v, "", "  ") }
+	if isYAML {
+		unmarshal = yaml.Unmarshal
+		marshal = yaml.Marshal
+	}
+
+	var header struct {
+		Version int `json:"version" yaml:"version"`
+	}
+	if err := unmarshal(data, &header); err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case header.Version > ConfigVersion:
+		return nil, 0, fmt.Errorf("config version %d is newer than supported (%d), update zzk", header.Version, ConfigVersion)
+	case header.Version < 0:
+		return nil, 0, fmt.Errorf("invalid config version %d", header.Version)
+	case header.Version == ConfigVersion:
+		return data, header.Version, nil
+	}
+
+	var raw map[string]any
+	if err := unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+	for version := header.Version; version < ConfigVersion; version++ {
+		if err := configMigrations[version](raw); err != nil {
+			return nil, 0, fmt.Errorf("upgrading config to version %d: %w", version+1, err)
+		}
+	}
+	raw["version"] = ConfigVersion
+
+	migrated, err := marshal(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	return migrated, header.Version, nil
+}
+
+// MigrateConfig upgrades the config file to ConfigVersion in place, keeping
+// the old file as <path>.v<N>.bak. It returns the version it upgraded from
+// and the backup path, or an empty path if the config was current. Configs
+// that fail to load are left for the command to report.
+func MigrateConfig() (int, string, error) {
+	config, err := LoadConfig()
+	if err != nil || config.migratedFrom == ConfigVersion {
+		return 0, "", nil
+	}
+
+	path := ConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", path, config.migratedFrom)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return 0, "", fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := SaveConfig(config); err != nil {
+		return 0, "", err
+	}
+	return config.migratedFrom, backup, nil
+}
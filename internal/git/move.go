@@ -0,0 +1,71 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// localOverrides are the keys in a repo's .git/config that would override
+// the identity applied through its folder
+var localOverrides = []string{"user.name", "user.email", "user.signingkey", "core.sshCommand", switchKey}
+
+// RepoMove is the plan for moving a repository into another identity's folder
+type RepoMove struct {
+	Identity Identity
+	Repo     string
+	// Dest is where the repo ends up; equal to Repo if it is already in
+	// the identity's folder
+	Dest string
+	// Unset are local config keys that would override the identity
+	Unset []string
+	// Remotes are the remotes to point at the identity's host
+	Remotes []SwitchChange
+}
+
+// PlanMove works out how to move the repository at repo under parent, one of
+// the identity's folders
+func PlanMove(identity Identity, repo, parent string) (*RepoMove, error) {
+	move := &RepoMove{Identity: identity, Repo: repo, Dest: filepath.Join(ExpandPath(parent), filepath.Base(repo))}
+	if MatchingFolder(identity, repo) != "" {
+		move.Dest = repo
+	} else if _, err := os.Stat(move.Dest); err == nil {
+		return nil, fmt.Errorf("%s already exists", move.Dest)
+	}
+
+	for _, key := range localOverrides {
+		if _, err := RunGit(repo, "config", "--local", key); err == nil {
+			move.Unset = append(move.Unset, key)
+		}
+	}
+
+	var err error
+	if move.Remotes, err = remoteChanges(identity, repo); err != nil {
+		return nil, err
+	}
+	return move, nil
+}
+
+// Apply moves the repository and rewrites its config
+func (m *RepoMove) Apply() error {
+	if m.Dest != m.Repo {
+		if err := os.MkdirAll(filepath.Dir(m.Dest), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(m.Dest), err)
+		}
+		if err := os.Rename(m.Repo, m.Dest); err != nil {
+			return fmt.Errorf("failed to move %s: %w", m.Repo, err)
+		}
+	}
+
+	for _, key := range m.Unset {
+		if _, err := RunGit(m.Dest, "config", "--local", "--unset-all", key); err != nil {
+			return fmt.Errorf("failed to unset %s: %w", key, err)
+		}
+	}
+	for _, change := range m.Remotes {
+		if err := ApplySwitchChange(m.Dest, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
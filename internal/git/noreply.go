@@ -0,0 +1,41 @@
+package git
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// IsNoreplyEmail reports whether email is a forge's private commit address,
+// like 1234+user@users.noreply.github.com or user@noreply.codeberg.org
+func IsNoreplyEmail(email string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	return ok && slices.Contains(strings.Split(domain, "."), "noreply")
+}
+
+// realEmailCommits flags the emails of commits in repo that aren't on any
+// remote yet and would disclose a real address. Pushed commits are left out:
+// they are already public and can only be fixed by rewriting history.
+func realEmailCommits(repo string) []AuditIssue {
+	output, err := RunGit(repo, "log", "HEAD", "--not", "--remotes", "--format=%ae%n%ce")
+	if err != nil || output == "" {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for email := range strings.SplitSeq(output, "\n") {
+		if email != "" && !IsNoreplyEmail(email) {
+			counts[email]++
+		}
+	}
+
+	var issues []AuditIssue
+	for _, email := range slices.Sorted(maps.Keys(counts)) {
+		issues = append(issues, AuditIssue{
+			Field: "commits", Got: email, Want: "a noreply address",
+			Message: fmt.Sprintf("unpushed commits use %s, not a noreply address", email),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// IdentityOverride holds the identity fields one machine changes, e.g.
+// {"folders": ["~/src/work"]}. Keys are the config's field names.
+type IdentityOverride map[string]any
+
+// overlay is an identity as written in the config and as overridden for
+// this machine
+type overlay struct {
+	host   string
+	keys   []string
+	base   Identity
+	merged Identity
+}
+
+// Hostname returns the name the config's overrides are looked up by: the
+// machine's host name, lowercased and without its domain
+func Hostname() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	host, _, _ = strings.Cut(strings.ToLower(host), ".")
+	return host
+}
+
+// applyOverrides merges the overrides for host into the identities. The
+// original identities are kept so SaveConfig writes them back unchanged.
+func (c *Config) applyOverrides(host string) error {
+	for _, key := range slices.Sorted(maps.Keys(c.Overrides)) {
+		for _, name := range slices.Sorted(maps.Keys(c.Overrides[key])) {
+			if !c.HasIdentity(name) {
+				return fieldErrorf(fmt.Sprintf("overrides.%s.%s", key, name), "unknown identity")
+			}
+		}
+	}
+
+	overrides := c.Overrides[host]
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		base := c.Identities[name]
+		merged, err := overrideIdentity(base, overrides[name])
+		if err != nil {
+			return fieldErrorf(fmt.Sprintf("overrides.%s.%s", host, name), "%v", err)
+		}
+		if c.overlays == nil {
+			c.overlays = make(map[string]overlay)
+		}
+		c.overlays[name] = overlay{host: host, keys: slices.Sorted(maps.Keys(overrides[name])), base: base, merged: merged}
+		c.Identities[name] = merged
+	}
+	return nil
+}
+
+// overrideIdentity decodes override on top of a copy of identity, so only
+// the fields it names change
+func overrideIdentity(identity Identity, override IdentityOverride) (Identity, error) {
+	// Decoding reuses slices and maps, so start from a deep copy
+	base, err := json.Marshal(identity)
+	if err != nil {
+		return identity, err
+	}
+	var merged Identity
+	if err := json.Unmarshal(base, &merged); err != nil {
+		return identity, err
+	}
+
+	data, err := json.Marshal(override)
+	if err != nil {
+		return identity, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&merged); err != nil {
+		return identity, fmt.Errorf("invalid override: %w", err)
+	}
+	return merged, nil
+}
+
+// Overridden returns the host and fields this machine's overrides change
+// for an identity, or "" and nil if none apply
+func (c *Config) Overridden(name string) (string, []string) {
+	o, ok := c.overlays[name]
+	if !ok {
+		return "", nil
+	}
+	return o.host, o.keys
+}
+
+// withoutOverrides returns the identities to save: those still as loaded
+// are written as they were before overrides were applied
+func (c *Config) withoutOverrides() map[string]Identity {
+	identities := make(map[string]Identity, len(c.Identities))
+	for name, identity := range c.Identities {
+		if o, ok := c.overlays[name]; ok && sameIdentity(identity, o.merged) {
+			identity = o.base
+		}
+		identities[name] = identity
+	}
+	return identities
+}
+
+// sameIdentity compares identities ignoring state derived from the rest of
+// the config
+func sameIdentity(a, b Identity) bool {
+	a.sharedDomain, b.sharedDomain = false, false
+	a.Name, b.Name = "", ""
+	return reflect.DeepEqual(a, b)
+}
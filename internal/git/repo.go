@@ -0,0 +1,133 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RunGit runs a git command in dir and returns its trimmed stdout
+func RunGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsGitRepo checks if dir is inside a git work tree
+func IsGitRepo(dir string) bool {
+	_, err := RunGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil
+}
+
+// HasCommits checks if the repository in dir has at least one commit
+func HasCommits(dir string) bool {
+	_, err := RunGit(dir, "rev-parse", "--verify", "HEAD")
+	return err == nil
+}
+
+// GetRemoteURL returns the URL of a remote, or empty string if not set
+func GetRemoteURL(dir, remote string) string {
+	url, err := RunGit(dir, "remote", "get-url", remote)
+	if err != nil {
+		return ""
+	}
+	return url
+}
+
+// SetRemoteURL adds the remote or updates its URL if it already exists
+func SetRemoteURL(dir, remote, url string) error {
+	if GetRemoteURL(dir, remote) == "" {
+		_, err := RunGit(dir, "remote", "add", remote, url)
+		return err
+	}
+	_, err := RunGit(dir, "remote", "set-url", remote, url)
+	return err
+}
+
+// Remotes returns the configured URL of each remote in dir, keyed by remote
+// name. Unlike GetRemoteURL, insteadOf rewrites are not applied.
+func Remotes(dir string) (map[string]string, error) {
+	remotes := make(map[string]string)
+	output, err := RunGit(dir, "config", "--get-regexp", `^remote\..*\.url$`)
+	if err != nil {
+		// Exit status 1 means no remotes
+		return remotes, nil
+	}
+
+	for line := range strings.SplitSeq(output, "\n") {
+		key, url, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".url")
+		remotes[name] = url
+	}
+	return remotes, nil
+}
+
+// ParseSSHRemote splits an SSH remote URL (git@host:owner/repo.git or
+// ssh://git@host/owner/repo.git) into host and repository path without .git
+func ParseSSHRemote(url string) (host, repoPath string, ok bool) {
+	if rest, found := strings.CutPrefix(url, "ssh://"); found {
+		_, rest, _ = strings.Cut(rest, "@")
+		host, repoPath, ok = strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host, ":")
+	} else if user, rest, found := strings.Cut(url, "@"); found && !strings.Contains(user, "/") {
+		host, repoPath, ok = strings.Cut(rest, ":")
+	}
+	if !ok || host == "" || repoPath == "" {
+		return "", "", false
+	}
+	return host, strings.TrimSuffix(repoPath, ".git"), true
+}
+
+// ParseRepoURL splits an SSH or HTTPS repository URL into host and repository
+// path without .git
+func ParseRepoURL(url string) (host, repoPath string, ok bool) {
+	if host, repoPath, ok := ParseSSHRemote(url); ok {
+		return host, repoPath, true
+	}
+
+	rest, found := strings.CutPrefix(url, "https://")
+	if !found {
+		rest, found = strings.CutPrefix(url, "http://")
+	}
+	if !found {
+		return "", "", false
+	}
+	host, repoPath, ok = strings.Cut(rest, "/")
+	repoPath = strings.TrimSuffix(strings.TrimSuffix(repoPath, "/"), ".git")
+	if !ok || host == "" || repoPath == "" {
+		return "", "", false
+	}
+	return host, repoPath, true
+}
+
+// Clone clones url into dest using the identity's key, streaming git's output
+func Clone(identity Identity, url, dest string) error {
+	cmd := exec.Command("git", "clone", url, dest)
+	// The includeIf for dest only applies once the repository exists
+	cmd.Env = append(os.Environ(), fmt.Sprintf("GIT_SSH_COMMAND=%s -o IdentitiesOnly=yes", identity.SSHCommand()))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// EventKind says what a sync event describes
+type EventKind string
+
+const (
+	// EventSection starts a phase of the sync, e.g. one identity
+	EventSection EventKind = "section"
+	// EventStep is the outcome of a single step within the current section
+	EventStep EventKind = "step"
+	// EventDone is the last event and carries the result
+	EventDone EventKind = "done"
+)
+
+// Level is the outcome of a step
+type Level string
+
+const (
+	LevelInfo  Level = "info"
+	LevelOK    Level = "ok"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+	// LevelDebug carries executed commands and their output
+	LevelDebug Level = "debug"
+)
+
+// Verbosity controls how much a console reporter prints
+type Verbosity int
+
+const (
+	// VerbosityQuiet prints errors only
+	VerbosityQuiet Verbosity = iota - 1
+	VerbosityNormal
+	// VerbosityVerbose adds executed commands and their output
+	VerbosityVerbose
+)
+
+// Event is a unit of sync progress
+type Event struct {
+	Kind     EventKind `json:"kind"`
+	Level    Level     `json:"level,omitempty"`
+	Identity string    `json:"identity,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	// Hints are follow-up suggestions for a step, e.g. how to fix a warning
+	Hints  []string    `json:"hints,omitempty"`
+	Result *SyncResult `json:"result,omitempty"`
+}
+
+// Reporter receives sync progress. Sync calls Report from a single goroutine.
+type Reporter interface {
+	Report(Event)
+}
+
+// ReporterFunc adapts a function to a Reporter
+type ReporterFunc func(Event)
+
+func (f ReporterFunc) Report(e Event) { f(e) }
+
+// consoleReporter renders events as the indented, symbol-prefixed text
+// zzk prints on the terminal
+type consoleReporter struct {
+	out       io.Writer
+	verbosity Verbosity
+	started   bool
+}
+
+// NewConsoleReporter returns a Reporter that writes human-readable progress to out
+func NewConsoleReporter(out io.Writer, verbosity Verbosity) Reporter {
+	return &consoleReporter{out: out, verbosity: verbosity}
+}
+
+func (r *consoleReporter) Report(e Event) {
+	if r.verbosity == VerbosityQuiet {
+		if e.Kind == EventStep && e.Level == LevelError {
+			fmt.Fprintf(r.out, "✗ %s\n", e.Message)
+		}
+		return
+	}
+	if e.Level == LevelDebug {
+		if r.verbosity == VerbosityVerbose {
+			for line := range strings.Lines(e.Message) {
+				fmt.Fprintf(r.out, "    %s\n", strings.TrimRight(line, "\n"))
+			}
+		}
+		return
+	}
+
+	switch e.Kind {
+	case EventSection:
+		if r.started {
+			fmt.Fprintln(r.out)
+		}
+		r.started = true
+		fmt.Fprintln(r.out, e.Message)
+	case EventStep:
+		fmt.Fprintf(r.out, "  %s %s\n", levelSymbol(e.Level), e.Message)
+		for _, hint := range e.Hints {
+			fmt.Fprintf(r.out, "    → %s\n", hint)
+		}
+	case EventDone:
+		if r.started {
+			fmt.Fprintln(r.out)
+		}
+		if e.Result != nil {
+			printSyncSummary(r.out, e.Result)
+		}
+	}
+}
+
+func levelSymbol(level Level) string {
+	switch level {
+	case LevelOK:
+		return "✓"
+	case LevelWarn:
+		return "⚠"
+	case LevelError:
+		return "✗"
+	default:
+		return "ℹ"
+	}
+}
+
+// reportCommand emits a command line as a debug event
+func reportCommand(r Reporter, identity string, cmd *exec.Cmd) {
+	r.Report(Event{Kind: EventStep, Level: LevelDebug, Identity: identity, Message: "$ " + commandLine(cmd)})
+}
+
+// reportOutput emits command output as a debug event, if there is any
+func reportOutput(r Reporter, identity string, output []byte) {
+	if text := strings.TrimSpace(string(output)); text != "" {
+		r.Report(Event{Kind: EventStep, Level: LevelDebug, Identity: identity, Message: text})
+	}
+}
+
+// commandLine renders cmd's arguments the way a shell would need them
+func commandLine(cmd *exec.Cmd) string {
+	args := make([]string, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'[]*?$") {
+			arg = strconv.Quote(arg)
+		}
+		args[i] = arg
+	}
+	return strings.Join(args, " ")
+}
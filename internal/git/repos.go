@@ -0,0 +1,61 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// syncRepoDepth is how deep sync looks for repositories to record, matching
+// the commands' --depth default
+const syncRepoDepth = 3
+
+// RepoState is what zzk last saw of a repository in an identity's folders
+type RepoState struct {
+	Remotes map[string]string `json:"remotes,omitempty"`
+	// LastSeen is when sync or audit last found the repository
+	LastSeen time.Time `json:"lastSeen"`
+	// LastVerified is when audit last found its config matching the identity
+	LastVerified time.Time `json:"lastVerified,omitzero"`
+}
+
+// RecordRepos replaces the repositories recorded for an identity with repos,
+// all found just now, and their remotes. Those in verified passed an audit
+// just now; the others keep the time they were last verified.
+func (s *State) RecordRepos(name string, repos, verified []string) {
+	identityState := s.Identities[name]
+	if identityState == nil {
+		identityState = &IdentityState{}
+		s.Identities[name] = identityState
+	}
+
+	now := time.Now()
+	recorded := make(map[string]*RepoState, len(repos))
+	for _, repo := range repos {
+		key := tildePath(repo)
+		repoState := &RepoState{LastSeen: now}
+		if previous, ok := identityState.Repos[key]; ok {
+			repoState.LastVerified = previous.LastVerified
+		}
+		if slices.Contains(verified, repo) {
+			repoState.LastVerified = now
+		}
+		repoState.Remotes, _ = Remotes(repo)
+		recorded[key] = repoState
+	}
+	identityState.Repos = recorded
+}
+
+// tildePath writes paths under the home directory with ~/, as the state does
+func tildePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -1,15 +1,20 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"golang.org/x/crypto/ssh"
 )
 
-func GenerateSSHKey(identity Identity) error {
+// GenerateSSHKey creates the identity's key pair, reporting the ssh-keygen
+// command and its output as debug events
+func GenerateSSHKey(identity Identity, reporter Reporter) error {
 	keyPath := ExpandPath(identity.SSHKeyPath())
 	pubKeyPath := ExpandPath(identity.SSHPubKeyPath())
 
@@ -21,17 +26,27 @@ func GenerateSSHKey(identity Identity) error {
 		return fmt.Errorf("failed to create .ssh directory: %w", err)
 	}
 
-	cmd := exec.Command("ssh-keygen",
-		"-t", "ed25519",
+	args := []string{"-t", identity.SSHKeyType()}
+	if identity.SSHKeyType() == KeyTypeRSA4096 {
+		args = []string{"-t", "rsa", "-b", "4096"}
+	}
+	for _, option := range identity.KeyOptions {
+		args = append(args, "-O", option)
+	}
+	args = append(args,
 		"-C", identity.SSHKeyComment(),
 		"-f", keyPath,
 		"-N", "",
 	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to generate SSH key: %w", err)
+	cmd := exec.Command("ssh-keygen", args...)
+	// Security keys may ask for a PIN
+	cmd.Stdin = os.Stdin
+	reportCommand(reporter, identity.Name, cmd)
+	output, err := cmd.CombinedOutput()
+	reportOutput(reporter, identity.Name, output)
+	if err != nil {
+		return fmt.Errorf("failed to generate SSH key: %w", commandError(err, output))
 	}
 
 	return nil
@@ -47,6 +62,49 @@ func SSHKeyExists(identity Identity) bool {
 	return err1 == nil && err2 == nil
 }
 
+// SSHKeyTypeMatches reports whether the existing public key has the configured
+// key type, returning the algorithm it actually has
+func SSHKeyTypeMatches(identity Identity) (bool, string) {
+	data, err := os.ReadFile(ExpandPath(identity.SSHPubKeyPath()))
+	if err != nil {
+		return true, ""
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return true, ""
+	}
+	return fields[0] == identity.SSHKeyAlgorithm(), fields[0]
+}
+
+// SSHKeyFingerprint returns the SHA256 fingerprint of the identity's public
+// key, in the form ssh-keygen -l prints
+func SSHKeyFingerprint(identity Identity) (string, error) {
+	data, err := os.ReadFile(ExpandPath(identity.SSHPubKeyPath()))
+	if err != nil {
+		return "", err
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", identity.SSHPubKeyPath(), err)
+	}
+	return ssh.FingerprintSHA256(key), nil
+}
+
+// KeyDrift compares the key on disk with the fingerprint recorded at the last
+// sync. It returns the current fingerprint and whether it differs; keys
+// without a recorded SHA256 fingerprint never count as drifted.
+func KeyDrift(identity Identity, state *State) (string, bool) {
+	current, err := SSHKeyFingerprint(identity)
+	if err != nil || state == nil {
+		return current, false
+	}
+	identityState, ok := state.Identities[identity.Name]
+	if !ok || !strings.HasPrefix(identityState.SSHKeyFingerprint, "SHA256:") {
+		return current, false
+	}
+	return current, identityState.SSHKeyFingerprint != current
+}
+
 func IsZZKManagedKey(pubKeyPath string) (bool, string) {
 	data, err := os.ReadFile(pubKeyPath)
 	if err != nil {
@@ -133,37 +191,105 @@ func CopyPublicKeyToHome(identity Identity) (bool, error) {
 	return true, nil
 }
 
-func AddKeyToSSHAgent(identity Identity) error {
+// AddKeyToSSHAgent adds the identity's key to its agent, replacing a copy
+// added earlier
+func AddKeyToSSHAgent(identity Identity, reporter Reporter) error {
 	keyPath := ExpandPath(identity.SSHKeyPath())
 
-	exec.Command("ssh-add", "-d", keyPath).Run()
+	// gpg-agent and other agents are reached through their own socket
+	var env []string
+	if socket := identity.AgentSocket(); socket != "" {
+		env = append(os.Environ(), "SSH_AUTH_SOCK="+ExpandPath(socket))
+	}
 
-	cmd := exec.Command("ssh-add", keyPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	remove := exec.Command("ssh-add", "-d", keyPath)
+	remove.Env = env
+	remove.Run()
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add key to SSH agent: %w", err)
+	args := []string{keyPath}
+	if identity.usesKeychain() {
+		// Store the passphrase so the key is loaded again after a reboot
+		args = append([]string{"--apple-use-keychain"}, args...)
+	}
+	cmd := exec.Command("ssh-add", args...)
+	cmd.Env = env
+	reportCommand(reporter, identity.Name, cmd)
+	output, err := cmd.CombinedOutput()
+	reportOutput(reporter, identity.Name, output)
+	if err != nil {
+		return commandError(err, output)
 	}
 
 	return nil
 }
 
-func TestSSHConnection(identity Identity, fromDir string) error {
-	originalDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+// commandError prefers a command's own last line of output over its exit status
+func commandError(err error, output []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("%s", last)
+	}
+	return err
+}
+
+// TestSSHConnection checks that the identity's key authenticates with its
+// domain, returning the keys ssh offered. It runs non-interactively, so it is
+// safe to call concurrently; unknown host keys are accepted on first use.
+func TestSSHConnection(ctx context.Context, identity Identity) ([]string, error) {
+	cmd := sshTestCommand(ctx, identity)
+	output, err := cmd.CombinedOutput()
+	return offeredKeys(output), sshTestResult(ctx, identity, output, err)
+}
+
+// sshTestCommand builds the connection test command. It offers only the
+// identity's key and ignores ~/.ssh/config, whose Host blocks could add other
+// keys; the identity's own SSH options are passed on the command line.
+func sshTestCommand(ctx context.Context, identity Identity) *exec.Cmd {
+	args := []string{"-T", "-v",
+		"-F", os.DevNull,
+		"-i", ExpandPath(identity.SSHKeyPath()),
+		"-o", "IdentitiesOnly=yes",
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=10",
+		"-o", "StrictHostKeyChecking=accept-new",
 	}
-	defer os.Chdir(originalDir)
+	args = append(args, identity.SSHOptionArgs()...)
+	return exec.CommandContext(ctx, "ssh", append(args, fmt.Sprintf("git@%s", identity.Domain))...)
+}
 
-	if err := os.Chdir(fromDir); err != nil {
-		return fmt.Errorf("failed to change to directory %s: %w", fromDir, err)
+// offeredKeys returns the public keys ssh -v reports offering, as
+// "path (fingerprint)"
+func offeredKeys(output []byte) []string {
+	var keys []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		_, offered, ok := strings.Cut(line, "Offering public key: ")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(offered)
+		if len(fields) >= 3 {
+			keys = append(keys, fmt.Sprintf("%s (%s)", tildePath(fields[0]), fields[2]))
+		} else if len(fields) > 0 {
+			keys = append(keys, tildePath(fields[0]))
+		}
 	}
+	return keys
+}
 
-	cmd := exec.Command("ssh", "-T", fmt.Sprintf("git@%s", identity.Domain))
-	output, err := cmd.CombinedOutput()
+// sshTestResult interprets the connection test's output
+func sshTestResult(ctx context.Context, identity Identity, output []byte, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out connecting to %s", identity.Domain)
+	}
 
-	outputStr := string(output)
+	// Leave out ssh -v's debug lines
+	var lines []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		if !strings.HasPrefix(line, "debug") && !strings.HasPrefix(line, "OpenSSH_") {
+			lines = append(lines, line)
+		}
+	}
+	outputStr := strings.Join(lines, "\n")
 
 	successPatterns := []string{
 		"successfully authenticated",
@@ -183,7 +309,7 @@ func TestSSHConnection(identity Identity, fromDir string) error {
 	}
 
 	if err != nil {
-		return fmt.Errorf("SSH test failed: %s", outputStr)
+		return fmt.Errorf("SSH test failed: %s", strings.TrimSpace(outputStr))
 	}
 
 	return nil
@@ -18,6 +18,31 @@ type State struct {
 type IdentityState struct {
 	LastSync          time.Time `json:"lastSync"`
 	SSHKeyFingerprint string    `json:"sshKeyFingerprint,omitempty"`
+	// Artifacts are what zzk created for the identity, so cleanup removes
+	// exactly those
+	Artifacts Artifacts `json:"artifacts,omitzero"`
+	// Repos are the repositories last found in the identity's folders, keyed
+	// by path
+	Repos map[string]*RepoState `json:"repos,omitempty"`
+}
+
+// Artifacts records what zzk created for an identity. Paths use ~/ so the
+// state stays valid when copied between machines.
+type Artifacts struct {
+	// Files are removed on cleanup
+	Files []string `json:"files,omitempty"`
+	// Folders were created by zzk and are removed on cleanup if empty
+	Folders []string `json:"folders,omitempty"`
+	// SSHHosts are the identity's Host entries in the managed ~/.ssh/config block
+	SSHHosts []string `json:"sshHosts,omitempty"`
+	// GitIncludes are the identity's includeIf conditions in the zzk gitconfig
+	GitIncludes []string `json:"gitIncludes,omitempty"`
+}
+
+// IsZero reports whether nothing was recorded, as in state written by
+// versions that didn't track artifacts
+func (a Artifacts) IsZero() bool {
+	return len(a.Files) == 0 && len(a.Folders) == 0 && len(a.SSHHosts) == 0 && len(a.GitIncludes) == 0
 }
 
 // LoadState loads the state file or creates a new one if it doesn't exist
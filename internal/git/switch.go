@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// switchKey records in a repo's local config which identity 'zzk git switch'
+// applied, so detection works outside the identity's folders
+const switchKey = "zzk.identity"
+
+// SwitchChange is one setting 'zzk git switch' changes in a repository
+type SwitchChange struct {
+	Field string
+	Got   string
+	Want  string
+}
+
+// SwitchedIdentity returns the identity name 'zzk git switch' recorded for
+// the repository containing dir, or "" if none
+func SwitchedIdentity(dir string) string {
+	name, err := RunGit(dir, "config", "--local", switchKey)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// SwitchChanges lists what applying identity to repo would change: the user
+// and signing key in its local config, the SSH command and the SSH remotes
+// on the identity's domain
+func SwitchChanges(identity Identity, repo string) ([]SwitchChange, error) {
+	var changes []SwitchChange
+	settings := [][2]string{
+		{"user.name", identity.User},
+		{"user.email", identity.Email},
+		{"user.signingkey", identity.SSHKeyPath()},
+		{"core.sshCommand", identity.SSHCommand()},
+		{switchKey, identity.Name},
+	}
+	for _, setting := range settings {
+		got, _ := RunGit(repo, "config", "--local", setting[0])
+		if got != setting[1] {
+			changes = append(changes, SwitchChange{Field: setting[0], Got: got, Want: setting[1]})
+		}
+	}
+
+	remotes, err := remoteChanges(identity, repo)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, remotes...), nil
+}
+
+// remoteChanges lists the SSH remotes of repo on the identity's domain or its
+// aliases that don't use the identity's host
+func remoteChanges(identity Identity, repo string) ([]SwitchChange, error) {
+	remotes, err := Remotes(repo)
+	if err != nil {
+		return nil, err
+	}
+	var changes []SwitchChange
+	for _, name := range slices.Sorted(maps.Keys(remotes)) {
+		url := remotes[name]
+		host, repoPath, ok := ParseSSHRemote(url)
+		if !ok || host == identity.SSHHost() {
+			continue
+		}
+		// Only touch the identity's domain and its aliases
+		if host != identity.Domain && !strings.HasPrefix(host, identity.Domain+"-") {
+			continue
+		}
+		changes = append(changes, SwitchChange{Field: "remote." + name + ".url", Got: url, Want: identity.RemoteURL(repoPath)})
+	}
+	return changes, nil
+}
+
+// ApplySwitchChange writes one change from SwitchChanges to repo
+func ApplySwitchChange(repo string, change SwitchChange) error {
+	if name, ok := strings.CutPrefix(change.Field, "remote."); ok {
+		return SetRemoteURL(repo, strings.TrimSuffix(name, ".url"), change.Want)
+	}
+	if _, err := RunGit(repo, "config", "--local", change.Field, change.Want); err != nil {
+		return fmt.Errorf("failed to set %s: %w", change.Field, err)
+	}
+	return nil
+}
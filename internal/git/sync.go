@@ -1,231 +1,543 @@
 package git
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
-	"path/filepath"
+	"os/exec"
 	"slices"
 	"strings"
+	"sync"
 	"time"
-
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
 type SyncResult struct {
-	OrphansRemoved []string
-	Created        []string
-	Updated        []string
-	Verified       []string
-	Failed         map[string]error
+	OrphansRemoved []string `json:"orphans_removed"`
+	Created        []string `json:"created"`
+	Updated        []string `json:"updated"`
+	Verified       []string `json:"verified"`
+	// Unverified maps identities whose connection test failed to the reason
+	Unverified map[string]string `json:"unverified"`
+	Failed     map[string]error  `json:"-"`
 }
 
-func Sync(config *Config) (*SyncResult, error) {
+// MarshalJSON encodes failures as messages, since errors don't marshal
+func (r *SyncResult) MarshalJSON() ([]byte, error) {
+	type plain SyncResult
+	failed := make(map[string]string, len(r.Failed))
+	for name, err := range r.Failed {
+		failed[name] = err.Error()
+	}
+	return json.Marshal(struct {
+		*plain
+		Failed map[string]string `json:"failed"`
+	}{(*plain)(r), failed})
+}
+
+// SyncOptions controls how Sync reports progress
+type SyncOptions struct {
+	// Reporter receives progress events; defaults to a console reporter on Out
+	Reporter Reporter
+	// Out receives console progress when no Reporter is set; defaults to stdout
+	Out io.Writer
+	// Verbosity is used for the default console reporter
+	Verbosity Verbosity
+	// Identities limits the sync to these identities and skips orphan
+	// cleanup. Global configs are still rewritten from the full config.
+	Identities []string
+	// NoHooks skips the git-pre-sync and git-post-sync hooks
+	NoHooks bool
+	// PruneOnly removes orphans and rewrites the global configs without
+	// them, skipping the identities themselves
+	PruneOnly bool
+	// NoPrune reports orphans but never removes them
+	NoPrune bool
+	// Parallel is how many identities are set up, and how many SSH
+	// connection tests run, at once; defaults to 4
+	Parallel int
+	// TestTimeout bounds each SSH connection test; defaults to 15s
+	TestTimeout time.Duration
+}
+
+func Sync(config *Config, opts SyncOptions) (*SyncResult, error) {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	reporter := opts.Reporter
+	if reporter == nil {
+		reporter = NewConsoleReporter(out, opts.Verbosity)
+	}
+	section := func(identity, message string) {
+		reporter.Report(Event{Kind: EventSection, Identity: identity, Message: message})
+	}
+	step := func(identity string, level Level, message string, hints ...string) {
+		reporter.Report(Event{Kind: EventStep, Level: level, Identity: identity, Message: message, Hints: hints})
+	}
+
 	result := &SyncResult{
 		OrphansRemoved: []string{},
 		Created:        []string{},
 		Updated:        []string{},
 		Verified:       []string{},
+		Unverified:     make(map[string]string),
 		Failed:         make(map[string]error),
 	}
 
+	if opts.PruneOnly && (opts.NoPrune || len(opts.Identities) > 0) {
+		return nil, errors.New("pruning only can't be combined with --no-prune or an identity")
+	}
+
+	// Identities may have been added since the config was loaded
+	config.markSharedDomains()
+
 	// Load state file (or create new one)
 	state, err := LoadState()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load state: %w", err)
 	}
 
-	fmt.Println("Reading config:", ConfigPath())
-	fmt.Printf("Found %d identities: %s\n\n", len(config.Identities), identityNames(config))
+	identities := config.Identities
+	if len(opts.Identities) > 0 {
+		identities = make(map[string]Identity)
+		for _, name := range opts.Identities {
+			identity, ok := config.GetIdentity(name)
+			if !ok {
+				return nil, fmt.Errorf("identity '%s' not found", name)
+			}
+			identities[name] = identity
+		}
+	}
 
-	fmt.Println("Detecting orphans...")
-	orphans, err := detectOrphans(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to detect orphans: %w", err)
+	hookIdentities := slices.Sorted(maps.Keys(identities))
+	if path := hookPath(HookPreSync); path != "" && !opts.NoHooks {
+		section("", "Running "+HookPreSync+" hook...")
+		if err := runHook(reporter, path, hookIdentities, nil); err != nil {
+			return nil, fmt.Errorf("%s hook failed: %w", HookPreSync, err)
+		}
+		step("", LevelOK, "Ran "+path)
+	}
+
+	section("", "Reading config: "+ConfigPath())
+	step("", LevelInfo, fmt.Sprintf("Found %d identities: %s", len(config.Identities), identityNames(config)))
+
+	var orphans []string
+	if len(opts.Identities) > 0 {
+		section("", fmt.Sprintf("Syncing %s only, skipping orphan detection", strings.Join(opts.Identities, ", ")))
+	} else {
+		section("", "Detecting orphans...")
+		orphans, err = detectOrphans(config, state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect orphans: %w", err)
+		}
 	}
 
 	// If orphans found, backup before removing
-	if len(orphans) > 0 {
-		fmt.Printf("  Found %d orphaned identities: %s\n", len(orphans), strings.Join(orphans, ", "))
+	if len(orphans) > 0 && opts.NoPrune {
+		step("", LevelWarn, fmt.Sprintf("Found %d orphaned identities, not removing them (--no-prune): %s", len(orphans), strings.Join(orphans, ", ")),
+			"Remove them with 'zzk git sync --prune-only'")
+	} else if len(orphans) > 0 {
+		step("", LevelInfo, fmt.Sprintf("Found %d orphaned identities: %s", len(orphans), strings.Join(orphans, ", ")))
 
-		// Collect files to backup
-		filesToBackup := []string{}
 		for _, orphan := range orphans {
-			home, _ := os.UserHomeDir()
-			keyPath := filepath.Join(home, ".ssh", fmt.Sprintf("%s_key", orphan))
-			pubKeyPath := keyPath + ".pub"
-			configPath := filepath.Join(home, fmt.Sprintf(".gitconfig-%s", orphan))
-
-			if _, err := os.Stat(keyPath); err == nil {
-				filesToBackup = append(filesToBackup, keyPath)
+			artifacts := identityArtifacts(state, orphan)
+			backupPath, err := backupArtifacts(artifacts, "orphan-cleanup")
+			if backupPath != "" {
+				step(orphan, LevelInfo, "Backed up orphaned files to: "+backupPath)
 			}
-			if _, err := os.Stat(pubKeyPath); err == nil {
-				filesToBackup = append(filesToBackup, pubKeyPath)
-			}
-			if _, err := os.Stat(configPath); err == nil {
-				filesToBackup = append(filesToBackup, configPath)
-			}
-		}
-
-		// Create backup if there are files to backup
-		if len(filesToBackup) > 0 {
-			backupPath, err := BackupFiles(filesToBackup, "orphan-cleanup")
 			if err != nil {
-				fmt.Printf("  ⚠ Warning: failed to create backup: %v\n", err)
-			} else {
-				fmt.Printf("  ℹ Backed up orphaned files to: %s\n", backupPath)
-				home, _ := os.UserHomeDir()
-				backupDir := filepath.Join(home, ".config", "zzk", "backups")
-				if err := RotateBackups(backupDir, 10); err != nil {
-					fmt.Printf("  ⚠ Warning: failed to rotate backups: %v\n", err)
+				if backupPath == "" {
+					step(orphan, LevelWarn, fmt.Sprintf("Warning: failed to create backup, keeping %s: %v", orphan, err))
+					continue
 				}
+				step(orphan, LevelWarn, fmt.Sprintf("Warning: %v", err))
 			}
-		}
 
-		// Remove orphans
-		for _, orphan := range orphans {
-			if err := cleanupIdentity(orphan); err != nil {
-				fmt.Printf("  ⚠ Warning: failed to clean up %s: %v\n", orphan, err)
+			if err := cleanupIdentity(artifacts); err != nil {
+				step(orphan, LevelWarn, fmt.Sprintf("Warning: failed to clean up %s: %v", orphan, err))
 			} else {
-				fmt.Printf("  ✓ Removed orphan: %s\n", orphan)
+				step(orphan, LevelOK, "Removed orphan: "+orphan)
 				result.OrphansRemoved = append(result.OrphansRemoved, orphan)
 				// Remove from state
 				delete(state.Identities, orphan)
 			}
 		}
-	} else {
-		fmt.Println("  No orphans found")
+	} else if len(opts.Identities) == 0 {
+		step("", LevelInfo, "No orphans found")
 	}
-	fmt.Println()
 
-	for _, identity := range config.Identities {
-		fmt.Printf("Processing: %s\n", identity.Name)
+	if opts.PruneOnly {
+		identities = nil
+	}
 
-		for _, folder := range identity.Folders {
-			expandedFolder := ExpandPath(folder)
-			if err := os.MkdirAll(expandedFolder, 0755); err != nil {
-				fmt.Printf("  ⚠ Warning: failed to create folder %s: %v\n", folder, err)
-			} else {
-				if _, err := os.Stat(expandedFolder); err == nil {
-					fmt.Printf("  ✓ Folder exists: %s\n", folder)
-				} else {
-					fmt.Printf("  ✓ Created folder: %s\n", folder)
-				}
-			}
-		}
+	// Identities are set up concurrently. Each one's events are buffered and
+	// reported in order once it's done, so the output stays grouped.
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = 4
+	}
+	reporter = &lockedReporter{reporter: reporter}
+	names := slices.Sorted(maps.Keys(identities))
+	outcomes := make([]*identitySync, len(names))
+	done := make([]chan struct{}, len(names))
+	sem := make(chan struct{}, parallel)
+	var interactive sync.Mutex
+	for i, name := range names {
+		done[i] = make(chan struct{})
+		go func() {
+			defer close(done[i])
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			outcomes[i] = syncIdentity(identities[name], state, reporter, &interactive)
+		}()
+	}
 
-		keyWasCreated := false
-		if !SSHKeyExists(identity) {
-			if err := GenerateSSHKey(identity); err != nil {
-				fmt.Printf("  ✗ Failed to generate SSH key: %v\n", err)
-				result.Failed[identity.Name] = err
-				fmt.Println()
-				continue
-			}
-			fmt.Printf("  ✓ Generated SSH key: %s [zzk:%s]\n", identity.SSHKeyPath(), identity.Name)
-			result.Created = append(result.Created, identity.Name)
-			keyWasCreated = true
-		} else {
-			fmt.Printf("  ✓ SSH key exists: %s [zzk:%s]\n", identity.SSHKeyPath(), identity.Name)
+	// Folders and files this run created, and the repositories found, recorded
+	// in the state
+	createdFolders := make(map[string][]string)
+	createdFiles := make(map[string][]string)
+	identityRepos := make(map[string][]string)
+
+	var tests []*connectionTest
+	for i, name := range names {
+		<-done[i]
+		outcome := outcomes[i]
+		for _, event := range outcome.events {
+			reporter.Report(event)
 		}
-
-		// Only copy public key if a new key was just created
-		if keyWasCreated {
-			copied, err := CopyPublicKeyToHome(identity)
-			if err != nil {
-				fmt.Printf("  ⚠ Warning: failed to copy public key: %v\n", err)
-			} else if copied {
-				fmt.Printf("  ✓ Copied public key to ~/%s_key.pub\n", identity.Name)
-			}
+		createdFolders[name] = outcome.folders
+		createdFiles[name] = outcome.files
+		identityRepos[name] = outcome.repos
+		if outcome.created {
+			result.Created = append(result.Created, name)
 		}
-
-		if err := CreateIdentityGitConfig(identity); err != nil {
-			fmt.Printf("  ✗ Failed to create git config: %v\n", err)
-			result.Failed[identity.Name] = err
-			fmt.Println()
+		if outcome.err != nil {
+			result.Failed[name] = outcome.err
 			continue
 		}
-		fmt.Printf("  ✓ Updated %s\n", identity.GitConfigPath())
-
-		if err := AddKeyToSSHAgent(identity); err != nil {
-			fmt.Printf("  ⚠ Warning: failed to add key to SSH agent: %v\n", err)
-		} else {
-			fmt.Printf("  ✓ Added key to SSH agent\n")
+		if !outcome.created {
+			result.Updated = append(result.Updated, name)
 		}
+		if outcome.test != nil {
+			tests = append(tests, outcome.test)
+		}
+	}
 
-		var testFromDir string
-		for _, folder := range identity.Folders {
-			expandedFolder := ExpandPath(folder)
-			if _, err := os.Stat(expandedFolder); err == nil {
-				testFromDir = expandedFolder
-				break
-			}
+	// Known host keys let the connection tests and first clones run unprompted
+	var domains []string
+	for _, identity := range identities {
+		if !identity.hasCustomRoute() && !slices.Contains(domains, identity.Domain) {
+			domains = append(domains, identity.Domain)
+		}
+	}
+	slices.Sort(domains)
+	if len(domains) > 0 {
+		section("", "Checking SSH host keys...")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	mismatched := make(map[string]bool)
+	for _, host := range SeedKnownHosts(ctx, domains) {
+		switch {
+		case errors.Is(host.Err, ErrHostKeyMismatch):
+			step("", LevelError, fmt.Sprintf("%s: %v", host.Domain, host.Err),
+				"Something may be intercepting your connection; connection tests for it are skipped")
+			mismatched[host.Domain] = true
+		case host.Err != nil:
+			step("", LevelWarn, fmt.Sprintf("%s: %v", host.Domain, host.Err))
+		case host.AlreadyKnown:
+			step("", LevelOK, host.Domain+": already in ~/.ssh/known_hosts")
+		case host.Pinned:
+			step("", LevelOK, fmt.Sprintf("%s: added %d host keys matching the published fingerprints", host.Domain, host.Added))
+		default:
+			step("", LevelInfo, fmt.Sprintf("%s: added %d host keys (not pinned, trusted on first use)", host.Domain, host.Added))
 		}
+	}
+	cancel()
 
-		if testFromDir != "" {
-			fmt.Printf("  Testing SSH connection to %s...\n", identity.Domain)
-			if err := TestSSHConnection(identity, testFromDir); err != nil {
-				fmt.Printf("  ⚠ SSH test failed: %v\n", err)
-				fmt.Printf("    → Your SSH key may not be added to %s yet\n", identity.Domain)
-				fmt.Printf("    → Add it: cat %s | pbcopy\n", identity.SSHPubKeyPath())
+	// Don't let accept-new trust a host key that failed verification
+	tests = slices.DeleteFunc(tests, func(test *connectionTest) bool {
+		if mismatched[test.identity.Domain] {
+			result.Unverified[test.identity.Name] = "host key doesn't match the published fingerprints"
+			return true
+		}
+		return false
+	})
+
+	if len(tests) > 0 {
+		section("", fmt.Sprintf("Testing SSH connections (%d)...", len(tests)))
+		runConnectionTests(tests, opts.Parallel, opts.TestTimeout)
+		for _, test := range tests {
+			identity := test.identity
+			reportCommand(reporter, identity.Name, test.cmd)
+			reportOutput(reporter, identity.Name, test.output)
+			var offered []string
+			if len(test.offered) > 0 {
+				offered = append(offered, "Offered "+strings.Join(test.offered, ", "))
+			}
+			if test.err != nil {
+				if len(test.offered) == 0 && strings.Contains(test.err.Error(), "permission denied") {
+					offered = append(offered, "No key was offered; check that "+identity.SSHKeyPath()+" is readable")
+				}
+				step(identity.Name, LevelWarn, fmt.Sprintf("%s: %v", identity.Name, test.err),
+					append(offered,
+						fmt.Sprintf("Your SSH key may not be added to %s yet", identity.Domain),
+						fmt.Sprintf("Add it: cat %s | pbcopy", identity.SSHPubKeyPath()))...)
+				result.Unverified[identity.Name] = test.err.Error()
 			} else {
-				fmt.Printf("  ✓ SSH connection verified\n")
+				step(identity.Name, LevelOK, identity.Name+": connection verified", offered...)
 				result.Verified = append(result.Verified, identity.Name)
 			}
-		} else {
-			fmt.Printf("  ⚠ SSH test skipped (no valid folders)\n")
 		}
-
-		fmt.Println()
 	}
 
-	fmt.Println("Updating global configurations...")
+	section("", "Updating global configurations...")
 	if err := UpdateGlobalGitConfig(config); err != nil {
 		return nil, fmt.Errorf("failed to update global git config: %w", err)
 	}
-	fmt.Println("  ✓ Updated ~/.gitconfig")
+	step("", LevelOK, "Updated ~/.gitconfig")
 
-	if err := UpdateSSHConfig(config); err != nil {
+	overrides, err := UpdateSSHConfig(config)
+	if err != nil {
 		return nil, fmt.Errorf("failed to update SSH config: %w", err)
 	}
-	fmt.Println("  ✓ Updated ~/.ssh/config")
+	step("", LevelOK, "Updated ~/.ssh/config")
+	for _, host := range overrides {
+		step("", LevelWarn, fmt.Sprintf("Your own 'Host %s' entry comes before zzk's block, so its settings take precedence", host))
+	}
 
 	if err := UpdateAllowedSigners(config); err != nil {
 		return nil, fmt.Errorf("failed to update allowed signers: %w", err)
 	}
-	fmt.Println("  ✓ Updated ~/.ssh/allowed_signers")
-	fmt.Println()
+	step("", LevelOK, "Updated ~/.ssh/allowed_signers")
 
 	// Update state file with sync timestamps
 	state.LastSync = time.Now()
-	for _, identity := range config.Identities {
-		fingerprint := getSSHKeyFingerprint(&identity)
+	hosts := config.SSHHosts()
+	for _, identity := range identities {
+		fingerprint, _ := SSHKeyFingerprint(identity)
 		if state.Identities[identity.Name] == nil {
 			state.Identities[identity.Name] = &IdentityState{}
 		}
 		state.Identities[identity.Name].LastSync = time.Now()
 		state.Identities[identity.Name].SSHKeyFingerprint = fingerprint
+		recordArtifacts(state.Identities[identity.Name], identity, hosts[identity.Name], createdFolders[identity.Name], createdFiles[identity.Name])
+		state.RecordRepos(identity.Name, identityRepos[identity.Name], nil)
 	}
 
 	if err := state.Save(); err != nil {
-		fmt.Printf("  ⚠ Warning: failed to save state: %v\n", err)
+		step("", LevelWarn, fmt.Sprintf("Warning: failed to save state: %v", err))
+	}
+
+	if path := hookPath(HookPostSync); path != "" && !opts.NoHooks {
+		section("", "Running "+HookPostSync+" hook...")
+		if err := runHook(reporter, path, hookIdentities, result); err != nil {
+			step("", LevelWarn, fmt.Sprintf("Warning: %s hook failed: %v", HookPostSync, err))
+		} else {
+			step("", LevelOK, "Ran "+path)
+		}
 	}
 
-	printSyncSummary(result)
+	reporter.Report(Event{Kind: EventDone, Result: result})
 
 	return result, nil
 }
 
-func detectOrphans(config *Config) ([]string, error) {
+// identitySync is the outcome of setting up one identity's folders, key and
+// gitconfig, with the events to report for it
+type identitySync struct {
+	events  []Event
+	created bool
+	err     error
+	folders []string
+	files   []string
+	repos   []string // Repositories in the identity's folders
+	test    *connectionTest
+}
+
+// lockedReporter lets concurrent identity syncs share a reporter
+type lockedReporter struct {
+	mu       sync.Mutex
+	reporter Reporter
+}
+
+func (r *lockedReporter) Report(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reporter.Report(e)
+}
+
+// syncIdentity creates the identity's folders, SSH key and gitconfig and adds
+// the key to the agent. Steps that may prompt (touching a security key,
+// ssh-add asking for a passphrase) hold interactive, so only one identity
+// prompts at a time; the touch prompt goes to live right away.
+func syncIdentity(identity Identity, state *State, live Reporter, interactive *sync.Mutex) *identitySync {
+	name := identity.Name
+	outcome := &identitySync{}
+	buffer := ReporterFunc(func(e Event) {
+		outcome.events = append(outcome.events, e)
+	})
+	step := func(level Level, message string, hints ...string) {
+		buffer.Report(Event{Kind: EventStep, Level: level, Identity: name, Message: message, Hints: hints})
+	}
+	buffer.Report(Event{Kind: EventSection, Identity: name, Message: "Processing: " + name})
+
+	for _, folder := range identity.Folders {
+		expandedFolder := ExpandPath(folder)
+		if _, err := os.Stat(expandedFolder); err == nil {
+			step(LevelOK, "Folder exists: "+folder)
+		} else if err := os.MkdirAll(expandedFolder, 0755); err != nil {
+			step(LevelWarn, fmt.Sprintf("Warning: failed to create folder %s: %v", folder, err))
+		} else {
+			step(LevelOK, "Created folder: "+folder)
+			outcome.folders = append(outcome.folders, folder)
+		}
+	}
+	outcome.repos = IdentityRepos(identity, syncRepoDepth)
+
+	if !SSHKeyExists(identity) {
+		var err error
+		if identity.IsSecurityKey() {
+			interactive.Lock()
+			live.Report(Event{Kind: EventStep, Level: LevelInfo, Identity: name, Message: "Touch your security key for " + name + " when it blinks"})
+			err = GenerateSSHKey(identity, buffer)
+			interactive.Unlock()
+		} else {
+			err = GenerateSSHKey(identity, buffer)
+		}
+		if err != nil {
+			step(LevelError, fmt.Sprintf("Failed to generate SSH key: %v", err))
+			outcome.err = err
+			return outcome
+		}
+		step(LevelOK, fmt.Sprintf("Generated SSH key: %s [zzk:%s]", identity.SSHKeyPath(), name))
+		outcome.created = true
+	} else {
+		step(LevelOK, fmt.Sprintf("SSH key exists: %s [zzk:%s]", identity.SSHKeyPath(), name))
+		if current, drifted := KeyDrift(identity, state); drifted {
+			step(LevelWarn, fmt.Sprintf("Key fingerprint changed since the last sync: was %s, now %s", state.Identities[name].SSHKeyFingerprint, current),
+				"If you didn't replace the key yourself, check "+identity.SSHKeyPath()+" before using it",
+				"The new fingerprint is recorded by this sync")
+		}
+		if ok, algorithm := SSHKeyTypeMatches(identity); !ok {
+			step(LevelWarn, fmt.Sprintf("Key is %s but key_type is %s; delete %s to regenerate it", algorithm, identity.SSHKeyType(), identity.SSHKeyPath()))
+		}
+	}
+
+	// Only copy public key if a new key was just created
+	if outcome.created {
+		copied, err := CopyPublicKeyToHome(identity)
+		if err != nil {
+			step(LevelWarn, fmt.Sprintf("Warning: failed to copy public key: %v", err))
+		} else if copied {
+			step(LevelOK, fmt.Sprintf("Copied public key to ~/%s_key.pub", name))
+			outcome.files = append(outcome.files, fmt.Sprintf("~/%s_key.pub", name))
+		}
+	}
+
+	if err := CreateIdentityGitConfig(identity); err != nil {
+		step(LevelError, fmt.Sprintf("Failed to create git config: %v", err))
+		outcome.err = err
+		return outcome
+	}
+	step(LevelOK, "Updated "+identity.GitConfigPath())
+
+	if identity.EnforceNoreply {
+		if !IsNoreplyEmail(identity.Email) {
+			step(LevelWarn, fmt.Sprintf("%s is not a noreply address, but enforce_noreply is set", identity.Email),
+				"Use your forge's private commit address, e.g. 1234+user@users.noreply.github.com")
+		}
+		var leaking []string
+		for _, repo := range outcome.repos {
+			if len(realEmailCommits(repo)) > 0 {
+				leaking = append(leaking, tildePath(repo))
+			}
+		}
+		if len(leaking) > 0 {
+			step(LevelWarn, fmt.Sprintf("Unpushed commits use a real email in %s", strings.Join(leaking, ", ")),
+				"See 'zzk git audit --identity "+name+"'")
+		}
+	}
+
+	if !identity.UsesSSHAdd() {
+		step(LevelInfo, fmt.Sprintf("Not adding key to an agent (agent: %s)", identity.Agent))
+	} else {
+		interactive.Lock()
+		err := AddKeyToSSHAgent(identity, buffer)
+		interactive.Unlock()
+		if err != nil {
+			step(LevelWarn, fmt.Sprintf("Warning: failed to add key to SSH agent: %v", err))
+		} else {
+			step(LevelOK, "Added key to SSH agent")
+		}
+	}
+
+	outcome.test = &connectionTest{identity: identity}
+	return outcome
+}
+
+// connectionTest is one identity's SSH connection test and its outcome
+type connectionTest struct {
+	identity Identity
+	cmd      *exec.Cmd
+	output   []byte
+	// offered are the keys ssh offered, see offeredKeys
+	offered []string
+	err     error
+}
+
+// runConnectionTests runs the tests with at most parallel at a time, each
+// bounded by timeout
+func runConnectionTests(tests []*connectionTest, parallel int, timeout time.Duration) {
+	if parallel <= 0 {
+		parallel = 4
+	}
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for _, test := range tests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			test.cmd = sshTestCommand(ctx, test.identity)
+			var err error
+			test.output, err = test.cmd.CombinedOutput()
+			test.offered = offeredKeys(test.output)
+			test.err = sshTestResult(ctx, test.identity, test.output, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// detectOrphans finds identities zzk set up that are no longer in the
+// config: those recorded in the state, and zzk-tagged keys and gitconfigs
+func detectOrphans(config *Config, state *State) ([]string, error) {
 	orphans := []string{}
 
+	for name := range state.Identities {
+		if !config.HasIdentity(name) {
+			orphans = append(orphans, name)
+		}
+	}
+
 	managedKeys, err := FindZZKManagedKeys()
 	if err != nil {
 		return nil, err
 	}
 
 	for identity := range managedKeys {
-		if !config.HasIdentity(identity) {
+		if !config.HasIdentity(identity) && !slices.Contains(orphans, identity) {
 			orphans = append(orphans, identity)
 		}
 	}
@@ -244,33 +556,10 @@ func detectOrphans(config *Config) ([]string, error) {
 		}
 	}
 
+	slices.Sort(orphans)
 	return orphans, nil
 }
 
-func cleanupIdentity(identityName string) error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	sshKeyPath := filepath.Join(home, ".ssh", fmt.Sprintf("%s_key", identityName))
-	sshPubKeyPath := sshKeyPath + ".pub"
-	os.Remove(sshKeyPath)
-	os.Remove(sshPubKeyPath)
-
-	homePubKey := filepath.Join(home, fmt.Sprintf("%s_key.pub", identityName))
-	os.Remove(homePubKey)
-
-	gitConfigPath := filepath.Join(home, fmt.Sprintf(".gitconfig-%s", identityName))
-	os.Remove(gitConfigPath)
-
-	caser := cases.Title(language.English)
-	serviceFolder := filepath.Join(home, caser.String(identityName))
-	os.Remove(serviceFolder) // Only succeeds if empty
-
-	return nil
-}
-
 func identityNames(config *Config) string {
 	names := []string{}
 	for name := range config.Identities {
@@ -279,52 +568,34 @@ func identityNames(config *Config) string {
 	return strings.Join(names, ", ")
 }
 
-func printSyncSummary(result *SyncResult) {
-	fmt.Println("Sync complete!")
-	fmt.Println()
+func printSyncSummary(out io.Writer, result *SyncResult) {
+	fmt.Fprintln(out, "Sync complete!")
+	fmt.Fprintln(out)
 
 	if len(result.OrphansRemoved) > 0 {
-		fmt.Printf("Orphans removed: %d\n", len(result.OrphansRemoved))
+		fmt.Fprintf(out, "Orphans removed: %d\n", len(result.OrphansRemoved))
 	}
 	if len(result.Created) > 0 {
-		fmt.Printf("Identities created: %d\n", len(result.Created))
+		fmt.Fprintf(out, "Identities created: %d\n", len(result.Created))
 	}
 	if len(result.Verified) > 0 {
-		fmt.Printf("SSH connections verified: %d\n", len(result.Verified))
+		fmt.Fprintf(out, "SSH connections verified: %d\n", len(result.Verified))
+	}
+	if len(result.Unverified) > 0 {
+		fmt.Fprintf(out, "SSH connections failed: %d\n", len(result.Unverified))
 	}
 	if len(result.Failed) > 0 {
-		fmt.Printf("Failed: %d\n", len(result.Failed))
+		fmt.Fprintf(out, "Failed: %d\n", len(result.Failed))
 		for identity, err := range result.Failed {
-			fmt.Printf("  - %s: %v\n", identity, err)
+			fmt.Fprintf(out, "  - %s: %v\n", identity, err)
 		}
 	}
 	needsKeyUpload := len(result.Created) > 0
 
 	if needsKeyUpload {
-		fmt.Println()
-		fmt.Println("Next steps for new identities:")
-		fmt.Println("1. Add your public keys to your accounts")
-		fmt.Println("2. Run 'zzk git sync' again to verify connections")
-	}
-}
-
-// getSSHKeyFingerprint returns the SSH key fingerprint for an identity
-func getSSHKeyFingerprint(identity *Identity) string {
-	keyPath := identity.SSHKeyPath()
-	if _, err := os.Stat(keyPath); err != nil {
-		return ""
-	}
-
-	// Read the public key file to generate a simple hash
-	pubKeyPath := keyPath + ".pub"
-	data, err := os.ReadFile(pubKeyPath)
-	if err != nil {
-		return ""
-	}
-
-	// Return a simple identifier (first 16 chars of the key)
-	if len(data) > 16 {
-		return string(data[:16])
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Next steps for new identities:")
+		fmt.Fprintln(out, "1. Add your public keys to your accounts ('zzk git push-key <identity>' or --upload-keys)")
+		fmt.Fprintln(out, "2. Run 'zzk git sync' again to verify connections")
 	}
-	return string(data)
 }
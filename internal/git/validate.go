@@ -0,0 +1,184 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError is a validation error for one field of an identity
+type FieldError struct {
+	// Field is the path within the identity, e.g. "email" or "folders[1]"
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func fieldErrorf(field, format string, args ...any) error {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// configLines maps field paths in a config file, e.g.
+// "identities.work.folders[0]", to the line they are on
+func configLines(data []byte, isYAML bool) map[string]int {
+	lines := make(map[string]int)
+	if isYAML {
+		var doc yaml.Node
+		if yaml.Unmarshal(data, &doc) == nil && len(doc.Content) > 0 {
+			yamlLines(doc.Content[0], "", lines)
+		}
+		return lines
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	jsonLines(dec, data, "", lines)
+	return lines
+}
+
+// yamlLines records the line of every key and sequence item below node
+func yamlLines(node *yaml.Node, path string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := joinPath(path, node.Content[i].Value)
+			lines[key] = node.Content[i].Line
+			yamlLines(node.Content[i+1], key, lines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			key := fmt.Sprintf("%s[%d]", path, i)
+			lines[key] = item.Line
+			yamlLines(item, key, lines)
+		}
+	}
+}
+
+// jsonLines reads one value from dec, recording the line of every key and
+// array item in it. Errors stop the walk; parsing reports them.
+func jsonLines(dec *json.Decoder, data []byte, path string, lines map[string]int) {
+	token, err := dec.Token()
+	if err != nil {
+		return
+	}
+	switch token {
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return
+			}
+			name, _ := key.(string)
+			keyPath := joinPath(path, name)
+			lines[keyPath], _ = lineAt(data, dec.InputOffset())
+			jsonLines(dec, data, keyPath, lines)
+		}
+		dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			// The offset after the item is on its last line; use the first
+			before := dec.InputOffset()
+			lines[itemPath], _ = lineAt(data, before+nextTokenStart(data[before:]))
+			jsonLines(dec, data, itemPath, lines)
+		}
+		dec.Token()
+	}
+}
+
+// nextTokenStart skips whitespace and a separating comma
+func nextTokenStart(data []byte) int64 {
+	for i, c := range data {
+		if !strings.ContainsRune(" \t\r\n,", rune(c)) {
+			return int64(i)
+		}
+	}
+	return int64(len(data))
+}
+
+// topField returns the identity field a FieldError path starts with, e.g.
+// "folders" for "folders[1]"
+func topField(path string) string {
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lineAt returns the 1-based line and column of a byte offset
+func lineAt(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// locateField finds the line of path, or of its closest parent that exists
+func locateField(lines map[string]int, path string) int {
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+		if i := strings.LastIndexAny(path, ".["); i >= 0 {
+			path = path[:i]
+		} else {
+			break
+		}
+	}
+	return 0
+}
+
+// describeParseError adds the line and field to JSON decoding errors; YAML
+// errors already include the line
+func describeParseError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := lineAt(data, syntaxErr.Offset)
+		return fmt.Errorf("line %d, column %d: %s", line, col, syntaxErr.Error())
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		line, _ := lineAt(data, typeErr.Offset)
+		field := typeErr.Field
+		if field == "" {
+			field = "config"
+		}
+		return fmt.Errorf("%s: expected %s, got %s at line %d", field, jsonTypeName(typeErr.Type.Kind().String()), typeErr.Value, line)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		line, _ := lineAt(data, int64(len(data)))
+		return fmt.Errorf("line %d: unexpected end of file", line)
+	}
+	return err
+}
+
+// jsonTypeName names Go kinds the way they are written in JSON
+func jsonTypeName(kind string) string {
+	switch kind {
+	case "slice", "array":
+		return "array"
+	case "map", "struct":
+		return "object"
+	case "bool":
+		return "true or false"
+	default:
+		return kind
+	}
+}
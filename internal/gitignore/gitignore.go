@@ -0,0 +1,183 @@
+package gitignore
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+const rawBaseURL = "https://raw.githubusercontent.com/github/gitignore/main/"
+
+// knownTemplates maps lowercase names to paths in the github/gitignore repository
+var knownTemplates = map[string]string{
+	"go":           "Go",
+	"node":         "Node",
+	"python":       "Python",
+	"rust":         "Rust",
+	"java":         "Java",
+	"kotlin":       "Kotlin",
+	"swift":        "Swift",
+	"c":            "C",
+	"c++":          "C++",
+	"cpp":          "C++",
+	"ruby":         "Ruby",
+	"dart":         "Dart",
+	"flutter":      "Dart",
+	"terraform":    "Terraform",
+	"unity":        "Unity",
+	"zig":          "Zig",
+	"macos":        "Global/macOS",
+	"linux":        "Global/Linux",
+	"windows":      "Global/Windows",
+	"vscode":       "Global/VisualStudioCode",
+	"jetbrains":    "Global/JetBrains",
+	"idea":         "Global/JetBrains",
+	"vim":          "Global/Vim",
+	"emacs":        "Global/Emacs",
+	"direnv":       "Global/direnv",
+	"archives":     "Global/Archives",
+	"backup":       "Global/Backup",
+	"diff":         "Global/Diff",
+	"xcode":        "Global/Xcode",
+	"sublime":      "Global/SublimeText",
+	"visualstudio": "VisualStudio",
+}
+
+// Template is a fetched gitignore template
+type Template struct {
+	Name  string // Display name (e.g. "Go", "macOS")
+	Rules []string
+}
+
+// candidatePaths returns the repository paths to try for a template name
+func candidatePaths(name string) []string {
+	if path, ok := knownTemplates[strings.ToLower(name)]; ok {
+		return []string{path}
+	}
+	capitalized := strings.ToUpper(name[:1]) + name[1:]
+	return []string{capitalized, "Global/" + capitalized, "community/" + capitalized}
+}
+
+// Fetch returns the template for name, from the local cache unless refresh is set
+func Fetch(name string, refresh bool) (*Template, error) {
+	if name == "" {
+		return nil, fmt.Errorf("empty template name")
+	}
+
+	cacheDir, err := fileutil.CacheDir("gitignore")
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	for _, path := range candidatePaths(name) {
+		cachePath := filepath.Join(cacheDir, strings.ReplaceAll(path, "/", "_")+".gitignore")
+
+		if !refresh {
+			if data, err := os.ReadFile(cachePath); err == nil {
+				return parseTemplate(path, string(data)), nil
+			}
+		}
+
+		data, found, err := download(client, rawBaseURL+path+".gitignore")
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		// Cache is best-effort
+		_ = os.WriteFile(cachePath, data, 0644)
+		return parseTemplate(path, string(data)), nil
+	}
+
+	return nil, fmt.Errorf("no gitignore template found for '%s'", name)
+}
+
+// download fetches url, returning found=false on 404
+func download(client *http.Client, url string) ([]byte, bool, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, true, nil
+}
+
+// parseTemplate extracts the rules (non-empty, non-comment lines) from a template
+func parseTemplate(path, content string) *Template {
+	tmpl := &Template{Name: filepath.Base(path)}
+	for line := range strings.SplitSeq(content, "\n") {
+		rule := strings.TrimRight(line, " \t\r")
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		tmpl.Rules = append(tmpl.Rules, rule)
+	}
+	return tmpl
+}
+
+// Merge appends the rules from templates that are not already present in existing.
+// It returns the new content and the number of rules added per template.
+func Merge(existing string, templates []*Template) (string, map[string]int) {
+	seen := make(map[string]bool)
+	for line := range strings.SplitSeq(existing, "\n") {
+		rule := strings.TrimSpace(line)
+		if rule != "" && !strings.HasPrefix(rule, "#") {
+			seen[rule] = true
+		}
+	}
+
+	var buf strings.Builder
+	buf.WriteString(existing)
+
+	added := make(map[string]int)
+	for _, tmpl := range templates {
+		var rules []string
+		for _, rule := range tmpl.Rules {
+			if seen[strings.TrimSpace(rule)] {
+				continue
+			}
+			seen[strings.TrimSpace(rule)] = true
+			rules = append(rules, rule)
+		}
+
+		added[tmpl.Name] = len(rules)
+		if len(rules) == 0 {
+			continue
+		}
+
+		if buf.Len() > 0 && !strings.HasSuffix(buf.String(), "\n\n") {
+			if !strings.HasSuffix(buf.String(), "\n") {
+				buf.WriteString("\n")
+			}
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "### %s ###\n", tmpl.Name)
+		for _, rule := range rules {
+			buf.WriteString(rule + "\n")
+		}
+	}
+
+	return buf.String(), added
+}
@@ -0,0 +1,151 @@
+package httpreq
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Methods are the HTTP methods accepted as the first argument
+var Methods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true,
+	"PATCH": true, "DELETE": true, "OPTIONS": true,
+}
+
+// Items holds the parsed request items given on the command line
+type Items struct {
+	Headers http.Header
+	Query   url.Values
+	JSON    map[string]any
+}
+
+// ParseItems parses httpie-style request items:
+//
+//	Header:value   request header
+//	key==value     query parameter
+//	key=value      JSON string field
+//	key:=json      raw JSON field (numbers, booleans, objects, ...)
+func ParseItems(args []string) (*Items, error) {
+	items := &Items{
+		Headers: make(http.Header),
+		Query:   make(url.Values),
+		JSON:    make(map[string]any),
+	}
+
+	for _, arg := range args {
+		// Checked in order of precedence so "a==b" isn't read as "a=" + "=b"
+		if key, value, ok := strings.Cut(arg, ":="); ok && !strings.ContainsAny(key, "=:") {
+			var raw any
+			if err := json.Unmarshal([]byte(value), &raw); err != nil {
+				return nil, fmt.Errorf("invalid JSON for %s: %w", key, err)
+			}
+			items.JSON[key] = raw
+			continue
+		}
+		if key, value, ok := strings.Cut(arg, "=="); ok && !strings.ContainsAny(key, "=:") {
+			items.Query.Add(key, value)
+			continue
+		}
+		if key, value, ok := strings.Cut(arg, "="); ok && !strings.ContainsAny(key, ":") {
+			items.JSON[key] = value
+			continue
+		}
+		if key, value, ok := strings.Cut(arg, ":"); ok && key != "" {
+			items.Headers.Add(key, strings.TrimSpace(value))
+			continue
+		}
+		return nil, fmt.Errorf("invalid request item %q (use Header:value, key==value, key=value or key:=json)", arg)
+	}
+
+	return items, nil
+}
+
+// Body returns the JSON-encoded body, or nil if no fields were given
+func (i *Items) Body() ([]byte, error) {
+	if len(i.JSON) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(i.JSON)
+}
+
+// Timing is the time spent in each phase of a request
+type Timing struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	Server   time.Duration // Time to first byte after the request was sent
+	Transfer time.Duration
+	Total    time.Duration
+}
+
+// Response is a completed response with its body read
+type Response struct {
+	*http.Response
+	Body   []byte
+	Timing Timing
+}
+
+// Do sends the request and reads the whole response, recording phase timings
+func Do(client *http.Client, req *http.Request) (*Response, error) {
+	var timing Timing
+	var dnsStart, connectStart, tlsStart, wroteRequest, firstByte time.Time
+
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { timing.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { timing.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { timing.TLS = time.Since(tlsStart) },
+		WroteRequest:      func(httptrace.WroteRequestInfo) { wroteRequest = time.Now() },
+		GotFirstResponseByte: func() {
+			firstByte = time.Now()
+			timing.Server = firstByte.Sub(wroteRequest)
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	timing.Total = time.Since(start)
+	if !firstByte.IsZero() {
+		timing.Transfer = time.Since(firstByte)
+	}
+
+	return &Response{Response: resp, Body: body, Timing: timing}, nil
+}
+
+// IsJSON reports whether the response declares a JSON content type
+func (r *Response) IsJSON() bool {
+	contentType := r.Header.Get("Content-Type")
+	return strings.Contains(contentType, "json")
+}
+
+// PrettyBody returns the body indented if it is JSON, otherwise unchanged
+func (r *Response) PrettyBody() []byte {
+	if !r.IsJSON() && !json.Valid(r.Body) {
+		return r.Body
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, r.Body, "", "  "); err != nil {
+		return r.Body
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,158 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Segment is one step of a path: an object key or an array index
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+func (s Segment) String() string {
+	if s.IsIndex {
+		return fmt.Sprintf("[%d]", s.Index)
+	}
+	return "." + s.Key
+}
+
+// Parse parses a path such as .identities.github-work.email, .items[0].name
+// or .["key.with.dots"]. A lone "." refers to the whole document.
+func Parse(path string) ([]Segment, error) {
+	var segments []Segment
+	rest := strings.TrimSpace(path)
+
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, `["`) || strings.HasPrefix(rest, `.["`):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.Index(rest, `"]`)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted key in %q", path)
+			}
+			segments = append(segments, Segment{Key: rest[2:end]})
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in %q", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q in %q", rest[1:end], path)
+			}
+			segments = append(segments, Segment{Index: index, IsIndex: true})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			if key == "" {
+				continue
+			}
+			// Bare numbers select array elements too: .items.0
+			if index, err := strconv.Atoi(key); err == nil {
+				segments = append(segments, Segment{Key: key, Index: index, IsIndex: true})
+			} else {
+				segments = append(segments, Segment{Key: key})
+			}
+		default:
+			return nil, fmt.Errorf("path must start with '.' (got %q)", path)
+		}
+	}
+
+	return segments, nil
+}
+
+// Get returns the raw JSON value at path, preserving key order of objects
+func Get(data []byte, path string) (json.RawMessage, error) {
+	segments, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := json.RawMessage(bytes.TrimSpace(data))
+	if !json.Valid(current) {
+		return nil, fmt.Errorf("invalid JSON input")
+	}
+
+	walked := ""
+	for _, segment := range segments {
+		next, err := step(current, segment)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", walked+segment.String(), err)
+		}
+		current = next
+		walked += segment.String()
+	}
+
+	return current, nil
+}
+
+func step(value json.RawMessage, segment Segment) (json.RawMessage, error) {
+	switch firstByte(value) {
+	case '{':
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err != nil {
+			return nil, err
+		}
+		key := segment.Key
+		child, ok := object[key]
+		if !ok {
+			return nil, fmt.Errorf("key not found")
+		}
+		return child, nil
+	case '[':
+		if !segment.IsIndex {
+			return nil, fmt.Errorf("cannot look up key %q in an array", segment.Key)
+		}
+		var array []json.RawMessage
+		if err := json.Unmarshal(value, &array); err != nil {
+			return nil, err
+		}
+		index := segment.Index
+		if index < 0 {
+			index += len(array)
+		}
+		if index < 0 || index >= len(array) {
+			return nil, fmt.Errorf("index out of range (length %d)", len(array))
+		}
+		return array[index], nil
+	default:
+		return nil, fmt.Errorf("cannot index into a scalar value")
+	}
+}
+
+func firstByte(value json.RawMessage) byte {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return 0
+	}
+	return trimmed[0]
+}
+
+// Raw returns strings unquoted and any other value as compact JSON
+func Raw(value json.RawMessage) (string, error) {
+	if firstByte(value) == '"' {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,115 @@
+package jump
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Store holds directory bookmarks, persisted in ~/.config/zzk/jump.json
+type Store struct {
+	Bookmarks map[string]string `json:"bookmarks"`
+}
+
+// StorePath returns the path to the bookmarks file
+func StorePath() (string, error) {
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jump.json"), nil
+}
+
+// Load loads the bookmarks, returning an empty store if none exist yet
+func Load() (*Store, error) {
+	path, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{Bookmarks: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", path, err)
+	}
+	if store.Bookmarks == nil {
+		store.Bookmarks = make(map[string]string)
+	}
+
+	return store, nil
+}
+
+// Save writes the bookmarks to disk
+func (s *Store) Save() error {
+	path, err := StorePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bookmarks: %w", err)
+	}
+
+	return fileutil.AtomicWrite(path, data, 0644)
+}
+
+// Add bookmarks dir under name, resolving it to an absolute path
+func (s *Store) Add(name, dir string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("bookmark name must not be empty")
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return "", fmt.Errorf("path does not exist: %s", absDir)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("not a directory: %s", absDir)
+	}
+
+	s.Bookmarks[name] = absDir
+	return absDir, nil
+}
+
+// Get returns the path for a bookmark
+func (s *Store) Get(name string) (string, bool) {
+	path, ok := s.Bookmarks[name]
+	return path, ok
+}
+
+// Remove deletes a bookmark
+func (s *Store) Remove(name string) error {
+	if _, ok := s.Bookmarks[name]; !ok {
+		return fmt.Errorf("bookmark '%s' not found", name)
+	}
+	delete(s.Bookmarks, name)
+	return nil
+}
+
+// Names returns the bookmark names in sorted order
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.Bookmarks))
+	for name := range s.Bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,205 @@
+package kb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Snippet is a named command with optional description and tags
+type Snippet struct {
+	Name        string    `json:"name"`
+	Command     string    `json:"command"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+// Store holds snippets, persisted in ~/.config/zzk/kb.json
+type Store struct {
+	Snippets map[string]*Snippet `json:"snippets"`
+}
+
+// StorePath returns the path to the snippets file
+func StorePath() (string, error) {
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "kb.json"), nil
+}
+
+// Load loads the snippets, returning an empty store if none exist yet
+func Load() (*Store, error) {
+	path, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{Snippets: make(map[string]*Snippet)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read snippets: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", path, err)
+	}
+	if store.Snippets == nil {
+		store.Snippets = make(map[string]*Snippet)
+	}
+
+	return store, nil
+}
+
+// Save writes the snippets to disk
+func (s *Store) Save() error {
+	path, err := StorePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snippets: %w", err)
+	}
+
+	return fileutil.AtomicWrite(path, data, 0644)
+}
+
+// Add stores a snippet, replacing any existing one with the same name
+func (s *Store) Add(snippet *Snippet) error {
+	if snippet.Name == "" || strings.ContainsAny(snippet.Name, " \t\n") {
+		return fmt.Errorf("snippet name must be a single word")
+	}
+	if strings.TrimSpace(snippet.Command) == "" {
+		return fmt.Errorf("snippet command must not be empty")
+	}
+	if snippet.Created.IsZero() {
+		snippet.Created = time.Now()
+	}
+	s.Snippets[snippet.Name] = snippet
+	return nil
+}
+
+// Get returns a snippet by name
+func (s *Store) Get(name string) (*Snippet, bool) {
+	snippet, ok := s.Snippets[name]
+	return snippet, ok
+}
+
+// Remove deletes a snippet
+func (s *Store) Remove(name string) error {
+	if _, ok := s.Snippets[name]; !ok {
+		return fmt.Errorf("snippet '%s' not found", name)
+	}
+	delete(s.Snippets, name)
+	return nil
+}
+
+// List returns snippets sorted by name, optionally only those with tag
+func (s *Store) List(tag string) []*Snippet {
+	var snippets []*Snippet
+	for _, snippet := range s.Snippets {
+		if tag != "" && !snippet.HasTag(tag) {
+			continue
+		}
+		snippets = append(snippets, snippet)
+	}
+	sort.Slice(snippets, func(i, j int) bool { return snippets[i].Name < snippets[j].Name })
+	return snippets
+}
+
+// HasTag reports whether the snippet is tagged with tag (case-insensitive)
+func (s *Snippet) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Search returns snippets matching every query term, best matches first.
+// Terms match as substrings (weighted by field) or, failing that, as fuzzy
+// subsequences of the name.
+func (s *Store) Search(query string) []*Snippet {
+	terms := strings.Fields(strings.ToLower(query))
+
+	type scored struct {
+		snippet *Snippet
+		score   int
+	}
+	var results []scored
+	for _, snippet := range s.Snippets {
+		total := 0
+		for _, term := range terms {
+			score := snippet.score(term)
+			if score == 0 {
+				total = 0
+				break
+			}
+			total += score
+		}
+		if total > 0 || len(terms) == 0 {
+			results = append(results, scored{snippet, total})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].snippet.Name < results[j].snippet.Name
+	})
+
+	snippets := make([]*Snippet, len(results))
+	for i, r := range results {
+		snippets[i] = r.snippet
+	}
+	return snippets
+}
+
+func (s *Snippet) score(term string) int {
+	name := strings.ToLower(s.Name)
+	switch {
+	case name == term:
+		return 200
+	case strings.HasPrefix(name, term):
+		return 120
+	case strings.Contains(name, term):
+		return 100
+	case s.HasTag(term):
+		return 80
+	case strings.Contains(strings.ToLower(s.Description), term):
+		return 50
+	case strings.Contains(strings.ToLower(s.Command), term):
+		return 30
+	case isSubsequence(term, name):
+		return 20
+	case isSubsequence(term, strings.ToLower(s.Description)):
+		return 5
+	}
+	return 0
+}
+
+// isSubsequence reports whether all characters of needle appear in order in haystack
+func isSubsequence(needle, haystack string) bool {
+	i := 0
+	for _, c := range haystack {
+		if i < len(needle) && rune(needle[i]) == c {
+			i++
+		}
+	}
+	return i == len(needle)
+}
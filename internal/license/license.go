@@ -0,0 +1,111 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+const apiURL = "https://api.github.com/licenses"
+
+// Aliases maps common short names to SPDX-style license keys
+var Aliases = map[string]string{
+	"mit":        "mit",
+	"apache":     "apache-2.0",
+	"apache2":    "apache-2.0",
+	"apache-2.0": "apache-2.0",
+	"gpl":        "gpl-3.0",
+	"gpl2":       "gpl-2.0",
+	"gpl-2.0":    "gpl-2.0",
+	"gpl3":       "gpl-3.0",
+	"gpl-3.0":    "gpl-3.0",
+	"lgpl":       "lgpl-3.0",
+	"lgpl-2.1":   "lgpl-2.1",
+	"lgpl-3.0":   "lgpl-3.0",
+	"agpl":       "agpl-3.0",
+	"agpl-3.0":   "agpl-3.0",
+	"mpl":        "mpl-2.0",
+	"mpl-2.0":    "mpl-2.0",
+	"bsd2":       "bsd-2-clause",
+	"bsd-2":      "bsd-2-clause",
+	"bsd3":       "bsd-3-clause",
+	"bsd-3":      "bsd-3-clause",
+	"isc":        "isc",
+	"unlicense":  "unlicense",
+	"0bsd":       "0bsd",
+}
+
+// ResolveKey resolves a user-supplied license name to a license key
+func ResolveKey(name string) (string, error) {
+	key, ok := Aliases[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown license '%s' (run 'zzk license --list')", name)
+	}
+	return key, nil
+}
+
+// Fetch returns the license template text for a key, using the local cache when available
+func Fetch(key string) (string, error) {
+	cacheDir, err := fileutil.CacheDir("licenses")
+	if err != nil {
+		return "", err
+	}
+
+	cachePath := filepath.Join(cacheDir, key+".txt")
+	if data, err := os.ReadFile(cachePath); err == nil {
+		return string(data), nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, apiURL+"/"+key, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "zzk")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch license: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch license %s: %s", key, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read license: %w", err)
+	}
+
+	var payload struct {
+		Body string `json:"body"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("failed to parse license: %w", err)
+	}
+
+	// Cache is best-effort
+	_ = os.WriteFile(cachePath, []byte(payload.Body), 0644)
+
+	return payload.Body, nil
+}
+
+// Render fills in the year and author placeholders used by the license templates
+func Render(text string, year int, author string) string {
+	replacer := strings.NewReplacer(
+		"[year]", fmt.Sprint(year),
+		"[yyyy]", fmt.Sprint(year),
+		"[fullname]", author,
+		"[name of copyright owner]", author,
+	)
+	return replacer.Replace(text)
+}
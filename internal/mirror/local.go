@@ -0,0 +1,52 @@
+package mirror
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalFS is a destination directory on this machine
+type LocalFS struct {
+	Root string
+}
+
+func (l *LocalFS) path(rel string) string {
+	return filepath.Join(l.Root, filepath.FromSlash(rel))
+}
+
+func (l *LocalFS) List() (map[string]Entry, error) {
+	if _, err := os.Stat(l.Root); os.IsNotExist(err) {
+		return map[string]Entry{}, nil
+	}
+	return listLocal(l.Root, nil)
+}
+
+func (l *LocalFS) MkdirAll(rel string) error {
+	return os.MkdirAll(l.path(rel), 0755)
+}
+
+func (l *LocalFS) Create(rel string) (io.WriteCloser, error) {
+	return os.Create(l.path(rel))
+}
+
+func (l *LocalFS) Rename(oldRel, newRel string) error {
+	return os.Rename(l.path(oldRel), l.path(newRel))
+}
+
+func (l *LocalFS) Remove(rel string) error {
+	return os.Remove(l.path(rel))
+}
+
+func (l *LocalFS) Chtimes(rel string, mtime time.Time) error {
+	return os.Chtimes(l.path(rel), mtime, mtime)
+}
+
+func (l *LocalFS) Chmod(rel string, mode os.FileMode) error {
+	return os.Chmod(l.path(rel), mode)
+}
+
+func (l *LocalFS) Close() error {
+	return nil
+}
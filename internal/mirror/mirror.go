@@ -0,0 +1,280 @@
+package mirror
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Entry describes a file or directory relative to a sync root
+type Entry struct {
+	Size    int64
+	ModTime time.Time
+	Mode    os.FileMode
+	Dir     bool
+}
+
+// FS is a sync destination. Paths are slash-separated and relative to its root.
+type FS interface {
+	// List returns every entry under the root, keyed by relative path
+	List() (map[string]Entry, error)
+	MkdirAll(rel string) error
+	Create(rel string) (io.WriteCloser, error)
+	Rename(oldRel, newRel string) error
+	Remove(rel string) error
+	Chtimes(rel string, mtime time.Time) error
+	Chmod(rel string, mode os.FileMode) error
+	Close() error
+}
+
+// Options controls what is synced
+type Options struct {
+	Include []string
+	Exclude []string
+	Delete  bool
+}
+
+// Action kinds
+const (
+	ActionNew    = "new"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is a single planned operation
+type Change struct {
+	Action string
+	Path   string
+	Size   int64
+	Dir    bool
+}
+
+// Plan compares the source directory with the destination
+func Plan(src string, dst FS, opts Options) ([]Change, error) {
+	srcEntries, err := listLocal(src, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	dstEntries, err := dst.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for rel, entry := range srcEntries {
+		if !opts.selected(rel, entry.Dir) {
+			continue
+		}
+		existing, ok := dstEntries[rel]
+		switch {
+		case entry.Dir:
+			if !ok {
+				changes = append(changes, Change{Action: ActionNew, Path: rel, Dir: true})
+			}
+		case !ok:
+			changes = append(changes, Change{Action: ActionNew, Path: rel, Size: entry.Size})
+		case existing.Dir || existing.Size != entry.Size || !sameTime(existing.ModTime, entry.ModTime):
+			changes = append(changes, Change{Action: ActionUpdate, Path: rel, Size: entry.Size})
+		}
+	}
+
+	if opts.Delete {
+		changes = append(changes, deletions(srcEntries, dstEntries, opts)...)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		// Deletes run first, deepest paths before their parents
+		if (a.Action == ActionDelete) != (b.Action == ActionDelete) {
+			return a.Action == ActionDelete
+		}
+		if a.Action == ActionDelete {
+			return a.Path > b.Path
+		}
+		return a.Path < b.Path
+	})
+	return changes, nil
+}
+
+// deletions lists destination entries missing from the source. Excluded
+// paths are left alone, as are directories that still hold any of them.
+func deletions(srcEntries, dstEntries map[string]Entry, opts Options) []Change {
+	var changes []Change
+	kept := make(map[string]bool)
+	for rel, entry := range dstEntries {
+		if src, ok := srcEntries[rel]; ok && src.Dir == entry.Dir {
+			continue
+		}
+		if entry.Dir || (!fileutil.ExcludedPath(rel, opts.Exclude) && opts.included(rel)) {
+			changes = append(changes, Change{Action: ActionDelete, Path: rel, Dir: entry.Dir})
+			continue
+		}
+		for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+			kept[dir] = true
+		}
+	}
+
+	var result []Change
+	for _, change := range changes {
+		if change.Dir && (kept[change.Path] || fileutil.ExcludedPath(change.Path, opts.Exclude)) {
+			continue
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+// Apply performs the planned changes, reporting each one before it runs
+func Apply(src string, dst FS, changes []Change, progress func(Change)) error {
+	if len(changes) > 0 {
+		if err := dst.MkdirAll("."); err != nil {
+			return fmt.Errorf("failed to create destination: %w", err)
+		}
+	}
+	for _, change := range changes {
+		if progress != nil {
+			progress(change)
+		}
+
+		var err error
+		switch {
+		case change.Action == ActionDelete:
+			err = dst.Remove(change.Path)
+		case change.Dir:
+			err = dst.MkdirAll(change.Path)
+		default:
+			err = copyFile(src, dst, change.Path)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s: %w", change.Action, change.Path, err)
+		}
+	}
+	return nil
+}
+
+// copyFile writes to a temporary name and renames it into place so an
+// interrupted sync never leaves a truncated file behind
+func copyFile(src string, dst FS, rel string) error {
+	localPath := filepath.Join(src, filepath.FromSlash(rel))
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if dir := path.Dir(rel); dir != "." {
+		if err := dst.MkdirAll(dir); err != nil {
+			return err
+		}
+	}
+
+	tmp := path.Join(path.Dir(rel), "."+path.Base(rel)+".zzk-tmp")
+	out, err := dst.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		dst.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		dst.Remove(tmp)
+		return err
+	}
+
+	if err := dst.Chmod(tmp, info.Mode().Perm()); err != nil {
+		dst.Remove(tmp)
+		return err
+	}
+	if err := dst.Chtimes(tmp, info.ModTime()); err != nil {
+		dst.Remove(tmp)
+		return err
+	}
+	if err := dst.Rename(tmp, rel); err != nil {
+		dst.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// listLocal walks a local directory, skipping excluded paths
+func listLocal(root string, exclude []string) (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if fileutil.ExcludedPath(rel, exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries[filepath.ToSlash(rel)] = Entry{Size: info.Size(), ModTime: info.ModTime(), Mode: info.Mode().Perm(), Dir: d.IsDir()}
+		return nil
+	})
+	return entries, err
+}
+
+// selected reports whether a source path should be synced
+func (o Options) selected(rel string, dir bool) bool {
+	if fileutil.ExcludedPath(rel, o.Exclude) {
+		return false
+	}
+	if dir {
+		// Directories are created on demand when includes are set
+		return len(o.Include) == 0
+	}
+	return o.included(rel)
+}
+
+// included matches include globs against the full path or the file name
+func (o Options) included(rel string) bool {
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, glob := range o.Include {
+		if ok, _ := path.Match(glob, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(glob, path.Base(rel)); ok {
+			return true
+		}
+		if strings.HasSuffix(glob, "/**") && strings.HasPrefix(rel, strings.TrimSuffix(glob, "**")) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameTime compares modification times at one second resolution, which is
+// all SFTP and many NAS filesystems preserve
+func sameTime(a, b time.Time) bool {
+	return a.Unix() == b.Unix()
+}
@@ -0,0 +1,295 @@
+package mirror
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Remote is a parsed [user@]host:path destination. User is empty when not
+// given, leaving it to ~/.ssh/config.
+type Remote struct {
+	User string
+	Host string
+	Path string
+}
+
+// ParseRemote recognizes scp-style destinations. Local paths, including
+// Windows drive letters, are reported as not remote.
+func ParseRemote(dest string) (Remote, bool) {
+	hostPart, remotePath, ok := strings.Cut(dest, ":")
+	if !ok || hostPart == "" || strings.ContainsAny(hostPart, `/\`) || len(hostPart) == 1 {
+		return Remote{}, false
+	}
+
+	r := Remote{Host: hostPart, Path: remotePath}
+	if u, h, ok := strings.Cut(hostPart, "@"); ok {
+		r.User, r.Host = u, h
+	}
+	// SFTP servers resolve relative paths against the login directory
+	r.Path = strings.TrimPrefix(r.Path, "~/")
+	if r.Path == "" || r.Path == "~" {
+		r.Path = "."
+	}
+	r.Path = path.Clean(r.Path)
+	return r, true
+}
+
+func (r Remote) String() string {
+	if r.User == "" {
+		return r.Host + ":" + r.Path
+	}
+	return fmt.Sprintf("%s@%s:%s", r.User, r.Host, r.Path)
+}
+
+// SSHHost is where ssh would connect for a remote, after ~/.ssh/config
+type SSHHost struct {
+	HostName      string
+	User          string
+	Port          int
+	IdentityFiles []string
+}
+
+// ResolveSSHHost applies ~/.ssh/config to r with 'ssh -G', so host aliases
+// work as they do for ssh. The user in r and port, unless 0, take precedence
+// over the config. Without ssh, r is used as is.
+func ResolveSSHHost(r Remote, port int) SSHHost {
+	host := SSHHost{HostName: r.Host, User: r.User, Port: port}
+	args := []string{"-G"}
+	if r.User != "" {
+		args = append(args, "-l", r.User)
+	}
+	if port != 0 {
+		args = append(args, "-p", strconv.Itoa(port))
+	}
+	if out, err := exec.Command("ssh", append(args, "--", r.Host)...).Output(); err == nil {
+		home, _ := os.UserHomeDir()
+		for line := range strings.Lines(string(out)) {
+			key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch key {
+			case "hostname":
+				host.HostName = value
+			case "user":
+				host.User = value
+			case "port":
+				if p, err := strconv.Atoi(value); err == nil {
+					host.Port = p
+				}
+			case "identityfile":
+				if rest, ok := strings.CutPrefix(value, "~/"); ok && home != "" {
+					value = filepath.Join(home, rest)
+				}
+				host.IdentityFiles = append(host.IdentityFiles, value)
+			}
+		}
+	}
+
+	if host.User == "" {
+		if current, err := user.Current(); err == nil {
+			host.User = current.Username
+		}
+	}
+	if host.Port == 0 {
+		host.Port = 22
+	}
+	return host
+}
+
+// SFTPFS is a destination directory on an SSH host
+type SFTPFS struct {
+	Root   string
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// DialSFTP connects to host, as resolved for the remote r, using the SSH
+// agent and the given private key files. Host keys are checked against
+// ~/.ssh/known_hosts, asking the server only for key types listed there.
+func DialSFTP(r Remote, host SSHHost, keyFiles []string) (*SFTPFS, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	knownHostsPath := filepath.Join(home, ".ssh", "known_hosts")
+	if _, err := os.Stat(knownHostsPath); os.IsNotExist(err) {
+		return nil, unknownHostError(host)
+	}
+	hostKeys, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if agentConn, err := net.Dial("unix", sock); err == nil {
+			defer agentConn.Close()
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+		}
+	}
+	var signers []ssh.Signer
+	for _, keyFile := range keyFiles {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			continue
+		}
+		// Passphrase-protected keys are only usable through the agent
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no SSH keys available (start ssh-agent or run 'zzk git sync' to create identity keys)")
+	}
+
+	addr := net.JoinHostPort(host.HostName, strconv.Itoa(host.Port))
+	config := &ssh.ClientConfig{
+		User:              host.User,
+		Auth:              methods,
+		HostKeyCallback:   hostKeys,
+		HostKeyAlgorithms: hostKeyAlgorithms(hostKeys, addr),
+		Timeout:           15 * time.Second,
+	}
+
+	conn, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+			return nil, unknownHostError(host)
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start SFTP on %s: %w", host.HostName, err)
+	}
+	return &SFTPFS{Root: r.Path, conn: conn, client: client}, nil
+}
+
+// hostKeyAlgorithms lists the algorithms of the keys known_hosts holds for
+// addr. Without them the server may pick a key type that has no entry and
+// fail the check, although ssh itself would connect.
+func hostKeyAlgorithms(hostKeys ssh.HostKeyCallback, addr string) []string {
+	// A fresh key never matches, and the error lists the known ones
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil
+	}
+	probe, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil
+	}
+	var keyErr *knownhosts.KeyError
+	if !errors.As(hostKeys(addr, &net.TCPAddr{}, probe), &keyErr) {
+		return nil
+	}
+
+	var algorithms []string
+	for _, known := range keyErr.Want {
+		switch keyType := known.Key.Type(); keyType {
+		case ssh.KeyAlgoRSA:
+			algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA)
+		default:
+			algorithms = append(algorithms, keyType)
+		}
+	}
+	return algorithms
+}
+
+func unknownHostError(host SSHHost) error {
+	target := host.HostName
+	if host.Port != 22 {
+		target += " -p " + strconv.Itoa(host.Port)
+	}
+	return fmt.Errorf("%s is not in known_hosts. Connect once with 'ssh %s@%s' to verify its host key", host.HostName, host.User, target)
+}
+
+func (s *SFTPFS) path(rel string) string {
+	return path.Join(s.Root, rel)
+}
+
+func (s *SFTPFS) List() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	if _, err := s.client.Stat(s.Root); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entries, nil
+		}
+		return nil, err
+	}
+
+	walker := s.client.Walk(s.Root)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return nil, err
+		}
+		if walker.Path() == s.Root {
+			continue
+		}
+		info := walker.Stat()
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			continue
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(walker.Path(), s.Root), "/")
+		if s.Root == "." {
+			rel = strings.TrimPrefix(walker.Path(), "./")
+		}
+		entries[rel] = Entry{Size: info.Size(), ModTime: info.ModTime(), Mode: info.Mode().Perm(), Dir: info.IsDir()}
+	}
+	return entries, nil
+}
+
+func (s *SFTPFS) MkdirAll(rel string) error {
+	return s.client.MkdirAll(s.path(rel))
+}
+
+func (s *SFTPFS) Create(rel string) (io.WriteCloser, error) {
+	return s.client.Create(s.path(rel))
+}
+
+func (s *SFTPFS) Rename(oldRel, newRel string) error {
+	// PosixRename overwrites the target atomically where the server supports it
+	if err := s.client.PosixRename(s.path(oldRel), s.path(newRel)); err == nil {
+		return nil
+	}
+	s.client.Remove(s.path(newRel))
+	return s.client.Rename(s.path(oldRel), s.path(newRel))
+}
+
+func (s *SFTPFS) Remove(rel string) error {
+	return s.client.Remove(s.path(rel))
+}
+
+func (s *SFTPFS) Chtimes(rel string, mtime time.Time) error {
+	return s.client.Chtimes(s.path(rel), mtime, mtime)
+}
+
+func (s *SFTPFS) Chmod(rel string, mode os.FileMode) error {
+	return s.client.Chmod(s.path(rel), mode)
+}
+
+func (s *SFTPFS) Close() error {
+	s.client.Close()
+	return s.conn.Close()
+}
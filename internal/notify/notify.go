@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Urgency levels, following the freedesktop notification spec
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+// LongRunning is how long a command must run before Done and Failed notify
+// from an interactive terminal
+const LongRunning = 30 * time.Second
+
+// Notification is a desktop notification
+type Notification struct {
+	Title   string
+	Body    string
+	Sound   bool
+	Urgency string
+}
+
+// Send shows a desktop notification.
+//
+// Platform-specific behavior:
+//   - macOS: osascript "display notification"
+//   - Linux: notify-send
+//   - Windows: a toast through PowerShell
+func Send(n Notification) error {
+	if n.Title == "" {
+		n.Title = "zzk"
+	}
+	if n.Urgency == "" {
+		n.Urgency = UrgencyNormal
+	}
+	switch n.Urgency {
+	case UrgencyLow, UrgencyNormal, UrgencyCritical:
+	default:
+		return fmt.Errorf("unknown urgency %q (use low, normal or critical)", n.Urgency)
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return sendDarwin(n)
+	case "linux":
+		return sendLinux(n)
+	case "windows":
+		return sendWindows(n)
+	default:
+		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+// Done notifies that a command finished, but only when it ran long enough
+// for the user to have looked away, or ran unattended (e.g. from zzk cron).
+// Notification errors are ignored since they should never fail the command.
+func Done(title, body string, started time.Time) {
+	if !worthNotifying(started) {
+		return
+	}
+	_ = Send(Notification{Title: title, Body: body, Sound: true})
+}
+
+// Failed is Done for errors, sent with critical urgency
+func Failed(title string, err error, started time.Time) {
+	if err == nil || !worthNotifying(started) {
+		return
+	}
+	_ = Send(Notification{Title: title, Body: err.Error(), Sound: true, Urgency: UrgencyCritical})
+}
+
+func worthNotifying(started time.Time) bool {
+	return !term.IsTerminal(int(os.Stdout.Fd())) || time.Since(started) >= LongRunning
+}
+
+func sendDarwin(n Notification) error {
+	script := fmt.Sprintf("display notification %s with title %s", appleScriptQuote(n.Body), appleScriptQuote(n.Title))
+	if n.Sound || n.Urgency == UrgencyCritical {
+		script += ` sound name "default"`
+	}
+	return run("osascript", "-e", script)
+}
+
+func sendLinux(n Notification) error {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return fmt.Errorf("notify-send is not installed.\n" +
+			"  Linux (Debian/Ubuntu): sudo apt install libnotify-bin\n" +
+			"  Linux (Fedora): sudo dnf install libnotify")
+	}
+	args := []string{"-a", "zzk", "-u", n.Urgency}
+	if n.Sound {
+		args = append(args, "-h", "string:sound-name:complete")
+	}
+	args = append(args, n.Title)
+	if n.Body != "" {
+		args = append(args, n.Body)
+	}
+	return run("notify-send", args...)
+}
+
+// powershellAppID is PowerShell's registered AppUserModelID; toasts from an
+// unregistered ID are silently dropped
+const powershellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+func sendWindows(n Notification) error {
+	audio := `<audio silent="true"/>`
+	if n.Sound || n.Urgency == UrgencyCritical {
+		audio = `<audio src="ms-winsoundevent:Notification.Default"/>`
+	}
+	scenario := ""
+	if n.Urgency == UrgencyCritical {
+		scenario = ` scenario="reminder"`
+	}
+	xml := fmt.Sprintf(`<toast%s><visual><binding template="ToastGeneric"><text>%s</text><text>%s</text></binding></visual>%s</toast>`,
+		scenario, xmlEscape(n.Title), xmlEscape(n.Body), audio)
+
+	script := strings.Join([]string{
+		`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null`,
+		`[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] > $null`,
+		`$xml = New-Object Windows.Data.Xml.Dom.XmlDocument`,
+		`$xml.LoadXml(` + powershellQuote(xml) + `)`,
+		`$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)`,
+		`[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(` + powershellQuote(powershellAppID) + `).Show($toast)`,
+	}, "; ")
+	return run("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+}
+
+func appleScriptQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func powershellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace(s)
+}
+
+func run(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s failed: %s", name, msg)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// UUIDv4 returns a random (version 4) UUID
+func UUIDv4() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+// UUIDv7 returns a time-ordered (version 7) UUID
+func UUIDv7() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+
+	b[6] = (b[6] & 0x0f) | 0x70
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+func formatUUID(b [16]byte) string {
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// ULID returns a lexicographically sortable identifier (48-bit ms timestamp + 80 random bits)
+func ULID() string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	rand.Read(b[6:])
+
+	// 128 bits as 26 Crockford base32 characters, most significant first
+	n := new(big.Int).SetBytes(b[:])
+	out := make([]byte, 26)
+	mask := big.NewInt(31)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[new(big.Int).And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(out)
+}
+
+// Hex returns n random hex characters
+func Hex(n int) string {
+	b := make([]byte, (n+1)/2)
+	rand.Read(b)
+	return hex.EncodeToString(b)[:n]
+}
+
+// Base58 returns n random characters from the Bitcoin base58 alphabet
+// (no 0/O/I/l, so tokens are easy to read and type)
+func Base58(n int) string {
+	return fromAlphabet(base58Alphabet, n)
+}
+
+func fromAlphabet(alphabet string, n int) string {
+	out := make([]byte, n)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range out {
+		idx, _ := rand.Int(rand.Reader, max)
+		out[i] = alphabet[idx.Int64()]
+	}
+	return string(out)
+}
+
+// Int returns a uniformly random integer in [min, max]
+func Int(min, max int64) (int64, error) {
+	if max < min {
+		return 0, fmt.Errorf("max (%d) is less than min (%d)", max, min)
+	}
+	// max-min+1 overflows int64 for ranges wider than half of it
+	span := new(big.Int).Sub(big.NewInt(max), big.NewInt(min))
+	n, err := rand.Int(rand.Reader, span.Add(span, big.NewInt(1)))
+	if err != nil {
+		return 0, err
+	}
+	return n.Add(n, big.NewInt(min)).Int64(), nil
+}
+
+// Roll rolls dice in NdS[+M] notation (e.g. d20, 2d6, 3d8+2), returning each die and the total
+func Roll(spec string) ([]int64, int64, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	countStr, rest, ok := strings.Cut(spec, "d")
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid dice %q (expected NdS, e.g. 2d6)", spec)
+	}
+
+	modifier := int64(0)
+	sidesStr := rest
+	if i := strings.IndexAny(rest, "+-"); i >= 0 {
+		m, err := strconv.ParseInt(rest[i:], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid modifier in %q", spec)
+		}
+		modifier = m
+		sidesStr = rest[:i]
+	}
+
+	count := int64(1)
+	if countStr != "" {
+		c, err := strconv.ParseInt(countStr, 10, 64)
+		if err != nil || c < 1 || c > 1000 {
+			return nil, 0, fmt.Errorf("invalid dice count in %q", spec)
+		}
+		count = c
+	}
+	sides, err := strconv.ParseInt(sidesStr, 10, 64)
+	if err != nil || sides < 2 {
+		return nil, 0, fmt.Errorf("invalid number of sides in %q", spec)
+	}
+
+	rolls := make([]int64, count)
+	total := modifier
+	for i := range rolls {
+		rolls[i], _ = Int(1, sides)
+		total += rolls[i]
+	}
+	return rolls, total, nil
+}
@@ -0,0 +1,122 @@
+package rename
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// exifDate reads DateTimeOriginal (or DateTime) from a JPEG's EXIF block
+func exifDate(path string) (time.Time, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".jpg" && ext != ".jpeg" {
+		return time.Time{}, false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+
+	// EXIF lives in an APP1 segment near the start of the file
+	head := make([]byte, 128*1024)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	if len(head) < 4 || head[0] != 0xFF || head[1] != 0xD8 {
+		return time.Time{}, false
+	}
+
+	pos := 2
+	for pos+4 <= len(head) {
+		if head[pos] != 0xFF {
+			return time.Time{}, false
+		}
+		marker := head[pos+1]
+		// The length counts its own two bytes, so anything shorter is corrupt
+		length := int(binary.BigEndian.Uint16(head[pos+2:]))
+		segStart, segEnd := pos+4, pos+2+length
+		if length < 2 || segEnd > len(head) {
+			return time.Time{}, false
+		}
+		if marker == 0xE1 && bytes.HasPrefix(head[segStart:segEnd], []byte("Exif\x00\x00")) {
+			return parseTIFFDate(head[segStart+6 : segEnd])
+		}
+		if marker == 0xDA { // Start of scan: no more metadata
+			break
+		}
+		pos = segEnd
+	}
+	return time.Time{}, false
+}
+
+const (
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagDateTimeOriginal = 0x9003
+)
+
+func parseTIFFDate(tiff []byte) (time.Time, bool) {
+	if len(tiff) < 8 {
+		return time.Time{}, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}, false
+	}
+
+	ifd0 := order.Uint32(tiff[4:])
+	entries := readIFD(tiff, order, ifd0)
+
+	if offset, ok := entries[tagExifIFD]; ok {
+		exif := readIFD(tiff, order, offset)
+		if offset, ok := exif[tagDateTimeOriginal]; ok {
+			if t, ok := parseExifTime(tiff, offset); ok {
+				return t, true
+			}
+		}
+	}
+	if offset, ok := entries[tagDateTime]; ok {
+		return parseExifTime(tiff, offset)
+	}
+	return time.Time{}, false
+}
+
+// readIFD returns tag → value/offset for the entries of one IFD
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) map[uint16]uint32 {
+	entries := make(map[uint16]uint32)
+	if int(offset)+2 > len(tiff) {
+		return entries
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		start := int(offset) + 2 + i*12
+		if start+12 > len(tiff) {
+			break
+		}
+		tag := order.Uint16(tiff[start:])
+		entries[tag] = order.Uint32(tiff[start+8:])
+	}
+	return entries
+}
+
+// parseExifTime parses the 19-character "2006:01:02 15:04:05" ASCII value at offset
+func parseExifTime(tiff []byte, offset uint32) (time.Time, bool) {
+	if int(offset)+19 > len(tiff) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation("2006:01:02 15:04:05", string(tiff[offset:offset+19]), time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,197 @@
+package rename
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Style selects how names are normalized
+type Style string
+
+const (
+	StyleNone     Style = ""
+	StyleRestrict Style = "restrict" // yt-dlp --restrict-filenames: ASCII, underscores
+	StyleSlug     Style = "slug"     // lowercase-with-dashes
+)
+
+// Options configures how new names are computed
+type Options struct {
+	Style      Style
+	Replace    []Replacement
+	DatePrefix bool // Prefix with EXIF capture date or modification date
+	DateFormat string
+}
+
+// Replacement is a literal or regular expression find/replace applied to the name stem
+type Replacement struct {
+	Pattern *regexp.Regexp
+	With    string
+}
+
+// ParseReplacement parses "find=replace"; find is literal unless regex is set
+func ParseReplacement(s string, regex bool) (Replacement, error) {
+	find, with, ok := strings.Cut(s, "=")
+	if !ok || find == "" {
+		return Replacement{}, fmt.Errorf("invalid replacement %q (expected find=replace)", s)
+	}
+	if !regex {
+		find = regexp.QuoteMeta(find)
+	}
+	pattern, err := regexp.Compile(find)
+	if err != nil {
+		return Replacement{}, fmt.Errorf("invalid pattern %q: %w", find, err)
+	}
+	return Replacement{Pattern: pattern, With: with}, nil
+}
+
+// Plan is a single proposed rename
+type Plan struct {
+	Old string
+	New string
+}
+
+// NewName computes the new base name for path
+func NewName(path string, opts Options) string {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	for _, r := range opts.Replace {
+		stem = r.Pattern.ReplaceAllString(stem, r.With)
+	}
+
+	switch opts.Style {
+	case StyleRestrict:
+		stem = Restrict(stem)
+		ext = strings.ToLower(ext)
+	case StyleSlug:
+		stem = Slugify(stem)
+		ext = strings.ToLower(ext)
+	}
+
+	if opts.DatePrefix {
+		format := opts.DateFormat
+		if format == "" {
+			format = "2006-01-02"
+		}
+		prefix := FileDate(path).Format(format)
+		sep := "_"
+		if opts.Style == StyleSlug {
+			sep = "-"
+		}
+		if !strings.HasPrefix(stem, prefix) {
+			stem = prefix + sep + stem
+		}
+	}
+
+	if stem == "" {
+		stem = "file"
+	}
+	return stem + ext
+}
+
+// stripAccents removes diacritics: "Café" → "Cafe"
+func stripAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+var (
+	restrictInvalid = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+	repeatedUnder   = regexp.MustCompile(`_{2,}`)
+	slugInvalid     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Restrict applies yt-dlp style --restrict-filenames rules: accents are
+// stripped, spaces and other unsafe characters become underscores, and
+// runs of underscores are collapsed
+func Restrict(s string) string {
+	s = stripAccents(s)
+	s = strings.ReplaceAll(s, "&", "and")
+	s = strings.NewReplacer(": ", "_-_", ":", "_-", "'", "", `"`, "", "?", "").Replace(s)
+	s = restrictInvalid.ReplaceAllString(s, "_")
+	s = repeatedUnder.ReplaceAllString(s, "_")
+	return strings.Trim(s, "_-. ")
+}
+
+// Slugify lowercases and joins words with dashes
+func Slugify(s string) string {
+	s = strings.ToLower(stripAccents(s))
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.ReplaceAll(s, "'", "")
+	s = slugInvalid.ReplaceAllString(s, "-")
+	return strings.Trim(s, "-")
+}
+
+// FileDate returns the EXIF capture date for JPEG images, or the modification time
+func FileDate(path string) time.Time {
+	if t, ok := exifDate(path); ok {
+		return t
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Now()
+	}
+	return info.ModTime()
+}
+
+// BuildPlan computes renames for files, skipping unchanged names and
+// resolving collisions with a numeric suffix
+func BuildPlan(files []string, opts Options) []Plan {
+	taken := make(map[string]bool)
+	for _, f := range files {
+		taken[strings.ToLower(f)] = true
+	}
+
+	var plans []Plan
+	for _, old := range files {
+		dir := filepath.Dir(old)
+		name := NewName(old, opts)
+		newPath := filepath.Join(dir, name)
+		if newPath == old {
+			continue
+		}
+
+		// Avoid clobbering existing files or names chosen earlier in this plan.
+		// Case-only renames of the same file are fine.
+		ext := filepath.Ext(name)
+		stem := strings.TrimSuffix(name, ext)
+		for i := 1; ; i++ {
+			key := strings.ToLower(newPath)
+			_, statErr := os.Lstat(newPath)
+			sameFile := strings.EqualFold(newPath, old)
+			if sameFile || (!taken[key] && os.IsNotExist(statErr)) {
+				break
+			}
+			newPath = filepath.Join(dir, fmt.Sprintf("%s_%d%s", stem, i, ext))
+		}
+
+		delete(taken, strings.ToLower(old))
+		taken[strings.ToLower(newPath)] = true
+		plans = append(plans, Plan{Old: old, New: newPath})
+	}
+	return plans
+}
+
+// Apply performs the renames
+func Apply(plans []Plan) error {
+	for _, p := range plans {
+		if err := os.Rename(p.Old, p.New); err != nil {
+			return fmt.Errorf("failed to rename %s: %w", p.Old, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"al.essio.dev/pkg/shellescape"
+)
+
+// crontab installs jobs as lines in the user's crontab, each tagged with a
+// trailing "# zzk:<name>" marker so other entries are left untouched
+type crontab struct{}
+
+func (crontab) Name() string { return "crontab" }
+
+func crontabMarker(name string) string {
+	return "# zzk:" + name
+}
+
+// cronExpr converts a spec to a five-field cron expression
+func cronExpr(spec Spec) (string, error) {
+	if spec.Interval > 0 {
+		switch {
+		case spec.Interval < time.Hour && spec.Interval%time.Minute == 0 && 60%int(spec.Interval.Minutes()) == 0:
+			return fmt.Sprintf("*/%d * * * *", int(spec.Interval.Minutes())), nil
+		case spec.Interval%time.Hour == 0 && spec.Interval <= 24*time.Hour && 24%int(spec.Interval.Hours()) == 0:
+			return fmt.Sprintf("0 */%d * * *", int(spec.Interval.Hours())), nil
+		default:
+			return "", fmt.Errorf("interval %s cannot be expressed in crontab (use a divisor of 60 minutes or 24 hours)", spec.Interval)
+		}
+	}
+
+	hour, weekday := "*", "*"
+	if spec.Hour >= 0 {
+		hour = fmt.Sprint(spec.Hour)
+	}
+	if spec.Weekday >= 0 {
+		weekday = fmt.Sprint(spec.Weekday)
+	}
+	return fmt.Sprintf("%d %s * * %s", spec.Minute, hour, weekday), nil
+}
+
+func readCrontab() (string, error) {
+	output, err := exec.Command("crontab", "-l").Output()
+	if err != nil {
+		// crontab -l exits non-zero when the user has no crontab yet
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read crontab: %w", err)
+	}
+	return string(output), nil
+}
+
+func writeCrontab(content string) error {
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(content)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write crontab: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// withoutJob returns the crontab with the job's line removed
+func withoutJob(content, name string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if line == "" && len(lines) == 0 {
+			continue
+		}
+		if strings.HasSuffix(line, crontabMarker(name)) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (crontab) Install(job *Job, spec Spec, command []string, logPath string) error {
+	expr, err := cronExpr(spec)
+	if err != nil {
+		return err
+	}
+	current, err := readCrontab()
+	if err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("%s %s >> %s 2>&1 %s\n", expr, shellescape.QuoteCommand(command), shellescape.Quote(logPath), crontabMarker(job.Name))
+	return writeCrontab(withoutJob(current, job.Name) + line)
+}
+
+func (crontab) Uninstall(name string) error {
+	current, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(current, crontabMarker(name)) {
+		return nil
+	}
+	return writeCrontab(withoutJob(current, name))
+}
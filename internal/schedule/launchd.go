@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// launchd installs jobs as per-user LaunchAgents
+type launchd struct{}
+
+func (launchd) Name() string { return "launchd" }
+
+func launchdLabel(name string) string {
+	return "dev.zzk." + name
+}
+
+func launchdPlistPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel(name)+".plist"), nil
+}
+
+func launchdDomain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+// renderPlist renders a LaunchAgent plist for the job
+func renderPlist(label string, spec Spec, command []string, logPath string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", html.EscapeString(label))
+
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range command {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", html.EscapeString(arg))
+	}
+	b.WriteString("\t</array>\n")
+
+	if spec.Interval > 0 {
+		fmt.Fprintf(&b, "\t<key>StartInterval</key>\n\t<integer>%d</integer>\n", int(spec.Interval.Seconds()))
+	} else {
+		b.WriteString("\t<key>StartCalendarInterval</key>\n\t<dict>\n")
+		if spec.Weekday >= 0 {
+			fmt.Fprintf(&b, "\t\t<key>Weekday</key>\n\t\t<integer>%d</integer>\n", spec.Weekday)
+		}
+		if spec.Hour >= 0 {
+			fmt.Fprintf(&b, "\t\t<key>Hour</key>\n\t\t<integer>%d</integer>\n", spec.Hour)
+		}
+		fmt.Fprintf(&b, "\t\t<key>Minute</key>\n\t\t<integer>%d</integer>\n", spec.Minute)
+		b.WriteString("\t</dict>\n")
+	}
+
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", html.EscapeString(logPath))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", html.EscapeString(logPath))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func (l launchd) Install(job *Job, spec Spec, command []string, logPath string) error {
+	path, err := launchdPlistPath(job.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(renderPlist(launchdLabel(job.Name), spec, command, logPath)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	// Reload in case an older version is already loaded
+	_ = exec.Command("launchctl", "bootout", launchdDomain()+"/"+launchdLabel(job.Name)).Run()
+	if output, err := exec.Command("launchctl", "bootstrap", launchdDomain(), path).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl bootstrap failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
+func (l launchd) Uninstall(name string) error {
+	path, err := launchdPlistPath(name)
+	if err != nil {
+		return err
+	}
+	_ = exec.Command("launchctl", "bootout", launchdDomain()+"/"+launchdLabel(name)).Run()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
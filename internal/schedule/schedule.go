@@ -0,0 +1,208 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Job is a recurring zzk command
+type Job struct {
+	Name     string   `json:"name"`
+	Schedule string   `json:"schedule"`
+	Args     []string `json:"args"` // Arguments passed to zzk
+	Enabled  bool     `json:"enabled"`
+}
+
+// Spec is a parsed schedule: either a fixed interval or a calendar time.
+// Calendar fields are -1 when they match any value.
+type Spec struct {
+	Interval time.Duration
+	Minute   int
+	Hour     int
+	Weekday  int // 0 = Sunday
+}
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ValidateName checks a job name is safe to use in file and unit names
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid job name %q (use lowercase letters, digits and dashes)", name)
+	}
+	return nil
+}
+
+// ParseSpec parses a schedule:
+//
+//	hourly            every hour on the hour
+//	daily[@HH:MM]     every day (default 03:00)
+//	weekly[@HH:MM]    every Sunday (default 03:00)
+//	every <duration>  fixed interval, e.g. "every 30m", "every 6h"
+func ParseSpec(s string) (Spec, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if rest, ok := strings.CutPrefix(s, "every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Minute {
+			return Spec{}, fmt.Errorf("invalid interval %q (e.g. 'every 30m', minimum 1m)", rest)
+		}
+		return Spec{Interval: d, Minute: -1, Hour: -1, Weekday: -1}, nil
+	}
+
+	base, at, hasAt := strings.Cut(s, "@")
+	hour, minute := 3, 0
+	if hasAt {
+		h, m, ok := strings.Cut(at, ":")
+		var err1, err2 error
+		hour, err1 = strconv.Atoi(h)
+		minute, err2 = strconv.Atoi(m)
+		if !ok || err1 != nil || err2 != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+			return Spec{}, fmt.Errorf("invalid time %q (expected HH:MM)", at)
+		}
+	}
+
+	switch base {
+	case "hourly":
+		if hasAt {
+			return Spec{}, fmt.Errorf("hourly does not take a time")
+		}
+		return Spec{Minute: 0, Hour: -1, Weekday: -1}, nil
+	case "daily":
+		return Spec{Minute: minute, Hour: hour, Weekday: -1}, nil
+	case "weekly":
+		return Spec{Minute: minute, Hour: hour, Weekday: 0}, nil
+	default:
+		return Spec{}, fmt.Errorf("invalid schedule %q (use hourly, daily[@HH:MM], weekly[@HH:MM] or 'every <duration>')", s)
+	}
+}
+
+// Config holds all registered jobs, persisted in ~/.config/zzk/cron.json
+type Config struct {
+	Jobs map[string]*Job `json:"jobs"`
+}
+
+func configPath() (string, error) {
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cron.json"), nil
+}
+
+// Load loads the registered jobs
+func Load() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{Jobs: make(map[string]*Job)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, fmt.Errorf("failed to read jobs: %w", err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", path, err)
+	}
+	if config.Jobs == nil {
+		config.Jobs = make(map[string]*Job)
+	}
+	return config, nil
+}
+
+// Save writes the registered jobs
+func (c *Config) Save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal jobs: %w", err)
+	}
+	return fileutil.AtomicWrite(path, data, 0644)
+}
+
+// Sorted returns the jobs sorted by name
+func (c *Config) Sorted() []*Job {
+	jobs := make([]*Job, 0, len(c.Jobs))
+	for _, job := range c.Jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// LogPath returns the file a job's output is appended to
+func LogPath(name string) (string, error) {
+	dir, err := fileutil.CacheDir("cron")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".log"), nil
+}
+
+// Backend installs jobs into a platform scheduler
+type Backend interface {
+	Name() string
+	Install(job *Job, spec Spec, command []string, logPath string) error
+	Uninstall(name string) error
+}
+
+// DetectBackend picks launchd on macOS, systemd user timers on Linux when
+// available, and crontab otherwise
+func DetectBackend() (Backend, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return launchd{}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if systemdAvailable() {
+			return systemd{}, nil
+		}
+		return crontab{}, nil
+	default:
+		return nil, fmt.Errorf("scheduling is not supported on %s", runtime.GOOS)
+	}
+}
+
+// Command returns the full command line for a job using the running zzk binary
+func Command(job *Job) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate zzk executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return append([]string{exe}, job.Args...), nil
+}
+
+// Enable installs a job into the backend
+func Enable(backend Backend, job *Job) error {
+	spec, err := ParseSpec(job.Schedule)
+	if err != nil {
+		return err
+	}
+	command, err := Command(job)
+	if err != nil {
+		return err
+	}
+	logPath, err := LogPath(job.Name)
+	if err != nil {
+		return err
+	}
+	return backend.Install(job, spec, command, logPath)
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemd installs jobs as user services with timers
+type systemd struct{}
+
+func (systemd) Name() string { return "systemd" }
+
+func systemdAvailable() bool {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return false
+	}
+	return exec.Command("systemctl", "--user", "show-environment").Run() == nil
+}
+
+func systemdUnit(name string) string {
+	return "zzk-" + name
+}
+
+func systemdUnitDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "systemd", "user"), nil
+}
+
+// onCalendar converts a calendar spec to a systemd OnCalendar expression
+func onCalendar(spec Spec) string {
+	if spec.Hour < 0 {
+		return fmt.Sprintf("*-*-* *:%02d:00", spec.Minute)
+	}
+	expr := fmt.Sprintf("*-*-* %02d:%02d:00", spec.Hour, spec.Minute)
+	if spec.Weekday >= 0 {
+		days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+		expr = days[spec.Weekday] + " " + expr
+	}
+	return expr
+}
+
+// systemdQuote quotes an argument for ExecStart
+func systemdQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\$%;") {
+		return arg
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + r.Replace(arg) + `"`
+}
+
+func renderService(job *Job, command []string, logPath string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = systemdQuote(arg)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=zzk %s
+
+[Service]
+Type=oneshot
+ExecStart=%s
+StandardOutput=append:%s
+StandardError=append:%s
+`, job.Name, strings.Join(quoted, " "), logPath, logPath)
+}
+
+func renderTimer(job *Job, spec Spec) string {
+	var trigger string
+	if spec.Interval > 0 {
+		secs := int(spec.Interval.Seconds())
+		trigger = fmt.Sprintf("OnBootSec=%ds\nOnUnitActiveSec=%ds", secs, secs)
+	} else {
+		trigger = "OnCalendar=" + onCalendar(spec) + "\nPersistent=true"
+	}
+	return fmt.Sprintf(`[Unit]
+Description=zzk %s (%s)
+
+[Timer]
+%s
+
+[Install]
+WantedBy=timers.target
+`, job.Name, job.Schedule, trigger)
+}
+
+func (systemd) Install(job *Job, spec Spec, command []string, logPath string) error {
+	dir, err := systemdUnitDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	unit := systemdUnit(job.Name)
+	if err := os.WriteFile(filepath.Join(dir, unit+".service"), []byte(renderService(job, command, logPath)), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, unit+".timer"), []byte(renderTimer(job, spec)), 0644); err != nil {
+		return err
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return systemctl("enable", "--now", unit+".timer")
+}
+
+func (systemd) Uninstall(name string) error {
+	dir, err := systemdUnitDir()
+	if err != nil {
+		return err
+	}
+	unit := systemdUnit(name)
+	_ = systemctl("disable", "--now", unit+".timer")
+
+	for _, ext := range []string{".service", ".timer"} {
+		if err := os.Remove(filepath.Join(dir, unit+ext)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return systemctl("daemon-reload")
+}
+
+func systemctl(args ...string) error {
+	output, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl --user %s failed: %w\n%s", strings.Join(args, " "), err, output)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Item is a single TODO/FIXME/HACK comment found in a repository
+type Item struct {
+	Identity string    `json:"identity,omitempty"`
+	Repo     string    `json:"repo"`
+	File     string    `json:"file"`
+	Line     int       `json:"line"`
+	Tag      string    `json:"tag"`
+	Text     string    `json:"text"`
+	Author   string    `json:"author,omitempty"`
+	Date     time.Time `json:"date,omitzero"`
+}
+
+// Tags are the markers searched for
+var Tags = []string{"TODO", "FIXME", "HACK"}
+
+var tagRegex = regexp.MustCompile(`\b(` + strings.Join(Tags, "|") + `)\b(\([^)]*\))?:?`)
+
+// Scan finds all tagged comments in the tracked files of a repository
+func Scan(repo string) ([]Item, error) {
+	pattern := `\b(` + strings.Join(Tags, "|") + `)\b`
+	cmd := exec.Command("git", "grep", "-z", "-n", "-I", "-E", pattern)
+	cmd.Dir = repo
+	output, err := cmd.Output()
+	if err != nil {
+		// git grep exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var items []Item
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// Format: file\0line\0content, so file names may contain colons
+		parts := strings.SplitN(scanner.Text(), "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		line, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		loc := tagRegex.FindStringSubmatchIndex(parts[2])
+		if loc == nil {
+			continue
+		}
+
+		items = append(items, Item{
+			Repo: repo,
+			File: parts[0],
+			Line: line,
+			Tag:  parts[2][loc[2]:loc[3]],
+			Text: strings.TrimSpace(parts[2][loc[1]:]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git grep output: %w", err)
+	}
+
+	return items, nil
+}
+
+// Blame fills in author and date for items using git blame, one run per file
+func Blame(repo string, items []Item) {
+	byFile := make(map[string][]int)
+	for i, item := range items {
+		byFile[item.File] = append(byFile[item.File], i)
+	}
+
+	for file, indexes := range byFile {
+		lines := blameFile(repo, file)
+		if lines == nil {
+			continue
+		}
+		for _, i := range indexes {
+			if info, ok := lines[items[i].Line]; ok {
+				items[i].Author = info.author
+				items[i].Date = info.date
+			}
+		}
+	}
+}
+
+type blameInfo struct {
+	author string
+	date   time.Time
+}
+
+// blameFile returns blame info keyed by final line number
+func blameFile(repo, file string) map[int]blameInfo {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", file)
+	cmd.Dir = repo
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	result := make(map[int]blameInfo)
+	var current blameInfo
+	var lineNo int
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			result[lineNo] = current
+			current = blameInfo{}
+		case strings.HasPrefix(line, "author "):
+			current.author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if ts, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current.date = time.Unix(ts, 0)
+			}
+		default:
+			// Header line: <sha> <orig-line> <final-line> [<count>], the
+			// hash being SHA-1 or, in SHA-256 repositories, 64 characters
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && (len(fields[0]) == 40 || len(fields[0]) == 64) {
+				if n, err := strconv.Atoi(fields[2]); err == nil {
+					lineNo = n
+				}
+			}
+		}
+	}
+	if scanner.Err() != nil {
+		return nil // Leave the items without blame rather than half of it
+	}
+
+	return result
+}
@@ -0,0 +1,230 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/ppowo/zzk/internal/crypt"
+	"github.com/ppowo/zzk/internal/fileutil"
+	"golang.org/x/term"
+)
+
+// PassphraseEnvVar holds the passphrase of the file vault
+const PassphraseEnvVar = "ZZK_VAULT_PASSPHRASE"
+
+// fileBackend keeps secrets in vault/secrets.age, the fallback for machines
+// without a keychain, encrypted with a passphrase read from
+// $ZZK_VAULT_PASSPHRASE or prompted for. Vaults from before passphrases were
+// encrypted to a key in vault/key.txt, next to the secrets; they are still
+// read, and re-encrypted with a passphrase the next time they are written.
+// One backend is opened per process, so the passphrase is asked for and the
+// vault decrypted once.
+type fileBackend struct {
+	dir string
+	mu  sync.Mutex
+
+	id         *age.X25519Identity // Set for a vault still using key.txt
+	passphrase string
+	secrets    map[string]string // Decrypted on first use
+}
+
+// open finds out how the vault is encrypted and gets its key or passphrase
+func (f *fileBackend) open() error {
+	if f.id != nil || f.passphrase != "" {
+		return nil
+	}
+	keyPath := filepath.Join(f.dir, "key.txt")
+	if _, err := os.Stat(keyPath); err == nil {
+		id, err := crypt.LoadOrCreateKey(keyPath)
+		if err != nil {
+			return err
+		}
+		f.id = id
+		return nil
+	}
+
+	_, err := os.Stat(filepath.Join(f.dir, "secrets.age"))
+	passphrase, err := readPassphrase(os.IsNotExist(err))
+	if err != nil {
+		return err
+	}
+	f.passphrase = passphrase
+	return nil
+}
+
+// readPassphrase returns the vault passphrase from $ZZK_VAULT_PASSPHRASE or
+// prompts for it without echo. A new passphrase is asked for twice.
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) && confirm {
+		return "", fmt.Errorf("the vault file needs a passphrase and cannot prompt for one in non-interactive mode (set %s)", PassphraseEnvVar)
+	}
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the vault file is protected by a passphrase and cannot prompt in non-interactive mode (set %s)", PassphraseEnvVar)
+	}
+
+	if confirm {
+		fmt.Fprintln(os.Stderr, "Choose a passphrase for the vault in "+filepath.Join("~", ".config", "zzk", "vault"))
+	}
+	fmt.Fprint(os.Stderr, "Vault passphrase: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(first) == 0 {
+		return "", fmt.Errorf("passphrase must not be empty")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		second, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if string(first) != string(second) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return string(first), nil
+}
+
+func (f *fileBackend) load() (map[string]string, error) {
+	if f.secrets != nil {
+		return f.secrets, nil
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string)
+	file, err := os.Open(filepath.Join(f.dir, "secrets.age"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			f.secrets = secrets
+			return secrets, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var identity age.Identity = f.id
+	if f.id == nil {
+		if identity, err = crypt.PassphraseIdentity(f.passphrase); err != nil {
+			return nil, err
+		}
+	}
+	r, err := crypt.NewDecryptor(file, identity)
+	if err != nil && f.id == nil {
+		return nil, fmt.Errorf("failed to decrypt vault (wrong passphrase?): %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault: %w", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault: %w", err)
+	}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid vault contents: %w", err)
+	}
+	f.secrets = secrets
+	return secrets, nil
+}
+
+func (f *fileBackend) save(secrets map[string]string) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	// A vault still using key.txt moves to a passphrase
+	if f.id != nil {
+		passphrase, err := readPassphrase(true)
+		if err != nil {
+			return err
+		}
+		f.passphrase = passphrase
+	}
+
+	var buf bytes.Buffer
+	w, err := crypt.NewEncryptor(&buf, crypt.Options{Passphrase: f.passphrase})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := fileutil.AtomicWrite(filepath.Join(f.dir, "secrets.age"), buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	f.secrets = secrets
+	if f.id != nil {
+		f.id = nil
+		if err := os.Remove(filepath.Join(f.dir, "key.txt")); err != nil {
+			return fmt.Errorf("vault is now protected by the passphrase, but removing its old key failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (f *fileBackend) get(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secrets, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (f *fileBackend) set(name, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	if err := f.save(secrets); err != nil {
+		f.secrets = nil // Read it again rather than keep changes that weren't saved
+		return err
+	}
+	return nil
+}
+
+func (f *fileBackend) remove(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets, name)
+	if err := f.save(secrets); err != nil {
+		f.secrets = nil // Read it again rather than keep changes that weren't saved
+		return err
+	}
+	return nil
+}
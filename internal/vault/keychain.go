@@ -0,0 +1,41 @@
+package vault
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keychainService groups zzk's entries in the OS keychain
+const keychainService = "zzk"
+
+// keychainBackend uses the macOS Keychain, the Secret Service on Linux
+// (GNOME Keyring, KWallet) or the Windows Credential Manager
+type keychainBackend struct{}
+
+// keychainAvailable probes for a working keychain; a missing entry is the
+// expected answer, anything else means there is no usable service
+func keychainAvailable() bool {
+	_, err := keyring.Get(keychainService, "zzk-probe")
+	return errors.Is(err, keyring.ErrNotFound)
+}
+
+func (keychainBackend) get(name string) (string, error) {
+	value, err := keyring.Get(keychainService, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+func (keychainBackend) set(name, value string) error {
+	return keyring.Set(keychainService, name, value)
+}
+
+func (keychainBackend) remove(name string) error {
+	err := keyring.Delete(keychainService, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,215 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Backend names
+const (
+	BackendKeychain = "keychain"
+	BackendFile     = "file"
+)
+
+// BackendEnvVar forces a backend, e.g. "file" on headless machines
+const BackendEnvVar = "ZZK_VAULT_BACKEND"
+
+// ErrNotFound is returned for secrets that don't exist
+var ErrNotFound = errors.New("secret not found")
+
+// backend stores secret values. The index of names is kept separately since
+// keychains can't list entries by service portably.
+type backend interface {
+	get(name string) (string, error)
+	set(name, value string) error
+	remove(name string) error
+}
+
+// index is ~/.config/zzk/vault/index.json. It holds no secret values.
+type index struct {
+	Backend string   `json:"backend"`
+	Secrets []string `json:"secrets"`
+}
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+
+// ValidateName checks a secret name, e.g. "claude/synthetic" or "forge/github.com"
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) || len(name) > 128 {
+		return fmt.Errorf("invalid secret name '%s' (use letters, digits, '.', '_', '-' and '/')", name)
+	}
+	return nil
+}
+
+// Dir returns ~/.config/zzk/vault
+func Dir() (string, error) {
+	base, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "vault")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create vault directory: %w", err)
+	}
+	return dir, nil
+}
+
+func loadIndex() (*index, string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, "", err
+	}
+	path := filepath.Join(dir, "index.json")
+
+	idx := &index{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("failed to read vault index: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, idx); err != nil {
+			return nil, "", fmt.Errorf("invalid vault index %s: %w", path, err)
+		}
+	}
+	return idx, path, nil
+}
+
+func (idx *index) save(path string) error {
+	sort.Strings(idx.Secrets)
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.AtomicWrite(path, data, 0600)
+}
+
+// open returns the backend recorded in the index, choosing one on first use.
+// The choice is remembered so secrets don't silently move between backends.
+func open() (backend, *index, string, error) {
+	idx, path, err := loadIndex()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	name := idx.Backend
+	if forced := os.Getenv(BackendEnvVar); forced != "" {
+		name = forced
+	}
+	if name == "" {
+		name = BackendFile
+		if keychainAvailable() {
+			name = BackendKeychain
+		}
+	}
+	if idx.Backend != "" && idx.Backend != name && len(idx.Secrets) > 0 {
+		return nil, nil, "", fmt.Errorf("vault uses the %s backend but %s backend was requested", idx.Backend, name)
+	}
+	idx.Backend = name
+
+	switch name {
+	case BackendKeychain:
+		return keychainBackend{}, idx, path, nil
+	case BackendFile:
+		f, err := fileVault()
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return f, idx, path, nil
+	default:
+		return nil, nil, "", fmt.Errorf("unknown vault backend '%s' (use keychain or file)", name)
+	}
+}
+
+// fileVault is the file backend, opened once per process so its passphrase
+// and secrets are kept for later calls
+var fileVault = sync.OnceValues(func() (*fileBackend, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &fileBackend{dir: dir}, nil
+})
+
+// Backend returns the name of the backend in use
+func Backend() (string, error) {
+	_, idx, _, err := open()
+	if err != nil {
+		return "", err
+	}
+	return idx.Backend, nil
+}
+
+// Get returns a secret, or ErrNotFound
+func Get(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	b, _, _, err := open()
+	if err != nil {
+		return "", err
+	}
+	return b.get(name)
+}
+
+// Set stores a secret, replacing any previous value
+func Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("secret value must not be empty")
+	}
+	b, idx, path, err := open()
+	if err != nil {
+		return err
+	}
+	if err := b.set(name, value); err != nil {
+		return fmt.Errorf("failed to store secret '%s': %w", name, err)
+	}
+	if !slices.Contains(idx.Secrets, name) {
+		idx.Secrets = append(idx.Secrets, name)
+	}
+	return idx.save(path)
+}
+
+// Delete removes a secret, or returns ErrNotFound
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	b, idx, path, err := open()
+	if err != nil {
+		return err
+	}
+	// A secret missing from the backend but still indexed (e.g. removed from
+	// the keychain by hand) is only dropped from the index
+	if err := b.remove(name); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("failed to delete secret '%s': %w", name, err)
+		}
+		if !slices.Contains(idx.Secrets, name) {
+			return err
+		}
+	}
+	idx.Secrets = slices.DeleteFunc(idx.Secrets, func(s string) bool { return s == name })
+	return idx.save(path)
+}
+
+// List returns the names of all stored secrets
+func List() ([]string, error) {
+	idx, _, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(idx.Secrets)
+	return idx.Secrets, nil
+}
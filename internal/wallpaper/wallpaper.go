@@ -0,0 +1,212 @@
+package wallpaper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// ImageExtensions are the file types considered wallpapers
+var ImageExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".heic": true, ".webp": true,
+}
+
+// Set sets the desktop wallpaper. display selects a single display on macOS
+// (1-based); 0 means all displays.
+//
+// Platform-specific behavior:
+//   - macOS: System Events via osascript
+//   - Linux: gsettings (GNOME/Unity/Budgie/Cinnamon), plasma-apply-wallpaperimage (KDE),
+//     swaymsg (sway), otherwise feh
+func Set(file string, display int) error {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return fmt.Errorf("wallpaper not found: %w", err)
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return setDarwin(abs, display)
+	case "linux":
+		if display != 0 {
+			return fmt.Errorf("--display is only supported on macOS")
+		}
+		return setLinux(abs)
+	default:
+		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+func setDarwin(file string, display int) error {
+	target := "every desktop"
+	if display > 0 {
+		target = fmt.Sprintf("desktop %d", display)
+	}
+	script := fmt.Sprintf(`tell application "System Events" to set picture of %s to POSIX file %q`, target, file)
+	return run("osascript", "-e", script)
+}
+
+func setLinux(file string) error {
+	desktop := strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP"))
+
+	switch {
+	case strings.Contains(desktop, "gnome"), strings.Contains(desktop, "unity"), strings.Contains(desktop, "budgie"):
+		uri := "file://" + file
+		if err := run("gsettings", "set", "org.gnome.desktop.background", "picture-uri", uri); err != nil {
+			return err
+		}
+		// GNOME 42+ uses a separate key for dark mode; ignore failure on older versions
+		_ = run("gsettings", "set", "org.gnome.desktop.background", "picture-uri-dark", uri)
+		return nil
+	case strings.Contains(desktop, "cinnamon"):
+		return run("gsettings", "set", "org.cinnamon.desktop.background", "picture-uri", "file://"+file)
+	case strings.Contains(desktop, "kde"):
+		return run("plasma-apply-wallpaperimage", file)
+	case os.Getenv("SWAYSOCK") != "":
+		return run("swaymsg", "output", "*", "bg", file, "fill")
+	}
+
+	if _, err := exec.LookPath("feh"); err == nil {
+		return run("feh", "--bg-fill", file)
+	}
+	return fmt.Errorf("no supported wallpaper tool found for desktop %q (install feh)", desktop)
+}
+
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w\n%s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Download fetches an image URL into the wallpaper cache and returns its path.
+// The file is kept so the desktop can keep referencing it.
+func Download(url string) (string, error) {
+	dir, err := fileutil.CacheDir("wallpaper")
+	if err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0]))
+	if !ImageExtensions[ext] {
+		ext = ".jpg"
+	}
+	sum := sha256.Sum256([]byte(url))
+	dest := filepath.Join(dir, hex.EncodeToString(sum[:8])+ext)
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download wallpaper: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download wallpaper: %s", resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("URL is not an image (Content-Type: %s)", contentType)
+	}
+
+	tmp := dest + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to download wallpaper: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return dest, os.Rename(tmp, dest)
+}
+
+// Random picks a random image below dir, avoiding the current wallpaper when possible
+func Random(dir string) (string, error) {
+	var images []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && p != dir {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && ImageExtensions[strings.ToLower(filepath.Ext(p))] {
+			images = append(images, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	if len(images) == 0 {
+		return "", fmt.Errorf("no images found in %s", dir)
+	}
+
+	current := Current()
+	for range 10 {
+		pick := images[rand.IntN(len(images))]
+		if abs, _ := filepath.Abs(pick); abs != current || len(images) == 1 {
+			return pick, nil
+		}
+	}
+	return images[0], nil
+}
+
+// statePath is where the last wallpaper set by zzk is recorded
+func statePath() (string, error) {
+	dir, err := fileutil.CacheDir("wallpaper")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "current"), nil
+}
+
+// Current returns the last wallpaper set by zzk, or "" if unknown
+func Current() string {
+	path, err := statePath()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Remember records the wallpaper that was just set
+func Remember(file string) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	return fileutil.AtomicWrite(path, []byte(abs+"\n"), 0644)
+}
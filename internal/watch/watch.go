@@ -0,0 +1,143 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Options configures a watcher
+type Options struct {
+	Paths    []string      // Files or directories to watch
+	Exclude  []string      // Glob patterns matched against each path component below the watched path
+	Debounce time.Duration // Quiet period before a batch of changes is delivered
+	Shallow  bool          // Don't watch subdirectories
+}
+
+// Watch watches opts.Paths (directories recursively) and calls onChange with the
+// changed paths after each debounced batch. It blocks until ctx is cancelled.
+func Watch(ctx context.Context, opts Options, onChange func(changed []string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, path := range opts.Paths {
+		if opts.Shallow {
+			if err := watcher.Add(path); err != nil {
+				return fmt.Errorf("cannot watch %s: %w", path, err)
+			}
+			continue
+		}
+		if err := addRecursive(watcher, path, opts.Exclude); err != nil {
+			return err
+		}
+	}
+
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = 200 * time.Millisecond
+	}
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if fileutil.ExcludedPath(relToWatched(opts.Paths, event.Name), opts.Exclude) {
+				continue
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+
+			// Newly created directories need to be watched too
+			if event.Has(fsnotify.Create) && !opts.Shallow {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = addRecursive(watcher, event.Name, opts.Exclude)
+				}
+			}
+
+			pending[event.Name] = true
+			timer.Reset(debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "⚠ Watch error: %v\n", err)
+
+		case <-timer.C:
+			changed := make([]string, 0, len(pending))
+			for path := range pending {
+				changed = append(changed, path)
+			}
+			sort.Strings(changed)
+			pending = make(map[string]bool)
+			onChange(changed)
+		}
+	}
+}
+
+// addRecursive adds path and, if it is a directory, all non-excluded subdirectories
+func addRecursive(watcher *fsnotify.Watcher, root string, exclude []string) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("cannot watch %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return watcher.Add(root)
+	}
+
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if rel, _ := filepath.Rel(root, path); path != root && fileutil.ExcludedPath(rel, exclude) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// relToWatched returns path relative to the innermost watched path holding
+// it, so excludes don't match the directories above it, like a ~/.cache
+// the watched directory sits in
+func relToWatched(watched []string, path string) string {
+	best := ""
+	for _, root := range watched {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if best == "" || len(rel) < len(best) {
+			best = rel
+		}
+	}
+	if best == "" {
+		return filepath.Base(path)
+	}
+	return best
+}
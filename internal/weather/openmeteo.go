@@ -0,0 +1,158 @@
+package weather
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type geoPlace struct {
+	Name      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// geocode resolves a place name with the Open-Meteo geocoding API
+func geocode(location string) (*geoPlace, error) {
+	var result struct {
+		Results []struct {
+			Name      string  `json:"name"`
+			Country   string  `json:"country"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+
+	u := "https://geocoding-api.open-meteo.com/v1/search?count=1&name=" + url.QueryEscape(location)
+	if err := getJSON(u, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("location %q not found", location)
+	}
+
+	r := result.Results[0]
+	return &geoPlace{Name: r.Name, Country: r.Country, Latitude: r.Latitude, Longitude: r.Longitude}, nil
+}
+
+// geolocate approximates the current location from the public IP address
+func geolocate() (*geoPlace, error) {
+	var result struct {
+		City      string  `json:"city"`
+		Country   string  `json:"country_name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}
+	if err := getJSON("https://ipapi.co/json/", &result); err != nil {
+		return nil, fmt.Errorf("failed to geolocate (set a location with --location): %w", err)
+	}
+	return &geoPlace{Name: result.City, Country: result.Country, Latitude: result.Latitude, Longitude: result.Longitude}, nil
+}
+
+func fetchOpenMeteo(location string, imperial bool) (*Report, error) {
+	var place *geoPlace
+	var err error
+	if location == "" {
+		place, err = geolocate()
+	} else {
+		place, err = geocode(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%.4f", place.Latitude))
+	params.Set("longitude", fmt.Sprintf("%.4f", place.Longitude))
+	params.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,wind_speed_10m,weather_code")
+	params.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max")
+	params.Set("timezone", "auto")
+	params.Set("forecast_days", "3")
+	if imperial {
+		params.Set("temperature_unit", "fahrenheit")
+		params.Set("wind_speed_unit", "mph")
+	}
+
+	var result struct {
+		Current struct {
+			Temperature float64 `json:"temperature_2m"`
+			Apparent    float64 `json:"apparent_temperature"`
+			Humidity    int     `json:"relative_humidity_2m"`
+			Wind        float64 `json:"wind_speed_10m"`
+			Code        int     `json:"weather_code"`
+		} `json:"current"`
+		Daily struct {
+			Time         []string  `json:"time"`
+			Code         []int     `json:"weather_code"`
+			Max          []float64 `json:"temperature_2m_max"`
+			Min          []float64 `json:"temperature_2m_min"`
+			PrecipChance []int     `json:"precipitation_probability_max"`
+		} `json:"daily"`
+	}
+	if err := getJSON("https://api.open-meteo.com/v1/forecast?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	name := place.Name
+	if place.Country != "" {
+		name += ", " + place.Country
+	}
+
+	description, icon := describeWMO(result.Current.Code)
+	report := &Report{
+		Location: name,
+		Current: Current{
+			Temp:        result.Current.Temperature,
+			FeelsLike:   result.Current.Apparent,
+			Humidity:    result.Current.Humidity,
+			Wind:        result.Current.Wind,
+			Description: description,
+			Icon:        icon,
+		},
+	}
+
+	daily := result.Daily
+	for i, date := range daily.Time {
+		if i >= len(daily.Code) || i >= len(daily.Max) || i >= len(daily.Min) {
+			break
+		}
+		day := Day{Date: date, Min: daily.Min[i], Max: daily.Max[i]}
+		day.Description, day.Icon = describeWMO(daily.Code[i])
+		if i < len(daily.PrecipChance) {
+			day.PrecipChance = daily.PrecipChance[i]
+		}
+		report.Days = append(report.Days, day)
+	}
+
+	return report, nil
+}
+
+// describeWMO maps a WMO weather interpretation code to a description and icon
+func describeWMO(code int) (string, string) {
+	switch {
+	case code == 0:
+		return "Clear sky", "☀️"
+	case code == 1:
+		return "Mainly clear", "🌤"
+	case code == 2:
+		return "Partly cloudy", "⛅"
+	case code == 3:
+		return "Overcast", "☁️"
+	case code == 45 || code == 48:
+		return "Fog", "🌫"
+	case code >= 51 && code <= 57:
+		return "Drizzle", "🌦"
+	case code >= 61 && code <= 67:
+		return "Rain", "🌧"
+	case code >= 71 && code <= 77:
+		return "Snow", "🌨"
+	case code >= 80 && code <= 82:
+		return "Rain showers", "🌧"
+	case code == 85 || code == 86:
+		return "Snow showers", "🌨"
+	case code >= 95:
+		return "Thunderstorm", "⛈"
+	default:
+		return "Unknown", "?"
+	}
+}
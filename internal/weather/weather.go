@@ -0,0 +1,201 @@
+package weather
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ppowo/zzk/internal/fileutil"
+)
+
+// Backends
+const (
+	OpenMeteo = "open-meteo"
+	Wttr      = "wttr"
+)
+
+// CacheTTL is how long a fetched report is reused
+const CacheTTL = 15 * time.Minute
+
+// Config holds the default location and backend, stored in ~/.config/zzk/weather.json
+type Config struct {
+	Location string `json:"location,omitempty"`
+	Backend  string `json:"backend,omitempty"`
+	Imperial bool   `json:"imperial,omitempty"`
+}
+
+// Report is the current conditions plus a short daily forecast
+type Report struct {
+	Location  string    `json:"location"`
+	Imperial  bool      `json:"imperial"`
+	Current   Current   `json:"current"`
+	Days      []Day     `json:"days"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Current holds the current conditions
+type Current struct {
+	Temp        float64 `json:"temp"`
+	FeelsLike   float64 `json:"feels_like"`
+	Humidity    int     `json:"humidity"`
+	Wind        float64 `json:"wind"`
+	Description string  `json:"description"`
+	Icon        string  `json:"icon"`
+}
+
+// Day is a single day of the forecast
+type Day struct {
+	Date         string  `json:"date"`
+	Min          float64 `json:"min"`
+	Max          float64 `json:"max"`
+	PrecipChance int     `json:"precip_chance"`
+	Description  string  `json:"description"`
+	Icon         string  `json:"icon"`
+}
+
+// TempUnit returns the temperature unit symbol for the report
+func (r *Report) TempUnit() string {
+	if r.Imperial {
+		return "°F"
+	}
+	return "°C"
+}
+
+// WindUnit returns the wind speed unit for the report
+func (r *Report) WindUnit() string {
+	if r.Imperial {
+		return "mph"
+	}
+	return "km/h"
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func configPath() (string, error) {
+	dir, err := fileutil.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "weather.json"), nil
+}
+
+// LoadConfig loads the weather config, returning defaults if none exists
+func LoadConfig() (*Config, error) {
+	config := &Config{Backend: OpenMeteo}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, fmt.Errorf("failed to read weather config: %w", err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", path, err)
+	}
+	if config.Backend == "" {
+		config.Backend = OpenMeteo
+	}
+	return config, nil
+}
+
+// SaveConfig writes the weather config
+func SaveConfig(config *Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.AtomicWrite(path, append(data, '\n'), 0644)
+}
+
+// Fetch returns a report for location (empty means geolocate by IP), using
+// the cache unless refresh is set
+func Fetch(backend, location string, imperial, refresh bool) (*Report, error) {
+	cachePath := ""
+	if dir, err := fileutil.CacheDir("weather"); err == nil {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%t", backend, strings.ToLower(location), imperial)))
+		cachePath = filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+	}
+
+	if cachePath != "" && !refresh {
+		if report := readCache(cachePath); report != nil {
+			return report, nil
+		}
+	}
+
+	var report *Report
+	var err error
+	switch backend {
+	case OpenMeteo:
+		report, err = fetchOpenMeteo(location, imperial)
+	case Wttr:
+		report, err = fetchWttr(location, imperial)
+	default:
+		return nil, fmt.Errorf("unknown backend %q (use %s or %s)", backend, OpenMeteo, Wttr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Imperial = imperial
+	report.FetchedAt = time.Now()
+	if cachePath != "" {
+		if data, err := json.Marshal(report); err == nil {
+			_ = fileutil.AtomicWrite(cachePath, data, 0644)
+		}
+	}
+
+	return report, nil
+}
+
+func readCache(path string) *Report {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil
+	}
+	if time.Since(report.FetchedAt) > CacheTTL {
+		return nil
+	}
+	return &report
+}
+
+// getJSON fetches url and decodes the JSON response into out
+func getJSON(url string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "zzk-weather/1.0")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch weather: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("weather service returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid weather response: %w", err)
+	}
+	return nil
+}
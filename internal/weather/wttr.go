@@ -0,0 +1,131 @@
+package weather
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func fetchWttr(location string, imperial bool) (*Report, error) {
+	type desc []struct {
+		Value string `json:"value"`
+	}
+	var result struct {
+		CurrentCondition []struct {
+			TempC       string `json:"temp_C"`
+			TempF       string `json:"temp_F"`
+			FeelsLikeC  string `json:"FeelsLikeC"`
+			FeelsLikeF  string `json:"FeelsLikeF"`
+			Humidity    string `json:"humidity"`
+			WindKmph    string `json:"windspeedKmph"`
+			WindMiles   string `json:"windspeedMiles"`
+			WeatherDesc desc   `json:"weatherDesc"`
+		} `json:"current_condition"`
+		NearestArea []struct {
+			AreaName desc `json:"areaName"`
+			Country  desc `json:"country"`
+		} `json:"nearest_area"`
+		Weather []struct {
+			Date     string `json:"date"`
+			MaxTempC string `json:"maxtempC"`
+			MaxTempF string `json:"maxtempF"`
+			MinTempC string `json:"mintempC"`
+			MinTempF string `json:"mintempF"`
+			Hourly   []struct {
+				ChanceOfRain string `json:"chanceofrain"`
+				WeatherDesc  desc   `json:"weatherDesc"`
+			} `json:"hourly"`
+		} `json:"weather"`
+	}
+
+	// wttr.in geolocates by IP when no location is given
+	if err := getJSON("https://wttr.in/"+url.PathEscape(location)+"?format=j1", &result); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Location: location}
+	if len(result.NearestArea) > 0 {
+		area := result.NearestArea[0]
+		if len(area.AreaName) > 0 {
+			report.Location = area.AreaName[0].Value
+			if len(area.Country) > 0 {
+				report.Location += ", " + area.Country[0].Value
+			}
+		}
+	}
+
+	pick := func(metric, imperialValue string) float64 {
+		if imperial {
+			return atof(imperialValue)
+		}
+		return atof(metric)
+	}
+
+	if len(result.CurrentCondition) > 0 {
+		cc := result.CurrentCondition[0]
+		report.Current = Current{
+			Temp:      pick(cc.TempC, cc.TempF),
+			FeelsLike: pick(cc.FeelsLikeC, cc.FeelsLikeF),
+			Humidity:  int(atof(cc.Humidity)),
+			Wind:      pick(cc.WindKmph, cc.WindMiles),
+		}
+		if len(cc.WeatherDesc) > 0 {
+			report.Current.Description = strings.TrimSpace(cc.WeatherDesc[0].Value)
+		}
+		report.Current.Icon = iconFor(report.Current.Description)
+	}
+
+	for _, w := range result.Weather {
+		day := Day{
+			Date: w.Date,
+			Min:  pick(w.MinTempC, w.MinTempF),
+			Max:  pick(w.MaxTempC, w.MaxTempF),
+		}
+		for _, hour := range w.Hourly {
+			if chance := int(atof(hour.ChanceOfRain)); chance > day.PrecipChance {
+				day.PrecipChance = chance
+			}
+		}
+		// Use the midday description for the day
+		if len(w.Hourly) > 0 {
+			midday := w.Hourly[len(w.Hourly)/2]
+			if len(midday.WeatherDesc) > 0 {
+				day.Description = strings.TrimSpace(midday.WeatherDesc[0].Value)
+			}
+		}
+		day.Icon = iconFor(day.Description)
+		report.Days = append(report.Days, day)
+	}
+
+	return report, nil
+}
+
+// iconFor picks an icon from a free-text weather description
+func iconFor(description string) string {
+	d := strings.ToLower(description)
+	switch {
+	case strings.Contains(d, "thunder"):
+		return "⛈"
+	case strings.Contains(d, "snow"), strings.Contains(d, "sleet"), strings.Contains(d, "ice"):
+		return "🌨"
+	case strings.Contains(d, "rain"), strings.Contains(d, "shower"):
+		return "🌧"
+	case strings.Contains(d, "drizzle"):
+		return "🌦"
+	case strings.Contains(d, "fog"), strings.Contains(d, "mist"):
+		return "🌫"
+	case strings.Contains(d, "overcast"), strings.Contains(d, "cloudy") && !strings.Contains(d, "partly"):
+		return "☁️"
+	case strings.Contains(d, "partly"):
+		return "⛅"
+	case strings.Contains(d, "sunny"), strings.Contains(d, "clear"):
+		return "☀️"
+	default:
+		return "?"
+	}
+}
+
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f
+}
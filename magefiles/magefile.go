@@ -1,15 +1,19 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/magefile/mage/mg"
 	"github.com/magefile/mage/sh"
+	"github.com/ppowo/zzk/internal/claude"
 )
 
 var Default = Build
@@ -138,6 +142,54 @@ func Clean() error {
 	return sh.Rm("bin")
 }
 
+// Registry stamps registry/claude-templates.json with the current time and
+// signs it with $ZZK_REGISTRY_KEY (the base64 Ed25519 private key seed),
+// writing bin/claude-templates.json and its .sig to attach to a release
+func Registry() error {
+	fmt.Println("Signing the template registry...")
+
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(os.Getenv("ZZK_REGISTRY_KEY")))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("ZZK_REGISTRY_KEY must hold the base64 Ed25519 private key seed")
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	if base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)) != claude.RegistryPublicKey {
+		return fmt.Errorf("ZZK_REGISTRY_KEY doesn't match the registry key built into zzk")
+	}
+
+	source, err := os.ReadFile(filepath.Join("registry", "claude-templates.json"))
+	if err != nil {
+		return err
+	}
+	var registry struct {
+		Updated   time.Time       `json:"updated"`
+		Templates json.RawMessage `json:"templates"`
+	}
+	if err := json.Unmarshal(source, &registry); err != nil {
+		return fmt.Errorf("invalid registry/claude-templates.json: %w", err)
+	}
+	registry.Updated = time.Now().UTC().Truncate(time.Second)
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll("bin", 0755); err != nil {
+		return err
+	}
+	out := filepath.Join("bin", "claude-templates.json")
+	if err := os.WriteFile(out, data, 0644); err != nil {
+		return err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(out+".sig", []byte(sig+"\n"), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Signed %s (attach it and %s.sig to the release)\n", out, out)
+	return nil
+}
+
 func Vet() error {
 	fmt.Println("Running go vet...")
 	return sh.Run("go", "vet", "./...")